@@ -1,6 +1,9 @@
 package garden
 
-import "time"
+import (
+	"errors"
+	"time"
+)
 
 //go:generate counterfeiter . Backend
 
@@ -11,4 +14,73 @@ type Backend interface {
 	Stop()
 
 	GraceTime(Container) time.Duration
+
+	// Restore creates a container from a checkpoint previously written to
+	// source by Container.Checkpoint, resuming the checkpointed processes.
+	// The spec is applied as for Create.
+	//
+	// Errors:
+	// * When source does not hold a checkpoint.
+	// * When the backend does not support checkpointing.
+	Restore(spec ContainerSpec, source string) (Container, error)
+}
+
+// ErrImagesUnsupported is returned for image requests to a server whose
+// backend is not an ImageManager.
+var ErrImagesUnsupported = errors.New("backend does not manage images")
+
+// ImageManager may be implemented by a Backend which caches the rootfs images
+// it fetches, so that images may be fetched ahead of the containers which
+// use them, e.g. to warm a cell before instances are placed on it, and
+// removed once they are stale.
+type ImageManager interface {
+	// WarmImage fetches the image with the rootfs URI into the cache, if it is
+	// not cached already, authenticating with the credentials if given.
+	WarmImage(uri string, credentials *RegistryCredentials) (CachedImage, error)
+
+	// Images returns the images in the cache.
+	Images() ([]CachedImage, error)
+
+	// RemoveImage removes the image with the rootfs URI from the cache.
+	//
+	// Errors:
+	// * ImageNotFoundError, when the image is not cached.
+	// * When a container still uses the image.
+	RemoveImage(uri string) error
+}
+
+// CachedImage describes a rootfs image in a backend's cache.
+type CachedImage struct {
+	// URI is the rootfs URI the image was fetched with, as given in
+	// ContainerSpec.RootFSPath.
+	URI string `json:"uri"`
+
+	SizeInBytes uint64    `json:"size_in_bytes"`
+	LastUsedAt  time.Time `json:"last_used_at"`
+
+	// Containers is how many containers use the image.
+	Containers int `json:"containers"`
+}
+
+// ReconciliationReporter may be implemented by a Backend which reconciles its
+// state with the host when it is started, to report what it did.
+type ReconciliationReporter interface {
+	Reconciliation() Reconciliation
+}
+
+// Reconciliation describes how the server and its backend recovered the
+// containers which existed when the server was started.
+type Reconciliation struct {
+	StartedAt time.Time `json:"started_at"`
+
+	// Recovered holds the handles of the containers which were still present
+	// once the backend had started.
+	Recovered []string `json:"recovered"`
+
+	// OrphansDestroyed holds the handles of containers which the backend
+	// found only partially present and destroyed.
+	OrphansDestroyed []string `json:"orphans_destroyed,omitempty"`
+
+	// ProcessesReattached is how many processes the backend reattached to.
+	ProcessesReattached int `json:"processes_reattached"`
 }
@@ -0,0 +1,38 @@
+package garden_test
+
+import (
+	"code.cloudfoundry.org/garden"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("BandwidthLimits", func() {
+	It("applies the rate and burst to both directions by default", func() {
+		limits := garden.BandwidthLimits{RateInBytesPerSecond: 100, BurstRateInBytesPerSecond: 200}
+
+		rate, burst := limits.Ingress()
+		Expect(rate).To(BeNumerically("==", 100))
+		Expect(burst).To(BeNumerically("==", 200))
+
+		rate, burst = limits.Egress()
+		Expect(rate).To(BeNumerically("==", 100))
+		Expect(burst).To(BeNumerically("==", 200))
+	})
+
+	It("lets each direction override the rate and burst", func() {
+		limits := garden.BandwidthLimits{
+			RateInBytesPerSecond:            100,
+			BurstRateInBytesPerSecond:       200,
+			IngressRateInBytesPerSecond:     10,
+			EgressBurstRateInBytesPerSecond: 20,
+		}
+
+		rate, burst := limits.Ingress()
+		Expect(rate).To(BeNumerically("==", 10))
+		Expect(burst).To(BeNumerically("==", 200))
+
+		rate, burst = limits.Egress()
+		Expect(rate).To(BeNumerically("==", 100))
+		Expect(burst).To(BeNumerically("==", 20))
+	})
+})
@@ -1,6 +1,12 @@
 package garden
 
-import "time"
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
 
 //go:generate counterfeiter . Client
 type Client interface {
@@ -20,7 +26,8 @@ type Client interface {
 	// Create creates a new container.
 	//
 	// Errors:
-	// * When the handle, if specified, is already taken.
+	// * When the handle, if specified, is already taken; backends should return a
+	//   HandleTakenError.
 	// * When one of the bind_mount paths does not exist.
 	// * When resource allocations fail (subnet, user ID, etc).
 	Create(ContainerSpec) (Container, error)
@@ -58,6 +65,217 @@ type Client interface {
 	Lookup(handle string) (Container, error)
 }
 
+// BulkOptions modifies how BulkInfo and BulkMetrics entries are computed.
+type BulkOptions struct {
+	// Snapshot, if true, computes all entries against the set of containers
+	// that existed when the request was received. Containers destroyed while
+	// entries are being collected are reported with Tombstone set, rather
+	// than being indistinguishable from handles that never existed.
+	Snapshot bool
+}
+
+// NamespaceProperty is the property naming the namespace, such as the
+// control plane which created it, that a container belongs to. Servers
+// configured with per-namespace port ranges map host ports for the container
+// only from its namespace's range.
+const NamespaceProperty = "garden.namespace"
+
+// CreatedAtProperty is the property in which the server records the time a
+// container was created, formatted as RFC 3339, when it is created or
+// restored. A time already given in the spec, such as when restoring a
+// container with its original properties, is kept rather than replaced.
+const CreatedAtProperty = "garden.created_at"
+
+// TTLProperty is the property in which a container may declare how long, as
+// a duration such as "1h30m", it may live after being created. It is only
+// enforced by servers that run a reaper.
+const TTLProperty = "garden.ttl"
+
+// MaxDescriptionLength is the largest description, in bytes, that a server
+// accepts for a container.
+const MaxDescriptionLength = 1024
+
+// MaxHostnameLength is the longest hostname, in bytes, that a server accepts
+// for a container, matching the kernel's bound.
+const MaxHostnameLength = 64
+
+const (
+	ExpirationReasonGraceTime = "grace_time"
+	ExpirationReasonTTL       = "ttl"
+)
+
+// Expiration describes when a container is due to be destroyed, and why.
+type Expiration struct {
+	Handle    string    `json:"handle"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Reason    string    `json:"reason"`
+}
+
+// HandleScheme describes how a server generates the handles of containers
+// created without one. The zero scheme leaves it to the backend.
+type HandleScheme struct {
+	// Prefix starts every generated handle.
+	Prefix string `json:"prefix,omitempty"`
+
+	// Length is the number of random characters, drawn from Charset, which
+	// follow the prefix.
+	Length int `json:"length,omitempty"`
+
+	// Charset is the characters handles are drawn from.
+	Charset string `json:"charset,omitempty"`
+
+	// UUIDv7 follows the prefix with a version 7 UUID instead of random
+	// characters, so that handles sort in the order they were generated.
+	UUIDv7 bool `json:"uuidv7,omitempty"`
+}
+
+// Kinds of container lifecycle Event.
+const (
+	EventCreated          = "created"
+	EventProcessStarted   = "process-started"
+	EventProcessRestarted = "process-restarted"
+	EventHealthChanged    = "health-changed"
+	EventStopped          = "stopped"
+	EventDestroyed        = "destroyed"
+	EventPropertyChanged  = "property-changed"
+	EventOOM              = "oom"
+)
+
+// Event is a change in the lifecycle of a container, as streamed by the
+// server's events route. For EventOOM, Time is when the container ran out of
+// memory, which may be some time before the event is streamed.
+type Event struct {
+	Kind   string    `json:"kind"`
+	Handle string    `json:"handle"`
+	Time   time.Time `json:"time"`
+
+	// ProcessID is set for EventProcessStarted and EventProcessRestarted.
+	ProcessID string `json:"process_id,omitempty"`
+
+	// Restarts is the number of times the container's main process has been
+	// restarted under its RestartPolicy, and is set for EventProcessRestarted.
+	Restarts int `json:"restarts,omitempty"`
+
+	// Health is the state the container's health changed to, and is set for
+	// EventHealthChanged.
+	Health string `json:"health,omitempty"`
+
+	// Property is the name of the property set or removed, and is set for
+	// EventPropertyChanged.
+	Property string `json:"property,omitempty"`
+
+	// Seq orders the events streamed by a server. It increases with each
+	// event, also across restarts of the server, and may be given back to the
+	// server to resume streaming after the event.
+	Seq uint64 `json:"seq,omitempty"`
+}
+
+// PortAllocation is a host port mapped to a container by NetIn.
+type PortAllocation struct {
+	Handle        string `json:"handle"`
+	HostPort      uint32 `json:"host_port"`
+	ContainerPort uint32 `json:"container_port"`
+	HostIP        string `json:"host_ip,omitempty"`
+}
+
+// QuarantinedPort is a host port released by a destroyed container which may
+// not be mapped again until Until.
+type QuarantinedPort struct {
+	HostPort uint32    `json:"host_port"`
+	Until    time.Time `json:"until"`
+}
+
+// PortAllocations describes the host ports in use by containers and those
+// waiting out the server's port reuse grace period.
+type PortAllocations struct {
+	Allocations []PortAllocation  `json:"allocations"`
+	Quarantined []QuarantinedPort `json:"quarantined"`
+}
+
+// ListOptions modifies the results of listing containers. Containers without
+// a CreatedAtProperty are ordered first and are excluded by either filter.
+type ListOptions struct {
+	// OrderByCreation, if true, orders the results oldest first.
+	OrderByCreation bool
+
+	// CreatedBefore, if not zero, only includes containers created before it.
+	CreatedBefore time.Time
+
+	// CreatedAfter, if not zero, only includes containers created after it.
+	CreatedAfter time.Time
+
+	// PropertyFilters, if any, only includes containers whose properties
+	// match all of them, as well as the filter properties.
+	PropertyFilters []PropertyFilter
+}
+
+// PropertyOperator is the test which a PropertyFilter applies to a property.
+type PropertyOperator string
+
+const (
+	// PropertyExists matches containers which have the property, whatever
+	// its value.
+	PropertyExists PropertyOperator = "exists"
+
+	// PropertyHasPrefix matches containers whose property starts with any of
+	// the values.
+	PropertyHasPrefix PropertyOperator = "prefix"
+
+	// PropertyIn matches containers whose property is any of the values.
+	PropertyIn PropertyOperator = "in"
+)
+
+// PropertyFilter matches containers by one of their properties, e.g. to find
+// those without an "owner" property:
+//
+//	PropertyFilter{Name: "owner", Operator: PropertyExists, Negate: true}
+//
+// A container without the property is matched only by a negated filter.
+type PropertyFilter struct {
+	Name     string
+	Operator PropertyOperator
+
+	// Values are the operands of PropertyHasPrefix and PropertyIn, which
+	// match nothing without any. PropertyExists ignores them.
+	Values []string
+
+	// Negate, if true, matches exactly those containers which the filter
+	// would otherwise not match.
+	Negate bool
+}
+
+// Matches reports whether the filter matches a container with the
+// properties.
+func (f PropertyFilter) Matches(properties Properties) bool {
+	return f.matches(properties) != f.Negate
+}
+
+func (f PropertyFilter) matches(properties Properties) bool {
+	value, found := properties[f.Name]
+	if !found {
+		return false
+	}
+
+	switch f.Operator {
+	case PropertyExists:
+		return true
+	case PropertyHasPrefix:
+		for _, prefix := range f.Values {
+			if strings.HasPrefix(value, prefix) {
+				return true
+			}
+		}
+	case PropertyIn:
+		for _, v := range f.Values {
+			if value == v {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 // ContainerSpec specifies the parameters for creating a container. All parameters are optional.
 type ContainerSpec struct {
 
@@ -72,6 +290,16 @@ type ContainerSpec struct {
 	// subject to the globally configured grace time.
 	GraceTime time.Duration `json:"grace_time,omitempty"`
 
+	// Hostname, if specified, is the container's hostname. It must be a valid
+	// hostname of at most MaxHostnameLength bytes. If not specified, the
+	// container's handle is used where it is a valid hostname.
+	Hostname string `json:"hostname,omitempty"`
+
+	// Description is free text saying what the container is for, for the
+	// benefit of operators inspecting the host. It may be at most
+	// MaxDescriptionLength bytes long.
+	Description string `json:"description,omitempty"`
+
 	// RootFSPath is a URI referring to the root file system for the container.
 	// The URI scheme must either be the empty string or "docker".
 	//
@@ -93,6 +321,19 @@ type ContainerSpec struct {
 	// * "docker://index.docker.io/busybox"
 	RootFSPath string `json:"rootfs,omitempty"`
 
+	// Image, if specified, is the OCI image the root file system is created
+	// from, rather than RootFSPath, which must then be empty. The digest the
+	// image resolved to is reported as ContainerInfo.ImageDigest, so that a
+	// container created from an unpinned reference can be reproduced.
+	Image *ImageRef `json:"image,omitempty"`
+
+	// RegistryCredentials, if specified, authenticate the backend to the
+	// registry named by a "docker" RootFSPath or by Image, e.g. to fetch an image
+	// from a private registry. They are sent only in the body of the create
+	// request, so the connection should be secured, e.g. with TLS or a unix
+	// socket, and are redacted wherever a spec is logged or formatted.
+	RegistryCredentials *RegistryCredentials `json:"registry_credentials,omitempty"`
+
 	// * bind_mounts: a list of mount point descriptions which will result in corresponding mount
 	// points being created in the container's file system.
 	//
@@ -128,6 +369,28 @@ type ContainerSpec struct {
 	//   already had a container allocated from it.
 	Network string `json:"network,omitempty"`
 
+	// ContainerIP, if specified, is the IP address the container is given,
+	// e.g. so that it keeps an address which appears in other systems' ACLs
+	// when recreated. It must lie within Network if that is also specified,
+	// and agree with it if Network gives an address. The assigned address is
+	// reported as ContainerInfo.ContainerIP.
+	//
+	// An IPTakenError is returned if another container has the address.
+	ContainerIP string `json:"container_ip,omitempty"`
+
+	// Nameservers are the IP addresses of the DNS servers the container
+	// resolves names with, in order of preference. If not specified, the
+	// backend's default nameservers are used.
+	Nameservers []string `json:"nameservers,omitempty"`
+
+	// SearchDomains are the domains searched, in order, when resolving names
+	// which are not fully qualified.
+	SearchDomains []string `json:"search_domains,omitempty"`
+
+	// Hosts are entries added to the container's /etc/hosts, after those the
+	// backend writes itself.
+	Hosts []HostsEntry `json:"hosts,omitempty"`
+
 	// Properties is a sequence of string key/value pairs providing arbitrary
 	// data about the container. The keys are assumed to be unique but this is not
 	// enforced via the protocol.
@@ -143,6 +406,216 @@ type ContainerSpec struct {
 
 	// Limits to be applied to the newly created container.
 	Limits Limits `json:"limits,omitempty"`
+
+	// Devices are the device nodes the container may access, e.g. /dev/fuse,
+	// without being privileged.
+	Devices []Device `json:"devices,omitempty"`
+
+	// Capabilities adjusts the capabilities the container's processes may
+	// hold, e.g. to drop those an unprivileged workload does not need.
+	Capabilities Capabilities `json:"capabilities,omitempty"`
+
+	// SeccompProfile, if specified, is the seccomp profile which filters the
+	// system calls of the container's processes, rather than the backend's
+	// default.
+	SeccompProfile *SeccompProfile `json:"seccomp_profile,omitempty"`
+
+	// LSMProfile, if specified, is the AppArmor profile or SELinux label,
+	// depending on which the host uses, that the container's processes are
+	// confined by, rather than the backend's default. It is reported as
+	// ContainerInfo.LSMProfile.
+	LSMProfile string `json:"lsm_profile,omitempty"`
+
+	// RestartPolicy, if specified, designates the container's main process,
+	// which the server runs once the container is created or restored and
+	// runs again when it exits as the policy says. The server holds the
+	// policy only in memory: once it restarts, the containers it recovers
+	// are no longer supervised.
+	RestartPolicy *RestartPolicy `json:"restart_policy,omitempty"`
+
+	// HealthCheck, if specified, is probed periodically by the server, which
+	// reports the container's health as ContainerInfo.Health and streams
+	// EventHealthChanged events as it changes. The server holds the check
+	// only in memory: once it restarts, the containers it recovers are no
+	// longer probed and report no health.
+	HealthCheck *HealthCheck `json:"health_check,omitempty"`
+}
+
+// Modes of a RestartPolicy.
+const (
+	RestartNever     = "never"
+	RestartOnFailure = "on-failure"
+	RestartAlways    = "always"
+)
+
+// Defaults for the backoff of a RestartPolicy.
+const (
+	DefaultRestartBackoff    = time.Second
+	DefaultMaxRestartBackoff = time.Minute
+)
+
+// RestartPolicy has the server supervise a container's main process, so that
+// clients need not watch for it exiting to run it again. The server stops
+// restarting the process once the container is stopped or destroyed.
+type RestartPolicy struct {
+	// Mode is whether the process is restarted when it exits: RestartNever,
+	// RestartOnFailure if it fails, i.e. exits with a non-zero status or
+	// cannot be waited for, or RestartAlways.
+	Mode string `json:"mode"`
+
+	// Process is the main process. Giving it a Name allows it to be attached
+	// to and signalled by name across restarts.
+	Process ProcessSpec `json:"process"`
+
+	// Backoff is how long the server waits before restarting the process,
+	// doubling after each restart up to MaxBackoff. It is reset once the
+	// process has run for longer than MaxBackoff. They default to
+	// DefaultRestartBackoff and DefaultMaxRestartBackoff.
+	Backoff    time.Duration `json:"backoff,omitempty"`
+	MaxBackoff time.Duration `json:"max_backoff,omitempty"`
+
+	// MaxRestarts, if non-zero, is the number of times the process is
+	// restarted before the server gives up on it.
+	MaxRestarts int `json:"max_restarts,omitempty"`
+}
+
+// Defaults for a HealthCheck.
+const (
+	DefaultHealthCheckInterval         = 10 * time.Second
+	DefaultHealthCheckTimeout          = 5 * time.Second
+	DefaultHealthCheckFailureThreshold = 3
+)
+
+// MaxHealthCheckOutputLength is the number of bytes of a probe's output which
+// are kept as HealthStatus.LastOutput.
+const MaxHealthCheckOutputLength = 4096
+
+// HealthCheck probes whether a container's workload is healthy, in one of
+// three ways, exactly one of which must be set: by running a process in the
+// container, which is healthy if it exits with status zero; by connecting to
+// a TCP port of the container's IP; or by a GET request to an HTTP port of the
+// container's IP, which is healthy if it responds with a 2xx or 3xx status.
+type HealthCheck struct {
+	Process *ProcessSpec `json:"process,omitempty"`
+	TCP     *TCPProbe    `json:"tcp,omitempty"`
+	HTTP    *HTTPProbe   `json:"http,omitempty"`
+
+	// Interval is how long the server waits between probes, and Timeout how
+	// long a probe may take before it fails. They default to
+	// DefaultHealthCheckInterval and DefaultHealthCheckTimeout.
+	Interval time.Duration `json:"interval,omitempty"`
+	Timeout  time.Duration `json:"timeout,omitempty"`
+
+	// FailureThreshold is the number of consecutive probes which must fail
+	// before the container is unhealthy. It defaults to
+	// DefaultHealthCheckFailureThreshold.
+	FailureThreshold int `json:"failure_threshold,omitempty"`
+}
+
+// TCPProbe connects to a port of the container's IP.
+type TCPProbe struct {
+	Port uint32 `json:"port"`
+}
+
+// HTTPProbe requests a path from a port of the container's IP.
+type HTTPProbe struct {
+	Port uint32 `json:"port"`
+	Path string `json:"path,omitempty"`
+}
+
+// States of a HealthStatus.
+const (
+	HealthStarting  = "starting"
+	HealthHealthy   = "healthy"
+	HealthUnhealthy = "unhealthy"
+)
+
+// HealthStatus is the result of a container's HealthCheck.
+type HealthStatus struct {
+	// State is HealthStarting until a probe succeeds or FailureThreshold
+	// probes have failed, and then HealthHealthy or HealthUnhealthy.
+	State string `json:"state"`
+
+	// ConsecutiveFailures is the number of probes which have failed since one
+	// last succeeded.
+	ConsecutiveFailures int `json:"consecutive_failures,omitempty"`
+
+	// LastProbedAt is when the last probe finished, and LastOutput what it
+	// output, truncated to MaxHealthCheckOutputLength bytes: the output of a
+	// process probe, or the response status or error of a TCP or HTTP probe.
+	LastProbedAt time.Time `json:"last_probed_at,omitempty"`
+	LastOutput   string    `json:"last_output,omitempty"`
+}
+
+// RegistryCredentials authenticate to a Docker registry either with a
+// username and password or with a bearer token.
+type RegistryCredentials struct {
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Token    string `json:"token,omitempty"`
+}
+
+// Redacted returns a copy of the credentials with the password and token, if
+// set, replaced by RedactedValue.
+func (c RegistryCredentials) Redacted() RegistryCredentials {
+	if c.Password != "" {
+		c.Password = RedactedValue
+	}
+
+	if c.Token != "" {
+		c.Token = RedactedValue
+	}
+
+	return c
+}
+
+// String formats the credentials with their secrets redacted, so that
+// formatting a spec does not reveal them.
+func (c RegistryCredentials) String() string {
+	r := c.Redacted()
+	return fmt.Sprintf("{Username:%s Password:%s Token:%s}", r.Username, r.Password, r.Token)
+}
+
+// Redactor returns a Redactor whose ValuePatterns match the password and
+// token, e.g. to remove them from the errors of an image fetcher which may
+// echo them back.
+func (c RegistryCredentials) Redactor() Redactor {
+	var patterns []*regexp.Regexp
+	for _, secret := range []string{c.Password, c.Token} {
+		if secret != "" {
+			patterns = append(patterns, regexp.MustCompile(regexp.QuoteMeta(secret)))
+		}
+	}
+
+	return Redactor{ValuePatterns: patterns}
+}
+
+// SeccompProfile selects a seccomp profile by the name of one which the
+// backend knows, e.g. one per class of workload, or gives a profile inline in
+// the JSON format of the OCI runtime specification. Exactly one of Name and
+// Inline must be set.
+type SeccompProfile struct {
+	Name   string          `json:"name,omitempty"`
+	Inline json.RawMessage `json:"inline,omitempty"`
+}
+
+// Device grants a container access to a device node.
+type Device struct {
+	// Path is the path of the device node in the container.
+	Path string `json:"path"`
+
+	// Type is whether the device is a character or block device.
+	Type DeviceType `json:"type"`
+
+	// Major and Minor are the device's numbers. If both are zero, they are
+	// taken from the device node at Path in the host.
+	Major int64 `json:"major,omitempty"`
+	Minor int64 `json:"minor,omitempty"`
+
+	// Permissions are those the container has on the device, made of "r" to
+	// read, "w" to write and "m" to create the node, e.g. "rw". If omitted,
+	// the container has all three.
+	Permissions string `json:"permissions,omitempty"`
 }
 
 type Limits struct {
@@ -150,6 +623,8 @@ type Limits struct {
 	CPU       CPULimits       `json:"cpu_limits,omitempty"`
 	Disk      DiskLimits      `json:"disk_limits,omitempty"`
 	Memory    MemoryLimits    `json:"memory_limits,omitempty"`
+	Pid       PidLimits       `json:"pid_limits,omitempty"`
+	BlockIO   BlockIOLimits   `json:"block_io_limits,omitempty"`
 }
 
 // BindMount specifies parameters for a single mount point.
@@ -176,6 +651,24 @@ type BindMount struct {
 	// If origin is "Host", src_path denotes a path in the host.
 	// If origin is "Container", src_path denotes a path in the container.
 	Origin BindMountOrigin `json:"origin,omitempty"`
+
+	// Propagation determines whether mounts made beneath the mount point, in
+	// the host or the container, are seen on the other side. It may be
+	// omitted and defaults to private, in which case neither side sees the
+	// other's mounts. If shared, each sees the other's. If slave, the
+	// container sees the host's, but not the other way around.
+	Propagation BindMountPropagation `json:"propagation,omitempty"`
+
+	// RecursiveReadOnly, if true, also makes the mounts beneath SrcPath
+	// read-only in the container, rather than only the mount point itself.
+	// It requires the mode to be "RO".
+	RecursiveReadOnly bool `json:"recursive_read_only,omitempty"`
+}
+
+// HostsEntry maps hostnames to an IP address in a container's /etc/hosts.
+type HostsEntry struct {
+	IP        string   `json:"ip"`
+	Hostnames []string `json:"hostnames"`
 }
 
 type Capacity struct {
@@ -184,6 +677,76 @@ type Capacity struct {
 	MaxContainers uint64 `json:"max_containers,omitempty"`
 }
 
+// CellPressure describes how heavily loaded the server's machine is, beyond
+// its capacity, so that schedulers may avoid a machine which is struggling.
+// Indicators which the machine does not provide are omitted.
+type CellPressure struct {
+	// Pressure stall information for the CPU, memory and IO, as reported by
+	// the kernel.
+	CPU    *PressureStall `json:"cpu,omitempty"`
+	Memory *PressureStall `json:"memory,omitempty"`
+	IO     *PressureStall `json:"io,omitempty"`
+
+	// LoadAverage is the load average over 1, 5 and 15 minutes.
+	LoadAverage *[3]float64 `json:"load_average,omitempty"`
+
+	// GraphDisk is the space on the volume holding container images.
+	GraphDisk *DiskSpace `json:"graph_disk,omitempty"`
+
+	// FileDescriptors is the usage of file descriptors by the server.
+	FileDescriptors *FileDescriptorUsage `json:"file_descriptors,omitempty"`
+}
+
+// PressureStall is the share of time in which some, or all, non-idle tasks
+// were stalled on a resource. Full is omitted for resources, such as the CPU
+// on older kernels, for which the kernel does not report it.
+type PressureStall struct {
+	Some PressureAverages  `json:"some"`
+	Full *PressureAverages `json:"full,omitempty"`
+}
+
+// PressureAverages are the percentages of time stalled over the last 10, 60
+// and 300 seconds, and the total time stalled.
+type PressureAverages struct {
+	Avg10  float64       `json:"avg10"`
+	Avg60  float64       `json:"avg60"`
+	Avg300 float64       `json:"avg300"`
+	Total  time.Duration `json:"total"`
+}
+
+type DiskSpace struct {
+	FreeBytes  uint64 `json:"free_bytes"`
+	TotalBytes uint64 `json:"total_bytes"`
+}
+
+type FileDescriptorUsage struct {
+	Open  uint64 `json:"open"`
+	Limit uint64 `json:"limit"`
+}
+
+// DrainStatus reports whether a server is draining, refusing to create
+// containers, and whether it has finished the requests it was handling.
+type DrainStatus struct {
+	Draining bool `json:"draining"`
+
+	// Since is when the server started draining, if it is.
+	Since time.Time `json:"since"`
+
+	// InFlight is how many requests the server is handling which a drain
+	// waits for: those creating containers, or streaming into or out of
+	// containers.
+	InFlight int `json:"in_flight"`
+
+	// Attached is how many connections running or attached to processes, or
+	// streaming their output, are open. They last as long as their
+	// processes, so a drain does not wait for them; they end as the
+	// processes' containers are evacuated.
+	Attached int `json:"attached"`
+
+	// Drained is true once the server is draining and nothing is in flight.
+	Drained bool `json:"drained"`
+}
+
 type Properties map[string]string
 
 type BindMountMode uint8
@@ -195,3 +758,16 @@ type BindMountOrigin uint8
 
 const BindMountOriginHost BindMountOrigin = 0
 const BindMountOriginContainer BindMountOrigin = 1
+
+type DeviceType string
+
+const (
+	DeviceTypeChar  DeviceType = "c"
+	DeviceTypeBlock DeviceType = "b"
+)
+
+type BindMountPropagation uint8
+
+const BindMountPropagationPrivate BindMountPropagation = 0
+const BindMountPropagationShared BindMountPropagation = 1
+const BindMountPropagationSlave BindMountPropagation = 2
@@ -1,14 +1,200 @@
+// Package client implements garden.Client, and the client-only methods of
+// Client, over the REST API served by the server package. Containers and
+// processes are returned as the garden interfaces, so consumers need not
+// depend on this package beyond New.
 package client
 
 import (
+	"context"
+	"time"
+
 	"code.cloudfoundry.org/garden"
 	"code.cloudfoundry.org/garden/client/connection"
 )
 
 type Client interface {
 	garden.Client
+
+	// EnsureContainer returns the container with the spec's handle, creating
+	// it from the spec if it does not exist. The spec is not compared with an
+	// existing container. If another client creates the container at the same
+	// time, the existing container is looked up again, a bounded number of
+	// times.
+	EnsureContainer(spec garden.ContainerSpec) (garden.Container, error)
+
+	// ContainersWithOptions is like Containers but orders and filters the
+	// results as specified by opts.
+	ContainersWithOptions(filter garden.Properties, opts garden.ListOptions) ([]garden.Container, error)
+
+	// DestroyDryRun returns what destroying the container would clean up,
+	// without destroying it.
+	DestroyDryRun(handle string) (garden.DestroyImpact, error)
+
+	// Expirations returns when each container is due to be destroyed by the
+	// server, soonest first.
+	Expirations() ([]garden.Expiration, error)
+
+	// HandleScheme returns how the server generates the handles of containers
+	// created without one, so that conforming handles can be pre-computed
+	// with the handles package. It is the zero scheme if the backend
+	// generates them.
+	HandleScheme() (garden.HandleScheme, error)
+
+	// PortAllocations returns the host ports mapped to containers and those
+	// quarantined after their containers were destroyed.
+	PortAllocations() (garden.PortAllocations, error)
+
+	// ProcessExit returns how the process exited, as retained by the server
+	// for a period after the process exits. It returns a
+	// garden.ProcessNotFoundError if the process has not exited or its exit is
+	// no longer retained.
+	ProcessExit(handle string, processID string) (garden.ProcessExit, error)
+
+	// CASProperty sets the container's property to newValue only if its
+	// current value is oldValue, atomically with respect to other changes to
+	// properties through the server, e.g. to use a property as a lock. An
+	// empty oldValue requires that the property is not set. It returns a
+	// garden.PropertyConflictError giving the current value otherwise.
+	CASProperty(handle string, name string, oldValue string, newValue string) error
+
+	// SetProperties sets each of the container's properties given in one
+	// request, e.g. to update many placement tags at once. The properties are
+	// set in turn, so if one fails to be set, those before it may have been.
+	SetProperties(handle string, properties garden.Properties) error
+
+	// RemoveProperties removes each of the container's named properties in one
+	// request. As for SetProperties, if one fails to be removed, those before
+	// it may have been.
+	RemoveProperties(handle string, names []string) error
+
+	// AttachByName attaches to the running process with the name given in its
+	// ProcessSpec, as Attach does given its ID.
+	AttachByName(handle string, name string, io garden.ProcessIO) (garden.Process, error)
+
+	// SignalByName delivers a signal to the running process with the name
+	// given in its ProcessSpec.
+	SignalByName(handle string, name string, signal garden.Signal) error
+
+	// ProcessExitByName returns how the process with the name given in its
+	// ProcessSpec exited, as ProcessExit does given its ID. A name refers to
+	// the process most recently started with it.
+	ProcessExitByName(handle string, name string) (garden.ProcessExit, error)
+
+	// RunDetached starts a process in the container without attaching to its
+	// IO, and returns its ID as soon as it has started. The server waits for
+	// the process, so that how it exited may be retrieved with ProcessExit,
+	// e.g. for fire-and-forget jobs. Its output is discarded.
+	RunDetached(handle string, spec garden.ProcessSpec) (string, error)
+
+	// ProcessEnv returns the environment the running process was started
+	// with, for debugging. The server may redact the values of sensitive
+	// variables, and returns a garden.PermissionDeniedError unless it permits
+	// environments to be retrieved.
+	ProcessEnv(handle string, processID string) ([]string, error)
+
+	// Reconciliation returns how the server recovered the containers which
+	// existed when it was last started.
+	Reconciliation() (garden.Reconciliation, error)
+
+	// Pressure returns how heavily loaded the server's machine is, e.g. so
+	// that a scheduler may avoid a machine which is under capacity but
+	// struggling.
+	Pressure() (garden.CellPressure, error)
+
+	// Drain makes the server refuse to create containers while it finishes
+	// the requests in flight, e.g. to evacuate its containers without
+	// downtime. Other requests are still served. The returned status reports
+	// when the server is drained.
+	Drain() (garden.DrainStatus, error)
+
+	// Undrain makes a draining server create containers again.
+	Undrain() (garden.DrainStatus, error)
+
+	// DrainStatus returns whether the server is draining, and whether it is
+	// drained.
+	DrainStatus() (garden.DrainStatus, error)
+
+	// Events streams container lifecycle events until ctx is done or the
+	// server ends the stream, at which point the channel is closed. The server
+	// ends the stream of a consumer which falls too far behind, so consumers
+	// should resynchronise with List and Info when resubscribing.
+	Events(ctx context.Context) (<-chan garden.Event, error)
+
+	// EventsSince is like Events, but first streams those events after the
+	// one with the given sequence number which the server still retains, so
+	// that a consumer may resume where it left off. A consumer can tell that
+	// it missed events which were no longer retained by a gap in the sequence
+	// numbers.
+	EventsSince(ctx context.Context, seq uint64) (<-chan garden.Event, error)
+
+	// StreamMetrics streams snapshots of the metrics of the containers with
+	// the given handles, or of all containers if none are given, at the given
+	// interval, rather than polling BulkMetrics. The channel is closed when
+	// ctx is done or the server ends the stream.
+	StreamMetrics(ctx context.Context, handles []string, interval time.Duration) (<-chan garden.MetricsSnapshot, error)
+
+	// Restore creates a container from a checkpoint written to source, a path
+	// on the server's host, by Container.Checkpoint.
+	Restore(spec garden.ContainerSpec, source string) (garden.Container, error)
+
+	// BulkCreate creates a container from each spec, several at once,
+	// returning an entry for each spec, in the same order, which holds either
+	// the handle of the created container or the error that prevented it from
+	// being created.
+	BulkCreate(specs []garden.ContainerSpec) ([]garden.ContainerCreateEntry, error)
+
+	// BulkDestroy destroys the containers, several at once, returning an entry
+	// for each handle which holds the error if it could not be destroyed.
+	BulkDestroy(handles []string) (map[string]garden.ContainerDestroyEntry, error)
+
+	// BulkStop stops the containers, several at once, as Container.Stop does,
+	// returning an entry for each handle which holds the error if it could not
+	// be stopped.
+	BulkStop(handles []string, kill bool) (map[string]garden.ContainerStopEntry, error)
+
+	// BulkInfoWithOptions is like BulkInfo but computes the entries as
+	// specified by opts.
+	BulkInfoWithOptions(handles []string, opts garden.BulkOptions) (map[string]garden.ContainerInfoEntry, error)
+
+	// BulkMetricsWithOptions is like BulkMetrics but computes the entries as
+	// specified by opts.
+	BulkMetricsWithOptions(handles []string, opts garden.BulkOptions) (map[string]garden.ContainerMetricsEntry, error)
+
+	// BulkMetricsDelta is like BulkMetricsWithOptions but returns only the
+	// entries which changed beyond the server's thresholds since the response
+	// whose token is given. Every entry is returned, and the result marked
+	// Full, if since is empty or the server no longer knows the token. The
+	// result's token is given to the next call, and the server forgets since.
+	BulkMetricsDelta(handles []string, opts garden.BulkOptions, since string) (garden.BulkMetricsDelta, error)
+
+	// Warm fetches the image with the rootfs URI into the server's image
+	// cache ahead of the containers which use it, authenticating with the
+	// credentials if given, and returns the cached image.
+	Warm(imageURI string, credentials *garden.RegistryCredentials) (garden.CachedImage, error)
+
+	// ListImages returns the images in the server's image cache.
+	ListImages() ([]garden.CachedImage, error)
+
+	// RemoveImage removes the image with the rootfs URI from the server's
+	// image cache, to reclaim its disk. It returns a
+	// garden.ImageNotFoundError if the image is not cached.
+	RemoveImage(imageURI string) error
+}
+
+// Process is implemented by the processes returned by Run and Attach.
+type Process interface {
+	garden.Process
+
+	// WaitWithContext is like Wait but stops waiting, leaving the process
+	// running, when ctx is done. It then returns a
+	// connection.WaitCancelledError.
+	WaitWithContext(ctx context.Context) (int, error)
 }
 
+// ensureContainerAttempts bounds how many times EnsureContainer looks up and
+// creates the container when racing with other clients.
+const ensureContainerAttempts = 3
+
 type client struct {
 	connection connection.Connection
 }
@@ -36,18 +222,72 @@ func (client *client) Create(spec garden.ContainerSpec) (garden.Container, error
 	return newContainer(handle, client.connection), nil
 }
 
+func (client *client) EnsureContainer(spec garden.ContainerSpec) (garden.Container, error) {
+	if spec.Handle == "" {
+		return client.Create(spec)
+	}
+
+	var err error
+	for attempt := 0; attempt < ensureContainerAttempts; attempt++ {
+		var container garden.Container
+		container, err = client.Lookup(spec.Handle)
+		if err == nil {
+			return container, nil
+		}
+
+		if _, ok := err.(garden.ContainerNotFoundError); !ok {
+			return nil, err
+		}
+
+		container, err = client.Create(spec)
+		if err == nil {
+			return container, nil
+		}
+
+		// the container was created by someone else since the lookup, but may
+		// yet be destroyed before it is looked up again
+		if _, ok := err.(garden.HandleTakenError); !ok {
+			return nil, err
+		}
+	}
+
+	return nil, err
+}
+
+func (client *client) Restore(spec garden.ContainerSpec, source string) (garden.Container, error) {
+	handle, err := client.connection.Restore(spec, source)
+	if err != nil {
+		return nil, err
+	}
+
+	return newContainer(handle, client.connection), nil
+}
+
 func (client *client) Containers(properties garden.Properties) ([]garden.Container, error) {
 	handles, err := client.connection.List(properties)
 	if err != nil {
 		return nil, err
 	}
 
+	return client.containers(handles), nil
+}
+
+func (client *client) ContainersWithOptions(properties garden.Properties, opts garden.ListOptions) ([]garden.Container, error) {
+	handles, err := client.connection.ListWithOptions(properties, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.containers(handles), nil
+}
+
+func (client *client) containers(handles []string) []garden.Container {
 	containers := []garden.Container{}
 	for _, handle := range handles {
 		containers = append(containers, newContainer(handle, client.connection))
 	}
 
-	return containers, nil
+	return containers
 }
 
 func (client *client) Destroy(handle string) error {
@@ -64,6 +304,126 @@ func (client *client) BulkMetrics(handles []string) (map[string]garden.Container
 	return client.connection.BulkMetrics(handles)
 }
 
+func (client *client) DestroyDryRun(handle string) (garden.DestroyImpact, error) {
+	return client.connection.DestroyDryRun(handle)
+}
+
+func (client *client) HandleScheme() (garden.HandleScheme, error) {
+	return client.connection.HandleScheme()
+}
+
+func (client *client) Expirations() ([]garden.Expiration, error) {
+	return client.connection.Expirations()
+}
+
+func (client *client) PortAllocations() (garden.PortAllocations, error) {
+	return client.connection.PortAllocations()
+}
+
+func (client *client) RunDetached(handle string, spec garden.ProcessSpec) (string, error) {
+	return client.connection.RunDetached(handle, spec)
+}
+
+func (client *client) ProcessExit(handle string, processID string) (garden.ProcessExit, error) {
+	return client.connection.ProcessExit(handle, processID)
+}
+
+func (client *client) CASProperty(handle string, name string, oldValue string, newValue string) error {
+	return client.connection.CASProperty(handle, name, oldValue, newValue)
+}
+
+func (client *client) SetProperties(handle string, properties garden.Properties) error {
+	return client.connection.SetProperties(handle, properties)
+}
+
+func (client *client) RemoveProperties(handle string, names []string) error {
+	return client.connection.RemoveProperties(handle, names)
+}
+
+func (client *client) AttachByName(handle string, name string, io garden.ProcessIO) (garden.Process, error) {
+	return client.connection.AttachByName(handle, name, io)
+}
+
+func (client *client) SignalByName(handle string, name string, signal garden.Signal) error {
+	return client.connection.SignalByName(handle, name, signal)
+}
+
+func (client *client) ProcessExitByName(handle string, name string) (garden.ProcessExit, error) {
+	return client.connection.ProcessExitByName(handle, name)
+}
+
+func (client *client) ProcessEnv(handle string, processID string) ([]string, error) {
+	return client.connection.ProcessEnv(handle, processID)
+}
+
+func (client *client) BulkCreate(specs []garden.ContainerSpec) ([]garden.ContainerCreateEntry, error) {
+	return client.connection.BulkCreate(specs)
+}
+
+func (client *client) BulkDestroy(handles []string) (map[string]garden.ContainerDestroyEntry, error) {
+	return client.connection.BulkDestroy(handles)
+}
+
+func (client *client) Pressure() (garden.CellPressure, error) {
+	return client.connection.Pressure()
+}
+
+func (client *client) Drain() (garden.DrainStatus, error) {
+	return client.connection.Drain()
+}
+
+func (client *client) Undrain() (garden.DrainStatus, error) {
+	return client.connection.Undrain()
+}
+
+func (client *client) DrainStatus() (garden.DrainStatus, error) {
+	return client.connection.DrainStatus()
+}
+
+func (client *client) Reconciliation() (garden.Reconciliation, error) {
+	return client.connection.Reconciliation()
+}
+
+func (client *client) BulkStop(handles []string, kill bool) (map[string]garden.ContainerStopEntry, error) {
+	return client.connection.BulkStop(handles, kill)
+}
+
+func (client *client) Events(ctx context.Context) (<-chan garden.Event, error) {
+	return client.connection.Events(ctx)
+}
+
+func (client *client) EventsSince(ctx context.Context, seq uint64) (<-chan garden.Event, error) {
+	return client.connection.EventsSince(ctx, seq)
+}
+
+func (client *client) StreamMetrics(ctx context.Context, handles []string, interval time.Duration) (<-chan garden.MetricsSnapshot, error) {
+	return client.connection.StreamMetrics(ctx, handles, interval)
+}
+
+func (client *client) BulkInfoWithOptions(handles []string, opts garden.BulkOptions) (map[string]garden.ContainerInfoEntry, error) {
+	return client.connection.BulkInfoWithOptions(handles, opts)
+}
+
+func (client *client) BulkMetricsWithOptions(handles []string, opts garden.BulkOptions) (map[string]garden.ContainerMetricsEntry, error) {
+	return client.connection.BulkMetricsWithOptions(handles, opts)
+}
+
+func (client *client) BulkMetricsDelta(handles []string, opts garden.BulkOptions, since string) (garden.BulkMetricsDelta, error) {
+	return client.connection.BulkMetricsDelta(handles, opts, since)
+}
+
+func (client *client) Warm(imageURI string, credentials *garden.RegistryCredentials) (garden.CachedImage, error) {
+	return client.connection.Warm(imageURI, credentials)
+}
+
+func (client *client) ListImages() ([]garden.CachedImage, error) {
+	return client.connection.ListImages()
+}
+
+func (client *client) RemoveImage(imageURI string) error {
+	return client.connection.RemoveImage(imageURI)
+}
+
 func (client *client) Lookup(handle string) (garden.Container, error) {
 	handles, err := client.connection.List(nil)
 	if err != nil {
@@ -97,6 +97,95 @@ var _ = Describe("Client", func() {
 		})
 	})
 
+	Describe("BulkDestroy", func() {
+		handles := []string{"handle1", "handle2"}
+
+		It("destroys the requested containers", func() {
+			expectedEntries := map[string]garden.ContainerDestroyEntry{
+				"handle1": {},
+				"handle2": {Err: garden.NewError("o no")},
+			}
+			fakeConnection.BulkDestroyReturns(expectedEntries, nil)
+
+			entries, err := client.BulkDestroy(handles)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(fakeConnection.BulkDestroyArgsForCall(0)).Should(Equal(handles))
+			Ω(entries).Should(Equal(expectedEntries))
+		})
+
+		Context("when there is a error with the connection", func() {
+			BeforeEach(func() {
+				fakeConnection.BulkDestroyReturns(nil, errors.New("Oh noes!"))
+			})
+
+			It("returns the error", func() {
+				_, err := client.BulkDestroy(handles)
+				Ω(err).Should(MatchError("Oh noes!"))
+			})
+		})
+	})
+
+	Describe("BulkCreate", func() {
+		specs := []garden.ContainerSpec{{Handle: "handle1"}, {Handle: "handle2"}}
+
+		It("creates the requested containers", func() {
+			expectedEntries := []garden.ContainerCreateEntry{
+				{Handle: "handle1"},
+				{Err: garden.NewError("o no")},
+			}
+			fakeConnection.BulkCreateReturns(expectedEntries, nil)
+
+			entries, err := client.BulkCreate(specs)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(fakeConnection.BulkCreateArgsForCall(0)).Should(Equal(specs))
+			Ω(entries).Should(Equal(expectedEntries))
+		})
+
+		Context("when there is a error with the connection", func() {
+			BeforeEach(func() {
+				fakeConnection.BulkCreateReturns(nil, errors.New("Oh noes!"))
+			})
+
+			It("returns the error", func() {
+				_, err := client.BulkCreate(specs)
+				Ω(err).Should(MatchError("Oh noes!"))
+			})
+		})
+	})
+
+	Describe("BulkStop", func() {
+		handles := []string{"handle1", "handle2"}
+
+		It("stops the requested containers", func() {
+			expectedEntries := map[string]garden.ContainerStopEntry{
+				"handle1": {},
+				"handle2": {Err: garden.NewError("o no")},
+			}
+			fakeConnection.BulkStopReturns(expectedEntries, nil)
+
+			entries, err := client.BulkStop(handles, true)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			requestedHandles, kill := fakeConnection.BulkStopArgsForCall(0)
+			Ω(requestedHandles).Should(Equal(handles))
+			Ω(kill).Should(BeTrue())
+			Ω(entries).Should(Equal(expectedEntries))
+		})
+
+		Context("when there is a error with the connection", func() {
+			BeforeEach(func() {
+				fakeConnection.BulkStopReturns(nil, errors.New("Oh noes!"))
+			})
+
+			It("returns the error", func() {
+				_, err := client.BulkStop(handles, false)
+				Ω(err).Should(MatchError("Oh noes!"))
+			})
+		})
+	})
+
 	Describe("BulkMetrics", func() {
 		expectedBulkMetrics := map[string]garden.ContainerMetricsEntry{
 			"handle1": garden.ContainerMetricsEntry{
@@ -147,6 +236,38 @@ var _ = Describe("Client", func() {
 		})
 	})
 
+	Describe("Restore", func() {
+		It("sends a restore request and returns a container", func() {
+			spec := garden.ContainerSpec{
+				RootFSPath: "/some/roofs",
+			}
+
+			fakeConnection.RestoreReturns("some-handle", nil)
+
+			container, err := client.Restore(spec, "/path/to/checkpoint")
+			Ω(err).ShouldNot(HaveOccurred())
+
+			restoredSpec, source := fakeConnection.RestoreArgsForCall(0)
+			Ω(restoredSpec).Should(Equal(spec))
+			Ω(source).Should(Equal("/path/to/checkpoint"))
+
+			Ω(container.Handle()).Should(Equal("some-handle"))
+		})
+
+		Context("when there is a connection error", func() {
+			disaster := errors.New("oh no!")
+
+			BeforeEach(func() {
+				fakeConnection.RestoreReturns("", disaster)
+			})
+
+			It("returns it", func() {
+				_, err := client.Restore(garden.ContainerSpec{}, "/path/to/checkpoint")
+				Ω(err).Should(Equal(disaster))
+			})
+		})
+	})
+
 	Describe("Create", func() {
 		It("sends a create request and returns a container", func() {
 			spec := garden.ContainerSpec{
@@ -230,6 +351,109 @@ var _ = Describe("Client", func() {
 		})
 	})
 
+	Describe("EnsureContainer", func() {
+		spec := garden.ContainerSpec{Handle: "some-handle"}
+
+		Context("when the container exists", func() {
+			BeforeEach(func() {
+				fakeConnection.ListReturns([]string{"some-handle"}, nil)
+			})
+
+			It("returns it without creating it", func() {
+				container, err := client.EnsureContainer(spec)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				Ω(container.Handle()).Should(Equal("some-handle"))
+				Ω(fakeConnection.CreateCallCount()).Should(Equal(0))
+			})
+		})
+
+		Context("when the container does not exist", func() {
+			BeforeEach(func() {
+				fakeConnection.ListReturns([]string{}, nil)
+				fakeConnection.CreateReturns("some-handle", nil)
+			})
+
+			It("creates it from the spec", func() {
+				container, err := client.EnsureContainer(spec)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				Ω(container.Handle()).Should(Equal("some-handle"))
+				Ω(fakeConnection.CreateArgsForCall(0)).Should(Equal(spec))
+			})
+
+			Context("and another client creates it first", func() {
+				BeforeEach(func() {
+					fakeConnection.ListStub = func(garden.Properties) ([]string, error) {
+						if fakeConnection.ListCallCount() == 1 {
+							return []string{}, nil
+						}
+
+						return []string{"some-handle"}, nil
+					}
+					fakeConnection.CreateReturns("", garden.HandleTakenError{Handle: "some-handle"})
+				})
+
+				It("returns the container created by the other client", func() {
+					container, err := client.EnsureContainer(spec)
+					Ω(err).ShouldNot(HaveOccurred())
+
+					Ω(container.Handle()).Should(Equal("some-handle"))
+					Ω(fakeConnection.CreateCallCount()).Should(Equal(1))
+				})
+			})
+
+			Context("and the handle stays taken but the container is never found", func() {
+				BeforeEach(func() {
+					fakeConnection.CreateReturns("", garden.HandleTakenError{Handle: "some-handle"})
+				})
+
+				It("gives up after a bounded number of attempts", func() {
+					_, err := client.EnsureContainer(spec)
+					Ω(err).Should(Equal(garden.HandleTakenError{Handle: "some-handle"}))
+
+					Ω(fakeConnection.CreateCallCount()).Should(Equal(3))
+				})
+			})
+
+			Context("and creating it fails", func() {
+				BeforeEach(func() {
+					fakeConnection.CreateReturns("", errors.New("oh no!"))
+				})
+
+				It("returns the error", func() {
+					_, err := client.EnsureContainer(spec)
+					Ω(err).Should(MatchError("oh no!"))
+					Ω(fakeConnection.CreateCallCount()).Should(Equal(1))
+				})
+			})
+		})
+
+		Context("when looking up the container fails", func() {
+			BeforeEach(func() {
+				fakeConnection.ListReturns(nil, errors.New("oh no!"))
+			})
+
+			It("returns the error without creating it", func() {
+				_, err := client.EnsureContainer(spec)
+				Ω(err).Should(MatchError("oh no!"))
+				Ω(fakeConnection.CreateCallCount()).Should(Equal(0))
+			})
+		})
+
+		Context("when the spec has no handle", func() {
+			It("creates a container", func() {
+				fakeConnection.CreateReturns("generated-handle", nil)
+
+				container, err := client.EnsureContainer(garden.ContainerSpec{})
+				Ω(err).ShouldNot(HaveOccurred())
+
+				Ω(container.Handle()).Should(Equal("generated-handle"))
+				Ω(fakeConnection.ListCallCount()).Should(Equal(0))
+			})
+		})
+	})
+
 	Describe("Lookup", func() {
 		It("sends a list request", func() {
 			fakeConnection.ListReturns([]string{"some-handle", "some-other-handle"}, nil)
@@ -3,12 +3,15 @@ package connection
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net"
+	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -21,6 +24,7 @@ import (
 
 var ErrDisconnected = errors.New("disconnected")
 var ErrInvalidMessage = errors.New("invalid message payload")
+var ErrStreamStalled = errors.New("connection: process stream stalled")
 
 //go:generate counterfeiter . Connection
 type Connection interface {
@@ -30,40 +34,110 @@ type Connection interface {
 
 	Create(spec garden.ContainerSpec) (string, error)
 	List(properties garden.Properties) ([]string, error)
+	ListWithOptions(properties garden.Properties, opts garden.ListOptions) ([]string, error)
 
 	// Destroys the container with the given handle. If the container cannot be
 	// found, garden.ContainerNotFoundError is returned. If deletion fails for another
 	// reason, another error type is returned.
 	Destroy(handle string) error
+	DestroyDryRun(handle string) (garden.DestroyImpact, error)
 
 	Stop(handle string, kill bool) error
+	StopWithTimeout(handle string, timeout time.Duration) error
+	Pause(handle string) error
+	Resume(handle string) error
+	Checkpoint(handle string, destination string) error
+	Commit(handle string, imageName string) (garden.CachedImage, error)
+	Restore(spec garden.ContainerSpec, source string) (string, error)
 
 	Info(handle string) (garden.ContainerInfo, error)
 	BulkInfo(handles []string) (map[string]garden.ContainerInfoEntry, error)
+	BulkCreate(specs []garden.ContainerSpec) ([]garden.ContainerCreateEntry, error)
+	BulkDestroy(handles []string) (map[string]garden.ContainerDestroyEntry, error)
+	BulkStop(handles []string, kill bool) (map[string]garden.ContainerStopEntry, error)
 	BulkMetrics(handles []string) (map[string]garden.ContainerMetricsEntry, error)
+	BulkInfoWithOptions(handles []string, opts garden.BulkOptions) (map[string]garden.ContainerInfoEntry, error)
+	BulkMetricsWithOptions(handles []string, opts garden.BulkOptions) (map[string]garden.ContainerMetricsEntry, error)
+	BulkMetricsDelta(handles []string, opts garden.BulkOptions, since string) (garden.BulkMetricsDelta, error)
 
 	StreamIn(handle string, spec garden.StreamInSpec) error
 	StreamOut(handle string, spec garden.StreamOutSpec) (io.ReadCloser, error)
 
 	CurrentBandwidthLimits(handle string) (garden.BandwidthLimits, error)
+	LimitBandwidth(handle string, limits garden.BandwidthLimits) error
 	CurrentCPULimits(handle string) (garden.CPULimits, error)
+	LimitCPU(handle string, limits garden.CPULimits) error
 	CurrentDiskLimits(handle string) (garden.DiskLimits, error)
 	CurrentMemoryLimits(handle string) (garden.MemoryLimits, error)
+	LimitMemory(handle string, limits garden.MemoryLimits) error
+	CurrentPidLimits(handle string) (garden.PidLimits, error)
+	LimitPids(handle string, limits garden.PidLimits) error
+	CurrentBlockIOLimits(handle string) (garden.BlockIOLimits, error)
+	LimitBlockIO(handle string, limits garden.BlockIOLimits) error
+	CurrentLimits(handle string) (garden.Limits, error)
+	LimitAll(handle string, limits garden.Limits) error
 
 	Run(handle string, spec garden.ProcessSpec, io garden.ProcessIO) (garden.Process, error)
+	RunDetached(handle string, spec garden.ProcessSpec) (string, error)
 	Attach(handle string, processID string, io garden.ProcessIO) (garden.Process, error)
+	AttachByName(handle string, name string, io garden.ProcessIO) (garden.Process, error)
+	SetTTY(handle string, processID string, spec garden.TTYSpec) error
+	Signal(handle string, processID string, signal garden.Signal) error
+	SignalByName(handle string, name string, signal garden.Signal) error
 
 	NetIn(handle string, hostPort, containerPort uint32) (uint32, uint32, error)
+	NetInWithHostIP(handle string, hostIP string, hostPort, containerPort uint32) (garden.PortMapping, error)
+	NetInMappings(handle string) ([]garden.PortMapping, error)
+	RemoveNetIn(handle string, hostPort uint32) error
 	NetOut(handle string, rule garden.NetOutRule) error
+	BulkNetOut(handle string, rules []garden.NetOutRule) error
+	NetOutRules(handle string) ([]garden.NetOutRule, error)
+	RemoveNetOut(handle string, ruleID string) error
+	RemoveAllNetOut(handle string) error
+	PortAllocations() (garden.PortAllocations, error)
+	Reconciliation() (garden.Reconciliation, error)
+	Pressure() (garden.CellPressure, error)
+	Drain() (garden.DrainStatus, error)
+	Undrain() (garden.DrainStatus, error)
+	DrainStatus() (garden.DrainStatus, error)
+	ProcessExit(handle string, processID string) (garden.ProcessExit, error)
+	ProcessExitByName(handle string, name string) (garden.ProcessExit, error)
+	ProcessEnv(handle string, processID string) ([]string, error)
 
 	SetGraceTime(handle string, graceTime time.Duration) error
+	SetDescription(handle string, description string) error
+	Expirations() ([]garden.Expiration, error)
+	HandleScheme() (garden.HandleScheme, error)
+
+	// Events streams container lifecycle events from the server until ctx is
+	// done or the stream ends, at which point the channel is closed. The
+	// server ends the stream if the consumer falls too far behind.
+	Events(ctx context.Context) (<-chan garden.Event, error)
+	EventsSince(ctx context.Context, seq uint64) (<-chan garden.Event, error)
+
+	// StreamMetrics streams snapshots of the metrics of the containers with
+	// the given handles, or of all containers if none are given, at the given
+	// interval until ctx is done or the stream ends, at which point the
+	// channel is closed.
+	StreamMetrics(ctx context.Context, handles []string, interval time.Duration) (<-chan garden.MetricsSnapshot, error)
 
 	Properties(handle string) (garden.Properties, error)
 	Property(handle string, name string) (string, error)
 	SetProperty(handle string, name string, value string) error
 
 	Metrics(handle string) (garden.Metrics, error)
+	DiskUsage(handle string, paths []string) ([]garden.PathDiskUsage, error)
+	HostResources(handle string) (garden.HostResources, error)
+	Processes(handle string) ([]garden.ProcessInfo, error)
+	ProcessMetrics(handle string) (map[string]garden.ProcessMetrics, error)
 	RemoveProperty(handle string, name string) error
+	CASProperty(handle string, name string, oldValue string, newValue string) error
+	SetProperties(handle string, properties garden.Properties) error
+	RemoveProperties(handle string, names []string) error
+
+	Warm(imageURI string, credentials *garden.RegistryCredentials) (garden.CachedImage, error)
+	ListImages() ([]garden.CachedImage, error)
+	RemoveImage(imageURI string) error
 }
 
 //go:generate counterfeiter . HijackStreamer
@@ -75,36 +149,86 @@ type HijackStreamer interface {
 type connection struct {
 	hijacker HijackStreamer
 	log      lager.Logger
+
+	// payloadContentType is the content type, other than JSON, in which to
+	// ask servers to encode process payloads.
+	payloadContentType string
+
+	// redactor redacts the values of sensitive environment variables before
+	// they are logged.
+	redactor garden.Redactor
 }
 
+// Error is returned for failed responses which do not carry one of the typed
+// garden errors.
 type Error struct {
 	StatusCode int
 	Message    string
+
+	// Header holds the headers of the failed response.
+	Header http.Header
+
+	// RetryAfter is parsed from the response's Retry-After header, and is zero
+	// if the server did not suggest when to retry.
+	RetryAfter time.Duration
 }
 
 func (err Error) Error() string {
 	return err.Message
 }
 
-func New(network, address string) Connection {
-	return NewWithLogger(network, address, lager.NewLogger("garden-connection"))
+// Option configures a connection.
+type Option func(*connection)
+
+// WithPayloadContentType asks the server to encode the payloads exchanged with
+// running and attached processes in the given content type, e.g.
+// transport.BinaryPayloadContentType, rather than as JSON. Servers which do
+// not support the content type use JSON.
+func WithPayloadContentType(contentType string) Option {
+	return func(c *connection) {
+		if contentType == transport.JSONPayloadContentType {
+			contentType = ""
+		}
+
+		c.payloadContentType = contentType
+	}
+}
+
+// WithRedactor redacts the values of environment variables whose names match
+// the redactor's EnvPatterns before they are logged, rather than those
+// matching garden.DefaultRedactedEnvPatterns.
+func WithRedactor(redactor garden.Redactor) Option {
+	return func(c *connection) {
+		c.redactor = redactor
+	}
 }
 
-func NewWithLogger(network, address string, logger lager.Logger) Connection {
+func New(network, address string, options ...Option) Connection {
+	return NewWithLogger(network, address, lager.NewLogger("garden-connection"), options...)
+}
+
+func NewWithLogger(network, address string, logger lager.Logger, options ...Option) Connection {
 	hijacker := NewHijackStreamer(network, address)
-	return NewWithHijacker(hijacker, logger)
+	return NewWithHijacker(hijacker, logger, options...)
 }
 
-func NewWithDialerAndLogger(dialer DialerFunc, log lager.Logger) Connection {
+func NewWithDialerAndLogger(dialer DialerFunc, log lager.Logger, options ...Option) Connection {
 	hijacker := NewHijackStreamerWithDialer(dialer)
-	return NewWithHijacker(hijacker, log)
+	return NewWithHijacker(hijacker, log, options...)
 }
 
-func NewWithHijacker(hijacker HijackStreamer, log lager.Logger) Connection {
-	return &connection{
+func NewWithHijacker(hijacker HijackStreamer, log lager.Logger, options ...Option) Connection {
+	conn := &connection{
 		hijacker: hijacker,
 		log:      log,
+		redactor: garden.DefaultRedactor(),
 	}
+
+	for _, option := range options {
+		option(conn)
+	}
+
+	return conn
 }
 
 func (c *connection) Ping() error {
@@ -126,6 +250,11 @@ func (c *connection) Create(spec garden.ContainerSpec) (string, error) {
 		Handle string `json:"handle"`
 	}{}
 
+	c.log.Debug("creating", lager.Data{
+		"handle": spec.Handle,
+		"env":    c.redactor.Env(spec.Env),
+	})
+
 	err := c.do(routes.Create, spec, &res, nil, nil)
 	if err != nil {
 		return "", err
@@ -148,6 +277,64 @@ func (c *connection) Stop(handle string, kill bool) error {
 	)
 }
 
+func (c *connection) StopWithTimeout(handle string, timeout time.Duration) error {
+	return c.do(
+		routes.Stop,
+		map[string]interface{}{
+			"kill":    false,
+			"timeout": timeout,
+		},
+		&struct{}{},
+		rata.Params{
+			"handle": handle,
+		},
+		nil,
+	)
+}
+
+func (c *connection) Pause(handle string) error {
+	return c.do(routes.Pause, nil, &struct{}{}, rata.Params{"handle": handle}, nil)
+}
+
+func (c *connection) Resume(handle string) error {
+	return c.do(routes.Resume, nil, &struct{}{}, rata.Params{"handle": handle}, nil)
+}
+
+func (c *connection) Checkpoint(handle string, destination string) error {
+	return c.do(
+		routes.Checkpoint,
+		transport.CheckpointRequest{Destination: destination},
+		&struct{}{},
+		rata.Params{"handle": handle},
+		nil,
+	)
+}
+
+func (c *connection) RunDetached(handle string, spec garden.ProcessSpec) (string, error) {
+	res := transport.RunDetachedResponse{}
+	err := c.do(routes.RunDetached, spec, &res, rata.Params{"handle": handle}, nil)
+	return res.ProcessID, err
+}
+
+func (c *connection) Commit(handle string, imageName string) (garden.CachedImage, error) {
+	res := garden.CachedImage{}
+	err := c.do(routes.Commit, transport.CommitRequest{ImageName: imageName}, &res, rata.Params{"handle": handle}, nil)
+	return res, err
+}
+
+func (c *connection) Restore(spec garden.ContainerSpec, source string) (string, error) {
+	res := struct {
+		Handle string `json:"handle"`
+	}{}
+
+	err := c.do(routes.Restore, transport.RestoreRequest{Spec: spec, Source: source}, &res, nil, nil)
+	if err != nil {
+		return "", err
+	}
+
+	return res.Handle, nil
+}
+
 func (c *connection) Destroy(handle string) error {
 	return c.do(
 		routes.Destroy,
@@ -160,7 +347,28 @@ func (c *connection) Destroy(handle string) error {
 	)
 }
 
+func (c *connection) DestroyDryRun(handle string) (garden.DestroyImpact, error) {
+	res := garden.DestroyImpact{}
+	err := c.do(
+		routes.Destroy,
+		nil,
+		&res,
+		rata.Params{
+			"handle": handle,
+		},
+		url.Values{"dry_run": []string{"true"}},
+	)
+
+	return res, err
+}
+
 func (c *connection) Run(handle string, spec garden.ProcessSpec, processIO garden.ProcessIO) (garden.Process, error) {
+	c.log.Debug("running", lager.Data{
+		"handle": handle,
+		"path":   spec.Path,
+		"env":    c.redactor.Env(spec.Env),
+	})
+
 	reqBody := new(bytes.Buffer)
 
 	err := transport.WriteMessage(reqBody, spec)
@@ -174,10 +382,14 @@ func (c *connection) Run(handle string, spec garden.ProcessSpec, processIO garde
 		rata.Params{
 			"handle": handle,
 		},
-		nil,
+		c.processIOQuery(processIO),
 		"application/json",
 	)
 	if err != nil {
+		if _, tooLarge := err.(garden.PayloadTooLargeError); tooLarge {
+			return nil, err
+		}
+
 		return nil, fmt.Errorf("hijack: %s", err)
 	}
 
@@ -185,6 +397,23 @@ func (c *connection) Run(handle string, spec garden.ProcessSpec, processIO garde
 }
 
 func (c *connection) Attach(handle string, processID string, processIO garden.ProcessIO) (garden.Process, error) {
+	return c.attach(handle, processID, processIO, c.processIOQuery(processIO))
+}
+
+// AttachByName attaches to the running process with the name given in its
+// ProcessSpec.
+func (c *connection) AttachByName(handle string, name string, processIO garden.ProcessIO) (garden.Process, error) {
+	query := c.processIOQuery(processIO)
+	if query == nil {
+		query = url.Values{}
+	}
+
+	query.Set(routes.ProcessByNameParam, "true")
+
+	return c.attach(handle, name, processIO, query)
+}
+
+func (c *connection) attach(handle string, processID string, processIO garden.ProcessIO, query url.Values) (garden.Process, error) {
 	reqBody := new(bytes.Buffer)
 
 	hijackedConn, hijackedResponseReader, err := c.hijacker.Hijack(
@@ -194,7 +423,7 @@ func (c *connection) Attach(handle string, processID string, processIO garden.Pr
 			"handle": handle,
 			"pid":    processID,
 		},
-		nil,
+		query,
 		"",
 	)
 	if err != nil {
@@ -205,6 +434,8 @@ func (c *connection) Attach(handle string, processID string, processIO garden.Pr
 }
 
 func (c *connection) streamProcess(handle string, processIO garden.ProcessIO, hijackedConn net.Conn, hijackedResponseReader *bufio.Reader) (garden.Process, error) {
+	// the first payload is always JSON, and names the content type of the
+	// payloads after it if they are not
 	decoder := json.NewDecoder(hijackedResponseReader)
 
 	payload := &transport.ProcessPayload{}
@@ -212,9 +443,25 @@ func (c *connection) streamProcess(handle string, processIO garden.ProcessIO, hi
 		return nil, err
 	}
 
+	if payload.ContentType != "" && payload.ContentType != c.payloadContentType {
+		hijackedConn.Close()
+		return nil, fmt.Errorf("connection: server encodes process payloads as %q, which was not asked for", payload.ContentType)
+	}
+
+	rest := bufio.NewReader(io.MultiReader(decoder.Buffered(), hijackedResponseReader))
+	if payload.ContentType != "" {
+		// skip the newline terminating the first payload
+		if b, err := rest.ReadByte(); err != nil || b != '\n' {
+			hijackedConn.Close()
+			return nil, fmt.Errorf("connection: first process payload is not terminated by a newline")
+		}
+	}
+
+	payloads := transport.NewPayloadDecoder(rest, payload.ContentType, false)
+
 	processPipeline := &processStream{
 		processID: payload.ProcessID,
-		conn:      hijackedConn,
+		encoder:   transport.NewPayloadEncoder(hijackedConn, payload.ContentType),
 	}
 
 	hijack := func(streamType string) (net.Conn, io.Reader, error) {
@@ -254,7 +501,7 @@ func (c *connection) streamProcess(handle string, processIO garden.ProcessIO, hi
 	}
 
 	var stderrConn net.Conn
-	if processIO.Stderr != nil {
+	if processIO.Stderr != nil && !processIO.Interleave {
 		var (
 			stderr io.Reader
 			err    error
@@ -278,13 +525,30 @@ func (c *connection) streamProcess(handle string, processIO garden.ProcessIO, hi
 			defer stderrConn.Close()
 		}
 
-		exitCode, err := streamHandler.wait(decoder)
+		exitCode, err := streamHandler.wait(payloads, hijackedConn, processPipeline)
 		process.exited(exitCode, err)
 	}()
 
 	return process, nil
 }
 
+func (c *connection) processIOQuery(processIO garden.ProcessIO) url.Values {
+	if !processIO.Interleave && c.payloadContentType == "" {
+		return nil
+	}
+
+	query := url.Values{}
+	if processIO.Interleave {
+		query.Set("interleave", "true")
+	}
+
+	if c.payloadContentType != "" {
+		query.Set(routes.PayloadContentTypeParam, c.payloadContentType)
+	}
+
+	return query
+}
+
 func (c *connection) NetIn(handle string, hostPort, containerPort uint32) (uint32, uint32, error) {
 	res := &transport.NetInResponse{}
 
@@ -309,6 +573,35 @@ func (c *connection) NetIn(handle string, hostPort, containerPort uint32) (uint3
 	return res.HostPort, res.ContainerPort, nil
 }
 
+func (c *connection) NetInWithHostIP(handle string, hostIP string, hostPort, containerPort uint32) (garden.PortMapping, error) {
+	res := &transport.NetInResponse{}
+
+	err := c.do(
+		routes.NetIn,
+		&transport.NetInRequest{
+			Handle:        handle,
+			HostPort:      hostPort,
+			ContainerPort: containerPort,
+			HostIP:        hostIP,
+		},
+		res,
+		rata.Params{
+			"handle": handle,
+		},
+		nil,
+	)
+
+	if err != nil {
+		return garden.PortMapping{}, err
+	}
+
+	return garden.PortMapping{
+		HostPort:      res.HostPort,
+		ContainerPort: res.ContainerPort,
+		HostIP:        res.HostIP,
+	}, nil
+}
+
 func (c *connection) NetOut(handle string, rule garden.NetOutRule) error {
 	return c.do(
 		routes.NetOut,
@@ -321,6 +614,115 @@ func (c *connection) NetOut(handle string, rule garden.NetOutRule) error {
 	)
 }
 
+func (c *connection) ProcessEnv(handle string, processID string) ([]string, error) {
+	res := []string{}
+	err := c.do(routes.ProcessEnv, nil, &res, rata.Params{"handle": handle, "pid": processID}, nil)
+	return res, err
+}
+
+func (c *connection) BulkNetOut(handle string, rules []garden.NetOutRule) error {
+	return c.do(
+		routes.BulkNetOut,
+		transport.BulkNetOutRequest{Rules: rules},
+		&struct{}{},
+		rata.Params{
+			"handle": handle,
+		},
+		nil,
+	)
+}
+
+func (c *connection) NetInMappings(handle string) ([]garden.PortMapping, error) {
+	res := []garden.PortMapping{}
+	err := c.do(routes.ListNetIn, nil, &res, rata.Params{"handle": handle}, nil)
+	return res, err
+}
+
+func (c *connection) RemoveNetIn(handle string, hostPort uint32) error {
+	return c.do(routes.RemoveNetIn, nil, &struct{}{}, rata.Params{"handle": handle, "host_port": strconv.FormatUint(uint64(hostPort), 10)}, nil)
+}
+
+func (c *connection) NetOutRules(handle string) ([]garden.NetOutRule, error) {
+	res := []garden.NetOutRule{}
+	err := c.do(routes.ListNetOut, nil, &res, rata.Params{"handle": handle}, nil)
+	return res, err
+}
+
+func (c *connection) RemoveNetOut(handle string, ruleID string) error {
+	return c.do(routes.RemoveNetOut, nil, &struct{}{}, rata.Params{"handle": handle, "rule_id": ruleID}, nil)
+}
+
+func (c *connection) RemoveAllNetOut(handle string) error {
+	return c.do(routes.RemoveAllNetOut, nil, &struct{}{}, rata.Params{"handle": handle}, nil)
+}
+
+func (c *connection) ProcessExit(handle string, processID string) (garden.ProcessExit, error) {
+	res := garden.ProcessExit{}
+	err := c.do(routes.ProcessExit, nil, &res, rata.Params{"handle": handle, "pid": processID}, nil)
+	return res, err
+}
+
+func (c *connection) ProcessExitByName(handle string, name string) (garden.ProcessExit, error) {
+	res := garden.ProcessExit{}
+	err := c.do(routes.ProcessExit, nil, &res, rata.Params{"handle": handle, "pid": name}, byNameQuery())
+	return res, err
+}
+
+func (c *connection) Pressure() (garden.CellPressure, error) {
+	res := garden.CellPressure{}
+	err := c.do(routes.Pressure, nil, &res, nil, nil)
+	return res, err
+}
+
+func (c *connection) Drain() (garden.DrainStatus, error) {
+	res := garden.DrainStatus{}
+	err := c.do(routes.Drain, nil, &res, nil, nil)
+	return res, err
+}
+
+func (c *connection) Undrain() (garden.DrainStatus, error) {
+	res := garden.DrainStatus{}
+	err := c.do(routes.Undrain, nil, &res, nil, nil)
+	return res, err
+}
+
+func (c *connection) DrainStatus() (garden.DrainStatus, error) {
+	res := garden.DrainStatus{}
+	err := c.do(routes.DrainStatus, nil, &res, nil, nil)
+	return res, err
+}
+
+func (c *connection) Reconciliation() (garden.Reconciliation, error) {
+	res := garden.Reconciliation{}
+	err := c.do(routes.Reconciliation, nil, &res, nil, nil)
+	return res, err
+}
+
+func (c *connection) Warm(imageURI string, credentials *garden.RegistryCredentials) (garden.CachedImage, error) {
+	res := garden.CachedImage{}
+	err := c.do(routes.WarmImage, transport.WarmImageRequest{URI: imageURI, RegistryCredentials: credentials}, &res, nil, nil)
+	return res, err
+}
+
+func (c *connection) ListImages() ([]garden.CachedImage, error) {
+	res := []garden.CachedImage{}
+	err := c.do(routes.ListImages, nil, &res, nil, nil)
+	return res, err
+}
+
+func (c *connection) RemoveImage(imageURI string) error {
+	return c.do(routes.RemoveImage, nil, &struct{}{}, nil, url.Values{routes.ImageURIParam: []string{imageURI}})
+}
+
+func (c *connection) PortAllocations() (garden.PortAllocations, error) {
+	res := garden.PortAllocations{}
+	if err := c.do(routes.PortAllocations, nil, &res, nil, nil); err != nil {
+		return garden.PortAllocations{}, err
+	}
+
+	return res, nil
+}
+
 func (c *connection) Property(handle string, name string) (string, error) {
 	var res struct {
 		Value string `json:"value"`
@@ -361,6 +763,30 @@ func (c *connection) SetProperty(handle string, name string, value string) error
 	return nil
 }
 
+func (c *connection) CASProperty(handle string, name string, oldValue string, newValue string) error {
+	return c.do(
+		routes.CASProperty,
+		&transport.CASPropertyRequest{
+			OldValue: oldValue,
+			Value:    newValue,
+		},
+		&struct{}{},
+		rata.Params{
+			"handle": handle,
+			"key":    name,
+		},
+		nil,
+	)
+}
+
+func (c *connection) SetProperties(handle string, properties garden.Properties) error {
+	return c.do(routes.SetProperties, properties, &struct{}{}, rata.Params{"handle": handle}, nil)
+}
+
+func (c *connection) RemoveProperties(handle string, names []string) error {
+	return c.do(routes.RemoveProperties, nil, &struct{}{}, rata.Params{"handle": handle}, url.Values{routes.PropertyKeyParam: names})
+}
+
 func (c *connection) RemoveProperty(handle string, name string) error {
 	err := c.do(
 		routes.RemoveProperty,
@@ -444,6 +870,126 @@ func (c *connection) CurrentMemoryLimits(handle string) (garden.MemoryLimits, er
 	return res, err
 }
 
+func (c *connection) LimitBandwidth(handle string, limits garden.BandwidthLimits) error {
+	return c.do(
+		routes.LimitBandwidth,
+		limits,
+		&struct{}{},
+		rata.Params{
+			"handle": handle,
+		},
+		nil,
+	)
+}
+
+func (c *connection) LimitCPU(handle string, limits garden.CPULimits) error {
+	return c.do(
+		routes.LimitCPU,
+		limits,
+		&struct{}{},
+		rata.Params{
+			"handle": handle,
+		},
+		nil,
+	)
+}
+
+func (c *connection) LimitMemory(handle string, limits garden.MemoryLimits) error {
+	return c.do(
+		routes.LimitMemory,
+		limits,
+		&struct{}{},
+		rata.Params{
+			"handle": handle,
+		},
+		nil,
+	)
+}
+
+func (c *connection) CurrentPidLimits(handle string) (garden.PidLimits, error) {
+	res := garden.PidLimits{}
+
+	err := c.do(
+		routes.CurrentPidLimits,
+		nil,
+		&res,
+		rata.Params{
+			"handle": handle,
+		},
+		nil,
+	)
+
+	return res, err
+}
+
+func (c *connection) LimitPids(handle string, limits garden.PidLimits) error {
+	return c.do(
+		routes.LimitPids,
+		limits,
+		&struct{}{},
+		rata.Params{
+			"handle": handle,
+		},
+		nil,
+	)
+}
+
+func (c *connection) CurrentBlockIOLimits(handle string) (garden.BlockIOLimits, error) {
+	res := garden.BlockIOLimits{}
+
+	err := c.do(
+		routes.CurrentBlockIOLimits,
+		nil,
+		&res,
+		rata.Params{
+			"handle": handle,
+		},
+		nil,
+	)
+
+	return res, err
+}
+
+func (c *connection) LimitBlockIO(handle string, limits garden.BlockIOLimits) error {
+	return c.do(
+		routes.LimitBlockIO,
+		limits,
+		&struct{}{},
+		rata.Params{
+			"handle": handle,
+		},
+		nil,
+	)
+}
+
+func (c *connection) CurrentLimits(handle string) (garden.Limits, error) {
+	res := garden.Limits{}
+
+	err := c.do(
+		routes.CurrentLimits,
+		nil,
+		&res,
+		rata.Params{
+			"handle": handle,
+		},
+		nil,
+	)
+
+	return res, err
+}
+
+func (c *connection) LimitAll(handle string, limits garden.Limits) error {
+	return c.do(
+		routes.LimitAll,
+		limits,
+		&struct{}{},
+		rata.Params{
+			"handle": handle,
+		},
+		nil,
+	)
+}
+
 func (c *connection) StreamIn(handle string, spec garden.StreamInSpec) error {
 	body, err := c.hijacker.Stream(
 		routes.StreamIn,
@@ -465,26 +1011,68 @@ func (c *connection) StreamIn(handle string, spec garden.StreamInSpec) error {
 }
 
 func (c *connection) StreamOut(handle string, spec garden.StreamOutSpec) (io.ReadCloser, error) {
-	return c.hijacker.Stream(
+	query := url.Values{
+		"user":   []string{spec.User},
+		"source": []string{spec.Path},
+	}
+
+	if spec.ResumeFrom != "" {
+		query.Set("resume_from", spec.ResumeFrom)
+	}
+
+	stream, err := c.hijacker.Stream(
 		routes.StreamOut,
 		nil,
 		rata.Params{
 			"handle": handle,
 		},
-		url.Values{
-			"user":   []string{spec.User},
-			"source": []string{spec.Path},
-		},
+		query,
 		"",
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	return newTruncationDetectingReader(stream, spec.Deadline), nil
 }
 
 func (c *connection) List(filterProperties garden.Properties) ([]string, error) {
+	return c.ListWithOptions(filterProperties, garden.ListOptions{})
+}
+
+func (c *connection) ListWithOptions(filterProperties garden.Properties, opts garden.ListOptions) ([]string, error) {
 	values := url.Values{}
 	for name, val := range filterProperties {
 		values[name] = []string{val}
 	}
 
+	if opts.OrderByCreation {
+		values.Set(routes.ListOrderParam, routes.ListOrderByCreation)
+	}
+
+	if !opts.CreatedBefore.IsZero() {
+		values.Set(routes.ListCreatedBeforeParam, opts.CreatedBefore.Format(time.RFC3339Nano))
+	}
+
+	if !opts.CreatedAfter.IsZero() {
+		values.Set(routes.ListCreatedAfterParam, opts.CreatedAfter.Format(time.RFC3339Nano))
+	}
+
+	for _, filter := range opts.PropertyFilters {
+		operator := string(filter.Operator)
+		if filter.Negate {
+			operator = routes.ListFilterNegation + operator
+		}
+
+		param := routes.ListFilterParamPrefix + operator + "." + filter.Name
+		if filter.Operator == garden.PropertyExists || len(filter.Values) == 0 {
+			values[param] = append(values[param], "")
+			continue
+		}
+
+		values[param] = append(values[param], filter.Values...)
+	}
+
 	res := &struct {
 		Handles []string
 	}{}
@@ -502,10 +1090,174 @@ func (c *connection) List(filterProperties garden.Properties) ([]string, error)
 	return res.Handles, nil
 }
 
+// SetTTY resizes the TTY of a running process without requiring the caller to
+// hold its hijacked stream, e.g. when forwarding SIGWINCH from a separate
+// client.
+func (c *connection) SetTTY(handle string, processID string, spec garden.TTYSpec) error {
+	return c.do(
+		routes.SetProcessTTY,
+		spec,
+		&struct{}{},
+		rata.Params{
+			"handle": handle,
+			"pid":    processID,
+		},
+		nil,
+	)
+}
+
+// Signal delivers a signal to a running process without requiring the caller
+// to hold its hijacked stream.
+func (c *connection) Signal(handle string, processID string, signal garden.Signal) error {
+	return c.signal(handle, processID, signal, nil)
+}
+
+// SignalByName delivers a signal to the running process with the name given
+// in its ProcessSpec.
+func (c *connection) SignalByName(handle string, name string, signal garden.Signal) error {
+	return c.signal(handle, name, signal, byNameQuery())
+}
+
+func (c *connection) signal(handle string, processID string, signal garden.Signal, query url.Values) error {
+	return c.do(
+		routes.SignalProcess,
+		map[string]garden.Signal{
+			"signal": signal,
+		},
+		&struct{}{},
+		rata.Params{
+			"handle": handle,
+			"pid":    processID,
+		},
+		query,
+	)
+}
+
+// byNameQuery is the query with which a process route addresses the process
+// by its name rather than its ID.
+func byNameQuery() url.Values {
+	return url.Values{routes.ProcessByNameParam: []string{"true"}}
+}
+
 func (c *connection) SetGraceTime(handle string, graceTime time.Duration) error {
 	return c.do(routes.SetGraceTime, graceTime, &struct{}{}, rata.Params{"handle": handle}, nil)
 }
 
+func (c *connection) SetDescription(handle string, description string) error {
+	return c.do(routes.SetDescription, description, &struct{}{}, rata.Params{"handle": handle}, nil)
+}
+
+func (c *connection) HandleScheme() (garden.HandleScheme, error) {
+	var scheme garden.HandleScheme
+	if err := c.do(routes.HandleScheme, nil, &scheme, nil, nil); err != nil {
+		return garden.HandleScheme{}, err
+	}
+
+	return scheme, nil
+}
+
+func (c *connection) Expirations() ([]garden.Expiration, error) {
+	res := &struct {
+		Expirations []garden.Expiration `json:"expirations"`
+	}{}
+
+	if err := c.do(routes.Expirations, nil, &res, nil, nil); err != nil {
+		return nil, err
+	}
+
+	return res.Expirations, nil
+}
+
+func (c *connection) Events(ctx context.Context) (<-chan garden.Event, error) {
+	return c.EventsSince(ctx, 0)
+}
+
+func (c *connection) EventsSince(ctx context.Context, seq uint64) (<-chan garden.Event, error) {
+	var query url.Values
+	if seq != 0 {
+		query = url.Values{routes.EventsSinceParam: []string{strconv.FormatUint(seq, 10)}}
+	}
+
+	stream, err := c.hijacker.Stream(routes.Events, nil, nil, query, "")
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan garden.Event)
+
+	go func() {
+		<-ctx.Done()
+		stream.Close()
+	}()
+
+	go func() {
+		defer close(events)
+		defer stream.Close()
+
+		decoder := json.NewDecoder(stream)
+		for {
+			var event garden.Event
+			if err := decoder.Decode(&event); err != nil {
+				if err != io.EOF && ctx.Err() == nil {
+					c.log.Error("decoding-event", err)
+				}
+				return
+			}
+
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+func (c *connection) StreamMetrics(ctx context.Context, handles []string, interval time.Duration) (<-chan garden.MetricsSnapshot, error) {
+	query := url.Values{routes.StreamMetricsIntervalParam: []string{interval.String()}}
+	if len(handles) > 0 {
+		query.Set(routes.StreamMetricsHandlesParam, strings.Join(handles, ","))
+	}
+
+	stream, err := c.hijacker.Stream(routes.StreamMetrics, nil, nil, query, "")
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots := make(chan garden.MetricsSnapshot)
+
+	go func() {
+		<-ctx.Done()
+		stream.Close()
+	}()
+
+	go func() {
+		defer close(snapshots)
+		defer stream.Close()
+
+		decoder := json.NewDecoder(stream)
+		for {
+			var snapshot garden.MetricsSnapshot
+			if err := decoder.Decode(&snapshot); err != nil {
+				if err != io.EOF && ctx.Err() == nil {
+					c.log.Error("decoding-metrics-snapshot", err)
+				}
+				return
+			}
+
+			select {
+			case snapshots <- snapshot:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return snapshots, nil
+}
+
 func (c *connection) Properties(handle string) (garden.Properties, error) {
 	res := make(garden.Properties)
 	err := c.do(routes.Properties, nil, &res, rata.Params{"handle": handle}, nil)
@@ -518,6 +1270,30 @@ func (c *connection) Metrics(handle string) (garden.Metrics, error) {
 	return res, err
 }
 
+func (c *connection) DiskUsage(handle string, paths []string) ([]garden.PathDiskUsage, error) {
+	res := []garden.PathDiskUsage{}
+	err := c.do(routes.DiskUsage, nil, &res, rata.Params{"handle": handle}, url.Values{routes.DiskUsagePathParam: paths})
+	return res, err
+}
+
+func (c *connection) HostResources(handle string) (garden.HostResources, error) {
+	res := garden.HostResources{}
+	err := c.do(routes.HostResources, nil, &res, rata.Params{"handle": handle}, nil)
+	return res, err
+}
+
+func (c *connection) Processes(handle string) ([]garden.ProcessInfo, error) {
+	res := []garden.ProcessInfo{}
+	err := c.do(routes.Processes, nil, &res, rata.Params{"handle": handle}, nil)
+	return res, err
+}
+
+func (c *connection) ProcessMetrics(handle string) (map[string]garden.ProcessMetrics, error) {
+	res := map[string]garden.ProcessMetrics{}
+	err := c.do(routes.ProcessMetrics, nil, &res, rata.Params{"handle": handle}, nil)
+	return res, err
+}
+
 func (c *connection) Info(handle string) (garden.ContainerInfo, error) {
 	res := garden.ContainerInfo{}
 
@@ -530,21 +1306,64 @@ func (c *connection) Info(handle string) (garden.ContainerInfo, error) {
 }
 
 func (c *connection) BulkInfo(handles []string) (map[string]garden.ContainerInfoEntry, error) {
+	return c.BulkInfoWithOptions(handles, garden.BulkOptions{})
+}
+
+func (c *connection) BulkInfoWithOptions(handles []string, opts garden.BulkOptions) (map[string]garden.ContainerInfoEntry, error) {
 	res := make(map[string]garden.ContainerInfoEntry)
-	queryParams := url.Values{
-		"handles": []string{strings.Join(handles, ",")},
-	}
-	err := c.do(routes.BulkInfo, nil, &res, nil, queryParams)
+	err := c.do(routes.BulkInfo, nil, &res, nil, bulkQueryParams(handles, opts))
+	return res, err
+}
+
+func (c *connection) BulkCreate(specs []garden.ContainerSpec) ([]garden.ContainerCreateEntry, error) {
+	res := []garden.ContainerCreateEntry{}
+	err := c.do(routes.BulkCreate, transport.BulkCreateRequest{Specs: specs}, &res, nil, nil)
+	return res, err
+}
+
+func (c *connection) BulkDestroy(handles []string) (map[string]garden.ContainerDestroyEntry, error) {
+	res := make(map[string]garden.ContainerDestroyEntry)
+	err := c.do(routes.BulkDestroy, transport.BulkDestroyRequest{Handles: handles}, &res, nil, nil)
+	return res, err
+}
+
+func (c *connection) BulkStop(handles []string, kill bool) (map[string]garden.ContainerStopEntry, error) {
+	res := make(map[string]garden.ContainerStopEntry)
+	err := c.do(routes.BulkStop, transport.BulkStopRequest{Handles: handles, Kill: kill}, &res, nil, nil)
 	return res, err
 }
 
 func (c *connection) BulkMetrics(handles []string) (map[string]garden.ContainerMetricsEntry, error) {
+	return c.BulkMetricsWithOptions(handles, garden.BulkOptions{})
+}
+
+func (c *connection) BulkMetricsWithOptions(handles []string, opts garden.BulkOptions) (map[string]garden.ContainerMetricsEntry, error) {
 	res := make(map[string]garden.ContainerMetricsEntry)
+	err := c.do(routes.BulkMetrics, nil, &res, nil, bulkQueryParams(handles, opts))
+	return res, err
+}
+
+func (c *connection) BulkMetricsDelta(handles []string, opts garden.BulkOptions, since string) (garden.BulkMetricsDelta, error) {
+	queryParams := bulkQueryParams(handles, opts)
+	if since != "" {
+		queryParams.Set(routes.BulkMetricsDeltaSinceParam, since)
+	}
+
+	var res garden.BulkMetricsDelta
+	err := c.do(routes.BulkMetricsDelta, nil, &res, nil, queryParams)
+	return res, err
+}
+
+func bulkQueryParams(handles []string, opts garden.BulkOptions) url.Values {
 	queryParams := url.Values{
 		"handles": []string{strings.Join(handles, ",")},
 	}
-	err := c.do(routes.BulkMetrics, nil, &res, nil, queryParams)
-	return res, err
+
+	if opts.Snapshot {
+		queryParams.Set("snapshot", "true")
+	}
+
+	return queryParams
 }
 
 func (c *connection) do(
@@ -2,6 +2,7 @@ package connection
 
 import (
 	"bufio"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -10,6 +11,8 @@ import (
 	"net/http"
 	"net/http/httputil"
 	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"code.cloudfoundry.org/garden"
@@ -19,32 +22,125 @@ import (
 
 type DialerFunc func(network, address string) (net.Conn, error)
 
+// defaultHost is used to generate request URLs when no host is configured.
+// Requests are always sent over the dialed connection, so it only affects the
+// Host header and the address handed to the dialer.
+const defaultHost = "http://api"
+
 type hijackable struct {
 	req               *rata.RequestGenerator
 	noKeepaliveClient *http.Client
 	dialer            DialerFunc
+
+	// err is returned for every request if the streamer was constructed with
+	// a host it cannot connect to.
+	err error
 }
 
+// NewHijackStreamer returns a HijackStreamer which dials the address on the
+// network. Failures to dial a unix socket are explained with a
+// *UnixSocketError.
 func NewHijackStreamer(network, address string) HijackStreamer {
+	if network == "unix" {
+		return NewHijackStreamerWithDialer(UnixSocketDialer(address, 0))
+	}
+
 	return NewHijackStreamerWithDialer(func(string, string) (net.Conn, error) {
 		return net.DialTimeout(network, address, 2*time.Second)
 	})
 }
 
 func NewHijackStreamerWithDialer(dialFunc DialerFunc) HijackStreamer {
+	return NewHijackStreamerWithDialerAndHost(dialFunc, defaultHost)
+}
+
+// NewHijackStreamerWithHost is like NewHijackStreamer but generates requests
+// against the given host, e.g. "https://garden.example.com". The scheme
+// defaults to http if omitted.
+func NewHijackStreamerWithHost(network, address, host string) HijackStreamer {
+	return NewHijackStreamerWithDialerAndHost(func(string, string) (net.Conn, error) {
+		return net.DialTimeout(network, address, 2*time.Second)
+	}, host)
+}
+
+// NewHijackStreamerWithDialerAndHost is like NewHijackStreamerWithDialer but
+// generates requests against the given host. The dialer is handed the host's
+// address, as host:port with the port defaulting to the scheme's. For an
+// https host the connection it returns is secured with TLS, verifying the
+// host's name; for an http host it is used as is, so the dialer may secure it
+// itself. Requests fail if the host has any other scheme.
+func NewHijackStreamerWithDialerAndHost(dialFunc DialerFunc, host string) HijackStreamer {
+	if !strings.Contains(host, "://") {
+		host = "http://" + host
+	}
+
+	host = strings.TrimRight(host, "/")
+
+	hostURL, err := url.Parse(host)
+	if err != nil {
+		return &hijackable{err: fmt.Errorf("connection: invalid host %q: %s", host, err)}
+	}
+
+	var port string
+	switch hostURL.Scheme {
+	case "http":
+		port = "80"
+	case "https":
+		port = "443"
+	default:
+		return &hijackable{err: fmt.Errorf("connection: unsupported scheme %q in host %q", hostURL.Scheme, host)}
+	}
+
+	address := hostURL.Host
+	if hostURL.Port() == "" {
+		address = net.JoinHostPort(hostURL.Hostname(), port)
+	}
+
+	dial := func(network, _ string) (net.Conn, error) {
+		return dialFunc(network, address)
+	}
+
+	if hostURL.Scheme == "https" {
+		dial = tlsDialer(dial, hostURL.Hostname())
+	}
+
 	return &hijackable{
-		req:    rata.NewRequestGenerator("http://api", routes.Routes),
-		dialer: dialFunc,
+		req:    rata.NewRequestGenerator(host, routes.Routes),
+		dialer: dial,
 		noKeepaliveClient: &http.Client{
 			Transport: &http.Transport{
-				Dial:              dialFunc,
+				Dial:              dial,
+				DialTLS:           dial,
 				DisableKeepAlives: true,
 			},
 		},
 	}
 }
 
+// tlsDialer returns a dialer which secures the connections the dialer returns
+// with TLS, verifying the server's name.
+func tlsDialer(dial DialerFunc, serverName string) DialerFunc {
+	return func(network, address string) (net.Conn, error) {
+		conn, err := dial(network, address)
+		if err != nil {
+			return nil, err
+		}
+
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: serverName})
+		if err := tlsConn.Handshake(); err != nil {
+			conn.Close()
+			return nil, err
+		}
+
+		return tlsConn, nil
+	}
+}
+
 func (h *hijackable) Hijack(handler string, body io.Reader, params rata.Params, query url.Values, contentType string) (net.Conn, *bufio.Reader, error) {
+	if h.err != nil {
+		return nil, nil, h.err
+	}
+
 	request, err := h.req.CreateRequest(handler, params, body)
 	if err != nil {
 		return nil, nil, err
@@ -58,7 +154,7 @@ func (h *hijackable) Hijack(handler string, body io.Reader, params rata.Params,
 		request.URL.RawQuery = query.Encode()
 	}
 
-	conn, err := h.dialer("tcp", "api") // net/addr don't matter here
+	conn, err := h.dialer("tcp", request.URL.Host)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -75,10 +171,17 @@ func (h *hijackable) Hijack(handler string, body io.Reader, params rata.Params,
 
 		errRespBytes, err := ioutil.ReadAll(httpResp.Body)
 		if err != nil {
-			return nil, nil, fmt.Errorf("Backend error: Exit status: %d, error reading response body: %s", httpResp.StatusCode, err)
+			return nil, nil, newResponseError(httpResp, fmt.Sprintf("Backend error: Exit status: %d, error reading response body: %s", httpResp.StatusCode, err))
+		}
+
+		var result garden.Error
+		if json.Unmarshal(errRespBytes, &result) == nil {
+			if tooLarge, ok := result.Err.(garden.PayloadTooLargeError); ok {
+				return nil, nil, tooLarge
+			}
 		}
 
-		return nil, nil, fmt.Errorf("Backend error: Exit status: %d, message: %s", httpResp.StatusCode, errRespBytes)
+		return nil, nil, newResponseError(httpResp, fmt.Sprintf("Backend error: Exit status: %d, message: %s", httpResp.StatusCode, errRespBytes))
 	}
 
 	hijackedConn, hijackedResponseReader := client.Hijack()
@@ -87,6 +190,10 @@ func (h *hijackable) Hijack(handler string, body io.Reader, params rata.Params,
 }
 
 func (c *hijackable) Stream(handler string, body io.Reader, params rata.Params, query url.Values, contentType string) (io.ReadCloser, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+
 	request, err := c.req.CreateRequest(handler, params, body)
 	if err != nil {
 		return nil, err
@@ -111,11 +218,78 @@ func (c *hijackable) Stream(handler string, body io.Reader, params rata.Params,
 		var result garden.Error
 		err := json.NewDecoder(httpResp.Body).Decode(&result)
 		if err != nil {
-			return nil, fmt.Errorf("bad response: %s", err)
+			return nil, newResponseError(httpResp, fmt.Sprintf("bad response: %s", err))
 		}
 
-		return nil, result.Err
+		return nil, responseError(httpResp, result.Err)
 	}
 
 	return httpResp.Body, nil
 }
+
+// responseError returns the typed garden error decoded from a failed response
+// as is, apart from filling in any retry hint from the headers, and wraps any
+// other error in an Error describing the response.
+func responseError(resp *http.Response, err error) error {
+	switch typed := err.(type) {
+	case garden.ServiceUnavailableError:
+		if typed.RetryAfter == 0 {
+			typed.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+		}
+		return typed
+	case garden.TooManyRequestsError:
+		if typed.RetryAfter == 0 {
+			typed.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+		}
+		return typed
+	case garden.ContainerNotFoundError,
+		garden.UnrecoverableError,
+		garden.BackendTimeoutError,
+		garden.PermissionDeniedError,
+		garden.NetworkSetupError,
+		garden.ProcessNotFoundError,
+		garden.HandleTakenError,
+		garden.IPTakenError,
+		garden.ImageNotFoundError,
+		garden.ProcessNameTakenError,
+		garden.PropertyConflictError,
+		garden.MalformedRequestError,
+		garden.PayloadTooLargeError,
+		garden.PortUnavailableError:
+		return err
+	}
+
+	return newResponseError(resp, err.Error())
+}
+
+func newResponseError(resp *http.Response, message string) Error {
+	return Error{
+		StatusCode: resp.StatusCode,
+		Message:    message,
+		Header:     resp.Header,
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+	}
+}
+
+// parseRetryAfter parses a Retry-After header given either as a number of
+// seconds or as an HTTP date, returning zero if it is missing or invalid.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(date); wait > 0 {
+			return wait
+		}
+	}
+
+	return 0
+}
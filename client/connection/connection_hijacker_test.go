@@ -4,12 +4,14 @@ import (
 	"bytes"
 	"errors"
 	"net"
+	"net/http"
 	"net/url"
 
 	"code.cloudfoundry.org/garden/client/connection"
 	"code.cloudfoundry.org/garden/routes"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
 	"github.com/tedsuo/rata"
 )
 
@@ -58,4 +60,118 @@ var _ = Describe("ConnectionHijacker", func() {
 		})
 	})
 
+	Describe("constructing hijacker with a host", func() {
+		var (
+			server         *ghttp.Server
+			hijackStreamer connection.HijackStreamer
+			dialedAddrs    []string
+		)
+
+		BeforeEach(func() {
+			server = ghttp.NewServer()
+			dialedAddrs = nil
+
+			dialer := func(network, addr string) (net.Conn, error) {
+				dialedAddrs = append(dialedAddrs, addr)
+				return net.Dial("tcp", server.HTTPTestServer.Listener.Addr().String())
+			}
+			hijackStreamer = connection.NewHijackStreamerWithDialerAndHost(dialer, "garden.example.com")
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		verifyHost := func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.Host).To(Equal("garden.example.com"))
+		}
+
+		Context("when Stream is called", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/ping"),
+					verifyHost,
+					ghttp.RespondWith(200, "{}"),
+				))
+			})
+
+			It("sends the host in the request", func() {
+				body, err := hijackStreamer.Stream(routes.Ping, nil, nil, nil, "")
+				Expect(err).NotTo(HaveOccurred())
+				body.Close()
+
+				Expect(dialedAddrs).To(ConsistOf("garden.example.com:80"))
+			})
+		})
+
+		Context("when Hijack is called", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/containers/some-test-handle/processes"),
+					verifyHost,
+					ghttp.RespondWith(200, "{}"),
+				))
+			})
+
+			It("sends the host in the request and dials its address", func() {
+				conn, _, err := hijackStreamer.Hijack(
+					routes.Run,
+					new(bytes.Buffer),
+					rata.Params{
+						"handle": "some-test-handle",
+					},
+					nil,
+					"application/json",
+				)
+				Expect(err).NotTo(HaveOccurred())
+				conn.Close()
+
+				Expect(dialedAddrs).To(ConsistOf("garden.example.com:80"))
+			})
+		})
+	})
+
+	Describe("constructing hijacker with an https host", func() {
+		var server *ghttp.Server
+
+		BeforeEach(func() {
+			server = ghttp.NewTLSServer()
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("secures the connections it dials with TLS, verifying the host", func() {
+			var dialedAddrs []string
+			dialer := func(network, addr string) (net.Conn, error) {
+				dialedAddrs = append(dialedAddrs, addr)
+				return net.Dial("tcp", server.HTTPTestServer.Listener.Addr().String())
+			}
+			hijackStreamer := connection.NewHijackStreamerWithDialerAndHost(dialer, "https://garden.example.com")
+
+			_, err := hijackStreamer.Stream(routes.Ping, nil, nil, nil, "")
+			Expect(err).To(MatchError(ContainSubstring("x509")))
+
+			_, _, err = hijackStreamer.Hijack(routes.Run, new(bytes.Buffer), rata.Params{"handle": "some-test-handle"}, nil, "application/json")
+			Expect(err).To(MatchError(ContainSubstring("x509")))
+
+			Expect(dialedAddrs).To(ConsistOf("garden.example.com:443", "garden.example.com:443"))
+			Expect(server.ReceivedRequests()).To(BeEmpty())
+		})
+	})
+
+	Describe("constructing hijacker with a host of another scheme", func() {
+		It("fails every request", func() {
+			hijackStreamer := connection.NewHijackStreamerWithDialerAndHost(func(string, string) (net.Conn, error) {
+				return nil, errors.New("should not dial")
+			}, "ftp://garden.example.com")
+
+			_, err := hijackStreamer.Stream(routes.Ping, nil, nil, nil, "")
+			Expect(err).To(MatchError(`connection: unsupported scheme "ftp" in host "ftp://garden.example.com"`))
+
+			_, _, err = hijackStreamer.Hijack(routes.Run, new(bytes.Buffer), rata.Params{"handle": "some-test-handle"}, nil, "application/json")
+			Expect(err).To(MatchError(`connection: unsupported scheme "ftp" in host "ftp://garden.example.com"`))
+		})
+	})
 })
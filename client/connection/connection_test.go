@@ -3,6 +3,7 @@ package connection_test
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -11,6 +12,7 @@ import (
 	"net"
 	"net/http"
 	"net/url"
+	"regexp"
 	"strings"
 	"time"
 
@@ -24,6 +26,7 @@ import (
 	"code.cloudfoundry.org/garden"
 	. "code.cloudfoundry.org/garden/client/connection"
 	"code.cloudfoundry.org/garden/client/connection/fakes"
+	"code.cloudfoundry.org/garden/routes"
 	"code.cloudfoundry.org/garden/transport"
 )
 
@@ -33,11 +36,13 @@ var _ = Describe("Connection", func() {
 		resourceLimits garden.ResourceLimits
 		server         *ghttp.Server
 		hijacker       HijackStreamer
+		logger         *lagertest.TestLogger
 		network        string
 		address        string
 	)
 
 	BeforeEach(func() {
+		logger = lagertest.NewTestLogger("test-connection")
 		server = ghttp.NewServer()
 		network = "tcp"
 		address = server.HTTPTestServer.Listener.Addr().String()
@@ -45,7 +50,7 @@ var _ = Describe("Connection", func() {
 	})
 
 	JustBeforeEach(func() {
-		connection = NewWithHijacker(hijacker, lagertest.NewTestLogger("test-connection"))
+		connection = NewWithHijacker(hijacker, logger)
 	})
 
 	BeforeEach(func() {
@@ -140,6 +145,49 @@ var _ = Describe("Connection", func() {
 			})
 		})
 
+		Context("when the service is unavailable and the server suggests when to retry", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", "/ping"),
+						ghttp.RespondWith(http.StatusServiceUnavailable, `{ "Type": "ServiceUnavailableError" , "Message": "busy"}`, http.Header{
+							"Retry-After": []string{"7"},
+						}),
+					),
+				)
+			})
+
+			It("returns a ServiceUnavailableError with the retry hint", func() {
+				err := connection.Ping()
+				Ω(err).Should(Equal(garden.ServiceUnavailableError{Cause: "busy", RetryAfter: 7 * time.Second}))
+			})
+		})
+
+		Context("when the request fails with an untyped error", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", "/ping"),
+						ghttp.RespondWith(http.StatusTooManyRequests, `{ "Message": "slow down"}`, http.Header{
+							"Retry-After": []string{"2"},
+							"X-Some":      []string{"header"},
+						}),
+					),
+				)
+			})
+
+			It("returns an Error with the status, headers and retry hint", func() {
+				err := connection.Ping()
+				Ω(err).Should(MatchError("slow down"))
+
+				connErr, ok := err.(Error)
+				Ω(ok).Should(BeTrue())
+				Ω(connErr.StatusCode).Should(Equal(http.StatusTooManyRequests))
+				Ω(connErr.Header.Get("X-Some")).Should(Equal("header"))
+				Ω(connErr.RetryAfter).Should(Equal(2 * time.Second))
+			})
+		})
+
 		Context("when the request fails with extra special error code http.StatusInternalServerError", func() {
 			BeforeEach(func() {
 				server.AppendHandlers(
@@ -257,6 +305,54 @@ var _ = Describe("Connection", func() {
 				Ω(handle).Should(Equal("foohandle"))
 			})
 		})
+
+		Context("with sensitive environment variables", func() {
+			BeforeEach(func() {
+				spec = garden.ContainerSpec{
+					Env: []string{"PATH=/bin", "DB_PASSWORD=hunter2", "LICENSE=xyz"},
+				}
+			})
+
+			It("redacts their values before logging", func() {
+				_, err := connection.Create(spec)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				Ω(logger).Should(gbytes.Say(`"env":\["PATH=/bin","DB_PASSWORD=\[REDACTED\]","LICENSE=xyz"\]`))
+				Ω(logger.Buffer().Contents()).ShouldNot(ContainSubstring("hunter2"))
+			})
+
+			Context("when a redactor is configured", func() {
+				JustBeforeEach(func() {
+					connection = NewWithHijacker(hijacker, logger, WithRedactor(garden.Redactor{
+						EnvPatterns: []*regexp.Regexp{regexp.MustCompile("^LICENSE$")},
+					}))
+				})
+
+				It("redacts the values of variables matching its patterns instead", func() {
+					_, err := connection.Create(spec)
+					Ω(err).ShouldNot(HaveOccurred())
+
+					Ω(logger).Should(gbytes.Say(`"env":\["PATH=/bin","DB_PASSWORD=hunter2","LICENSE=\[REDACTED\]"\]`))
+					Ω(logger.Buffer().Contents()).ShouldNot(ContainSubstring("xyz"))
+				})
+			})
+		})
+
+		Context("with properties", func() {
+			BeforeEach(func() {
+				spec = garden.ContainerSpec{
+					Properties: garden.Properties{"owner": "someone", "api_token": "abc123"},
+				}
+			})
+
+			It("does not log them", func() {
+				_, err := connection.Create(spec)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				Ω(logger.Buffer().Contents()).ShouldNot(ContainSubstring("api_token"))
+				Ω(logger.Buffer().Contents()).ShouldNot(ContainSubstring("abc123"))
+			})
+		})
 	})
 
 	Describe("Destroying", func() {
@@ -289,6 +385,89 @@ var _ = Describe("Connection", func() {
 		})
 	})
 
+	Describe("Bulk creating", func() {
+		specs := []garden.ContainerSpec{{Handle: "foo"}, {Handle: "bar"}}
+
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/containers/bulk_create"),
+					ghttp.VerifyJSONRepresenting(transport.BulkCreateRequest{Specs: specs}),
+					ghttp.RespondWith(200, `[{"Handle":"foo","Err":null},{"Handle":"","Err":{"Type":"HandleTakenError","Handle":"bar"}}]`)))
+		})
+
+		It("returns an entry for each spec", func() {
+			entries, err := connection.BulkCreate(specs)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(entries).Should(HaveLen(2))
+			Ω(entries[0]).Should(Equal(garden.ContainerCreateEntry{Handle: "foo"}))
+			Ω(entries[1].Err).ShouldNot(BeNil())
+			Ω(entries[1].Err.Err).Should(Equal(garden.HandleTakenError{Handle: "bar"}))
+		})
+	})
+
+	Describe("Bulk stopping", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("PUT", "/containers/bulk_stop"),
+					ghttp.VerifyJSONRepresenting(transport.BulkStopRequest{Handles: []string{"foo", "bar"}, Kill: true}),
+					ghttp.RespondWith(200, `{"foo":{"Err":null},"bar":{"Err":{"Type":"ContainerNotFoundError","Handle":"bar"}}}`)))
+		})
+
+		It("returns an entry for each handle", func() {
+			entries, err := connection.BulkStop([]string{"foo", "bar"}, true)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(entries["foo"].Err).Should(BeNil())
+			Ω(entries["bar"].Err).ShouldNot(BeNil())
+			Ω(entries["bar"].Err.Err).Should(Equal(garden.ContainerNotFoundError{Handle: "bar"}))
+		})
+	})
+
+	Describe("Bulk destroying", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/containers/bulk_destroy"),
+					ghttp.VerifyJSONRepresenting(transport.BulkDestroyRequest{Handles: []string{"foo", "bar"}}),
+					ghttp.RespondWith(200, `{"foo":{"Err":null},"bar":{"Err":{"Type":"ContainerNotFoundError","Handle":"bar"}}}`)))
+		})
+
+		It("returns an entry for each handle", func() {
+			entries, err := connection.BulkDestroy([]string{"foo", "bar"})
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(entries["foo"].Err).Should(BeNil())
+			Ω(entries["bar"].Err).ShouldNot(BeNil())
+			Ω(entries["bar"].Err.Err).Should(Equal(garden.ContainerNotFoundError{Handle: "bar"}))
+		})
+	})
+
+	Describe("Destroying as a dry run", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("DELETE", "/containers/foo", "dry_run=true"),
+					ghttp.RespondWith(200, marshalProto(&garden.DestroyImpact{
+						ProcessIDs: []string{"process-1"},
+						HostPorts:  []uint32{61001},
+						DiskBytes:  1024,
+					}))))
+		})
+
+		It("should return the impact of destroying the container", func() {
+			impact, err := connection.DestroyDryRun("foo")
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(impact).Should(Equal(garden.DestroyImpact{
+				ProcessIDs: []string{"process-1"},
+				HostPorts:  []uint32{61001},
+				DiskBytes:  1024,
+			}))
+		})
+	})
+
 	Describe("Stopping", func() {
 		BeforeEach(func() {
 			server.AppendHandlers(
@@ -306,6 +485,99 @@ var _ = Describe("Connection", func() {
 		})
 	})
 
+	Describe("Stopping with a timeout", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("PUT", "/containers/foo/stop"),
+					verifyRequestBody(map[string]interface{}{
+						"kill":    false,
+						"timeout": float64(30 * time.Second),
+					}, make(map[string]interface{})),
+					ghttp.RespondWith(200, "{}")))
+		})
+
+		It("should stop the container with the timeout", func() {
+			err := connection.StopWithTimeout("foo", 30*time.Second)
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+	})
+
+	Describe("Pausing", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("PUT", "/containers/foo/pause"),
+					ghttp.RespondWith(200, "{}")))
+		})
+
+		It("should pause the container", func() {
+			err := connection.Pause("foo")
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+	})
+
+	Describe("Resuming", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("PUT", "/containers/foo/resume"),
+					ghttp.RespondWith(200, "{}")))
+		})
+
+		It("should resume the container", func() {
+			err := connection.Resume("foo")
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+	})
+
+	Describe("Checkpointing", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("PUT", "/containers/foo/checkpoint"),
+					ghttp.VerifyJSONRepresenting(transport.CheckpointRequest{Destination: "/path/to/checkpoint"}),
+					ghttp.RespondWith(200, "{}")))
+		})
+
+		It("should checkpoint the container", func() {
+			err := connection.Checkpoint("foo", "/path/to/checkpoint")
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+	})
+
+	Describe("Committing", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/containers/foo/commit"),
+					ghttp.VerifyJSONRepresenting(transport.CommitRequest{ImageName: "base:1.0"}),
+					ghttp.RespondWith(200, marshalProto(&garden.CachedImage{URI: "/var/images/base-1.0"}))))
+		})
+
+		It("should return the committed image", func() {
+			Ω(connection.Commit("foo", "base:1.0")).Should(Equal(garden.CachedImage{URI: "/var/images/base-1.0"}))
+		})
+	})
+
+	Describe("Restoring", func() {
+		spec := garden.ContainerSpec{Handle: "foo", RootFSPath: "some-rootfs-path"}
+
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/containers/restore"),
+					ghttp.VerifyJSONRepresenting(transport.RestoreRequest{Spec: spec, Source: "/path/to/checkpoint"}),
+					ghttp.RespondWith(200, marshalProto(&struct{ Handle string }{"foo"}))))
+		})
+
+		It("should restore the container and return its handle", func() {
+			handle, err := connection.Restore(spec, "/path/to/checkpoint")
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(handle).Should(Equal("foo"))
+		})
+	})
+
 	Describe("fetching limit info", func() {
 		Describe("getting memory limits", func() {
 			BeforeEach(func() {
@@ -326,6 +598,26 @@ var _ = Describe("Connection", func() {
 			})
 		})
 
+		Describe("getting memory limits with a swap limit", func() {
+			var noSwap uint64
+
+			BeforeEach(func() {
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", "/containers/foo/limits/memory"),
+						ghttp.RespondWith(200, `{"limit_in_bytes":40,"swap_limit_in_bytes":0}`),
+					),
+				)
+			})
+
+			It("distinguishes a swap limit of zero from no swap limit", func() {
+				currentLimits, err := connection.CurrentMemoryLimits("foo")
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(currentLimits.SwapLimitInBytes).Should(Equal(&noSwap))
+				Ω(currentLimits.Swappiness).Should(BeNil())
+			})
+		})
+
 		Describe("getting cpu limits", func() {
 			BeforeEach(func() {
 				server.AppendHandlers(
@@ -367,89 +659,444 @@ var _ = Describe("Connection", func() {
 				Ω(limits.BurstRateInBytesPerSecond).Should(BeNumerically("==", 2))
 			})
 		})
-	})
 
-	Describe("NetIn", func() {
-		BeforeEach(func() {
-			server.AppendHandlers(
-				ghttp.CombineHandlers(
-					ghttp.VerifyRequest("POST", "/containers/foo-handle/net/in"),
-					verifyRequestBody(map[string]interface{}{
-						"handle":         "foo-handle",
-						"host_port":      float64(8080),
-						"container_port": float64(8081),
-					}, make(map[string]interface{})),
-					ghttp.RespondWith(200, marshalProto(map[string]interface{}{
-						"host_port":      1234,
-						"container_port": 1235,
-					}))))
-		})
+		Describe("getting pid limits", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", "/containers/foo/limits/pid"),
+						ghttp.RespondWith(200, marshalProto(&garden.PidLimits{
+							Max: 1024,
+						})),
+					),
+				)
+			})
 
-		It("should return the allocated ports", func() {
-			hostPort, containerPort, err := connection.NetIn("foo-handle", 8080, 8081)
-			Ω(err).ShouldNot(HaveOccurred())
-			Ω(hostPort).Should(Equal(uint32(1234)))
-			Ω(containerPort).Should(Equal(uint32(1235)))
+			It("gets the pid limit", func() {
+				limits, err := connection.CurrentPidLimits("foo")
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(limits.Max).Should(BeNumerically("==", 1024))
+			})
 		})
-	})
-
-	Describe("NetOut", func() {
-		var (
-			rule   garden.NetOutRule
-			handle string
-		)
 
-		BeforeEach(func() {
-			handle = "foo-handle"
-		})
+		Describe("getting block IO limits", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", "/containers/foo/limits/block_io"),
+						ghttp.RespondWith(200, marshalProto(&garden.BlockIOLimits{
+							ReadIOPS: 1024,
+						})),
+					),
+				)
+			})
 
-		JustBeforeEach(func() {
-			server.AppendHandlers(
-				ghttp.CombineHandlers(
-					ghttp.VerifyRequest("POST", fmt.Sprintf("/containers/%s/net/out", handle)),
-					verifyRequestBody(&rule, &garden.NetOutRule{}),
-					ghttp.RespondWith(200, "{}")))
+			It("gets the block IO limits", func() {
+				limits, err := connection.CurrentBlockIOLimits("foo")
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(limits.ReadIOPS).Should(BeNumerically("==", 1024))
+			})
 		})
 
-		Context("when a NetOutRule is passed", func() {
+		Describe("getting all limits", func() {
 			BeforeEach(func() {
-				rule = garden.NetOutRule{
-					Protocol: garden.ProtocolICMP,
-					Networks: []garden.IPRange{garden.IPRangeFromIP(net.ParseIP("1.2.3.4"))},
-					Ports:    []garden.PortRange{garden.PortRangeFromPort(2), garden.PortRangeFromPort(4)},
-					ICMPs:    &garden.ICMPControl{Type: 3, Code: garden.ICMPControlCode(3)},
-					Log:      true,
-				}
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", "/containers/foo/limits"),
+						ghttp.RespondWith(200, marshalProto(&garden.Limits{
+							CPU:    garden.CPULimits{LimitInShares: 40},
+							Memory: garden.MemoryLimits{LimitInBytes: 50},
+						})),
+					),
+				)
 			})
 
-			It("should send the rule over the wire", func() {
-				Ω(connection.NetOut(handle, rule)).Should(Succeed())
+			It("gets the limits", func() {
+				limits, err := connection.CurrentLimits("foo")
+				Ω(err).ShouldNot(HaveOccurred())
+
+				Ω(limits).Should(Equal(garden.Limits{
+					CPU:    garden.CPULimits{LimitInShares: 40},
+					Memory: garden.MemoryLimits{LimitInBytes: 50},
+				}))
 			})
 		})
 	})
 
-	Describe("Listing containers", func() {
+	Describe("limiting bandwidth", func() {
 		BeforeEach(func() {
 			server.AppendHandlers(
 				ghttp.CombineHandlers(
-					ghttp.VerifyRequest("GET", "/containers", "foo=bar"),
-					ghttp.RespondWith(200, marshalProto(&struct {
-						Handles []string `json:"handles"`
-					}{
-						[]string{"container1", "container2", "container3"},
-					}))))
+					ghttp.VerifyRequest("PUT", "/containers/foo/limits/bandwidth"),
+					ghttp.VerifyJSON(`{"rate":1024,"egress_rate":512}`),
+					ghttp.RespondWith(200, "{}"),
+				),
+			)
 		})
 
-		It("should return the list of containers", func() {
-			handles, err := connection.List(map[string]string{"foo": "bar"})
-
-			Ω(err).ShouldNot(HaveOccurred())
-			Ω(handles).Should(Equal([]string{"container1", "container2", "container3"}))
+		It("sends the limits", func() {
+			Ω(connection.LimitBandwidth("foo", garden.BandwidthLimits{RateInBytesPerSecond: 1024, EgressRateInBytesPerSecond: 512})).Should(Succeed())
 		})
 	})
 
-	Describe("Getting container properties", func() {
-		handle := "container-handle"
+	Describe("limiting cpu", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("PUT", "/containers/foo/limits/cpu"),
+					ghttp.VerifyJSON(`{"limit_in_shares":2,"quota_in_microseconds":50000,"period_in_microseconds":100000,"cpuset":"0-3,6"}`),
+					ghttp.RespondWith(200, "{}"),
+				),
+			)
+		})
+
+		It("sends the limits", func() {
+			Ω(connection.LimitCPU("foo", garden.CPULimits{
+				LimitInShares:        2,
+				QuotaInMicroseconds:  50000,
+				PeriodInMicroseconds: 100000,
+				Cpuset:               "0-3,6",
+			})).Should(Succeed())
+		})
+	})
+
+	Describe("limiting memory", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("PUT", "/containers/foo/limits/memory"),
+					ghttp.VerifyJSON(`{"limit_in_bytes":1024,"swap_limit_in_bytes":0}`),
+					ghttp.RespondWith(200, "{}"),
+				),
+			)
+		})
+
+		It("sends the limits, including a swap limit of zero", func() {
+			var noSwap uint64
+			Ω(connection.LimitMemory("foo", garden.MemoryLimits{LimitInBytes: 1024, SwapLimitInBytes: &noSwap})).Should(Succeed())
+		})
+	})
+
+	Describe("limiting pids", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("PUT", "/containers/foo/limits/pid"),
+					ghttp.VerifyJSONRepresenting(garden.PidLimits{Max: 1024}),
+					ghttp.RespondWith(200, "{}"),
+				),
+			)
+		})
+
+		It("sends the limit", func() {
+			Ω(connection.LimitPids("foo", garden.PidLimits{Max: 1024})).Should(Succeed())
+		})
+	})
+
+	Describe("limiting block IO", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("PUT", "/containers/foo/limits/block_io"),
+					ghttp.VerifyJSONRepresenting(garden.BlockIOLimits{ReadIOPS: 1024}),
+					ghttp.RespondWith(200, "{}"),
+				),
+			)
+		})
+
+		It("sends the limit", func() {
+			Ω(connection.LimitBlockIO("foo", garden.BlockIOLimits{ReadIOPS: 1024})).Should(Succeed())
+		})
+	})
+
+	Describe("setting all limits", func() {
+		limits := garden.Limits{
+			Disk:   garden.DiskLimits{ByteHard: 1024},
+			Memory: garden.MemoryLimits{LimitInBytes: 2048},
+		}
+
+		Context("when the request succeeds", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("PUT", "/containers/foo/limits"),
+						ghttp.VerifyJSONRepresenting(limits),
+						ghttp.RespondWith(200, "{}"),
+					),
+				)
+			})
+
+			It("sends the limits", func() {
+				Ω(connection.LimitAll("foo", limits)).Should(Succeed())
+			})
+		})
+
+		Context("when the request fails", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("PUT", "/containers/foo/limits"),
+						ghttp.RespondWith(500, ""),
+					),
+				)
+			})
+
+			It("returns an error", func() {
+				Ω(connection.LimitAll("foo", limits)).ShouldNot(Succeed())
+			})
+		})
+	})
+
+	Describe("NetIn", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/containers/foo-handle/net/in"),
+					verifyRequestBody(map[string]interface{}{
+						"handle":         "foo-handle",
+						"host_port":      float64(8080),
+						"container_port": float64(8081),
+					}, make(map[string]interface{})),
+					ghttp.RespondWith(200, marshalProto(map[string]interface{}{
+						"host_port":      1234,
+						"container_port": 1235,
+					}))))
+		})
+
+		It("should return the allocated ports", func() {
+			hostPort, containerPort, err := connection.NetIn("foo-handle", 8080, 8081)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(hostPort).Should(Equal(uint32(1234)))
+			Ω(containerPort).Should(Equal(uint32(1235)))
+		})
+	})
+
+	Describe("NetIn with a host IP", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/containers/foo-handle/net/in"),
+					verifyRequestBody(map[string]interface{}{
+						"handle":         "foo-handle",
+						"host_port":      float64(8080),
+						"container_port": float64(8081),
+						"host_ip":        "10.0.0.1",
+					}, make(map[string]interface{})),
+					ghttp.RespondWith(200, marshalProto(map[string]interface{}{
+						"host_port":      8080,
+						"container_port": 8081,
+						"host_ip":        "10.0.0.1",
+					}))))
+		})
+
+		It("should return the mapping with the host IP", func() {
+			mapping, err := connection.NetInWithHostIP("foo-handle", "10.0.0.1", 8080, 8081)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(mapping).Should(Equal(garden.PortMapping{HostPort: 8080, ContainerPort: 8081, HostIP: "10.0.0.1"}))
+		})
+	})
+
+	Describe("Listing NetIn mappings", func() {
+		mappings := []garden.PortMapping{
+			{HostPort: 1234, ContainerPort: 8080},
+		}
+
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/containers/foo-handle/net/in"),
+					ghttp.RespondWith(200, marshalProto(mappings))))
+		})
+
+		It("should return the container's mappings", func() {
+			Ω(connection.NetInMappings("foo-handle")).Should(Equal(mappings))
+		})
+	})
+
+	Describe("Removing a NetIn mapping", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("DELETE", "/containers/foo-handle/net/in/1234"),
+					ghttp.RespondWith(200, "{}")))
+		})
+
+		It("should send the request", func() {
+			Ω(connection.RemoveNetIn("foo-handle", 1234)).Should(Succeed())
+		})
+	})
+
+	Describe("NetOut", func() {
+		var (
+			rule   garden.NetOutRule
+			handle string
+		)
+
+		BeforeEach(func() {
+			handle = "foo-handle"
+		})
+
+		JustBeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", fmt.Sprintf("/containers/%s/net/out", handle)),
+					verifyRequestBody(&rule, &garden.NetOutRule{}),
+					ghttp.RespondWith(200, "{}")))
+		})
+
+		Context("when a NetOutRule is passed", func() {
+			BeforeEach(func() {
+				rule = garden.NetOutRule{
+					Protocol: garden.ProtocolICMP,
+					Networks: []garden.IPRange{garden.IPRangeFromIP(net.ParseIP("1.2.3.4"))},
+					Ports:    []garden.PortRange{garden.PortRangeFromPort(2), garden.PortRangeFromPort(4)},
+					ICMPs:    &garden.ICMPControl{Type: 3, Code: garden.ICMPControlCode(3)},
+					Log:      true,
+				}
+			})
+
+			It("should send the rule over the wire", func() {
+				Ω(connection.NetOut(handle, rule)).Should(Succeed())
+			})
+		})
+	})
+
+	Describe("BulkNetOut", func() {
+		rules := []garden.NetOutRule{
+			{ID: "some-rule", Protocol: garden.ProtocolTCP, Ports: []garden.PortRange{garden.PortRangeFromPort(443)}},
+			{Protocol: garden.ProtocolICMP},
+		}
+
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/containers/foo-handle/net/out/bulk"),
+					ghttp.VerifyJSONRepresenting(transport.BulkNetOutRequest{Rules: rules}),
+					ghttp.RespondWith(200, `{"ids":["some-rule","5f1c2ab79e3d4c80"]}`)))
+		})
+
+		It("should send the rules over the wire", func() {
+			Ω(connection.BulkNetOut("foo-handle", rules)).Should(Succeed())
+		})
+	})
+
+	Describe("Listing NetOut rules", func() {
+		rules := []garden.NetOutRule{
+			{ID: "some-rule", Protocol: garden.ProtocolTCP},
+		}
+
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/containers/foo-handle/net/out"),
+					ghttp.RespondWith(200, marshalProto(rules))))
+		})
+
+		It("should return the container's rules", func() {
+			Ω(connection.NetOutRules("foo-handle")).Should(Equal(rules))
+		})
+	})
+
+	Describe("Removing a NetOut rule", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("DELETE", "/containers/foo-handle/net/out/some-rule"),
+					ghttp.RespondWith(200, "{}")))
+		})
+
+		It("should send the request", func() {
+			Ω(connection.RemoveNetOut("foo-handle", "some-rule")).Should(Succeed())
+		})
+	})
+
+	Describe("Removing all NetOut rules", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("DELETE", "/containers/foo-handle/net/out"),
+					ghttp.RespondWith(200, "{}")))
+		})
+
+		It("should send the request", func() {
+			Ω(connection.RemoveAllNetOut("foo-handle")).Should(Succeed())
+		})
+	})
+
+	Describe("Listing containers", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/containers", "foo=bar"),
+					ghttp.RespondWith(200, marshalProto(&struct {
+						Handles []string `json:"handles"`
+					}{
+						[]string{"container1", "container2", "container3"},
+					}))))
+		})
+
+		It("should return the list of containers", func() {
+			handles, err := connection.List(map[string]string{"foo": "bar"})
+
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(handles).Should(Equal([]string{"container1", "container2", "container3"}))
+		})
+	})
+
+	Describe("Listing containers with options", func() {
+		createdBefore := time.Date(2016, 1, 2, 3, 4, 5, 0, time.UTC)
+
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/containers",
+						"foo=bar&garden.created_before=2016-01-02T03%3A04%3A05Z&garden.order=created_at"),
+					ghttp.RespondWith(200, marshalProto(&struct {
+						Handles []string `json:"handles"`
+					}{
+						[]string{"container1", "container2"},
+					}))))
+		})
+
+		It("should pass the options as query params", func() {
+			handles, err := connection.ListWithOptions(map[string]string{"foo": "bar"}, garden.ListOptions{
+				OrderByCreation: true,
+				CreatedBefore:   createdBefore,
+			})
+
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(handles).Should(Equal([]string{"container1", "container2"}))
+		})
+	})
+
+	Describe("Listing containers with property filters", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/containers",
+						"garden.filter.in.zone=z1&garden.filter.in.zone=z2&garden.filter.not_exists.owner="),
+					ghttp.RespondWith(200, marshalProto(&struct {
+						Handles []string `json:"handles"`
+					}{
+						[]string{"container1"},
+					}))))
+		})
+
+		It("should pass the filters as query params", func() {
+			handles, err := connection.ListWithOptions(nil, garden.ListOptions{
+				PropertyFilters: []garden.PropertyFilter{
+					{Name: "owner", Operator: garden.PropertyExists, Negate: true},
+					{Name: "zone", Operator: garden.PropertyIn, Values: []string{"z1", "z2"}},
+				},
+			})
+
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(handles).Should(Equal([]string{"container1"}))
+		})
+	})
+
+	Describe("Getting container properties", func() {
+		handle := "container-handle"
 		var status int
 
 		BeforeEach(func() {
@@ -524,8 +1171,52 @@ var _ = Describe("Connection", func() {
 
 	})
 
-	Describe("Getting container metrics", func() {
-		handle := "container-handle"
+	Describe("Setting several container properties", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("PUT", "/containers/container-handle/properties"),
+					ghttp.VerifyJSONRepresenting(garden.Properties{"zone": "z1", "rack": "r2"}),
+					ghttp.RespondWith(200, "{}")))
+		})
+
+		It("sets the properties in one request", func() {
+			Ω(connection.SetProperties("container-handle", garden.Properties{"zone": "z1", "rack": "r2"})).Should(Succeed())
+		})
+	})
+
+	Describe("Removing several container properties", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("DELETE", "/containers/container-handle/properties", "key=zone&key=rack"),
+					ghttp.RespondWith(200, "{}")))
+		})
+
+		It("removes the properties in one request", func() {
+			Ω(connection.RemoveProperties("container-handle", []string{"zone", "rack"})).Should(Succeed())
+		})
+	})
+
+	Describe("Compare-and-swap of a container property", func() {
+		handle := "container-handle"
+
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("PUT", fmt.Sprintf("/containers/%s/properties/owner/cas", handle)),
+					ghttp.VerifyJSONRepresenting(transport.CASPropertyRequest{OldValue: "some-owner", Value: "other-owner"}),
+					ghttp.RespondWith(409, marshalProto(garden.Error{Err: garden.PropertyConflictError{Handle: handle, Name: "owner", Value: "third-owner"}}))))
+		})
+
+		It("returns the conflict", func() {
+			err := connection.CASProperty(handle, "owner", "some-owner", "other-owner")
+			Ω(err).Should(Equal(garden.PropertyConflictError{Handle: handle, Name: "owner", Value: "third-owner"}))
+		})
+	})
+
+	Describe("Getting container metrics", func() {
+		handle := "container-handle"
 		metrics := garden.Metrics{
 			MemoryStat: garden.ContainerMemoryStat{
 				Cache:                   1,
@@ -564,81 +1255,569 @@ var _ = Describe("Connection", func() {
 				System: 3,
 			},
 
-			DiskStat: garden.ContainerDiskStat{
-				TotalBytesUsed:      11,
-				TotalInodesUsed:     12,
-				ExclusiveBytesUsed:  13,
-				ExclusiveInodesUsed: 14,
-			},
-		}
-		var status int
+			DiskStat: garden.ContainerDiskStat{
+				TotalBytesUsed:      11,
+				TotalInodesUsed:     12,
+				ExclusiveBytesUsed:  13,
+				ExclusiveInodesUsed: 14,
+			},
+
+			NetworkStat: garden.ContainerNetworkStat{
+				RxBytes:   21,
+				TxBytes:   22,
+				RxPackets: 23,
+				TxPackets: 24,
+				RxDropped: 25,
+				TxDropped: 26,
+			},
+		}
+		var status int
+
+		BeforeEach(func() {
+			status = 200
+		})
+
+		JustBeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", fmt.Sprintf("/containers/%s/metrics", handle)),
+					ghttp.RespondWith(status, marshalProto(metrics))))
+		})
+
+		It("returns the MemoryStat, CPUStat, DiskStat and NetworkStat", func() {
+			returnedMetrics, err := connection.Metrics(handle)
+
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(returnedMetrics).Should(Equal(metrics))
+		})
+
+		Context("when getting container metrics fails", func() {
+			BeforeEach(func() {
+				status = 400
+			})
+
+			It("returns an error", func() {
+				_, err := connection.Metrics(handle)
+				Ω(err).Should(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("Getting container host resources", func() {
+		handle := "container-handle"
+		resources := garden.HostResources{
+			HostPorts:            []uint32{61001, 61002},
+			BindMountSourcePaths: []string{"/var/vcap/data/foo"},
+			UIDMappings: []garden.IDMapping{
+				{HostID: 100000, ContainerID: 0, Size: 65536},
+			},
+		}
+
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", fmt.Sprintf("/containers/%s/host_resources", handle)),
+					ghttp.RespondWith(200, marshalProto(resources))))
+		})
+
+		It("returns the host resources", func() {
+			returnedResources, err := connection.HostResources(handle)
+
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(returnedResources).Should(Equal(resources))
+		})
+	})
+
+	Describe("Listing processes", func() {
+		handle := "container-handle"
+		processes := []garden.ProcessInfo{
+			{
+				ID:        "some-process",
+				Path:      "/bin/sleep",
+				Args:      []string{"10"},
+				StartedAt: time.Date(2016, 1, 2, 3, 4, 5, 0, time.UTC),
+				State:     garden.ProcessStateRunning,
+			},
+		}
+
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", fmt.Sprintf("/containers/%s/processes", handle)),
+					ghttp.RespondWith(200, marshalProto(processes))))
+		})
+
+		It("returns the processes", func() {
+			returnedProcesses, err := connection.Processes(handle)
+
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(returnedProcesses).Should(Equal(processes))
+		})
+	})
+
+	Describe("Setting the grace time", func() {
+		var (
+			status    int
+			handle    string
+			graceTime time.Duration
+		)
+
+		BeforeEach(func() {
+			handle = "container-handle"
+			graceTime = time.Second * 5
+			status = 200
+		})
+
+		JustBeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("PUT", fmt.Sprintf("/containers/%s/grace_time", handle)),
+					// interface{} confusion: the JSON decoder decodes numberics to float64...
+					verifyRequestBody(float64(graceTime), float64(0)),
+					ghttp.RespondWith(status, "{}"),
+				),
+			)
+		})
+
+		It("send SetGraceTime request", func() {
+			Ω(connection.SetGraceTime(handle, graceTime)).Should(Succeed())
+		})
+
+		Context("when setting grace time fails", func() {
+			BeforeEach(func() {
+				status = 400
+			})
+
+			It("returns an error", func() {
+				Ω(connection.SetGraceTime(handle, graceTime)).ShouldNot(Succeed())
+			})
+		})
+	})
+
+	Describe("Setting the description", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("PUT", "/containers/foo/description"),
+					ghttp.VerifyJSON(`"runs the nightly build"`),
+					ghttp.RespondWith(200, "{}"),
+				),
+			)
+		})
+
+		It("sends the description", func() {
+			Ω(connection.SetDescription("foo", "runs the nightly build")).Should(Succeed())
+		})
+	})
+
+	Describe("Subscribing to events", func() {
+		var unblock chan struct{}
+
+		BeforeEach(func() {
+			unblock = make(chan struct{})
+
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/events"),
+					func(w http.ResponseWriter, r *http.Request) {
+						transport.WriteMessage(w, garden.Event{Kind: garden.EventCreated, Handle: "container1"})
+						transport.WriteMessage(w, garden.Event{Kind: garden.EventProcessStarted, Handle: "container1", ProcessID: "process1"})
+						w.(http.Flusher).Flush()
+						<-unblock
+					}))
+		})
+
+		AfterEach(func() {
+			close(unblock)
+		})
+
+		It("should stream the events until the context is done", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			events, err := connection.Events(ctx)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Eventually(events).Should(Receive(Equal(garden.Event{Kind: garden.EventCreated, Handle: "container1"})))
+			Eventually(events).Should(Receive(Equal(garden.Event{Kind: garden.EventProcessStarted, Handle: "container1", ProcessID: "process1"})))
+
+			cancel()
+			Eventually(events).Should(BeClosed())
+		})
+	})
+
+	Describe("Streaming metrics", func() {
+		var unblock chan struct{}
+
+		snapshot := garden.MetricsSnapshot{
+			Time: time.Date(2016, 1, 2, 3, 4, 5, 0, time.UTC),
+			Entries: map[string]garden.ContainerMetricsEntry{
+				"container1": {Metrics: garden.Metrics{DiskStat: garden.ContainerDiskStat{TotalBytesUsed: 1}}},
+			},
+		}
+
+		BeforeEach(func() {
+			unblock = make(chan struct{})
+
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/metrics/stream", "handles=container1%2Ccontainer2&interval=15s"),
+					func(w http.ResponseWriter, r *http.Request) {
+						transport.WriteMessage(w, snapshot)
+						w.(http.Flusher).Flush()
+						<-unblock
+					}))
+		})
+
+		AfterEach(func() {
+			close(unblock)
+		})
+
+		It("should stream the snapshots until the context is done", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			snapshots, err := connection.StreamMetrics(ctx, []string{"container1", "container2"}, 15*time.Second)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Eventually(snapshots).Should(Receive(Equal(snapshot)))
+
+			cancel()
+			Eventually(snapshots).Should(BeClosed())
+		})
+	})
+
+	Describe("Resuming events", func() {
+		var unblock chan struct{}
+
+		BeforeEach(func() {
+			unblock = make(chan struct{})
+
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/events", "since=41"),
+					func(w http.ResponseWriter, r *http.Request) {
+						transport.WriteMessage(w, garden.Event{Kind: garden.EventCreated, Handle: "container1", Seq: 42})
+						w.(http.Flusher).Flush()
+						<-unblock
+					}))
+		})
+
+		AfterEach(func() {
+			close(unblock)
+		})
+
+		It("asks to stream the events after the sequence number", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			events, err := connection.EventsSince(ctx, 41)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Eventually(events).Should(Receive(Equal(garden.Event{Kind: garden.EventCreated, Handle: "container1", Seq: 42})))
+		})
+	})
+
+	Describe("Getting a process exit", func() {
+		exitedAt := time.Date(2016, 1, 2, 3, 4, 5, 0, time.UTC)
+
+		Context("when the server retains the exit", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", "/containers/container1/processes/process1/exit"),
+						ghttp.RespondWith(200, marshalProto(&garden.ProcessExit{
+							ProcessID:  "process1",
+							ExitStatus: 42,
+							ExitedAt:   exitedAt,
+							Usage:      &garden.ProcessUsage{CPUTime: time.Second, MaxMemoryBytes: 1024},
+						}))))
+			})
+
+			It("should return the exit", func() {
+				exit, err := connection.ProcessExit("container1", "process1")
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(exit).Should(Equal(garden.ProcessExit{
+					ProcessID:  "process1",
+					ExitStatus: 42,
+					ExitedAt:   exitedAt,
+					Usage:      &garden.ProcessUsage{CPUTime: time.Second, MaxMemoryBytes: 1024},
+				}))
+			})
+		})
+
+		Context("when the server does not know the process", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", "/containers/container1/processes/process1/exit"),
+						ghttp.RespondWith(404, marshalProto(garden.Error{Err: garden.ProcessNotFoundError{Handle: "container1", ProcessID: "process1"}}))))
+			})
+
+			It("should return a ProcessNotFoundError", func() {
+				_, err := connection.ProcessExit("container1", "process1")
+				Ω(err).Should(Equal(garden.ProcessNotFoundError{Handle: "container1", ProcessID: "process1"}))
+			})
+		})
+	})
+
+	Describe("Getting a process environment", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/containers/container1/processes/process1/env"),
+					ghttp.RespondWith(200, marshalProto([]string{"PATH=/bin", "DB_PASSWORD=[REDACTED]"}))))
+		})
+
+		It("should return the environment", func() {
+			env, err := connection.ProcessEnv("container1", "process1")
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(env).Should(Equal([]string{"PATH=/bin", "DB_PASSWORD=[REDACTED]"}))
+		})
+	})
+
+	Describe("Getting the reconciliation report", func() {
+		startedAt := time.Date(2016, 1, 2, 3, 4, 5, 0, time.UTC)
+
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/reconciliation"),
+					ghttp.RespondWith(200, marshalProto(&garden.Reconciliation{
+						StartedAt:           startedAt,
+						Recovered:           []string{"container1"},
+						OrphansDestroyed:    []string{"container2"},
+						ProcessesReattached: 3,
+					}))))
+		})
+
+		It("should return the report", func() {
+			reconciliation, err := connection.Reconciliation()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(reconciliation).Should(Equal(garden.Reconciliation{
+				StartedAt:           startedAt,
+				Recovered:           []string{"container1"},
+				OrphansDestroyed:    []string{"container2"},
+				ProcessesReattached: 3,
+			}))
+		})
+	})
+
+	Describe("Getting the pressure on the machine", func() {
+		pressure := garden.CellPressure{
+			Memory: &garden.PressureStall{
+				Some: garden.PressureAverages{Avg10: 1.5, Avg60: 1, Avg300: 0.5, Total: time.Second},
+				Full: &garden.PressureAverages{Avg10: 0.5},
+			},
+			LoadAverage:     &[3]float64{1, 2, 3},
+			GraphDisk:       &garden.DiskSpace{FreeBytes: 1024, TotalBytes: 4096},
+			FileDescriptors: &garden.FileDescriptorUsage{Open: 12, Limit: 1024},
+		}
+
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/pressure"),
+					ghttp.RespondWith(200, marshalProto(pressure))))
+		})
+
+		It("should return the pressure", func() {
+			Ω(connection.Pressure()).Should(Equal(pressure))
+		})
+	})
+
+	Describe("Draining the server", func() {
+		status := garden.DrainStatus{
+			Draining: true,
+			Since:    time.Date(2016, 1, 2, 3, 4, 5, 0, time.UTC),
+			InFlight: 2,
+		}
+
+		It("should start draining and return the status", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/drain"),
+					ghttp.RespondWith(200, marshalProto(status))))
+
+			Ω(connection.Drain()).Should(Equal(status))
+		})
+
+		It("should stop draining", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("DELETE", "/drain"),
+					ghttp.RespondWith(200, marshalProto(garden.DrainStatus{}))))
+
+			Ω(connection.Undrain()).Should(Equal(garden.DrainStatus{}))
+		})
+
+		It("should return the drain status", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/drain"),
+					ghttp.RespondWith(200, marshalProto(status))))
+
+			Ω(connection.DrainStatus()).Should(Equal(status))
+		})
+	})
+
+	Describe("Getting port allocations", func() {
+		until := time.Date(2016, 1, 2, 3, 4, 5, 0, time.UTC)
+
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/ports"),
+					ghttp.RespondWith(200, marshalProto(&garden.PortAllocations{
+						Allocations: []garden.PortAllocation{{Handle: "container1", HostPort: 61001, ContainerPort: 8080}},
+						Quarantined: []garden.QuarantinedPort{{HostPort: 61002, Until: until}},
+					}))))
+		})
+
+		It("should return the allocated and quarantined ports", func() {
+			allocations, err := connection.PortAllocations()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(allocations.Allocations).Should(Equal([]garden.PortAllocation{{Handle: "container1", HostPort: 61001, ContainerPort: 8080}}))
+			Ω(allocations.Quarantined).Should(HaveLen(1))
+			Ω(allocations.Quarantined[0].HostPort).Should(BeEquivalentTo(61002))
+			Ω(allocations.Quarantined[0].Until).Should(BeTemporally("==", until))
+		})
+	})
+
+	Describe("Warming an image", func() {
+		credentials := &garden.RegistryCredentials{Username: "user", Password: "secret"}
 
 		BeforeEach(func() {
-			status = 200
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/images"),
+					ghttp.VerifyJSONRepresenting(transport.WarmImageRequest{URI: "docker:///busybox", RegistryCredentials: credentials}),
+					ghttp.RespondWith(200, marshalProto(&garden.CachedImage{URI: "docker:///busybox", SizeInBytes: 1024}))))
 		})
 
-		JustBeforeEach(func() {
+		It("should return the cached image", func() {
+			Ω(connection.Warm("docker:///busybox", credentials)).Should(Equal(garden.CachedImage{URI: "docker:///busybox", SizeInBytes: 1024}))
+		})
+	})
+
+	Describe("Listing images", func() {
+		BeforeEach(func() {
 			server.AppendHandlers(
 				ghttp.CombineHandlers(
-					ghttp.VerifyRequest("GET", fmt.Sprintf("/containers/%s/metrics", handle)),
-					ghttp.RespondWith(status, marshalProto(metrics))))
+					ghttp.VerifyRequest("GET", "/images"),
+					ghttp.RespondWith(200, marshalProto([]garden.CachedImage{{URI: "docker:///busybox", Containers: 2}}))))
 		})
 
-		It("returns the MemoryStat, CPUStat and DiskStat", func() {
-			returnedMetrics, err := connection.Metrics(handle)
+		It("should return the cached images", func() {
+			Ω(connection.ListImages()).Should(Equal([]garden.CachedImage{{URI: "docker:///busybox", Containers: 2}}))
+		})
+	})
 
-			Ω(err).ShouldNot(HaveOccurred())
-			Ω(returnedMetrics).Should(Equal(metrics))
+	Describe("Removing an image", func() {
+		Context("when the image is cached", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("DELETE", "/images", "uri=docker%3A%2F%2F%2Fbusybox%23latest"),
+						ghttp.RespondWith(200, "{}")))
+			})
+
+			It("should send the URI as a query parameter", func() {
+				Ω(connection.RemoveImage("docker:///busybox#latest")).Should(Succeed())
+			})
 		})
 
-		Context("when getting container metrics fails", func() {
+		Context("when the image is not cached", func() {
 			BeforeEach(func() {
-				status = 400
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("DELETE", "/images"),
+						ghttp.RespondWith(404, marshalProto(garden.Error{Err: garden.ImageNotFoundError{URI: "docker:///busybox"}}))))
 			})
 
-			It("returns an error", func() {
-				_, err := connection.Metrics(handle)
-				Ω(err).Should(HaveOccurred())
+			It("should return an ImageNotFoundError", func() {
+				Ω(connection.RemoveImage("docker:///busybox")).Should(Equal(garden.ImageNotFoundError{URI: "docker:///busybox"}))
 			})
 		})
 	})
 
-	Describe("Setting the grace time", func() {
-		var (
-			status    int
-			handle    string
-			graceTime time.Duration
-		)
+	Describe("Listing expirations", func() {
+		expiresAt := time.Date(2016, 1, 2, 3, 4, 5, 0, time.UTC)
 
 		BeforeEach(func() {
-			handle = "container-handle"
-			graceTime = time.Second * 5
-			status = 200
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/expirations"),
+					ghttp.RespondWith(200, marshalProto(&struct {
+						Expirations []garden.Expiration `json:"expirations"`
+					}{
+						[]garden.Expiration{
+							{Handle: "container1", ExpiresAt: expiresAt, Reason: garden.ExpirationReasonTTL},
+						},
+					}))))
 		})
 
-		JustBeforeEach(func() {
+		It("should return the expirations", func() {
+			expirations, err := connection.Expirations()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(expirations).Should(HaveLen(1))
+			Ω(expirations[0].Handle).Should(Equal("container1"))
+			Ω(expirations[0].ExpiresAt).Should(BeTemporally("==", expiresAt))
+			Ω(expirations[0].Reason).Should(Equal(garden.ExpirationReasonTTL))
+		})
+	})
+
+	Describe("Getting process metrics", func() {
+		metrics := map[string]garden.ProcessMetrics{
+			"some-process": {CPUStat: garden.ContainerCPUStat{Usage: 3}, MemoryUsageInBytes: 1024},
+		}
+
+		BeforeEach(func() {
 			server.AppendHandlers(
 				ghttp.CombineHandlers(
-					ghttp.VerifyRequest("PUT", fmt.Sprintf("/containers/%s/grace_time", handle)),
-					// interface{} confusion: the JSON decoder decodes numberics to float64...
-					verifyRequestBody(float64(graceTime), float64(0)),
-					ghttp.RespondWith(status, "{}"),
-				),
-			)
+					ghttp.VerifyRequest("GET", "/containers/foo/processes/metrics"),
+					ghttp.RespondWith(200, marshalProto(metrics))))
 		})
 
-		It("send SetGraceTime request", func() {
-			Ω(connection.SetGraceTime(handle, graceTime)).Should(Succeed())
+		It("should return the metrics of each process", func() {
+			value, err := connection.ProcessMetrics("foo")
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(value).Should(Equal(metrics))
 		})
+	})
 
-		Context("when setting grace time fails", func() {
-			BeforeEach(func() {
-				status = 400
-			})
+	Describe("Getting disk usage", func() {
+		usage := []garden.PathDiskUsage{
+			{Path: "/var/log", BytesUsed: 1024},
+			{Path: "/tmp", BytesUsed: 2048},
+		}
 
-			It("returns an error", func() {
-				Ω(connection.SetGraceTime(handle, graceTime)).ShouldNot(Succeed())
-			})
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/containers/foo/disk_usage", "path=%2Fvar%2Flog&path=%2Ftmp"),
+					ghttp.RespondWith(200, marshalProto(usage))))
+		})
+
+		It("should return the usage of each path", func() {
+			value, err := connection.DiskUsage("foo", []string{"/var/log", "/tmp"})
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(value).Should(Equal(usage))
+		})
+	})
+
+	Describe("Getting the handle scheme", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/handle_scheme"),
+					ghttp.RespondWith(200, marshalProto(garden.HandleScheme{Prefix: "app-", UUIDv7: true}))))
+		})
+
+		It("should return the scheme", func() {
+			scheme, err := connection.HandleScheme()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(scheme).Should(Equal(garden.HandleScheme{Prefix: "app-", UUIDv7: true}))
 		})
 	})
 
@@ -654,6 +1833,10 @@ var _ = Describe("Connection", func() {
 				},
 				HostIP:        "host-ip",
 				ContainerIP:   "container-ip",
+				ContainerIPv6: "fd00::2",
+				ExternalIPv6:  "2001:db8::2",
+				Hostname:      "some-hostname",
+				LSMProfile:    "garden-untrusted",
 				ContainerPath: "container-path",
 				ProcessIDs:    []string{"process-handle-1", "process-handle-2"},
 				Properties: garden.Properties{
@@ -752,6 +1935,19 @@ var _ = Describe("Connection", func() {
 				Ω(bulkInfo).Should(Equal(expectedBulkInfo))
 			})
 		})
+
+		Context("when a snapshot is requested", func() {
+			It("passes the snapshot query param", func() {
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", "/containers/bulk_info", queryParams+"&snapshot=true"),
+						ghttp.RespondWith(200, marshalProto(expectedBulkInfo))))
+
+				bulkInfo, err := connection.BulkInfoWithOptions(handles, garden.BulkOptions{Snapshot: true})
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(bulkInfo).Should(Equal(expectedBulkInfo))
+			})
+		})
 	})
 
 	Describe("BulkMetrics", func() {
@@ -841,6 +2037,54 @@ var _ = Describe("Connection", func() {
 		})
 	})
 
+	Describe("BulkMetricsDelta", func() {
+		handles := []string{"handle1", "handle2"}
+
+		expectedDelta := garden.BulkMetricsDelta{
+			Token: "next-token",
+			Entries: map[string]garden.ContainerMetricsEntry{
+				"handle2": garden.ContainerMetricsEntry{
+					Metrics: garden.Metrics{
+						DiskStat: garden.ContainerDiskStat{TotalBytesUsed: 6},
+					},
+				},
+			},
+		}
+
+		It("sends the token of the previous response and returns the changed entries", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/containers/bulk_metrics/delta", "handles=handle1%2Chandle2&since=some-token"),
+					ghttp.RespondWith(200, marshalProto(expectedDelta))))
+
+			delta, err := connection.BulkMetricsDelta(handles, garden.BulkOptions{}, "some-token")
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(delta).Should(Equal(expectedDelta))
+		})
+
+		It("sends no token if none is given", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/containers/bulk_metrics/delta", "handles=handle1%2Chandle2&snapshot=true"),
+					ghttp.RespondWith(200, marshalProto(expectedDelta))))
+
+			_, err := connection.BulkMetricsDelta(handles, garden.BulkOptions{Snapshot: true}, "")
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		Context("when the request fails", func() {
+			It("returns the error", func() {
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", "/containers/bulk_metrics/delta"),
+						ghttp.RespondWith(500, "")))
+
+				_, err := connection.BulkMetricsDelta(handles, garden.BulkOptions{}, "some-token")
+				Ω(err).Should(HaveOccurred())
+			})
+		})
+	})
+
 	Describe("Streaming in", func() {
 		Context("when streaming in succeeds", func() {
 			BeforeEach(func() {
@@ -908,6 +2152,24 @@ var _ = Describe("Connection", func() {
 				Ω(server.ReceivedRequests()).Should(HaveLen(1))
 			})
 		})
+
+		Context("when the stream is larger than the server allows", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("PUT", "/containers/foo-handle/files"),
+						ghttp.RespondWith(http.StatusRequestEntityTooLarge, `{"Type":"PayloadTooLargeError","Limit":8}`),
+					),
+				)
+			})
+
+			It("returns a PayloadTooLargeError", func() {
+				buffer := bytes.NewBufferString("chunk-1chunk-2")
+
+				err := connection.StreamIn("foo-handle", garden.StreamInSpec{User: "bob", Path: "/bar", TarStream: buffer})
+				Ω(err).Should(Equal(garden.PayloadTooLargeError{Limit: 8}))
+			})
+		})
 	})
 
 	Describe("Streaming Out", func() {
@@ -954,6 +2216,89 @@ var _ = Describe("Connection", func() {
 				Ω(err).Should(HaveOccurred())
 			})
 		})
+
+		Context("when the stream is cut off part way through", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", "/containers/foo-handle/files", "user=frank&source=%2Fbar"),
+						func(w http.ResponseWriter, r *http.Request) {
+							conn, _, err := w.(http.Hijacker).Hijack()
+							Ω(err).ShouldNot(HaveOccurred())
+							defer conn.Close()
+
+							fmt.Fprint(conn, "HTTP/1.1 200 OK\r\nTransfer-Encoding: chunked\r\n\r\n5\r\nhello\r\n")
+						},
+					),
+				)
+			})
+
+			It("returns a TruncatedStreamError with the offset reached", func() {
+				reader, err := connection.StreamOut("foo-handle", garden.StreamOutSpec{User: "frank", Path: "/bar"})
+				Ω(err).ShouldNot(HaveOccurred())
+				defer reader.Close()
+
+				readBytes, err := ioutil.ReadAll(reader)
+				Ω(readBytes).Should(Equal([]byte("hello")))
+				Ω(err).Should(BeAssignableToTypeOf(TruncatedStreamError{}))
+				Ω(err.(TruncatedStreamError).Offset).Should(BeEquivalentTo(5))
+			})
+		})
+
+		Context("when the deadline passes before the stream completes", func() {
+			var unblock chan struct{}
+
+			BeforeEach(func() {
+				unblock = make(chan struct{})
+
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", "/containers/foo-handle/files", "user=frank&source=%2Fbar"),
+						func(w http.ResponseWriter, r *http.Request) {
+							w.Write([]byte("hello"))
+							w.(http.Flusher).Flush()
+							<-unblock
+						},
+					),
+				)
+			})
+
+			AfterEach(func() {
+				close(unblock)
+			})
+
+			It("returns a TruncatedStreamError caused by the deadline", func() {
+				reader, err := connection.StreamOut("foo-handle", garden.StreamOutSpec{
+					User:     "frank",
+					Path:     "/bar",
+					Deadline: time.Now().Add(100 * time.Millisecond),
+				})
+				Ω(err).ShouldNot(HaveOccurred())
+				defer reader.Close()
+
+				_, err = ioutil.ReadAll(reader)
+				Ω(err).Should(Equal(TruncatedStreamError{Offset: 5, Cause: ErrStreamDeadlineExceeded}))
+			})
+		})
+
+		Context("when resuming from an entry", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", "/containers/foo-handle/files", "resume_from=some%2Fentry&source=%2Fbar&user=frank"),
+						ghttp.RespondWith(200, "rest"),
+					),
+				)
+			})
+
+			It("asks garden to resume from the entry", func() {
+				reader, err := connection.StreamOut("foo-handle", garden.StreamOutSpec{User: "frank", Path: "/bar", ResumeFrom: "some/entry"})
+				Ω(err).ShouldNot(HaveOccurred())
+				defer reader.Close()
+
+				Ω(ioutil.ReadAll(reader)).Should(Equal([]byte("rest")))
+			})
+		})
 	})
 
 	Describe("Running", func() {
@@ -962,6 +2307,22 @@ var _ = Describe("Connection", func() {
 			stdInContent chan string
 		)
 
+		Context("when the spec is larger than the server allows", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("POST", "/containers/foo-handle/processes"),
+						ghttp.RespondWith(http.StatusRequestEntityTooLarge, `{"Type":"PayloadTooLargeError","Limit":64}`),
+					),
+				)
+			})
+
+			It("returns a PayloadTooLargeError", func() {
+				_, err := connection.Run("foo-handle", garden.ProcessSpec{Path: "lol"}, garden.ProcessIO{})
+				Ω(err).Should(Equal(garden.PayloadTooLargeError{Limit: 64}))
+			})
+		})
+
 		Context("when streaming succeeds to completion", func() {
 			BeforeEach(func() {
 				spec = garden.ProcessSpec{
@@ -1151,7 +2512,60 @@ var _ = Describe("Connection", func() {
 
 				status, err := process.Wait()
 				Ω(err).ShouldNot(HaveOccurred())
-				Ω(status).Should(Equal(3))
+				Ω(status).Should(Equal(3))
+			})
+		})
+
+		Context("when the process's stdin is closed", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("POST", "/containers/foo-handle/processes"),
+						func(w http.ResponseWriter, r *http.Request) {
+							w.WriteHeader(http.StatusOK)
+
+							conn, br, err := w.(http.Hijacker).Hijack()
+							Ω(err).ShouldNot(HaveOccurred())
+
+							defer conn.Close()
+
+							decoder := json.NewDecoder(br)
+
+							transport.WriteMessage(conn, map[string]interface{}{
+								"process_id": "process-handle",
+								"stream_id":  "123",
+							})
+
+							var payload map[string]interface{}
+							err = decoder.Decode(&payload)
+							Ω(err).ShouldNot(HaveOccurred())
+
+							Ω(payload).Should(Equal(map[string]interface{}{
+								"process_id":  "process-handle",
+								"close_stdin": true,
+							}))
+
+							transport.WriteMessage(conn, map[string]interface{}{
+								"process_id":  "process-handle",
+								"exit_status": 0,
+							})
+						},
+					),
+				)
+			})
+
+			It("sends the appropriate protocol message", func() {
+				pipeR, _ := io.Pipe()
+				process, err := connection.Run("foo-handle", garden.ProcessSpec{}, garden.ProcessIO{
+					Stdin: pipeR,
+				})
+				Ω(err).ShouldNot(HaveOccurred())
+
+				Ω(process.CloseStdin()).Should(Succeed())
+
+				status, err := process.Wait()
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(status).Should(Equal(0))
 			})
 		})
 
@@ -1428,6 +2842,292 @@ var _ = Describe("Connection", func() {
 			})
 		})
 
+		Context("when the connection stops sending heartbeats", func() {
+			var silent chan struct{}
+
+			BeforeEach(func() {
+				silent = make(chan struct{})
+
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("POST", "/containers/foo-handle/processes"),
+						func(w http.ResponseWriter, r *http.Request) {
+							w.WriteHeader(http.StatusOK)
+
+							conn, _, err := w.(http.Hijacker).Hijack()
+							Ω(err).ShouldNot(HaveOccurred())
+
+							defer conn.Close()
+
+							transport.WriteMessage(conn, map[string]interface{}{
+								"process_id": "process-handle",
+								"stream_id":  "123",
+							})
+
+							transport.WriteMessage(conn, map[string]interface{}{
+								"process_id": "process-handle",
+								"heartbeat":  50 * time.Millisecond,
+							})
+
+							<-silent
+						},
+					),
+				)
+			})
+
+			AfterEach(func() {
+				close(silent)
+			})
+
+			Describe("waiting on the process", func() {
+				It("returns ErrStreamStalled", func() {
+					process, err := connection.Run("foo-handle", garden.ProcessSpec{
+						Path: "lol",
+					}, garden.ProcessIO{})
+
+					Ω(err).ShouldNot(HaveOccurred())
+
+					_, err = process.Wait()
+					Ω(err).Should(Equal(ErrStreamStalled))
+				})
+			})
+		})
+
+		Context("when heartbeats carry an idle timeout", func() {
+			var echoed chan transport.ProcessPayload
+
+			BeforeEach(func() {
+				echoed = make(chan transport.ProcessPayload, 1)
+
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("POST", "/containers/foo-handle/processes"),
+						func(w http.ResponseWriter, r *http.Request) {
+							w.WriteHeader(http.StatusOK)
+
+							conn, br, err := w.(http.Hijacker).Hijack()
+							Ω(err).ShouldNot(HaveOccurred())
+
+							defer conn.Close()
+
+							transport.WriteMessage(conn, map[string]interface{}{
+								"process_id": "process-handle",
+								"stream_id":  "123",
+							})
+
+							transport.WriteMessage(conn, map[string]interface{}{
+								"process_id":   "process-handle",
+								"heartbeat":    time.Second,
+								"idle_timeout": 5 * time.Second,
+							})
+
+							var payload transport.ProcessPayload
+							Ω(json.NewDecoder(br).Decode(&payload)).Should(Succeed())
+							echoed <- payload
+
+							transport.WriteMessage(conn, map[string]interface{}{
+								"process_id":  "process-handle",
+								"exit_status": 0,
+							})
+						},
+					),
+				)
+			})
+
+			It("echoes the heartbeats back", func() {
+				process, err := connection.Run("foo-handle", garden.ProcessSpec{
+					Path: "lol",
+				}, garden.ProcessIO{})
+				Ω(err).ShouldNot(HaveOccurred())
+
+				var payload transport.ProcessPayload
+				Eventually(echoed).Should(Receive(&payload))
+				Ω(payload.ProcessID).Should(Equal("process-handle"))
+				Ω(payload.Heartbeat).ShouldNot(BeNil())
+				Ω(*payload.Heartbeat).Should(Equal(time.Second))
+
+				Ω(process.Wait()).Should(Equal(0))
+			})
+		})
+
+		Context("when payloads are to be encoded in binary", func() {
+			var stdinPayloads chan *transport.ProcessPayload
+
+			BeforeEach(func() {
+				stdinPayloads = make(chan *transport.ProcessPayload, 1)
+
+				query := url.Values{routes.PayloadContentTypeParam: []string{transport.BinaryPayloadContentType}}
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("POST", "/containers/foo-handle/processes", query.Encode()),
+						func(w http.ResponseWriter, r *http.Request) {
+							w.WriteHeader(http.StatusOK)
+
+							conn, br, err := w.(http.Hijacker).Hijack()
+							Ω(err).ShouldNot(HaveOccurred())
+
+							defer conn.Close()
+
+							transport.WriteMessage(conn, &transport.ProcessPayload{
+								ProcessID:   "process-handle",
+								ContentType: transport.BinaryPayloadContentType,
+							})
+
+							payload := &transport.ProcessPayload{}
+							Ω(transport.NewPayloadDecoder(br, transport.BinaryPayloadContentType, true).Decode(payload)).Should(Succeed())
+							stdinPayloads <- payload
+
+							status := 3
+							Ω(transport.NewPayloadEncoder(conn, transport.BinaryPayloadContentType).Encode(&transport.ProcessPayload{
+								ProcessID:  "process-handle",
+								ExitStatus: &status,
+							})).Should(Succeed())
+						},
+					),
+				)
+			})
+
+			JustBeforeEach(func() {
+				connection = NewWithHijacker(hijacker, logger, WithPayloadContentType(transport.BinaryPayloadContentType))
+			})
+
+			It("asks for and exchanges binary payloads once the server confirms them", func() {
+				process, err := connection.Run("foo-handle", garden.ProcessSpec{Path: "lol"}, garden.ProcessIO{
+					Stdin: bytes.NewBufferString("stdin data"),
+				})
+				Ω(err).ShouldNot(HaveOccurred())
+
+				var payload *transport.ProcessPayload
+				Eventually(stdinPayloads).Should(Receive(&payload))
+				Ω(payload.ProcessID).Should(Equal("process-handle"))
+				Ω(*payload.Source).Should(Equal(transport.Stdin))
+				Ω(*payload.Data).Should(Equal("stdin data"))
+
+				Ω(process.Wait()).Should(Equal(3))
+			})
+		})
+
+		Context("when the output is interleaved", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("POST", "/containers/foo-handle/processes", "interleave=true"),
+						func(w http.ResponseWriter, r *http.Request) {
+							w.WriteHeader(http.StatusOK)
+
+							conn, _, err := w.(http.Hijacker).Hijack()
+							Ω(err).ShouldNot(HaveOccurred())
+
+							defer conn.Close()
+
+							transport.WriteMessage(conn, map[string]interface{}{
+								"process_id": "process-handle",
+								"stream_id":  "123",
+							})
+
+							transport.WriteMessage(conn, map[string]interface{}{
+								"process_id":  "process-handle",
+								"exit_status": 0,
+							})
+						},
+					),
+					stdoutStream("foo-handle", "process-handle", 123, func(conn net.Conn) {
+						conn.Write([]byte("2016-01-02T03:04:05Z stderr oops\n"))
+					}),
+				)
+			})
+
+			It("requests interleaving and only streams stdout", func() {
+				stdout := gbytes.NewBuffer()
+				stderr := gbytes.NewBuffer()
+
+				process, err := connection.Run("foo-handle", garden.ProcessSpec{
+					Path: "lol",
+				}, garden.ProcessIO{
+					Stdout:     stdout,
+					Stderr:     stderr,
+					Interleave: true,
+				})
+				Ω(err).ShouldNot(HaveOccurred())
+
+				Eventually(stdout).Should(gbytes.Say("2016-01-02T03:04:05Z stderr oops\n"))
+
+				_, err = process.Wait()
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(stderr.Contents()).Should(BeEmpty())
+			})
+		})
+
+		Context("when waiting with a context", func() {
+			var silent chan struct{}
+
+			type contextWaiter interface {
+				WaitWithContext(ctx context.Context) (int, error)
+			}
+
+			BeforeEach(func() {
+				silent = make(chan struct{})
+
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("POST", "/containers/foo-handle/processes"),
+						func(w http.ResponseWriter, r *http.Request) {
+							w.WriteHeader(http.StatusOK)
+
+							conn, _, err := w.(http.Hijacker).Hijack()
+							Ω(err).ShouldNot(HaveOccurred())
+
+							defer conn.Close()
+
+							transport.WriteMessage(conn, map[string]interface{}{
+								"process_id": "process-handle",
+								"stream_id":  "123",
+							})
+
+							<-silent
+
+							transport.WriteMessage(conn, map[string]interface{}{
+								"process_id":  "process-handle",
+								"exit_status": 42,
+							})
+						},
+					),
+				)
+			})
+
+			It("returns a WaitCancelledError when the context is cancelled", func() {
+				process, err := connection.Run("foo-handle", garden.ProcessSpec{
+					Path: "lol",
+				}, garden.ProcessIO{})
+				Ω(err).ShouldNot(HaveOccurred())
+
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				_, err = process.(contextWaiter).WaitWithContext(ctx)
+				Ω(err).Should(Equal(WaitCancelledError{Cause: context.Canceled}))
+
+				close(silent)
+
+				status, err := process.Wait()
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(status).Should(Equal(42))
+			})
+
+			It("returns the exit status when the process exits first", func() {
+				process, err := connection.Run("foo-handle", garden.ProcessSpec{
+					Path: "lol",
+				}, garden.ProcessIO{})
+				Ω(err).ShouldNot(HaveOccurred())
+
+				close(silent)
+
+				status, err := process.(contextWaiter).WaitWithContext(context.Background())
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(status).Should(Equal(42))
+			})
+		})
+
 		Context("when the connection returns an error status", func() {
 			BeforeEach(func() {
 				server.AppendHandlers(ghttp.CombineHandlers(
@@ -1448,6 +3148,91 @@ var _ = Describe("Connection", func() {
 		})
 	})
 
+	Describe("Setting the TTY of a process", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("PUT", "/containers/foo/processes/process-id/tty"),
+					verifyRequestBody(map[string]interface{}{
+						"window_size": map[string]interface{}{
+							"columns": float64(80),
+							"rows":    float64(24),
+						},
+					}, make(map[string]interface{})),
+					ghttp.RespondWith(200, "{}")))
+		})
+
+		It("should set the TTY of the process", func() {
+			err := connection.SetTTY("foo", "process-id", garden.TTYSpec{
+				WindowSize: &garden.WindowSize{Columns: 80, Rows: 24},
+			})
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+	})
+
+	Describe("Signalling a process", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("PUT", "/containers/foo/processes/process-id/signal"),
+					verifyRequestBody(map[string]interface{}{
+						"signal": float64(garden.SignalUser1),
+					}, make(map[string]interface{})),
+					ghttp.RespondWith(200, "{}")))
+		})
+
+		It("should signal the process", func() {
+			err := connection.Signal("foo", "process-id", garden.SignalUser1)
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+	})
+
+	Describe("Signalling a process by name", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("PUT", "/containers/foo/processes/web/signal", "by_name=true"),
+					verifyRequestBody(map[string]interface{}{
+						"signal": float64(garden.SignalTerminate),
+					}, make(map[string]interface{})),
+					ghttp.RespondWith(200, "{}")))
+		})
+
+		It("should signal the process", func() {
+			err := connection.SignalByName("foo", "web", garden.SignalTerminate)
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+	})
+
+	Describe("Getting the exit of a process by name", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/containers/foo/processes/web/exit", "by_name=true"),
+					ghttp.RespondWith(200, marshalProto(garden.ProcessExit{ProcessID: "process-id", ExitStatus: 3}))))
+		})
+
+		It("should return the exit", func() {
+			Ω(connection.ProcessExitByName("foo", "web")).Should(Equal(garden.ProcessExit{ProcessID: "process-id", ExitStatus: 3}))
+		})
+	})
+
+	Describe("Running detached", func() {
+		spec := garden.ProcessSpec{Path: "/some/job", Args: []string{"--once"}}
+
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/containers/foo-handle/processes/detached"),
+					ghttp.VerifyJSONRepresenting(spec),
+					ghttp.RespondWith(200, marshalProto(&transport.RunDetachedResponse{ProcessID: "process-handle"}))))
+		})
+
+		It("should return the process ID", func() {
+			Ω(connection.RunDetached("foo-handle", spec)).Should(Equal("process-handle"))
+		})
+	})
+
 	Describe("Attaching", func() {
 		Context("when streaming succeeds to completion", func() {
 			BeforeEach(func() {
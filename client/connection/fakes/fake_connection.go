@@ -2,6 +2,7 @@
 package fakes
 
 import (
+	"context"
 	"io"
 	"sync"
 	"time"
@@ -105,35 +106,32 @@ type FakeConnection struct {
 		result1 io.ReadCloser
 		result2 error
 	}
-	LimitBandwidthStub        func(handle string, limits garden.BandwidthLimits) (garden.BandwidthLimits, error)
+	LimitBandwidthStub        func(handle string, limits garden.BandwidthLimits) error
 	limitBandwidthMutex       sync.RWMutex
 	limitBandwidthArgsForCall []struct {
 		handle string
 		limits garden.BandwidthLimits
 	}
 	limitBandwidthReturns struct {
-		result1 garden.BandwidthLimits
-		result2 error
+		result1 error
 	}
-	LimitCPUStub        func(handle string, limits garden.CPULimits) (garden.CPULimits, error)
+	LimitCPUStub        func(handle string, limits garden.CPULimits) error
 	limitCPUMutex       sync.RWMutex
 	limitCPUArgsForCall []struct {
 		handle string
 		limits garden.CPULimits
 	}
 	limitCPUReturns struct {
-		result1 garden.CPULimits
-		result2 error
+		result1 error
 	}
-	LimitMemoryStub        func(handle string, limit garden.MemoryLimits) (garden.MemoryLimits, error)
+	LimitMemoryStub        func(handle string, limit garden.MemoryLimits) error
 	limitMemoryMutex       sync.RWMutex
 	limitMemoryArgsForCall []struct {
 		handle string
 		limit  garden.MemoryLimits
 	}
 	limitMemoryReturns struct {
-		result1 garden.MemoryLimits
-		result2 error
+		result1 error
 	}
 	CurrentBandwidthLimitsStub        func(handle string) (garden.BandwidthLimits, error)
 	currentBandwidthLimitsMutex       sync.RWMutex
@@ -270,6 +268,524 @@ type FakeConnection struct {
 	removePropertyReturns struct {
 		result1 error
 	}
+	BulkInfoWithOptionsStub        func(handles []string, opts garden.BulkOptions) (map[string]garden.ContainerInfoEntry, error)
+	bulkInfoWithOptionsMutex       sync.RWMutex
+	bulkInfoWithOptionsArgsForCall []struct {
+		handles []string
+		opts    garden.BulkOptions
+	}
+	bulkInfoWithOptionsReturns struct {
+		result1 map[string]garden.ContainerInfoEntry
+		result2 error
+	}
+	BulkMetricsWithOptionsStub        func(handles []string, opts garden.BulkOptions) (map[string]garden.ContainerMetricsEntry, error)
+	bulkMetricsWithOptionsMutex       sync.RWMutex
+	bulkMetricsWithOptionsArgsForCall []struct {
+		handles []string
+		opts    garden.BulkOptions
+	}
+	bulkMetricsWithOptionsReturns struct {
+		result1 map[string]garden.ContainerMetricsEntry
+		result2 error
+	}
+	SetTTYStub        func(handle string, processID string, spec garden.TTYSpec) error
+	setTTYMutex       sync.RWMutex
+	setTTYArgsForCall []struct {
+		handle    string
+		processID string
+		spec      garden.TTYSpec
+	}
+	setTTYReturns struct {
+		result1 error
+	}
+	SignalStub        func(handle string, processID string, signal garden.Signal) error
+	signalMutex       sync.RWMutex
+	signalArgsForCall []struct {
+		handle    string
+		processID string
+		signal    garden.Signal
+	}
+	signalReturns struct {
+		result1 error
+	}
+	ListWithOptionsStub        func(properties garden.Properties, opts garden.ListOptions) ([]string, error)
+	listWithOptionsMutex       sync.RWMutex
+	listWithOptionsArgsForCall []struct {
+		properties garden.Properties
+		opts       garden.ListOptions
+	}
+	listWithOptionsReturns struct {
+		result1 []string
+		result2 error
+	}
+	ExpirationsStub        func() ([]garden.Expiration, error)
+	expirationsMutex       sync.RWMutex
+	expirationsArgsForCall []struct{}
+	expirationsReturns     struct {
+		result1 []garden.Expiration
+		result2 error
+	}
+	HostResourcesStub        func(handle string) (garden.HostResources, error)
+	hostResourcesMutex       sync.RWMutex
+	hostResourcesArgsForCall []struct {
+		handle string
+	}
+	hostResourcesReturns struct {
+		result1 garden.HostResources
+		result2 error
+	}
+	DestroyDryRunStub        func(handle string) (garden.DestroyImpact, error)
+	destroyDryRunMutex       sync.RWMutex
+	destroyDryRunArgsForCall []struct {
+		handle string
+	}
+	destroyDryRunReturns struct {
+		result1 garden.DestroyImpact
+		result2 error
+	}
+	PauseStub        func(handle string) error
+	pauseMutex       sync.RWMutex
+	pauseArgsForCall []struct {
+		handle string
+	}
+	pauseReturns struct {
+		result1 error
+	}
+	ResumeStub        func(handle string) error
+	resumeMutex       sync.RWMutex
+	resumeArgsForCall []struct {
+		handle string
+	}
+	resumeReturns struct {
+		result1 error
+	}
+	PortAllocationsStub        func() (garden.PortAllocations, error)
+	portAllocationsMutex       sync.RWMutex
+	portAllocationsArgsForCall []struct{}
+	portAllocationsReturns     struct {
+		result1 garden.PortAllocations
+		result2 error
+	}
+	CheckpointStub        func(handle string, destination string) error
+	checkpointMutex       sync.RWMutex
+	checkpointArgsForCall []struct {
+		handle      string
+		destination string
+	}
+	checkpointReturns struct {
+		result1 error
+	}
+	RestoreStub        func(spec garden.ContainerSpec, source string) (string, error)
+	restoreMutex       sync.RWMutex
+	restoreArgsForCall []struct {
+		spec   garden.ContainerSpec
+		source string
+	}
+	restoreReturns struct {
+		result1 string
+		result2 error
+	}
+	EventsStub        func(ctx context.Context) (<-chan garden.Event, error)
+	eventsMutex       sync.RWMutex
+	eventsArgsForCall []struct {
+		ctx context.Context
+	}
+	eventsReturns struct {
+		result1 <-chan garden.Event
+		result2 error
+	}
+	ProcessesStub        func(handle string) ([]garden.ProcessInfo, error)
+	processesMutex       sync.RWMutex
+	processesArgsForCall []struct {
+		handle string
+	}
+	processesReturns struct {
+		result1 []garden.ProcessInfo
+		result2 error
+	}
+	ProcessExitStub        func(handle string, processID string) (garden.ProcessExit, error)
+	processExitMutex       sync.RWMutex
+	processExitArgsForCall []struct {
+		handle    string
+		processID string
+	}
+	processExitReturns struct {
+		result1 garden.ProcessExit
+		result2 error
+	}
+	BulkDestroyStub        func(handles []string) (map[string]garden.ContainerDestroyEntry, error)
+	bulkDestroyMutex       sync.RWMutex
+	bulkDestroyArgsForCall []struct {
+		handles []string
+	}
+	bulkDestroyReturns struct {
+		result1 map[string]garden.ContainerDestroyEntry
+		result2 error
+	}
+	ReconciliationStub        func() (garden.Reconciliation, error)
+	reconciliationMutex       sync.RWMutex
+	reconciliationArgsForCall []struct{}
+	reconciliationReturns     struct {
+		result1 garden.Reconciliation
+		result2 error
+	}
+	BulkStopStub        func(handles []string, kill bool) (map[string]garden.ContainerStopEntry, error)
+	bulkStopMutex       sync.RWMutex
+	bulkStopArgsForCall []struct {
+		handles []string
+		kill    bool
+	}
+	bulkStopReturns struct {
+		result1 map[string]garden.ContainerStopEntry
+		result2 error
+	}
+	BulkCreateStub        func(specs []garden.ContainerSpec) ([]garden.ContainerCreateEntry, error)
+	bulkCreateMutex       sync.RWMutex
+	bulkCreateArgsForCall []struct {
+		specs []garden.ContainerSpec
+	}
+	bulkCreateReturns struct {
+		result1 []garden.ContainerCreateEntry
+		result2 error
+	}
+	StopWithTimeoutStub        func(handle string, timeout time.Duration) error
+	stopWithTimeoutMutex       sync.RWMutex
+	stopWithTimeoutArgsForCall []struct {
+		handle  string
+		timeout time.Duration
+	}
+	stopWithTimeoutReturns struct {
+		result1 error
+	}
+	CurrentLimitsStub        func(handle string) (garden.Limits, error)
+	currentLimitsMutex       sync.RWMutex
+	currentLimitsArgsForCall []struct {
+		handle string
+	}
+	currentLimitsReturns struct {
+		result1 garden.Limits
+		result2 error
+	}
+	LimitAllStub        func(handle string, limits garden.Limits) error
+	limitAllMutex       sync.RWMutex
+	limitAllArgsForCall []struct {
+		handle string
+		limits garden.Limits
+	}
+	limitAllReturns struct {
+		result1 error
+	}
+	CurrentPidLimitsStub        func(handle string) (garden.PidLimits, error)
+	currentPidLimitsMutex       sync.RWMutex
+	currentPidLimitsArgsForCall []struct {
+		handle string
+	}
+	currentPidLimitsReturns struct {
+		result1 garden.PidLimits
+		result2 error
+	}
+	LimitPidsStub        func(handle string, limits garden.PidLimits) error
+	limitPidsMutex       sync.RWMutex
+	limitPidsArgsForCall []struct {
+		handle string
+		limits garden.PidLimits
+	}
+	limitPidsReturns struct {
+		result1 error
+	}
+	CurrentBlockIOLimitsStub        func(handle string) (garden.BlockIOLimits, error)
+	currentBlockIOLimitsMutex       sync.RWMutex
+	currentBlockIOLimitsArgsForCall []struct {
+		handle string
+	}
+	currentBlockIOLimitsReturns struct {
+		result1 garden.BlockIOLimits
+		result2 error
+	}
+	LimitBlockIOStub        func(handle string, limits garden.BlockIOLimits) error
+	limitBlockIOMutex       sync.RWMutex
+	limitBlockIOArgsForCall []struct {
+		handle string
+		limits garden.BlockIOLimits
+	}
+	limitBlockIOReturns struct {
+		result1 error
+	}
+	EventsSinceStub        func(ctx context.Context, seq uint64) (<-chan garden.Event, error)
+	eventsSinceMutex       sync.RWMutex
+	eventsSinceArgsForCall []struct {
+		ctx context.Context
+		seq uint64
+	}
+	eventsSinceReturns struct {
+		result1 <-chan garden.Event
+		result2 error
+	}
+	HandleSchemeStub        func() (garden.HandleScheme, error)
+	handleSchemeMutex       sync.RWMutex
+	handleSchemeArgsForCall []struct{}
+	handleSchemeReturns     struct {
+		result1 garden.HandleScheme
+		result2 error
+	}
+	SetDescriptionStub        func(handle string, description string) error
+	setDescriptionMutex       sync.RWMutex
+	setDescriptionArgsForCall []struct {
+		handle      string
+		description string
+	}
+	setDescriptionReturns struct {
+		result1 error
+	}
+	ProcessMetricsStub        func(handle string) (map[string]garden.ProcessMetrics, error)
+	processMetricsMutex       sync.RWMutex
+	processMetricsArgsForCall []struct {
+		handle string
+	}
+	processMetricsReturns struct {
+		result1 map[string]garden.ProcessMetrics
+		result2 error
+	}
+	StreamMetricsStub        func(ctx context.Context, handles []string, interval time.Duration) (<-chan garden.MetricsSnapshot, error)
+	streamMetricsMutex       sync.RWMutex
+	streamMetricsArgsForCall []struct {
+		ctx      context.Context
+		handles  []string
+		interval time.Duration
+	}
+	streamMetricsReturns struct {
+		result1 <-chan garden.MetricsSnapshot
+		result2 error
+	}
+	DiskUsageStub        func(handle string, paths []string) ([]garden.PathDiskUsage, error)
+	diskUsageMutex       sync.RWMutex
+	diskUsageArgsForCall []struct {
+		handle string
+		paths  []string
+	}
+	diskUsageReturns struct {
+		result1 []garden.PathDiskUsage
+		result2 error
+	}
+	NetOutRulesStub        func(handle string) ([]garden.NetOutRule, error)
+	netOutRulesMutex       sync.RWMutex
+	netOutRulesArgsForCall []struct {
+		handle string
+	}
+	netOutRulesReturns struct {
+		result1 []garden.NetOutRule
+		result2 error
+	}
+	ProcessEnvStub        func(handle string, processID string) ([]string, error)
+	processEnvMutex       sync.RWMutex
+	processEnvArgsForCall []struct {
+		handle    string
+		processID string
+	}
+	processEnvReturns struct {
+		result1 []string
+		result2 error
+	}
+	PressureStub        func() (garden.CellPressure, error)
+	pressureMutex       sync.RWMutex
+	pressureArgsForCall []struct{}
+	pressureReturns     struct {
+		result1 garden.CellPressure
+		result2 error
+	}
+	RemoveNetOutStub        func(handle string, ruleID string) error
+	removeNetOutMutex       sync.RWMutex
+	removeNetOutArgsForCall []struct {
+		handle string
+		ruleID string
+	}
+	removeNetOutReturns struct {
+		result1 error
+	}
+	RemoveAllNetOutStub        func(handle string) error
+	removeAllNetOutMutex       sync.RWMutex
+	removeAllNetOutArgsForCall []struct {
+		handle string
+	}
+	removeAllNetOutReturns struct {
+		result1 error
+	}
+	BulkNetOutStub        func(handle string, rules []garden.NetOutRule) error
+	bulkNetOutMutex       sync.RWMutex
+	bulkNetOutArgsForCall []struct {
+		handle string
+		rules  []garden.NetOutRule
+	}
+	bulkNetOutReturns struct {
+		result1 error
+	}
+	BulkMetricsDeltaStub        func(handles []string, opts garden.BulkOptions, since string) (garden.BulkMetricsDelta, error)
+	bulkMetricsDeltaMutex       sync.RWMutex
+	bulkMetricsDeltaArgsForCall []struct {
+		handles []string
+		opts    garden.BulkOptions
+		since   string
+	}
+	bulkMetricsDeltaReturns struct {
+		result1 garden.BulkMetricsDelta
+		result2 error
+	}
+	NetInMappingsStub        func(handle string) ([]garden.PortMapping, error)
+	netInMappingsMutex       sync.RWMutex
+	netInMappingsArgsForCall []struct {
+		handle string
+	}
+	netInMappingsReturns struct {
+		result1 []garden.PortMapping
+		result2 error
+	}
+	RemoveNetInStub        func(handle string, hostPort uint32) error
+	removeNetInMutex       sync.RWMutex
+	removeNetInArgsForCall []struct {
+		handle   string
+		hostPort uint32
+	}
+	removeNetInReturns struct {
+		result1 error
+	}
+	NetInWithHostIPStub        func(handle string, hostIP string, hostPort uint32, containerPort uint32) (garden.PortMapping, error)
+	netInWithHostIPMutex       sync.RWMutex
+	netInWithHostIPArgsForCall []struct {
+		handle        string
+		hostIP        string
+		hostPort      uint32
+		containerPort uint32
+	}
+	netInWithHostIPReturns struct {
+		result1 garden.PortMapping
+		result2 error
+	}
+	WarmStub        func(imageURI string, credentials *garden.RegistryCredentials) (garden.CachedImage, error)
+	warmMutex       sync.RWMutex
+	warmArgsForCall []struct {
+		imageURI    string
+		credentials *garden.RegistryCredentials
+	}
+	warmReturns struct {
+		result1 garden.CachedImage
+		result2 error
+	}
+	ListImagesStub        func() ([]garden.CachedImage, error)
+	listImagesMutex       sync.RWMutex
+	listImagesArgsForCall []struct{}
+	listImagesReturns     struct {
+		result1 []garden.CachedImage
+		result2 error
+	}
+	RemoveImageStub        func(imageURI string) error
+	removeImageMutex       sync.RWMutex
+	removeImageArgsForCall []struct {
+		imageURI string
+	}
+	removeImageReturns struct {
+		result1 error
+	}
+	CommitStub        func(handle string, imageName string) (garden.CachedImage, error)
+	commitMutex       sync.RWMutex
+	commitArgsForCall []struct {
+		handle    string
+		imageName string
+	}
+	commitReturns struct {
+		result1 garden.CachedImage
+		result2 error
+	}
+	RunDetachedStub        func(handle string, spec garden.ProcessSpec) (string, error)
+	runDetachedMutex       sync.RWMutex
+	runDetachedArgsForCall []struct {
+		handle string
+		spec   garden.ProcessSpec
+	}
+	runDetachedReturns struct {
+		result1 string
+		result2 error
+	}
+	AttachByNameStub        func(handle string, name string, io garden.ProcessIO) (garden.Process, error)
+	attachByNameMutex       sync.RWMutex
+	attachByNameArgsForCall []struct {
+		handle string
+		name   string
+		io     garden.ProcessIO
+	}
+	attachByNameReturns struct {
+		result1 garden.Process
+		result2 error
+	}
+	SignalByNameStub        func(handle string, name string, signal garden.Signal) error
+	signalByNameMutex       sync.RWMutex
+	signalByNameArgsForCall []struct {
+		handle string
+		name   string
+		signal garden.Signal
+	}
+	signalByNameReturns struct {
+		result1 error
+	}
+	ProcessExitByNameStub        func(handle string, name string) (garden.ProcessExit, error)
+	processExitByNameMutex       sync.RWMutex
+	processExitByNameArgsForCall []struct {
+		handle string
+		name   string
+	}
+	processExitByNameReturns struct {
+		result1 garden.ProcessExit
+		result2 error
+	}
+	CASPropertyStub        func(handle string, name string, oldValue string, newValue string) error
+	cASPropertyMutex       sync.RWMutex
+	cASPropertyArgsForCall []struct {
+		handle   string
+		name     string
+		oldValue string
+		newValue string
+	}
+	cASPropertyReturns struct {
+		result1 error
+	}
+	SetPropertiesStub        func(handle string, properties garden.Properties) error
+	setPropertiesMutex       sync.RWMutex
+	setPropertiesArgsForCall []struct {
+		handle     string
+		properties garden.Properties
+	}
+	setPropertiesReturns struct {
+		result1 error
+	}
+	RemovePropertiesStub        func(handle string, names []string) error
+	removePropertiesMutex       sync.RWMutex
+	removePropertiesArgsForCall []struct {
+		handle string
+		names  []string
+	}
+	removePropertiesReturns struct {
+		result1 error
+	}
+	DrainStub        func() (garden.DrainStatus, error)
+	drainMutex       sync.RWMutex
+	drainArgsForCall []struct{}
+	drainReturns     struct {
+		result1 garden.DrainStatus
+		result2 error
+	}
+	UndrainStub        func() (garden.DrainStatus, error)
+	undrainMutex       sync.RWMutex
+	undrainArgsForCall []struct{}
+	undrainReturns     struct {
+		result1 garden.DrainStatus
+		result2 error
+	}
+	DrainStatusStub        func() (garden.DrainStatus, error)
+	drainStatusMutex       sync.RWMutex
+	drainStatusArgsForCall []struct{}
+	drainStatusReturns     struct {
+		result1 garden.DrainStatus
+		result2 error
+	}
 }
 
 func (fake *FakeConnection) Ping() error {
@@ -618,7 +1134,7 @@ func (fake *FakeConnection) StreamOutReturns(result1 io.ReadCloser, result2 erro
 	}{result1, result2}
 }
 
-func (fake *FakeConnection) LimitBandwidth(handle string, limits garden.BandwidthLimits) (garden.BandwidthLimits, error) {
+func (fake *FakeConnection) LimitBandwidth(handle string, limits garden.BandwidthLimits) error {
 	fake.limitBandwidthMutex.Lock()
 	fake.limitBandwidthArgsForCall = append(fake.limitBandwidthArgsForCall, struct {
 		handle string
@@ -628,7 +1144,7 @@ func (fake *FakeConnection) LimitBandwidth(handle string, limits garden.Bandwidt
 	if fake.LimitBandwidthStub != nil {
 		return fake.LimitBandwidthStub(handle, limits)
 	} else {
-		return fake.limitBandwidthReturns.result1, fake.limitBandwidthReturns.result2
+		return fake.limitBandwidthReturns.result1
 	}
 }
 
@@ -644,15 +1160,14 @@ func (fake *FakeConnection) LimitBandwidthArgsForCall(i int) (string, garden.Ban
 	return fake.limitBandwidthArgsForCall[i].handle, fake.limitBandwidthArgsForCall[i].limits
 }
 
-func (fake *FakeConnection) LimitBandwidthReturns(result1 garden.BandwidthLimits, result2 error) {
+func (fake *FakeConnection) LimitBandwidthReturns(result1 error) {
 	fake.LimitBandwidthStub = nil
 	fake.limitBandwidthReturns = struct {
-		result1 garden.BandwidthLimits
-		result2 error
-	}{result1, result2}
+		result1 error
+	}{result1}
 }
 
-func (fake *FakeConnection) LimitCPU(handle string, limits garden.CPULimits) (garden.CPULimits, error) {
+func (fake *FakeConnection) LimitCPU(handle string, limits garden.CPULimits) error {
 	fake.limitCPUMutex.Lock()
 	fake.limitCPUArgsForCall = append(fake.limitCPUArgsForCall, struct {
 		handle string
@@ -662,7 +1177,7 @@ func (fake *FakeConnection) LimitCPU(handle string, limits garden.CPULimits) (ga
 	if fake.LimitCPUStub != nil {
 		return fake.LimitCPUStub(handle, limits)
 	} else {
-		return fake.limitCPUReturns.result1, fake.limitCPUReturns.result2
+		return fake.limitCPUReturns.result1
 	}
 }
 
@@ -678,15 +1193,14 @@ func (fake *FakeConnection) LimitCPUArgsForCall(i int) (string, garden.CPULimits
 	return fake.limitCPUArgsForCall[i].handle, fake.limitCPUArgsForCall[i].limits
 }
 
-func (fake *FakeConnection) LimitCPUReturns(result1 garden.CPULimits, result2 error) {
+func (fake *FakeConnection) LimitCPUReturns(result1 error) {
 	fake.LimitCPUStub = nil
 	fake.limitCPUReturns = struct {
-		result1 garden.CPULimits
-		result2 error
-	}{result1, result2}
+		result1 error
+	}{result1}
 }
 
-func (fake *FakeConnection) LimitMemory(handle string, limit garden.MemoryLimits) (garden.MemoryLimits, error) {
+func (fake *FakeConnection) LimitMemory(handle string, limit garden.MemoryLimits) error {
 	fake.limitMemoryMutex.Lock()
 	fake.limitMemoryArgsForCall = append(fake.limitMemoryArgsForCall, struct {
 		handle string
@@ -696,7 +1210,7 @@ func (fake *FakeConnection) LimitMemory(handle string, limit garden.MemoryLimits
 	if fake.LimitMemoryStub != nil {
 		return fake.LimitMemoryStub(handle, limit)
 	} else {
-		return fake.limitMemoryReturns.result1, fake.limitMemoryReturns.result2
+		return fake.limitMemoryReturns.result1
 	}
 }
 
@@ -712,12 +1226,11 @@ func (fake *FakeConnection) LimitMemoryArgsForCall(i int) (string, garden.Memory
 	return fake.limitMemoryArgsForCall[i].handle, fake.limitMemoryArgsForCall[i].limit
 }
 
-func (fake *FakeConnection) LimitMemoryReturns(result1 garden.MemoryLimits, result2 error) {
+func (fake *FakeConnection) LimitMemoryReturns(result1 error) {
 	fake.LimitMemoryStub = nil
 	fake.limitMemoryReturns = struct {
-		result1 garden.MemoryLimits
-		result2 error
-	}{result1, result2}
+		result1 error
+	}{result1}
 }
 
 func (fake *FakeConnection) CurrentBandwidthLimits(handle string) (garden.BandwidthLimits, error) {
@@ -1191,4 +1704,1836 @@ func (fake *FakeConnection) RemovePropertyReturns(result1 error) {
 	}{result1}
 }
 
+func (fake *FakeConnection) BulkInfoWithOptions(handles []string, opts garden.BulkOptions) (map[string]garden.ContainerInfoEntry, error) {
+	fake.bulkInfoWithOptionsMutex.Lock()
+	fake.bulkInfoWithOptionsArgsForCall = append(fake.bulkInfoWithOptionsArgsForCall, struct {
+		handles []string
+		opts    garden.BulkOptions
+	}{handles, opts})
+	fake.bulkInfoWithOptionsMutex.Unlock()
+	if fake.BulkInfoWithOptionsStub != nil {
+		return fake.BulkInfoWithOptionsStub(handles, opts)
+	} else {
+		return fake.bulkInfoWithOptionsReturns.result1, fake.bulkInfoWithOptionsReturns.result2
+	}
+}
+
+func (fake *FakeConnection) BulkInfoWithOptionsCallCount() int {
+	fake.bulkInfoWithOptionsMutex.RLock()
+	defer fake.bulkInfoWithOptionsMutex.RUnlock()
+	return len(fake.bulkInfoWithOptionsArgsForCall)
+}
+
+func (fake *FakeConnection) BulkInfoWithOptionsArgsForCall(i int) ([]string, garden.BulkOptions) {
+	fake.bulkInfoWithOptionsMutex.RLock()
+	defer fake.bulkInfoWithOptionsMutex.RUnlock()
+	return fake.bulkInfoWithOptionsArgsForCall[i].handles, fake.bulkInfoWithOptionsArgsForCall[i].opts
+}
+
+func (fake *FakeConnection) BulkInfoWithOptionsReturns(result1 map[string]garden.ContainerInfoEntry, result2 error) {
+	fake.BulkInfoWithOptionsStub = nil
+	fake.bulkInfoWithOptionsReturns = struct {
+		result1 map[string]garden.ContainerInfoEntry
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeConnection) BulkMetricsWithOptions(handles []string, opts garden.BulkOptions) (map[string]garden.ContainerMetricsEntry, error) {
+	fake.bulkMetricsWithOptionsMutex.Lock()
+	fake.bulkMetricsWithOptionsArgsForCall = append(fake.bulkMetricsWithOptionsArgsForCall, struct {
+		handles []string
+		opts    garden.BulkOptions
+	}{handles, opts})
+	fake.bulkMetricsWithOptionsMutex.Unlock()
+	if fake.BulkMetricsWithOptionsStub != nil {
+		return fake.BulkMetricsWithOptionsStub(handles, opts)
+	} else {
+		return fake.bulkMetricsWithOptionsReturns.result1, fake.bulkMetricsWithOptionsReturns.result2
+	}
+}
+
+func (fake *FakeConnection) BulkMetricsWithOptionsCallCount() int {
+	fake.bulkMetricsWithOptionsMutex.RLock()
+	defer fake.bulkMetricsWithOptionsMutex.RUnlock()
+	return len(fake.bulkMetricsWithOptionsArgsForCall)
+}
+
+func (fake *FakeConnection) BulkMetricsWithOptionsArgsForCall(i int) ([]string, garden.BulkOptions) {
+	fake.bulkMetricsWithOptionsMutex.RLock()
+	defer fake.bulkMetricsWithOptionsMutex.RUnlock()
+	return fake.bulkMetricsWithOptionsArgsForCall[i].handles, fake.bulkMetricsWithOptionsArgsForCall[i].opts
+}
+
+func (fake *FakeConnection) BulkMetricsWithOptionsReturns(result1 map[string]garden.ContainerMetricsEntry, result2 error) {
+	fake.BulkMetricsWithOptionsStub = nil
+	fake.bulkMetricsWithOptionsReturns = struct {
+		result1 map[string]garden.ContainerMetricsEntry
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeConnection) SetTTY(handle string, processID string, spec garden.TTYSpec) error {
+	fake.setTTYMutex.Lock()
+	fake.setTTYArgsForCall = append(fake.setTTYArgsForCall, struct {
+		handle    string
+		processID string
+		spec      garden.TTYSpec
+	}{handle, processID, spec})
+	fake.setTTYMutex.Unlock()
+	if fake.SetTTYStub != nil {
+		return fake.SetTTYStub(handle, processID, spec)
+	} else {
+		return fake.setTTYReturns.result1
+	}
+}
+
+func (fake *FakeConnection) SetTTYCallCount() int {
+	fake.setTTYMutex.RLock()
+	defer fake.setTTYMutex.RUnlock()
+	return len(fake.setTTYArgsForCall)
+}
+
+func (fake *FakeConnection) SetTTYArgsForCall(i int) (string, string, garden.TTYSpec) {
+	fake.setTTYMutex.RLock()
+	defer fake.setTTYMutex.RUnlock()
+	return fake.setTTYArgsForCall[i].handle, fake.setTTYArgsForCall[i].processID, fake.setTTYArgsForCall[i].spec
+}
+
+func (fake *FakeConnection) SetTTYReturns(result1 error) {
+	fake.SetTTYStub = nil
+	fake.setTTYReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeConnection) Signal(handle string, processID string, signal garden.Signal) error {
+	fake.signalMutex.Lock()
+	fake.signalArgsForCall = append(fake.signalArgsForCall, struct {
+		handle    string
+		processID string
+		signal    garden.Signal
+	}{handle, processID, signal})
+	fake.signalMutex.Unlock()
+	if fake.SignalStub != nil {
+		return fake.SignalStub(handle, processID, signal)
+	} else {
+		return fake.signalReturns.result1
+	}
+}
+
+func (fake *FakeConnection) SignalCallCount() int {
+	fake.signalMutex.RLock()
+	defer fake.signalMutex.RUnlock()
+	return len(fake.signalArgsForCall)
+}
+
+func (fake *FakeConnection) SignalArgsForCall(i int) (string, string, garden.Signal) {
+	fake.signalMutex.RLock()
+	defer fake.signalMutex.RUnlock()
+	return fake.signalArgsForCall[i].handle, fake.signalArgsForCall[i].processID, fake.signalArgsForCall[i].signal
+}
+
+func (fake *FakeConnection) SignalReturns(result1 error) {
+	fake.SignalStub = nil
+	fake.signalReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeConnection) ListWithOptions(properties garden.Properties, opts garden.ListOptions) ([]string, error) {
+	fake.listWithOptionsMutex.Lock()
+	fake.listWithOptionsArgsForCall = append(fake.listWithOptionsArgsForCall, struct {
+		properties garden.Properties
+		opts       garden.ListOptions
+	}{properties, opts})
+	fake.listWithOptionsMutex.Unlock()
+	if fake.ListWithOptionsStub != nil {
+		return fake.ListWithOptionsStub(properties, opts)
+	} else {
+		return fake.listWithOptionsReturns.result1, fake.listWithOptionsReturns.result2
+	}
+}
+
+func (fake *FakeConnection) ListWithOptionsCallCount() int {
+	fake.listWithOptionsMutex.RLock()
+	defer fake.listWithOptionsMutex.RUnlock()
+	return len(fake.listWithOptionsArgsForCall)
+}
+
+func (fake *FakeConnection) ListWithOptionsArgsForCall(i int) (garden.Properties, garden.ListOptions) {
+	fake.listWithOptionsMutex.RLock()
+	defer fake.listWithOptionsMutex.RUnlock()
+	return fake.listWithOptionsArgsForCall[i].properties, fake.listWithOptionsArgsForCall[i].opts
+}
+
+func (fake *FakeConnection) ListWithOptionsReturns(result1 []string, result2 error) {
+	fake.ListWithOptionsStub = nil
+	fake.listWithOptionsReturns = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeConnection) Expirations() ([]garden.Expiration, error) {
+	fake.expirationsMutex.Lock()
+	fake.expirationsArgsForCall = append(fake.expirationsArgsForCall, struct{}{})
+	fake.expirationsMutex.Unlock()
+	if fake.ExpirationsStub != nil {
+		return fake.ExpirationsStub()
+	} else {
+		return fake.expirationsReturns.result1, fake.expirationsReturns.result2
+	}
+}
+
+func (fake *FakeConnection) ExpirationsCallCount() int {
+	fake.expirationsMutex.RLock()
+	defer fake.expirationsMutex.RUnlock()
+	return len(fake.expirationsArgsForCall)
+}
+
+func (fake *FakeConnection) ExpirationsReturns(result1 []garden.Expiration, result2 error) {
+	fake.ExpirationsStub = nil
+	fake.expirationsReturns = struct {
+		result1 []garden.Expiration
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeConnection) HostResources(handle string) (garden.HostResources, error) {
+	fake.hostResourcesMutex.Lock()
+	fake.hostResourcesArgsForCall = append(fake.hostResourcesArgsForCall, struct {
+		handle string
+	}{handle})
+	fake.hostResourcesMutex.Unlock()
+	if fake.HostResourcesStub != nil {
+		return fake.HostResourcesStub(handle)
+	} else {
+		return fake.hostResourcesReturns.result1, fake.hostResourcesReturns.result2
+	}
+}
+
+func (fake *FakeConnection) HostResourcesCallCount() int {
+	fake.hostResourcesMutex.RLock()
+	defer fake.hostResourcesMutex.RUnlock()
+	return len(fake.hostResourcesArgsForCall)
+}
+
+func (fake *FakeConnection) HostResourcesArgsForCall(i int) string {
+	fake.hostResourcesMutex.RLock()
+	defer fake.hostResourcesMutex.RUnlock()
+	return fake.hostResourcesArgsForCall[i].handle
+}
+
+func (fake *FakeConnection) HostResourcesReturns(result1 garden.HostResources, result2 error) {
+	fake.HostResourcesStub = nil
+	fake.hostResourcesReturns = struct {
+		result1 garden.HostResources
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeConnection) DestroyDryRun(handle string) (garden.DestroyImpact, error) {
+	fake.destroyDryRunMutex.Lock()
+	fake.destroyDryRunArgsForCall = append(fake.destroyDryRunArgsForCall, struct {
+		handle string
+	}{handle})
+	fake.destroyDryRunMutex.Unlock()
+	if fake.DestroyDryRunStub != nil {
+		return fake.DestroyDryRunStub(handle)
+	} else {
+		return fake.destroyDryRunReturns.result1, fake.destroyDryRunReturns.result2
+	}
+}
+
+func (fake *FakeConnection) DestroyDryRunCallCount() int {
+	fake.destroyDryRunMutex.RLock()
+	defer fake.destroyDryRunMutex.RUnlock()
+	return len(fake.destroyDryRunArgsForCall)
+}
+
+func (fake *FakeConnection) DestroyDryRunArgsForCall(i int) string {
+	fake.destroyDryRunMutex.RLock()
+	defer fake.destroyDryRunMutex.RUnlock()
+	return fake.destroyDryRunArgsForCall[i].handle
+}
+
+func (fake *FakeConnection) DestroyDryRunReturns(result1 garden.DestroyImpact, result2 error) {
+	fake.DestroyDryRunStub = nil
+	fake.destroyDryRunReturns = struct {
+		result1 garden.DestroyImpact
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeConnection) Pause(handle string) error {
+	fake.pauseMutex.Lock()
+	fake.pauseArgsForCall = append(fake.pauseArgsForCall, struct {
+		handle string
+	}{handle})
+	fake.pauseMutex.Unlock()
+	if fake.PauseStub != nil {
+		return fake.PauseStub(handle)
+	} else {
+		return fake.pauseReturns.result1
+	}
+}
+
+func (fake *FakeConnection) PauseCallCount() int {
+	fake.pauseMutex.RLock()
+	defer fake.pauseMutex.RUnlock()
+	return len(fake.pauseArgsForCall)
+}
+
+func (fake *FakeConnection) PauseArgsForCall(i int) string {
+	fake.pauseMutex.RLock()
+	defer fake.pauseMutex.RUnlock()
+	return fake.pauseArgsForCall[i].handle
+}
+
+func (fake *FakeConnection) PauseReturns(result1 error) {
+	fake.PauseStub = nil
+	fake.pauseReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeConnection) Resume(handle string) error {
+	fake.resumeMutex.Lock()
+	fake.resumeArgsForCall = append(fake.resumeArgsForCall, struct {
+		handle string
+	}{handle})
+	fake.resumeMutex.Unlock()
+	if fake.ResumeStub != nil {
+		return fake.ResumeStub(handle)
+	} else {
+		return fake.resumeReturns.result1
+	}
+}
+
+func (fake *FakeConnection) ResumeCallCount() int {
+	fake.resumeMutex.RLock()
+	defer fake.resumeMutex.RUnlock()
+	return len(fake.resumeArgsForCall)
+}
+
+func (fake *FakeConnection) ResumeArgsForCall(i int) string {
+	fake.resumeMutex.RLock()
+	defer fake.resumeMutex.RUnlock()
+	return fake.resumeArgsForCall[i].handle
+}
+
+func (fake *FakeConnection) ResumeReturns(result1 error) {
+	fake.ResumeStub = nil
+	fake.resumeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeConnection) PortAllocations() (garden.PortAllocations, error) {
+	fake.portAllocationsMutex.Lock()
+	fake.portAllocationsArgsForCall = append(fake.portAllocationsArgsForCall, struct{}{})
+	fake.portAllocationsMutex.Unlock()
+	if fake.PortAllocationsStub != nil {
+		return fake.PortAllocationsStub()
+	} else {
+		return fake.portAllocationsReturns.result1, fake.portAllocationsReturns.result2
+	}
+}
+
+func (fake *FakeConnection) PortAllocationsCallCount() int {
+	fake.portAllocationsMutex.RLock()
+	defer fake.portAllocationsMutex.RUnlock()
+	return len(fake.portAllocationsArgsForCall)
+}
+
+func (fake *FakeConnection) PortAllocationsReturns(result1 garden.PortAllocations, result2 error) {
+	fake.PortAllocationsStub = nil
+	fake.portAllocationsReturns = struct {
+		result1 garden.PortAllocations
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeConnection) Checkpoint(handle string, destination string) error {
+	fake.checkpointMutex.Lock()
+	fake.checkpointArgsForCall = append(fake.checkpointArgsForCall, struct {
+		handle      string
+		destination string
+	}{handle, destination})
+	fake.checkpointMutex.Unlock()
+	if fake.CheckpointStub != nil {
+		return fake.CheckpointStub(handle, destination)
+	} else {
+		return fake.checkpointReturns.result1
+	}
+}
+
+func (fake *FakeConnection) CheckpointCallCount() int {
+	fake.checkpointMutex.RLock()
+	defer fake.checkpointMutex.RUnlock()
+	return len(fake.checkpointArgsForCall)
+}
+
+func (fake *FakeConnection) CheckpointArgsForCall(i int) (string, string) {
+	fake.checkpointMutex.RLock()
+	defer fake.checkpointMutex.RUnlock()
+	return fake.checkpointArgsForCall[i].handle, fake.checkpointArgsForCall[i].destination
+}
+
+func (fake *FakeConnection) CheckpointReturns(result1 error) {
+	fake.CheckpointStub = nil
+	fake.checkpointReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeConnection) Restore(spec garden.ContainerSpec, source string) (string, error) {
+	fake.restoreMutex.Lock()
+	fake.restoreArgsForCall = append(fake.restoreArgsForCall, struct {
+		spec   garden.ContainerSpec
+		source string
+	}{spec, source})
+	fake.restoreMutex.Unlock()
+	if fake.RestoreStub != nil {
+		return fake.RestoreStub(spec, source)
+	} else {
+		return fake.restoreReturns.result1, fake.restoreReturns.result2
+	}
+}
+
+func (fake *FakeConnection) RestoreCallCount() int {
+	fake.restoreMutex.RLock()
+	defer fake.restoreMutex.RUnlock()
+	return len(fake.restoreArgsForCall)
+}
+
+func (fake *FakeConnection) RestoreArgsForCall(i int) (garden.ContainerSpec, string) {
+	fake.restoreMutex.RLock()
+	defer fake.restoreMutex.RUnlock()
+	return fake.restoreArgsForCall[i].spec, fake.restoreArgsForCall[i].source
+}
+
+func (fake *FakeConnection) RestoreReturns(result1 string, result2 error) {
+	fake.RestoreStub = nil
+	fake.restoreReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeConnection) Events(ctx context.Context) (<-chan garden.Event, error) {
+	fake.eventsMutex.Lock()
+	fake.eventsArgsForCall = append(fake.eventsArgsForCall, struct {
+		ctx context.Context
+	}{ctx})
+	fake.eventsMutex.Unlock()
+	if fake.EventsStub != nil {
+		return fake.EventsStub(ctx)
+	} else {
+		return fake.eventsReturns.result1, fake.eventsReturns.result2
+	}
+}
+
+func (fake *FakeConnection) EventsCallCount() int {
+	fake.eventsMutex.RLock()
+	defer fake.eventsMutex.RUnlock()
+	return len(fake.eventsArgsForCall)
+}
+
+func (fake *FakeConnection) EventsArgsForCall(i int) context.Context {
+	fake.eventsMutex.RLock()
+	defer fake.eventsMutex.RUnlock()
+	return fake.eventsArgsForCall[i].ctx
+}
+
+func (fake *FakeConnection) EventsReturns(result1 <-chan garden.Event, result2 error) {
+	fake.EventsStub = nil
+	fake.eventsReturns = struct {
+		result1 <-chan garden.Event
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeConnection) Processes(handle string) ([]garden.ProcessInfo, error) {
+	fake.processesMutex.Lock()
+	fake.processesArgsForCall = append(fake.processesArgsForCall, struct {
+		handle string
+	}{handle})
+	fake.processesMutex.Unlock()
+	if fake.ProcessesStub != nil {
+		return fake.ProcessesStub(handle)
+	} else {
+		return fake.processesReturns.result1, fake.processesReturns.result2
+	}
+}
+
+func (fake *FakeConnection) ProcessesCallCount() int {
+	fake.processesMutex.RLock()
+	defer fake.processesMutex.RUnlock()
+	return len(fake.processesArgsForCall)
+}
+
+func (fake *FakeConnection) ProcessesArgsForCall(i int) string {
+	fake.processesMutex.RLock()
+	defer fake.processesMutex.RUnlock()
+	return fake.processesArgsForCall[i].handle
+}
+
+func (fake *FakeConnection) ProcessesReturns(result1 []garden.ProcessInfo, result2 error) {
+	fake.ProcessesStub = nil
+	fake.processesReturns = struct {
+		result1 []garden.ProcessInfo
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeConnection) ProcessExit(handle string, processID string) (garden.ProcessExit, error) {
+	fake.processExitMutex.Lock()
+	fake.processExitArgsForCall = append(fake.processExitArgsForCall, struct {
+		handle    string
+		processID string
+	}{handle, processID})
+	fake.processExitMutex.Unlock()
+	if fake.ProcessExitStub != nil {
+		return fake.ProcessExitStub(handle, processID)
+	} else {
+		return fake.processExitReturns.result1, fake.processExitReturns.result2
+	}
+}
+
+func (fake *FakeConnection) ProcessExitCallCount() int {
+	fake.processExitMutex.RLock()
+	defer fake.processExitMutex.RUnlock()
+	return len(fake.processExitArgsForCall)
+}
+
+func (fake *FakeConnection) ProcessExitArgsForCall(i int) (string, string) {
+	fake.processExitMutex.RLock()
+	defer fake.processExitMutex.RUnlock()
+	return fake.processExitArgsForCall[i].handle, fake.processExitArgsForCall[i].processID
+}
+
+func (fake *FakeConnection) ProcessExitReturns(result1 garden.ProcessExit, result2 error) {
+	fake.ProcessExitStub = nil
+	fake.processExitReturns = struct {
+		result1 garden.ProcessExit
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeConnection) BulkDestroy(handles []string) (map[string]garden.ContainerDestroyEntry, error) {
+	fake.bulkDestroyMutex.Lock()
+	fake.bulkDestroyArgsForCall = append(fake.bulkDestroyArgsForCall, struct {
+		handles []string
+	}{handles})
+	fake.bulkDestroyMutex.Unlock()
+	if fake.BulkDestroyStub != nil {
+		return fake.BulkDestroyStub(handles)
+	} else {
+		return fake.bulkDestroyReturns.result1, fake.bulkDestroyReturns.result2
+	}
+}
+
+func (fake *FakeConnection) BulkDestroyCallCount() int {
+	fake.bulkDestroyMutex.RLock()
+	defer fake.bulkDestroyMutex.RUnlock()
+	return len(fake.bulkDestroyArgsForCall)
+}
+
+func (fake *FakeConnection) BulkDestroyArgsForCall(i int) []string {
+	fake.bulkDestroyMutex.RLock()
+	defer fake.bulkDestroyMutex.RUnlock()
+	return fake.bulkDestroyArgsForCall[i].handles
+}
+
+func (fake *FakeConnection) BulkDestroyReturns(result1 map[string]garden.ContainerDestroyEntry, result2 error) {
+	fake.BulkDestroyStub = nil
+	fake.bulkDestroyReturns = struct {
+		result1 map[string]garden.ContainerDestroyEntry
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeConnection) Reconciliation() (garden.Reconciliation, error) {
+	fake.reconciliationMutex.Lock()
+	fake.reconciliationArgsForCall = append(fake.reconciliationArgsForCall, struct{}{})
+	fake.reconciliationMutex.Unlock()
+	if fake.ReconciliationStub != nil {
+		return fake.ReconciliationStub()
+	} else {
+		return fake.reconciliationReturns.result1, fake.reconciliationReturns.result2
+	}
+}
+
+func (fake *FakeConnection) ReconciliationCallCount() int {
+	fake.reconciliationMutex.RLock()
+	defer fake.reconciliationMutex.RUnlock()
+	return len(fake.reconciliationArgsForCall)
+}
+
+func (fake *FakeConnection) ReconciliationReturns(result1 garden.Reconciliation, result2 error) {
+	fake.ReconciliationStub = nil
+	fake.reconciliationReturns = struct {
+		result1 garden.Reconciliation
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeConnection) BulkStop(handles []string, kill bool) (map[string]garden.ContainerStopEntry, error) {
+	fake.bulkStopMutex.Lock()
+	fake.bulkStopArgsForCall = append(fake.bulkStopArgsForCall, struct {
+		handles []string
+		kill    bool
+	}{handles, kill})
+	fake.bulkStopMutex.Unlock()
+	if fake.BulkStopStub != nil {
+		return fake.BulkStopStub(handles, kill)
+	} else {
+		return fake.bulkStopReturns.result1, fake.bulkStopReturns.result2
+	}
+}
+
+func (fake *FakeConnection) BulkStopCallCount() int {
+	fake.bulkStopMutex.RLock()
+	defer fake.bulkStopMutex.RUnlock()
+	return len(fake.bulkStopArgsForCall)
+}
+
+func (fake *FakeConnection) BulkStopArgsForCall(i int) ([]string, bool) {
+	fake.bulkStopMutex.RLock()
+	defer fake.bulkStopMutex.RUnlock()
+	return fake.bulkStopArgsForCall[i].handles, fake.bulkStopArgsForCall[i].kill
+}
+
+func (fake *FakeConnection) BulkStopReturns(result1 map[string]garden.ContainerStopEntry, result2 error) {
+	fake.BulkStopStub = nil
+	fake.bulkStopReturns = struct {
+		result1 map[string]garden.ContainerStopEntry
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeConnection) BulkCreate(specs []garden.ContainerSpec) ([]garden.ContainerCreateEntry, error) {
+	fake.bulkCreateMutex.Lock()
+	fake.bulkCreateArgsForCall = append(fake.bulkCreateArgsForCall, struct {
+		specs []garden.ContainerSpec
+	}{specs})
+	fake.bulkCreateMutex.Unlock()
+	if fake.BulkCreateStub != nil {
+		return fake.BulkCreateStub(specs)
+	} else {
+		return fake.bulkCreateReturns.result1, fake.bulkCreateReturns.result2
+	}
+}
+
+func (fake *FakeConnection) BulkCreateCallCount() int {
+	fake.bulkCreateMutex.RLock()
+	defer fake.bulkCreateMutex.RUnlock()
+	return len(fake.bulkCreateArgsForCall)
+}
+
+func (fake *FakeConnection) BulkCreateArgsForCall(i int) []garden.ContainerSpec {
+	fake.bulkCreateMutex.RLock()
+	defer fake.bulkCreateMutex.RUnlock()
+	return fake.bulkCreateArgsForCall[i].specs
+}
+
+func (fake *FakeConnection) BulkCreateReturns(result1 []garden.ContainerCreateEntry, result2 error) {
+	fake.BulkCreateStub = nil
+	fake.bulkCreateReturns = struct {
+		result1 []garden.ContainerCreateEntry
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeConnection) StopWithTimeout(handle string, timeout time.Duration) error {
+	fake.stopWithTimeoutMutex.Lock()
+	fake.stopWithTimeoutArgsForCall = append(fake.stopWithTimeoutArgsForCall, struct {
+		handle  string
+		timeout time.Duration
+	}{handle, timeout})
+	fake.stopWithTimeoutMutex.Unlock()
+	if fake.StopWithTimeoutStub != nil {
+		return fake.StopWithTimeoutStub(handle, timeout)
+	} else {
+		return fake.stopWithTimeoutReturns.result1
+	}
+}
+
+func (fake *FakeConnection) StopWithTimeoutCallCount() int {
+	fake.stopWithTimeoutMutex.RLock()
+	defer fake.stopWithTimeoutMutex.RUnlock()
+	return len(fake.stopWithTimeoutArgsForCall)
+}
+
+func (fake *FakeConnection) StopWithTimeoutArgsForCall(i int) (string, time.Duration) {
+	fake.stopWithTimeoutMutex.RLock()
+	defer fake.stopWithTimeoutMutex.RUnlock()
+	return fake.stopWithTimeoutArgsForCall[i].handle, fake.stopWithTimeoutArgsForCall[i].timeout
+}
+
+func (fake *FakeConnection) StopWithTimeoutReturns(result1 error) {
+	fake.StopWithTimeoutStub = nil
+	fake.stopWithTimeoutReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeConnection) CurrentLimits(handle string) (garden.Limits, error) {
+	fake.currentLimitsMutex.Lock()
+	fake.currentLimitsArgsForCall = append(fake.currentLimitsArgsForCall, struct {
+		handle string
+	}{handle})
+	fake.currentLimitsMutex.Unlock()
+	if fake.CurrentLimitsStub != nil {
+		return fake.CurrentLimitsStub(handle)
+	} else {
+		return fake.currentLimitsReturns.result1, fake.currentLimitsReturns.result2
+	}
+}
+
+func (fake *FakeConnection) CurrentLimitsCallCount() int {
+	fake.currentLimitsMutex.RLock()
+	defer fake.currentLimitsMutex.RUnlock()
+	return len(fake.currentLimitsArgsForCall)
+}
+
+func (fake *FakeConnection) CurrentLimitsArgsForCall(i int) string {
+	fake.currentLimitsMutex.RLock()
+	defer fake.currentLimitsMutex.RUnlock()
+	return fake.currentLimitsArgsForCall[i].handle
+}
+
+func (fake *FakeConnection) CurrentLimitsReturns(result1 garden.Limits, result2 error) {
+	fake.CurrentLimitsStub = nil
+	fake.currentLimitsReturns = struct {
+		result1 garden.Limits
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeConnection) LimitAll(handle string, limits garden.Limits) error {
+	fake.limitAllMutex.Lock()
+	fake.limitAllArgsForCall = append(fake.limitAllArgsForCall, struct {
+		handle string
+		limits garden.Limits
+	}{handle, limits})
+	fake.limitAllMutex.Unlock()
+	if fake.LimitAllStub != nil {
+		return fake.LimitAllStub(handle, limits)
+	} else {
+		return fake.limitAllReturns.result1
+	}
+}
+
+func (fake *FakeConnection) LimitAllCallCount() int {
+	fake.limitAllMutex.RLock()
+	defer fake.limitAllMutex.RUnlock()
+	return len(fake.limitAllArgsForCall)
+}
+
+func (fake *FakeConnection) LimitAllArgsForCall(i int) (string, garden.Limits) {
+	fake.limitAllMutex.RLock()
+	defer fake.limitAllMutex.RUnlock()
+	return fake.limitAllArgsForCall[i].handle, fake.limitAllArgsForCall[i].limits
+}
+
+func (fake *FakeConnection) LimitAllReturns(result1 error) {
+	fake.LimitAllStub = nil
+	fake.limitAllReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeConnection) CurrentPidLimits(handle string) (garden.PidLimits, error) {
+	fake.currentPidLimitsMutex.Lock()
+	fake.currentPidLimitsArgsForCall = append(fake.currentPidLimitsArgsForCall, struct {
+		handle string
+	}{handle})
+	fake.currentPidLimitsMutex.Unlock()
+	if fake.CurrentPidLimitsStub != nil {
+		return fake.CurrentPidLimitsStub(handle)
+	} else {
+		return fake.currentPidLimitsReturns.result1, fake.currentPidLimitsReturns.result2
+	}
+}
+
+func (fake *FakeConnection) CurrentPidLimitsCallCount() int {
+	fake.currentPidLimitsMutex.RLock()
+	defer fake.currentPidLimitsMutex.RUnlock()
+	return len(fake.currentPidLimitsArgsForCall)
+}
+
+func (fake *FakeConnection) CurrentPidLimitsArgsForCall(i int) string {
+	fake.currentPidLimitsMutex.RLock()
+	defer fake.currentPidLimitsMutex.RUnlock()
+	return fake.currentPidLimitsArgsForCall[i].handle
+}
+
+func (fake *FakeConnection) CurrentPidLimitsReturns(result1 garden.PidLimits, result2 error) {
+	fake.CurrentPidLimitsStub = nil
+	fake.currentPidLimitsReturns = struct {
+		result1 garden.PidLimits
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeConnection) LimitPids(handle string, limits garden.PidLimits) error {
+	fake.limitPidsMutex.Lock()
+	fake.limitPidsArgsForCall = append(fake.limitPidsArgsForCall, struct {
+		handle string
+		limits garden.PidLimits
+	}{handle, limits})
+	fake.limitPidsMutex.Unlock()
+	if fake.LimitPidsStub != nil {
+		return fake.LimitPidsStub(handle, limits)
+	} else {
+		return fake.limitPidsReturns.result1
+	}
+}
+
+func (fake *FakeConnection) LimitPidsCallCount() int {
+	fake.limitPidsMutex.RLock()
+	defer fake.limitPidsMutex.RUnlock()
+	return len(fake.limitPidsArgsForCall)
+}
+
+func (fake *FakeConnection) LimitPidsArgsForCall(i int) (string, garden.PidLimits) {
+	fake.limitPidsMutex.RLock()
+	defer fake.limitPidsMutex.RUnlock()
+	return fake.limitPidsArgsForCall[i].handle, fake.limitPidsArgsForCall[i].limits
+}
+
+func (fake *FakeConnection) LimitPidsReturns(result1 error) {
+	fake.LimitPidsStub = nil
+	fake.limitPidsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeConnection) CurrentBlockIOLimits(handle string) (garden.BlockIOLimits, error) {
+	fake.currentBlockIOLimitsMutex.Lock()
+	fake.currentBlockIOLimitsArgsForCall = append(fake.currentBlockIOLimitsArgsForCall, struct {
+		handle string
+	}{handle})
+	fake.currentBlockIOLimitsMutex.Unlock()
+	if fake.CurrentBlockIOLimitsStub != nil {
+		return fake.CurrentBlockIOLimitsStub(handle)
+	} else {
+		return fake.currentBlockIOLimitsReturns.result1, fake.currentBlockIOLimitsReturns.result2
+	}
+}
+
+func (fake *FakeConnection) CurrentBlockIOLimitsCallCount() int {
+	fake.currentBlockIOLimitsMutex.RLock()
+	defer fake.currentBlockIOLimitsMutex.RUnlock()
+	return len(fake.currentBlockIOLimitsArgsForCall)
+}
+
+func (fake *FakeConnection) CurrentBlockIOLimitsArgsForCall(i int) string {
+	fake.currentBlockIOLimitsMutex.RLock()
+	defer fake.currentBlockIOLimitsMutex.RUnlock()
+	return fake.currentBlockIOLimitsArgsForCall[i].handle
+}
+
+func (fake *FakeConnection) CurrentBlockIOLimitsReturns(result1 garden.BlockIOLimits, result2 error) {
+	fake.CurrentBlockIOLimitsStub = nil
+	fake.currentBlockIOLimitsReturns = struct {
+		result1 garden.BlockIOLimits
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeConnection) LimitBlockIO(handle string, limits garden.BlockIOLimits) error {
+	fake.limitBlockIOMutex.Lock()
+	fake.limitBlockIOArgsForCall = append(fake.limitBlockIOArgsForCall, struct {
+		handle string
+		limits garden.BlockIOLimits
+	}{handle, limits})
+	fake.limitBlockIOMutex.Unlock()
+	if fake.LimitBlockIOStub != nil {
+		return fake.LimitBlockIOStub(handle, limits)
+	} else {
+		return fake.limitBlockIOReturns.result1
+	}
+}
+
+func (fake *FakeConnection) LimitBlockIOCallCount() int {
+	fake.limitBlockIOMutex.RLock()
+	defer fake.limitBlockIOMutex.RUnlock()
+	return len(fake.limitBlockIOArgsForCall)
+}
+
+func (fake *FakeConnection) LimitBlockIOArgsForCall(i int) (string, garden.BlockIOLimits) {
+	fake.limitBlockIOMutex.RLock()
+	defer fake.limitBlockIOMutex.RUnlock()
+	return fake.limitBlockIOArgsForCall[i].handle, fake.limitBlockIOArgsForCall[i].limits
+}
+
+func (fake *FakeConnection) LimitBlockIOReturns(result1 error) {
+	fake.LimitBlockIOStub = nil
+	fake.limitBlockIOReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeConnection) EventsSince(ctx context.Context, seq uint64) (<-chan garden.Event, error) {
+	fake.eventsSinceMutex.Lock()
+	fake.eventsSinceArgsForCall = append(fake.eventsSinceArgsForCall, struct {
+		ctx context.Context
+		seq uint64
+	}{ctx, seq})
+	fake.eventsSinceMutex.Unlock()
+	if fake.EventsSinceStub != nil {
+		return fake.EventsSinceStub(ctx, seq)
+	} else {
+		return fake.eventsSinceReturns.result1, fake.eventsSinceReturns.result2
+	}
+}
+
+func (fake *FakeConnection) EventsSinceCallCount() int {
+	fake.eventsSinceMutex.RLock()
+	defer fake.eventsSinceMutex.RUnlock()
+	return len(fake.eventsSinceArgsForCall)
+}
+
+func (fake *FakeConnection) EventsSinceArgsForCall(i int) (context.Context, uint64) {
+	fake.eventsSinceMutex.RLock()
+	defer fake.eventsSinceMutex.RUnlock()
+	return fake.eventsSinceArgsForCall[i].ctx, fake.eventsSinceArgsForCall[i].seq
+}
+
+func (fake *FakeConnection) EventsSinceReturns(result1 <-chan garden.Event, result2 error) {
+	fake.EventsSinceStub = nil
+	fake.eventsSinceReturns = struct {
+		result1 <-chan garden.Event
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeConnection) HandleScheme() (garden.HandleScheme, error) {
+	fake.handleSchemeMutex.Lock()
+	fake.handleSchemeArgsForCall = append(fake.handleSchemeArgsForCall, struct{}{})
+	fake.handleSchemeMutex.Unlock()
+	if fake.HandleSchemeStub != nil {
+		return fake.HandleSchemeStub()
+	} else {
+		return fake.handleSchemeReturns.result1, fake.handleSchemeReturns.result2
+	}
+}
+
+func (fake *FakeConnection) HandleSchemeCallCount() int {
+	fake.handleSchemeMutex.RLock()
+	defer fake.handleSchemeMutex.RUnlock()
+	return len(fake.handleSchemeArgsForCall)
+}
+
+func (fake *FakeConnection) HandleSchemeReturns(result1 garden.HandleScheme, result2 error) {
+	fake.HandleSchemeStub = nil
+	fake.handleSchemeReturns = struct {
+		result1 garden.HandleScheme
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeConnection) SetDescription(handle string, description string) error {
+	fake.setDescriptionMutex.Lock()
+	fake.setDescriptionArgsForCall = append(fake.setDescriptionArgsForCall, struct {
+		handle      string
+		description string
+	}{handle, description})
+	fake.setDescriptionMutex.Unlock()
+	if fake.SetDescriptionStub != nil {
+		return fake.SetDescriptionStub(handle, description)
+	} else {
+		return fake.setDescriptionReturns.result1
+	}
+}
+
+func (fake *FakeConnection) SetDescriptionCallCount() int {
+	fake.setDescriptionMutex.RLock()
+	defer fake.setDescriptionMutex.RUnlock()
+	return len(fake.setDescriptionArgsForCall)
+}
+
+func (fake *FakeConnection) SetDescriptionArgsForCall(i int) (string, string) {
+	fake.setDescriptionMutex.RLock()
+	defer fake.setDescriptionMutex.RUnlock()
+	return fake.setDescriptionArgsForCall[i].handle, fake.setDescriptionArgsForCall[i].description
+}
+
+func (fake *FakeConnection) SetDescriptionReturns(result1 error) {
+	fake.SetDescriptionStub = nil
+	fake.setDescriptionReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeConnection) ProcessMetrics(handle string) (map[string]garden.ProcessMetrics, error) {
+	fake.processMetricsMutex.Lock()
+	fake.processMetricsArgsForCall = append(fake.processMetricsArgsForCall, struct {
+		handle string
+	}{handle})
+	fake.processMetricsMutex.Unlock()
+	if fake.ProcessMetricsStub != nil {
+		return fake.ProcessMetricsStub(handle)
+	} else {
+		return fake.processMetricsReturns.result1, fake.processMetricsReturns.result2
+	}
+}
+
+func (fake *FakeConnection) ProcessMetricsCallCount() int {
+	fake.processMetricsMutex.RLock()
+	defer fake.processMetricsMutex.RUnlock()
+	return len(fake.processMetricsArgsForCall)
+}
+
+func (fake *FakeConnection) ProcessMetricsArgsForCall(i int) string {
+	fake.processMetricsMutex.RLock()
+	defer fake.processMetricsMutex.RUnlock()
+	return fake.processMetricsArgsForCall[i].handle
+}
+
+func (fake *FakeConnection) ProcessMetricsReturns(result1 map[string]garden.ProcessMetrics, result2 error) {
+	fake.ProcessMetricsStub = nil
+	fake.processMetricsReturns = struct {
+		result1 map[string]garden.ProcessMetrics
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeConnection) StreamMetrics(ctx context.Context, handles []string, interval time.Duration) (<-chan garden.MetricsSnapshot, error) {
+	fake.streamMetricsMutex.Lock()
+	fake.streamMetricsArgsForCall = append(fake.streamMetricsArgsForCall, struct {
+		ctx      context.Context
+		handles  []string
+		interval time.Duration
+	}{ctx, handles, interval})
+	fake.streamMetricsMutex.Unlock()
+	if fake.StreamMetricsStub != nil {
+		return fake.StreamMetricsStub(ctx, handles, interval)
+	} else {
+		return fake.streamMetricsReturns.result1, fake.streamMetricsReturns.result2
+	}
+}
+
+func (fake *FakeConnection) StreamMetricsCallCount() int {
+	fake.streamMetricsMutex.RLock()
+	defer fake.streamMetricsMutex.RUnlock()
+	return len(fake.streamMetricsArgsForCall)
+}
+
+func (fake *FakeConnection) StreamMetricsArgsForCall(i int) (context.Context, []string, time.Duration) {
+	fake.streamMetricsMutex.RLock()
+	defer fake.streamMetricsMutex.RUnlock()
+	return fake.streamMetricsArgsForCall[i].ctx, fake.streamMetricsArgsForCall[i].handles, fake.streamMetricsArgsForCall[i].interval
+}
+
+func (fake *FakeConnection) StreamMetricsReturns(result1 <-chan garden.MetricsSnapshot, result2 error) {
+	fake.StreamMetricsStub = nil
+	fake.streamMetricsReturns = struct {
+		result1 <-chan garden.MetricsSnapshot
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeConnection) DiskUsage(handle string, paths []string) ([]garden.PathDiskUsage, error) {
+	fake.diskUsageMutex.Lock()
+	fake.diskUsageArgsForCall = append(fake.diskUsageArgsForCall, struct {
+		handle string
+		paths  []string
+	}{handle, paths})
+	fake.diskUsageMutex.Unlock()
+	if fake.DiskUsageStub != nil {
+		return fake.DiskUsageStub(handle, paths)
+	} else {
+		return fake.diskUsageReturns.result1, fake.diskUsageReturns.result2
+	}
+}
+
+func (fake *FakeConnection) DiskUsageCallCount() int {
+	fake.diskUsageMutex.RLock()
+	defer fake.diskUsageMutex.RUnlock()
+	return len(fake.diskUsageArgsForCall)
+}
+
+func (fake *FakeConnection) DiskUsageArgsForCall(i int) (string, []string) {
+	fake.diskUsageMutex.RLock()
+	defer fake.diskUsageMutex.RUnlock()
+	return fake.diskUsageArgsForCall[i].handle, fake.diskUsageArgsForCall[i].paths
+}
+
+func (fake *FakeConnection) DiskUsageReturns(result1 []garden.PathDiskUsage, result2 error) {
+	fake.DiskUsageStub = nil
+	fake.diskUsageReturns = struct {
+		result1 []garden.PathDiskUsage
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeConnection) NetOutRules(handle string) ([]garden.NetOutRule, error) {
+	fake.netOutRulesMutex.Lock()
+	fake.netOutRulesArgsForCall = append(fake.netOutRulesArgsForCall, struct {
+		handle string
+	}{handle})
+	fake.netOutRulesMutex.Unlock()
+	if fake.NetOutRulesStub != nil {
+		return fake.NetOutRulesStub(handle)
+	} else {
+		return fake.netOutRulesReturns.result1, fake.netOutRulesReturns.result2
+	}
+}
+
+func (fake *FakeConnection) NetOutRulesCallCount() int {
+	fake.netOutRulesMutex.RLock()
+	defer fake.netOutRulesMutex.RUnlock()
+	return len(fake.netOutRulesArgsForCall)
+}
+
+func (fake *FakeConnection) NetOutRulesArgsForCall(i int) string {
+	fake.netOutRulesMutex.RLock()
+	defer fake.netOutRulesMutex.RUnlock()
+	return fake.netOutRulesArgsForCall[i].handle
+}
+
+func (fake *FakeConnection) NetOutRulesReturns(result1 []garden.NetOutRule, result2 error) {
+	fake.NetOutRulesStub = nil
+	fake.netOutRulesReturns = struct {
+		result1 []garden.NetOutRule
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeConnection) ProcessEnv(handle string, processID string) ([]string, error) {
+	fake.processEnvMutex.Lock()
+	fake.processEnvArgsForCall = append(fake.processEnvArgsForCall, struct {
+		handle    string
+		processID string
+	}{handle, processID})
+	fake.processEnvMutex.Unlock()
+	if fake.ProcessEnvStub != nil {
+		return fake.ProcessEnvStub(handle, processID)
+	} else {
+		return fake.processEnvReturns.result1, fake.processEnvReturns.result2
+	}
+}
+
+func (fake *FakeConnection) ProcessEnvCallCount() int {
+	fake.processEnvMutex.RLock()
+	defer fake.processEnvMutex.RUnlock()
+	return len(fake.processEnvArgsForCall)
+}
+
+func (fake *FakeConnection) ProcessEnvArgsForCall(i int) (string, string) {
+	fake.processEnvMutex.RLock()
+	defer fake.processEnvMutex.RUnlock()
+	return fake.processEnvArgsForCall[i].handle, fake.processEnvArgsForCall[i].processID
+}
+
+func (fake *FakeConnection) ProcessEnvReturns(result1 []string, result2 error) {
+	fake.ProcessEnvStub = nil
+	fake.processEnvReturns = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeConnection) Pressure() (garden.CellPressure, error) {
+	fake.pressureMutex.Lock()
+	fake.pressureArgsForCall = append(fake.pressureArgsForCall, struct{}{})
+	fake.pressureMutex.Unlock()
+	if fake.PressureStub != nil {
+		return fake.PressureStub()
+	} else {
+		return fake.pressureReturns.result1, fake.pressureReturns.result2
+	}
+}
+
+func (fake *FakeConnection) PressureCallCount() int {
+	fake.pressureMutex.RLock()
+	defer fake.pressureMutex.RUnlock()
+	return len(fake.pressureArgsForCall)
+}
+
+func (fake *FakeConnection) PressureReturns(result1 garden.CellPressure, result2 error) {
+	fake.PressureStub = nil
+	fake.pressureReturns = struct {
+		result1 garden.CellPressure
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeConnection) RemoveNetOut(handle string, ruleID string) error {
+	fake.removeNetOutMutex.Lock()
+	fake.removeNetOutArgsForCall = append(fake.removeNetOutArgsForCall, struct {
+		handle string
+		ruleID string
+	}{handle, ruleID})
+	fake.removeNetOutMutex.Unlock()
+	if fake.RemoveNetOutStub != nil {
+		return fake.RemoveNetOutStub(handle, ruleID)
+	} else {
+		return fake.removeNetOutReturns.result1
+	}
+}
+
+func (fake *FakeConnection) RemoveNetOutCallCount() int {
+	fake.removeNetOutMutex.RLock()
+	defer fake.removeNetOutMutex.RUnlock()
+	return len(fake.removeNetOutArgsForCall)
+}
+
+func (fake *FakeConnection) RemoveNetOutArgsForCall(i int) (string, string) {
+	fake.removeNetOutMutex.RLock()
+	defer fake.removeNetOutMutex.RUnlock()
+	return fake.removeNetOutArgsForCall[i].handle, fake.removeNetOutArgsForCall[i].ruleID
+}
+
+func (fake *FakeConnection) RemoveNetOutReturns(result1 error) {
+	fake.RemoveNetOutStub = nil
+	fake.removeNetOutReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeConnection) RemoveAllNetOut(handle string) error {
+	fake.removeAllNetOutMutex.Lock()
+	fake.removeAllNetOutArgsForCall = append(fake.removeAllNetOutArgsForCall, struct {
+		handle string
+	}{handle})
+	fake.removeAllNetOutMutex.Unlock()
+	if fake.RemoveAllNetOutStub != nil {
+		return fake.RemoveAllNetOutStub(handle)
+	} else {
+		return fake.removeAllNetOutReturns.result1
+	}
+}
+
+func (fake *FakeConnection) RemoveAllNetOutCallCount() int {
+	fake.removeAllNetOutMutex.RLock()
+	defer fake.removeAllNetOutMutex.RUnlock()
+	return len(fake.removeAllNetOutArgsForCall)
+}
+
+func (fake *FakeConnection) RemoveAllNetOutArgsForCall(i int) string {
+	fake.removeAllNetOutMutex.RLock()
+	defer fake.removeAllNetOutMutex.RUnlock()
+	return fake.removeAllNetOutArgsForCall[i].handle
+}
+
+func (fake *FakeConnection) RemoveAllNetOutReturns(result1 error) {
+	fake.RemoveAllNetOutStub = nil
+	fake.removeAllNetOutReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeConnection) BulkNetOut(handle string, rules []garden.NetOutRule) error {
+	fake.bulkNetOutMutex.Lock()
+	fake.bulkNetOutArgsForCall = append(fake.bulkNetOutArgsForCall, struct {
+		handle string
+		rules  []garden.NetOutRule
+	}{handle, rules})
+	fake.bulkNetOutMutex.Unlock()
+	if fake.BulkNetOutStub != nil {
+		return fake.BulkNetOutStub(handle, rules)
+	} else {
+		return fake.bulkNetOutReturns.result1
+	}
+}
+
+func (fake *FakeConnection) BulkNetOutCallCount() int {
+	fake.bulkNetOutMutex.RLock()
+	defer fake.bulkNetOutMutex.RUnlock()
+	return len(fake.bulkNetOutArgsForCall)
+}
+
+func (fake *FakeConnection) BulkNetOutArgsForCall(i int) (string, []garden.NetOutRule) {
+	fake.bulkNetOutMutex.RLock()
+	defer fake.bulkNetOutMutex.RUnlock()
+	return fake.bulkNetOutArgsForCall[i].handle, fake.bulkNetOutArgsForCall[i].rules
+}
+
+func (fake *FakeConnection) BulkNetOutReturns(result1 error) {
+	fake.BulkNetOutStub = nil
+	fake.bulkNetOutReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeConnection) BulkMetricsDelta(handles []string, opts garden.BulkOptions, since string) (garden.BulkMetricsDelta, error) {
+	fake.bulkMetricsDeltaMutex.Lock()
+	fake.bulkMetricsDeltaArgsForCall = append(fake.bulkMetricsDeltaArgsForCall, struct {
+		handles []string
+		opts    garden.BulkOptions
+		since   string
+	}{handles, opts, since})
+	fake.bulkMetricsDeltaMutex.Unlock()
+	if fake.BulkMetricsDeltaStub != nil {
+		return fake.BulkMetricsDeltaStub(handles, opts, since)
+	} else {
+		return fake.bulkMetricsDeltaReturns.result1, fake.bulkMetricsDeltaReturns.result2
+	}
+}
+
+func (fake *FakeConnection) BulkMetricsDeltaCallCount() int {
+	fake.bulkMetricsDeltaMutex.RLock()
+	defer fake.bulkMetricsDeltaMutex.RUnlock()
+	return len(fake.bulkMetricsDeltaArgsForCall)
+}
+
+func (fake *FakeConnection) BulkMetricsDeltaArgsForCall(i int) ([]string, garden.BulkOptions, string) {
+	fake.bulkMetricsDeltaMutex.RLock()
+	defer fake.bulkMetricsDeltaMutex.RUnlock()
+	return fake.bulkMetricsDeltaArgsForCall[i].handles, fake.bulkMetricsDeltaArgsForCall[i].opts, fake.bulkMetricsDeltaArgsForCall[i].since
+}
+
+func (fake *FakeConnection) BulkMetricsDeltaReturns(result1 garden.BulkMetricsDelta, result2 error) {
+	fake.BulkMetricsDeltaStub = nil
+	fake.bulkMetricsDeltaReturns = struct {
+		result1 garden.BulkMetricsDelta
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeConnection) NetInMappings(handle string) ([]garden.PortMapping, error) {
+	fake.netInMappingsMutex.Lock()
+	fake.netInMappingsArgsForCall = append(fake.netInMappingsArgsForCall, struct {
+		handle string
+	}{handle})
+	fake.netInMappingsMutex.Unlock()
+	if fake.NetInMappingsStub != nil {
+		return fake.NetInMappingsStub(handle)
+	} else {
+		return fake.netInMappingsReturns.result1, fake.netInMappingsReturns.result2
+	}
+}
+
+func (fake *FakeConnection) NetInMappingsCallCount() int {
+	fake.netInMappingsMutex.RLock()
+	defer fake.netInMappingsMutex.RUnlock()
+	return len(fake.netInMappingsArgsForCall)
+}
+
+func (fake *FakeConnection) NetInMappingsArgsForCall(i int) string {
+	fake.netInMappingsMutex.RLock()
+	defer fake.netInMappingsMutex.RUnlock()
+	return fake.netInMappingsArgsForCall[i].handle
+}
+
+func (fake *FakeConnection) NetInMappingsReturns(result1 []garden.PortMapping, result2 error) {
+	fake.NetInMappingsStub = nil
+	fake.netInMappingsReturns = struct {
+		result1 []garden.PortMapping
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeConnection) RemoveNetIn(handle string, hostPort uint32) error {
+	fake.removeNetInMutex.Lock()
+	fake.removeNetInArgsForCall = append(fake.removeNetInArgsForCall, struct {
+		handle   string
+		hostPort uint32
+	}{handle, hostPort})
+	fake.removeNetInMutex.Unlock()
+	if fake.RemoveNetInStub != nil {
+		return fake.RemoveNetInStub(handle, hostPort)
+	} else {
+		return fake.removeNetInReturns.result1
+	}
+}
+
+func (fake *FakeConnection) RemoveNetInCallCount() int {
+	fake.removeNetInMutex.RLock()
+	defer fake.removeNetInMutex.RUnlock()
+	return len(fake.removeNetInArgsForCall)
+}
+
+func (fake *FakeConnection) RemoveNetInArgsForCall(i int) (string, uint32) {
+	fake.removeNetInMutex.RLock()
+	defer fake.removeNetInMutex.RUnlock()
+	return fake.removeNetInArgsForCall[i].handle, fake.removeNetInArgsForCall[i].hostPort
+}
+
+func (fake *FakeConnection) RemoveNetInReturns(result1 error) {
+	fake.RemoveNetInStub = nil
+	fake.removeNetInReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeConnection) NetInWithHostIP(handle string, hostIP string, hostPort uint32, containerPort uint32) (garden.PortMapping, error) {
+	fake.netInWithHostIPMutex.Lock()
+	fake.netInWithHostIPArgsForCall = append(fake.netInWithHostIPArgsForCall, struct {
+		handle        string
+		hostIP        string
+		hostPort      uint32
+		containerPort uint32
+	}{handle, hostIP, hostPort, containerPort})
+	fake.netInWithHostIPMutex.Unlock()
+	if fake.NetInWithHostIPStub != nil {
+		return fake.NetInWithHostIPStub(handle, hostIP, hostPort, containerPort)
+	} else {
+		return fake.netInWithHostIPReturns.result1, fake.netInWithHostIPReturns.result2
+	}
+}
+
+func (fake *FakeConnection) NetInWithHostIPCallCount() int {
+	fake.netInWithHostIPMutex.RLock()
+	defer fake.netInWithHostIPMutex.RUnlock()
+	return len(fake.netInWithHostIPArgsForCall)
+}
+
+func (fake *FakeConnection) NetInWithHostIPArgsForCall(i int) (string, string, uint32, uint32) {
+	fake.netInWithHostIPMutex.RLock()
+	defer fake.netInWithHostIPMutex.RUnlock()
+	return fake.netInWithHostIPArgsForCall[i].handle, fake.netInWithHostIPArgsForCall[i].hostIP, fake.netInWithHostIPArgsForCall[i].hostPort, fake.netInWithHostIPArgsForCall[i].containerPort
+}
+
+func (fake *FakeConnection) NetInWithHostIPReturns(result1 garden.PortMapping, result2 error) {
+	fake.NetInWithHostIPStub = nil
+	fake.netInWithHostIPReturns = struct {
+		result1 garden.PortMapping
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeConnection) Warm(imageURI string, credentials *garden.RegistryCredentials) (garden.CachedImage, error) {
+	fake.warmMutex.Lock()
+	fake.warmArgsForCall = append(fake.warmArgsForCall, struct {
+		imageURI    string
+		credentials *garden.RegistryCredentials
+	}{imageURI, credentials})
+	fake.warmMutex.Unlock()
+	if fake.WarmStub != nil {
+		return fake.WarmStub(imageURI, credentials)
+	} else {
+		return fake.warmReturns.result1, fake.warmReturns.result2
+	}
+}
+
+func (fake *FakeConnection) WarmCallCount() int {
+	fake.warmMutex.RLock()
+	defer fake.warmMutex.RUnlock()
+	return len(fake.warmArgsForCall)
+}
+
+func (fake *FakeConnection) WarmArgsForCall(i int) (string, *garden.RegistryCredentials) {
+	fake.warmMutex.RLock()
+	defer fake.warmMutex.RUnlock()
+	return fake.warmArgsForCall[i].imageURI, fake.warmArgsForCall[i].credentials
+}
+
+func (fake *FakeConnection) WarmReturns(result1 garden.CachedImage, result2 error) {
+	fake.WarmStub = nil
+	fake.warmReturns = struct {
+		result1 garden.CachedImage
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeConnection) ListImages() ([]garden.CachedImage, error) {
+	fake.listImagesMutex.Lock()
+	fake.listImagesArgsForCall = append(fake.listImagesArgsForCall, struct{}{})
+	fake.listImagesMutex.Unlock()
+	if fake.ListImagesStub != nil {
+		return fake.ListImagesStub()
+	} else {
+		return fake.listImagesReturns.result1, fake.listImagesReturns.result2
+	}
+}
+
+func (fake *FakeConnection) ListImagesCallCount() int {
+	fake.listImagesMutex.RLock()
+	defer fake.listImagesMutex.RUnlock()
+	return len(fake.listImagesArgsForCall)
+}
+
+func (fake *FakeConnection) ListImagesReturns(result1 []garden.CachedImage, result2 error) {
+	fake.ListImagesStub = nil
+	fake.listImagesReturns = struct {
+		result1 []garden.CachedImage
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeConnection) RemoveImage(imageURI string) error {
+	fake.removeImageMutex.Lock()
+	fake.removeImageArgsForCall = append(fake.removeImageArgsForCall, struct {
+		imageURI string
+	}{imageURI})
+	fake.removeImageMutex.Unlock()
+	if fake.RemoveImageStub != nil {
+		return fake.RemoveImageStub(imageURI)
+	} else {
+		return fake.removeImageReturns.result1
+	}
+}
+
+func (fake *FakeConnection) RemoveImageCallCount() int {
+	fake.removeImageMutex.RLock()
+	defer fake.removeImageMutex.RUnlock()
+	return len(fake.removeImageArgsForCall)
+}
+
+func (fake *FakeConnection) RemoveImageArgsForCall(i int) string {
+	fake.removeImageMutex.RLock()
+	defer fake.removeImageMutex.RUnlock()
+	return fake.removeImageArgsForCall[i].imageURI
+}
+
+func (fake *FakeConnection) RemoveImageReturns(result1 error) {
+	fake.RemoveImageStub = nil
+	fake.removeImageReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeConnection) Commit(handle string, imageName string) (garden.CachedImage, error) {
+	fake.commitMutex.Lock()
+	fake.commitArgsForCall = append(fake.commitArgsForCall, struct {
+		handle    string
+		imageName string
+	}{handle, imageName})
+	fake.commitMutex.Unlock()
+	if fake.CommitStub != nil {
+		return fake.CommitStub(handle, imageName)
+	} else {
+		return fake.commitReturns.result1, fake.commitReturns.result2
+	}
+}
+
+func (fake *FakeConnection) CommitCallCount() int {
+	fake.commitMutex.RLock()
+	defer fake.commitMutex.RUnlock()
+	return len(fake.commitArgsForCall)
+}
+
+func (fake *FakeConnection) CommitArgsForCall(i int) (string, string) {
+	fake.commitMutex.RLock()
+	defer fake.commitMutex.RUnlock()
+	return fake.commitArgsForCall[i].handle, fake.commitArgsForCall[i].imageName
+}
+
+func (fake *FakeConnection) CommitReturns(result1 garden.CachedImage, result2 error) {
+	fake.CommitStub = nil
+	fake.commitReturns = struct {
+		result1 garden.CachedImage
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeConnection) RunDetached(handle string, spec garden.ProcessSpec) (string, error) {
+	fake.runDetachedMutex.Lock()
+	fake.runDetachedArgsForCall = append(fake.runDetachedArgsForCall, struct {
+		handle string
+		spec   garden.ProcessSpec
+	}{handle, spec})
+	fake.runDetachedMutex.Unlock()
+	if fake.RunDetachedStub != nil {
+		return fake.RunDetachedStub(handle, spec)
+	} else {
+		return fake.runDetachedReturns.result1, fake.runDetachedReturns.result2
+	}
+}
+
+func (fake *FakeConnection) RunDetachedCallCount() int {
+	fake.runDetachedMutex.RLock()
+	defer fake.runDetachedMutex.RUnlock()
+	return len(fake.runDetachedArgsForCall)
+}
+
+func (fake *FakeConnection) RunDetachedArgsForCall(i int) (string, garden.ProcessSpec) {
+	fake.runDetachedMutex.RLock()
+	defer fake.runDetachedMutex.RUnlock()
+	return fake.runDetachedArgsForCall[i].handle, fake.runDetachedArgsForCall[i].spec
+}
+
+func (fake *FakeConnection) RunDetachedReturns(result1 string, result2 error) {
+	fake.RunDetachedStub = nil
+	fake.runDetachedReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeConnection) AttachByName(handle string, name string, io garden.ProcessIO) (garden.Process, error) {
+	fake.attachByNameMutex.Lock()
+	fake.attachByNameArgsForCall = append(fake.attachByNameArgsForCall, struct {
+		handle string
+		name   string
+		io     garden.ProcessIO
+	}{handle, name, io})
+	fake.attachByNameMutex.Unlock()
+	if fake.AttachByNameStub != nil {
+		return fake.AttachByNameStub(handle, name, io)
+	} else {
+		return fake.attachByNameReturns.result1, fake.attachByNameReturns.result2
+	}
+}
+
+func (fake *FakeConnection) AttachByNameCallCount() int {
+	fake.attachByNameMutex.RLock()
+	defer fake.attachByNameMutex.RUnlock()
+	return len(fake.attachByNameArgsForCall)
+}
+
+func (fake *FakeConnection) AttachByNameArgsForCall(i int) (string, string, garden.ProcessIO) {
+	fake.attachByNameMutex.RLock()
+	defer fake.attachByNameMutex.RUnlock()
+	return fake.attachByNameArgsForCall[i].handle, fake.attachByNameArgsForCall[i].name, fake.attachByNameArgsForCall[i].io
+}
+
+func (fake *FakeConnection) AttachByNameReturns(result1 garden.Process, result2 error) {
+	fake.AttachByNameStub = nil
+	fake.attachByNameReturns = struct {
+		result1 garden.Process
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeConnection) SignalByName(handle string, name string, signal garden.Signal) error {
+	fake.signalByNameMutex.Lock()
+	fake.signalByNameArgsForCall = append(fake.signalByNameArgsForCall, struct {
+		handle string
+		name   string
+		signal garden.Signal
+	}{handle, name, signal})
+	fake.signalByNameMutex.Unlock()
+	if fake.SignalByNameStub != nil {
+		return fake.SignalByNameStub(handle, name, signal)
+	} else {
+		return fake.signalByNameReturns.result1
+	}
+}
+
+func (fake *FakeConnection) SignalByNameCallCount() int {
+	fake.signalByNameMutex.RLock()
+	defer fake.signalByNameMutex.RUnlock()
+	return len(fake.signalByNameArgsForCall)
+}
+
+func (fake *FakeConnection) SignalByNameArgsForCall(i int) (string, string, garden.Signal) {
+	fake.signalByNameMutex.RLock()
+	defer fake.signalByNameMutex.RUnlock()
+	return fake.signalByNameArgsForCall[i].handle, fake.signalByNameArgsForCall[i].name, fake.signalByNameArgsForCall[i].signal
+}
+
+func (fake *FakeConnection) SignalByNameReturns(result1 error) {
+	fake.SignalByNameStub = nil
+	fake.signalByNameReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeConnection) ProcessExitByName(handle string, name string) (garden.ProcessExit, error) {
+	fake.processExitByNameMutex.Lock()
+	fake.processExitByNameArgsForCall = append(fake.processExitByNameArgsForCall, struct {
+		handle string
+		name   string
+	}{handle, name})
+	fake.processExitByNameMutex.Unlock()
+	if fake.ProcessExitByNameStub != nil {
+		return fake.ProcessExitByNameStub(handle, name)
+	} else {
+		return fake.processExitByNameReturns.result1, fake.processExitByNameReturns.result2
+	}
+}
+
+func (fake *FakeConnection) ProcessExitByNameCallCount() int {
+	fake.processExitByNameMutex.RLock()
+	defer fake.processExitByNameMutex.RUnlock()
+	return len(fake.processExitByNameArgsForCall)
+}
+
+func (fake *FakeConnection) ProcessExitByNameArgsForCall(i int) (string, string) {
+	fake.processExitByNameMutex.RLock()
+	defer fake.processExitByNameMutex.RUnlock()
+	return fake.processExitByNameArgsForCall[i].handle, fake.processExitByNameArgsForCall[i].name
+}
+
+func (fake *FakeConnection) ProcessExitByNameReturns(result1 garden.ProcessExit, result2 error) {
+	fake.ProcessExitByNameStub = nil
+	fake.processExitByNameReturns = struct {
+		result1 garden.ProcessExit
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeConnection) CASProperty(handle string, name string, oldValue string, newValue string) error {
+	fake.cASPropertyMutex.Lock()
+	fake.cASPropertyArgsForCall = append(fake.cASPropertyArgsForCall, struct {
+		handle   string
+		name     string
+		oldValue string
+		newValue string
+	}{handle, name, oldValue, newValue})
+	fake.cASPropertyMutex.Unlock()
+	if fake.CASPropertyStub != nil {
+		return fake.CASPropertyStub(handle, name, oldValue, newValue)
+	} else {
+		return fake.cASPropertyReturns.result1
+	}
+}
+
+func (fake *FakeConnection) CASPropertyCallCount() int {
+	fake.cASPropertyMutex.RLock()
+	defer fake.cASPropertyMutex.RUnlock()
+	return len(fake.cASPropertyArgsForCall)
+}
+
+func (fake *FakeConnection) CASPropertyArgsForCall(i int) (string, string, string, string) {
+	fake.cASPropertyMutex.RLock()
+	defer fake.cASPropertyMutex.RUnlock()
+	return fake.cASPropertyArgsForCall[i].handle, fake.cASPropertyArgsForCall[i].name, fake.cASPropertyArgsForCall[i].oldValue, fake.cASPropertyArgsForCall[i].newValue
+}
+
+func (fake *FakeConnection) CASPropertyReturns(result1 error) {
+	fake.CASPropertyStub = nil
+	fake.cASPropertyReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeConnection) SetProperties(handle string, properties garden.Properties) error {
+	fake.setPropertiesMutex.Lock()
+	fake.setPropertiesArgsForCall = append(fake.setPropertiesArgsForCall, struct {
+		handle     string
+		properties garden.Properties
+	}{handle, properties})
+	fake.setPropertiesMutex.Unlock()
+	if fake.SetPropertiesStub != nil {
+		return fake.SetPropertiesStub(handle, properties)
+	} else {
+		return fake.setPropertiesReturns.result1
+	}
+}
+
+func (fake *FakeConnection) SetPropertiesCallCount() int {
+	fake.setPropertiesMutex.RLock()
+	defer fake.setPropertiesMutex.RUnlock()
+	return len(fake.setPropertiesArgsForCall)
+}
+
+func (fake *FakeConnection) SetPropertiesArgsForCall(i int) (string, garden.Properties) {
+	fake.setPropertiesMutex.RLock()
+	defer fake.setPropertiesMutex.RUnlock()
+	return fake.setPropertiesArgsForCall[i].handle, fake.setPropertiesArgsForCall[i].properties
+}
+
+func (fake *FakeConnection) SetPropertiesReturns(result1 error) {
+	fake.SetPropertiesStub = nil
+	fake.setPropertiesReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeConnection) RemoveProperties(handle string, names []string) error {
+	fake.removePropertiesMutex.Lock()
+	fake.removePropertiesArgsForCall = append(fake.removePropertiesArgsForCall, struct {
+		handle string
+		names  []string
+	}{handle, names})
+	fake.removePropertiesMutex.Unlock()
+	if fake.RemovePropertiesStub != nil {
+		return fake.RemovePropertiesStub(handle, names)
+	} else {
+		return fake.removePropertiesReturns.result1
+	}
+}
+
+func (fake *FakeConnection) RemovePropertiesCallCount() int {
+	fake.removePropertiesMutex.RLock()
+	defer fake.removePropertiesMutex.RUnlock()
+	return len(fake.removePropertiesArgsForCall)
+}
+
+func (fake *FakeConnection) RemovePropertiesArgsForCall(i int) (string, []string) {
+	fake.removePropertiesMutex.RLock()
+	defer fake.removePropertiesMutex.RUnlock()
+	return fake.removePropertiesArgsForCall[i].handle, fake.removePropertiesArgsForCall[i].names
+}
+
+func (fake *FakeConnection) RemovePropertiesReturns(result1 error) {
+	fake.RemovePropertiesStub = nil
+	fake.removePropertiesReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeConnection) Drain() (garden.DrainStatus, error) {
+	fake.drainMutex.Lock()
+	fake.drainArgsForCall = append(fake.drainArgsForCall, struct{}{})
+	fake.drainMutex.Unlock()
+	if fake.DrainStub != nil {
+		return fake.DrainStub()
+	} else {
+		return fake.drainReturns.result1, fake.drainReturns.result2
+	}
+}
+
+func (fake *FakeConnection) DrainCallCount() int {
+	fake.drainMutex.RLock()
+	defer fake.drainMutex.RUnlock()
+	return len(fake.drainArgsForCall)
+}
+
+func (fake *FakeConnection) DrainReturns(result1 garden.DrainStatus, result2 error) {
+	fake.DrainStub = nil
+	fake.drainReturns = struct {
+		result1 garden.DrainStatus
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeConnection) Undrain() (garden.DrainStatus, error) {
+	fake.undrainMutex.Lock()
+	fake.undrainArgsForCall = append(fake.undrainArgsForCall, struct{}{})
+	fake.undrainMutex.Unlock()
+	if fake.UndrainStub != nil {
+		return fake.UndrainStub()
+	} else {
+		return fake.undrainReturns.result1, fake.undrainReturns.result2
+	}
+}
+
+func (fake *FakeConnection) UndrainCallCount() int {
+	fake.undrainMutex.RLock()
+	defer fake.undrainMutex.RUnlock()
+	return len(fake.undrainArgsForCall)
+}
+
+func (fake *FakeConnection) UndrainReturns(result1 garden.DrainStatus, result2 error) {
+	fake.UndrainStub = nil
+	fake.undrainReturns = struct {
+		result1 garden.DrainStatus
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeConnection) DrainStatus() (garden.DrainStatus, error) {
+	fake.drainStatusMutex.Lock()
+	fake.drainStatusArgsForCall = append(fake.drainStatusArgsForCall, struct{}{})
+	fake.drainStatusMutex.Unlock()
+	if fake.DrainStatusStub != nil {
+		return fake.DrainStatusStub()
+	} else {
+		return fake.drainStatusReturns.result1, fake.drainStatusReturns.result2
+	}
+}
+
+func (fake *FakeConnection) DrainStatusCallCount() int {
+	fake.drainStatusMutex.RLock()
+	defer fake.drainStatusMutex.RUnlock()
+	return len(fake.drainStatusArgsForCall)
+}
+
+func (fake *FakeConnection) DrainStatusReturns(result1 garden.DrainStatus, result2 error) {
+	fake.DrainStatusStub = nil
+	fake.drainStatusReturns = struct {
+		result1 garden.DrainStatus
+		result2 error
+	}{result1, result2}
+}
+
 var _ connection.Connection = new(FakeConnection)
@@ -0,0 +1,153 @@
+package connection
+
+import (
+	"runtime/debug"
+	"sort"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/lager"
+)
+
+// Leak is a connection which has remained open for longer than a
+// LeakDetector's threshold.
+type Leak struct {
+	StreamEvent
+
+	OpenedAt time.Time
+
+	// Stack is the stack trace of the goroutine which opened the connection.
+	Stack string
+}
+
+// LeakDetector tracks the connections opened by a HijackStreamer, such as
+// process streams and response bodies, and reports those which have not been
+// closed within a threshold. It is intended for debugging file descriptor
+// leaks in long-running consumers, e.g.:
+//
+//	detector := connection.NewLeakDetector(time.Hour, logger)
+//	hijacker := connection.NewObservedHijackStreamer(
+//		connection.NewHijackStreamer(network, address),
+//		detector.Hooks(),
+//	)
+//	conn := connection.NewWithHijacker(hijacker, logger)
+//	detector.Start(time.Minute)
+//
+// Capturing a stack trace for every connection is expensive, so it should not
+// be left enabled unless needed.
+type LeakDetector struct {
+	threshold time.Duration
+	logger    lager.Logger
+
+	outstanding map[uint64]Leak
+	mu          sync.Mutex
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+func NewLeakDetector(threshold time.Duration, logger lager.Logger) *LeakDetector {
+	return &LeakDetector{
+		threshold: threshold,
+		logger:    logger.Session("leak-detector"),
+
+		outstanding: make(map[uint64]Leak),
+	}
+}
+
+// Hooks returns the StreamHooks with which to observe a HijackStreamer.
+func (d *LeakDetector) Hooks() StreamHooks {
+	return StreamHooks{
+		OnOpen:  d.opened,
+		OnClose: d.closed,
+	}
+}
+
+func (d *LeakDetector) opened(event StreamEvent) {
+	leak := Leak{
+		StreamEvent: event,
+		OpenedAt:    time.Now(),
+		Stack:       string(debug.Stack()),
+	}
+
+	d.mu.Lock()
+	d.outstanding[event.ID] = leak
+	d.mu.Unlock()
+}
+
+func (d *LeakDetector) closed(event StreamEvent) {
+	d.mu.Lock()
+	delete(d.outstanding, event.ID)
+	d.mu.Unlock()
+}
+
+// Leaks returns the connections which have been open for longer than the
+// threshold, oldest first.
+func (d *LeakDetector) Leaks() []Leak {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	leaks := []Leak{}
+	for _, leak := range d.outstanding {
+		if time.Since(leak.OpenedAt) > d.threshold {
+			leaks = append(leaks, leak)
+		}
+	}
+
+	sort.Sort(byAge(leaks))
+
+	return leaks
+}
+
+type byAge []Leak
+
+func (l byAge) Len() int           { return len(l) }
+func (l byAge) Swap(i, j int)      { l[i], l[j] = l[j], l[i] }
+func (l byAge) Less(i, j int) bool { return l[i].OpenedAt.Before(l[j].OpenedAt) }
+
+// Report logs each of the current Leaks, returning how many there were.
+func (d *LeakDetector) Report() int {
+	leaks := d.Leaks()
+	for _, leak := range leaks {
+		d.logger.Info("leaked", lager.Data{
+			"route":    leak.Route,
+			"handle":   leak.Handle,
+			"hijacked": leak.Hijacked,
+			"age":      time.Since(leak.OpenedAt).String(),
+			"stack":    leak.Stack,
+		})
+	}
+
+	return len(leaks)
+}
+
+// Start reports leaks at the given interval until Stop is called.
+func (d *LeakDetector) Start(interval time.Duration) {
+	d.stop = make(chan struct{})
+	d.done = make(chan struct{})
+
+	go func() {
+		defer close(d.done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				d.Report()
+			case <-d.stop:
+				return
+			}
+		}
+	}()
+}
+
+func (d *LeakDetector) Stop() {
+	if d.stop == nil {
+		return
+	}
+
+	close(d.stop)
+	<-d.done
+}
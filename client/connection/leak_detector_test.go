@@ -0,0 +1,88 @@
+package connection_test
+
+import (
+	"bufio"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/garden/client/connection"
+	"code.cloudfoundry.org/garden/client/connection/connectionfakes"
+	"code.cloudfoundry.org/garden/routes"
+	"code.cloudfoundry.org/lager/lagertest"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/tedsuo/rata"
+)
+
+var _ = Describe("LeakDetector", func() {
+	var (
+		innerHijacker *connectionfakes.FakeHijackStreamer
+		logger        *lagertest.TestLogger
+		detector      *connection.LeakDetector
+		hijacker      connection.HijackStreamer
+	)
+
+	BeforeEach(func() {
+		innerHijacker = new(connectionfakes.FakeHijackStreamer)
+		innerHijacker.StreamStub = func(string, io.Reader, rata.Params, url.Values, string) (io.ReadCloser, error) {
+			return ioutil.NopCloser(strings.NewReader("")), nil
+		}
+		innerHijacker.HijackStub = func(string, io.Reader, rata.Params, url.Values, string) (net.Conn, *bufio.Reader, error) {
+			conn, _ := net.Pipe()
+			return conn, bufio.NewReader(conn), nil
+		}
+
+		logger = lagertest.NewTestLogger("test")
+		detector = connection.NewLeakDetector(50*time.Millisecond, logger)
+		hijacker = connection.NewObservedHijackStreamer(innerHijacker, detector.Hooks())
+	})
+
+	AfterEach(func() {
+		detector.Stop()
+	})
+
+	It("reports connections left open for longer than the threshold", func() {
+		leaked, _, err := hijacker.Hijack(routes.Attach, nil, rata.Params{"handle": "some-handle"}, nil, "")
+		Expect(err).NotTo(HaveOccurred())
+		defer leaked.Close()
+
+		closed, err := hijacker.Stream(routes.StreamOut, nil, rata.Params{"handle": "other-handle"}, nil, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(closed.Close()).To(Succeed())
+
+		Expect(detector.Leaks()).To(BeEmpty())
+
+		Eventually(detector.Leaks).Should(HaveLen(1))
+
+		leaks := detector.Leaks()
+		Expect(leaks[0].Route).To(Equal(routes.Attach))
+		Expect(leaks[0].Handle).To(Equal("some-handle"))
+		Expect(leaks[0].Hijacked).To(BeTrue())
+		Expect(leaks[0].Stack).To(ContainSubstring("leak_detector_test.go"))
+	})
+
+	It("stops reporting connections once they are closed", func() {
+		stream, err := hijacker.Stream(routes.StreamOut, nil, rata.Params{"handle": "some-handle"}, nil, "")
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(detector.Leaks).Should(HaveLen(1))
+
+		Expect(stream.Close()).To(Succeed())
+		Expect(detector.Leaks()).To(BeEmpty())
+	})
+
+	It("periodically logs leaks once started", func() {
+		_, err := hijacker.Stream(routes.StreamOut, nil, rata.Params{"handle": "some-handle"}, nil, "")
+		Expect(err).NotTo(HaveOccurred())
+
+		detector.Start(10 * time.Millisecond)
+
+		Eventually(logger).Should(gbytes.Say("leak-detector.leaked"))
+		Expect(detector.Report()).To(Equal(1))
+	})
+})
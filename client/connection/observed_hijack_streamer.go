@@ -0,0 +1,172 @@
+package connection
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/tedsuo/rata"
+)
+
+// StreamEvent describes a connection to the server opened by a
+// HijackStreamer, for the purpose of detecting leaked streams.
+type StreamEvent struct {
+	// ID distinguishes the connections opened by a HijackStreamer, so that
+	// close events may be matched up with open events.
+	ID uint64
+
+	// Route is the name of the route the connection was opened for.
+	Route string
+
+	// Handle is the container the connection concerns, if any.
+	Handle string
+
+	// Hijacked is true for connections that were hijacked, e.g. to stream
+	// process input and output, rather than plain request streams.
+	Hijacked bool
+
+	// Duration, BytesRead and BytesWritten are only set when the connection
+	// is closed.
+	Duration     time.Duration
+	BytesRead    int64
+	BytesWritten int64
+}
+
+// StreamHooks are called as connections are opened and closed. Either may be
+// nil.
+type StreamHooks struct {
+	OnOpen  func(StreamEvent)
+	OnClose func(StreamEvent)
+}
+
+type observedHijackStreamer struct {
+	lastID uint64
+
+	hijacker HijackStreamer
+	hooks    StreamHooks
+}
+
+// NewObservedHijackStreamer wraps a HijackStreamer so that the hooks are
+// called whenever it opens a connection and when that connection is closed.
+func NewObservedHijackStreamer(hijacker HijackStreamer, hooks StreamHooks) HijackStreamer {
+	return &observedHijackStreamer{
+		hijacker: hijacker,
+		hooks:    hooks,
+	}
+}
+
+func (o *observedHijackStreamer) Hijack(handler string, body io.Reader, params rata.Params, query url.Values, contentType string) (net.Conn, *bufio.Reader, error) {
+	conn, br, err := o.hijacker.Hijack(handler, body, params, query, contentType)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stream := o.open(StreamEvent{Route: handler, Handle: params["handle"], Hijacked: true})
+	observed := &observedConn{Conn: conn, stream: stream}
+
+	return observed, bufio.NewReader(&countingReader{r: br, stream: stream}), nil
+}
+
+func (o *observedHijackStreamer) Stream(handler string, body io.Reader, params rata.Params, query url.Values, contentType string) (io.ReadCloser, error) {
+	rc, err := o.hijacker.Stream(handler, body, params, query, contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	stream := o.open(StreamEvent{Route: handler, Handle: params["handle"]})
+
+	return &observedReadCloser{r: &countingReader{r: rc, stream: stream}, closer: rc, stream: stream}, nil
+}
+
+func (o *observedHijackStreamer) open(event StreamEvent) *observedStream {
+	event.ID = atomic.AddUint64(&o.lastID, 1)
+
+	if o.hooks.OnOpen != nil {
+		o.hooks.OnOpen(event)
+	}
+
+	return &observedStream{
+		event:   event,
+		opened:  time.Now(),
+		onClose: o.hooks.OnClose,
+	}
+}
+
+type observedStream struct {
+	event   StreamEvent
+	opened  time.Time
+	onClose func(StreamEvent)
+
+	bytesRead    int64
+	bytesWritten int64
+	closeOnce    sync.Once
+}
+
+func (s *observedStream) closed() {
+	s.closeOnce.Do(func() {
+		if s.onClose == nil {
+			return
+		}
+
+		event := s.event
+		event.Duration = time.Since(s.opened)
+		event.BytesRead = atomic.LoadInt64(&s.bytesRead)
+		event.BytesWritten = atomic.LoadInt64(&s.bytesWritten)
+
+		s.onClose(event)
+	})
+}
+
+type countingReader struct {
+	r      io.Reader
+	stream *observedStream
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	atomic.AddInt64(&c.stream.bytesRead, int64(n))
+	return n, err
+}
+
+type observedConn struct {
+	net.Conn
+	stream *observedStream
+}
+
+func (c *observedConn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	atomic.AddInt64(&c.stream.bytesRead, int64(n))
+	return n, err
+}
+
+func (c *observedConn) Write(p []byte) (int, error) {
+	n, err := c.Conn.Write(p)
+	atomic.AddInt64(&c.stream.bytesWritten, int64(n))
+	return n, err
+}
+
+func (c *observedConn) Close() error {
+	err := c.Conn.Close()
+	c.stream.closed()
+	return err
+}
+
+type observedReadCloser struct {
+	r      io.Reader
+	closer io.Closer
+	stream *observedStream
+}
+
+func (o *observedReadCloser) Read(p []byte) (int, error) {
+	return o.r.Read(p)
+}
+
+func (o *observedReadCloser) Close() error {
+	err := o.closer.Close()
+	o.stream.closed()
+	return err
+}
@@ -0,0 +1,132 @@
+package connection_test
+
+import (
+	"bufio"
+	"errors"
+	"io/ioutil"
+	"net"
+	"strings"
+
+	"code.cloudfoundry.org/garden/client/connection"
+	"code.cloudfoundry.org/garden/client/connection/connectionfakes"
+	"code.cloudfoundry.org/garden/routes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/tedsuo/rata"
+)
+
+var _ = Describe("ObservedHijackStreamer", func() {
+	var (
+		innerHijacker *connectionfakes.FakeHijackStreamer
+		opened        []connection.StreamEvent
+		closed        []connection.StreamEvent
+		hijacker      connection.HijackStreamer
+	)
+
+	BeforeEach(func() {
+		innerHijacker = new(connectionfakes.FakeHijackStreamer)
+		opened = nil
+		closed = nil
+
+		hijacker = connection.NewObservedHijackStreamer(innerHijacker, connection.StreamHooks{
+			OnOpen:  func(e connection.StreamEvent) { opened = append(opened, e) },
+			OnClose: func(e connection.StreamEvent) { closed = append(closed, e) },
+		})
+	})
+
+	Describe("Hijack", func() {
+		var serverConn net.Conn
+
+		BeforeEach(func() {
+			var clientConn net.Conn
+			clientConn, serverConn = net.Pipe()
+			innerHijacker.HijackReturns(clientConn, bufio.NewReader(clientConn), nil)
+		})
+
+		It("fires the hooks with the route, handle and bytes transferred", func() {
+			conn, br, err := hijacker.Hijack(routes.Attach, nil, rata.Params{"handle": "some-handle", "pid": "some-pid"}, nil, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(opened).To(HaveLen(1))
+			Expect(opened[0].Route).To(Equal(routes.Attach))
+			Expect(opened[0].Handle).To(Equal("some-handle"))
+			Expect(opened[0].Hijacked).To(BeTrue())
+			Expect(closed).To(BeEmpty())
+
+			go func() {
+				defer GinkgoRecover()
+				buf := make([]byte, 3)
+				_, err := serverConn.Read(buf)
+				Expect(err).NotTo(HaveOccurred())
+				_, err = serverConn.Write([]byte("hello\n"))
+				Expect(err).NotTo(HaveOccurred())
+			}()
+
+			_, err = conn.Write([]byte("abc"))
+			Expect(err).NotTo(HaveOccurred())
+
+			line, err := br.ReadString('\n')
+			Expect(err).NotTo(HaveOccurred())
+			Expect(line).To(Equal("hello\n"))
+
+			Expect(conn.Close()).To(Succeed())
+			conn.Close()
+
+			Expect(closed).To(HaveLen(1))
+			Expect(closed[0].ID).To(Equal(opened[0].ID))
+			Expect(closed[0].Route).To(Equal(routes.Attach))
+			Expect(closed[0].Handle).To(Equal("some-handle"))
+			Expect(closed[0].BytesWritten).To(BeEquivalentTo(3))
+			Expect(closed[0].BytesRead).To(BeEquivalentTo(6))
+			Expect(closed[0].Duration).To(BeNumerically(">", 0))
+		})
+
+		Context("when hijacking fails", func() {
+			BeforeEach(func() {
+				innerHijacker.HijackReturns(nil, nil, errors.New("boom"))
+			})
+
+			It("returns the error without firing the hooks", func() {
+				_, _, err := hijacker.Hijack(routes.Run, nil, nil, nil, "")
+				Expect(err).To(MatchError("boom"))
+				Expect(opened).To(BeEmpty())
+			})
+		})
+	})
+
+	Describe("Stream", func() {
+		BeforeEach(func() {
+			innerHijacker.StreamReturns(ioutil.NopCloser(strings.NewReader("some-body")), nil)
+		})
+
+		It("fires the hooks with the route, handle and bytes read", func() {
+			rc, err := hijacker.Stream(routes.StreamOut, nil, rata.Params{"handle": "some-handle"}, nil, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(opened).To(HaveLen(1))
+			Expect(opened[0].Route).To(Equal(routes.StreamOut))
+			Expect(opened[0].Handle).To(Equal("some-handle"))
+			Expect(opened[0].Hijacked).To(BeFalse())
+
+			body, err := ioutil.ReadAll(rc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(body)).To(Equal("some-body"))
+			Expect(closed).To(BeEmpty())
+
+			Expect(rc.Close()).To(Succeed())
+
+			Expect(closed).To(HaveLen(1))
+			Expect(closed[0].BytesRead).To(BeEquivalentTo(9))
+			Expect(closed[0].BytesWritten).To(BeZero())
+		})
+
+		Context("when there are no hooks", func() {
+			It("still streams", func() {
+				hijacker = connection.NewObservedHijackStreamer(innerHijacker, connection.StreamHooks{})
+				rc, err := hijacker.Stream(routes.List, nil, nil, nil, "")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(rc.Close()).To(Succeed())
+			})
+		})
+	})
+})
@@ -1,11 +1,23 @@
 package connection
 
 import (
+	"context"
+	"fmt"
 	"sync"
 
 	"code.cloudfoundry.org/garden"
 )
 
+// WaitCancelledError is returned by WaitWithContext when the context is done
+// before the process exits. The remote process is left running.
+type WaitCancelledError struct {
+	Cause error
+}
+
+func (err WaitCancelledError) Error() string {
+	return fmt.Sprintf("connection: wait cancelled: %s", err.Cause)
+}
+
 type process struct {
 	id string
 
@@ -14,6 +26,7 @@ type process struct {
 	exitStatus         int
 	exitErr            error
 	doneL              *sync.Cond
+	doneCh             chan struct{}
 }
 
 func newProcess(id string, processInputStream *processStream) *process {
@@ -21,6 +34,7 @@ func newProcess(id string, processInputStream *processStream) *process {
 		id:                 id,
 		processInputStream: processInputStream,
 		doneL:              sync.NewCond(&sync.Mutex{}),
+		doneCh:             make(chan struct{}),
 	}
 }
 
@@ -40,6 +54,18 @@ func (p *process) Wait() (int, error) {
 	return p.exitStatus, p.exitErr
 }
 
+// WaitWithContext is like Wait but returns a WaitCancelledError if ctx is done
+// before the process exits. Failures of the process stream are returned as
+// from Wait.
+func (p *process) WaitWithContext(ctx context.Context) (int, error) {
+	select {
+	case <-p.doneCh:
+		return p.Wait()
+	case <-ctx.Done():
+		return 0, WaitCancelledError{Cause: ctx.Err()}
+	}
+}
+
 func (p *process) SetTTY(tty garden.TTYSpec) error {
 	return p.processInputStream.SetTTY(tty)
 }
@@ -48,11 +74,16 @@ func (p *process) Signal(signal garden.Signal) error {
 	return p.processInputStream.Signal(signal)
 }
 
+func (p *process) CloseStdin() error {
+	return p.processInputStream.CloseStdin()
+}
+
 func (p *process) exited(exitStatus int, err error) {
 	p.doneL.L.Lock()
 	p.exitStatus = exitStatus
 	p.exitErr = err
 	p.done = true
+	close(p.doneCh)
 	p.doneL.L.Unlock()
 
 	p.doneL.Broadcast()
@@ -1,8 +1,9 @@
 package connection
 
 import (
-	"net"
+	"io"
 	"sync"
+	"time"
 
 	"code.cloudfoundry.org/garden"
 	"code.cloudfoundry.org/garden/transport"
@@ -10,29 +11,58 @@ import (
 
 type processStream struct {
 	processID string
-	conn      net.Conn
+	encoder   transport.PayloadEncoder
+
+	stdinClosed bool
 
 	sync.Mutex
 }
 
+// maxStdinChunkSize is the most stdin sent in one payload, so that payloads
+// stay well within what servers accept.
+const maxStdinChunkSize = 1 << 20
+
 func (s *processStream) Write(data []byte) (int, error) {
-	d := string(data)
 	stdin := transport.Stdin
-	return len(data), s.sendPayload(transport.ProcessPayload{
-		ProcessID: s.processID,
-		Source:    &stdin,
-		Data:      &d,
-	})
+
+	written := 0
+	for written < len(data) {
+		chunk := data[written:]
+		if len(chunk) > maxStdinChunkSize {
+			chunk = chunk[:maxStdinChunkSize]
+		}
+
+		d := string(chunk)
+		err := s.sendStdinPayload(&transport.ProcessPayload{
+			ProcessID: s.processID,
+			Source:    &stdin,
+			Data:      &d,
+		})
+		if err != nil {
+			return written, err
+		}
+
+		written += len(chunk)
+	}
+
+	return written, nil
 }
 
 func (s *processStream) Close() error {
 	stdin := transport.Stdin
-	return s.sendPayload(transport.ProcessPayload{
+	return s.closeStdin(&transport.ProcessPayload{
 		ProcessID: s.processID,
 		Source:    &stdin,
 	})
 }
 
+func (s *processStream) CloseStdin() error {
+	return s.closeStdin(&transport.ProcessPayload{
+		ProcessID:  s.processID,
+		CloseStdin: true,
+	})
+}
+
 func (s *processStream) SetTTY(spec garden.TTYSpec) error {
 	return s.sendPayload(&transport.ProcessPayload{
 		ProcessID: s.processID,
@@ -47,10 +77,19 @@ func (s *processStream) Signal(signal garden.Signal) error {
 	})
 }
 
-func (s *processStream) sendPayload(payload interface{}) error {
+// Heartbeat echoes a heartbeat back to the server, so that it does not close
+// the connection as idle.
+func (s *processStream) Heartbeat(interval time.Duration) error {
+	return s.sendPayload(&transport.ProcessPayload{
+		ProcessID: s.processID,
+		Heartbeat: &interval,
+	})
+}
+
+func (s *processStream) sendPayload(payload *transport.ProcessPayload) error {
 	s.Lock()
 
-	err := transport.WriteMessage(s.conn, payload)
+	err := s.encoder.Encode(payload)
 	if err != nil {
 		s.Unlock()
 		return err
@@ -61,6 +100,30 @@ func (s *processStream) sendPayload(payload interface{}) error {
 	return nil
 }
 
+func (s *processStream) sendStdinPayload(payload *transport.ProcessPayload) error {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.stdinClosed {
+		return io.ErrClosedPipe
+	}
+
+	return s.encoder.Encode(payload)
+}
+
+func (s *processStream) closeStdin(payload *transport.ProcessPayload) error {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.stdinClosed {
+		return nil
+	}
+
+	s.stdinClosed = true
+
+	return s.encoder.Encode(payload)
+}
+
 func (s *processStream) ProcessID() string {
 	return s.processID
 }
@@ -1,16 +1,21 @@
 package connection
 
 import (
-	"encoding/json"
 	"fmt"
 	"io"
 	"net"
 	"sync"
+	"time"
 
+	"code.cloudfoundry.org/garden"
 	"code.cloudfoundry.org/garden/transport"
 	"code.cloudfoundry.org/lager"
 )
 
+// heartbeatTolerance is the number of heartbeat intervals that may pass
+// without any frame arriving before a process stream is considered stalled.
+const heartbeatTolerance = 3
+
 type hijackFunc func(streamType string) (net.Conn, io.Reader, error)
 
 type streamHandler struct {
@@ -33,7 +38,7 @@ func (sh *streamHandler) streamIn(processWriter io.WriteCloser, stdin io.Reader)
 	go func(processInputStream io.WriteCloser, stdin io.Reader, log lager.Logger) {
 		if _, err := io.Copy(processInputStream, stdin); err == nil {
 			processInputStream.Close()
-		} else {
+		} else if err != io.ErrClosedPipe {
 			log.Error("streaming-stdin-payload", err)
 		}
 	}(processWriter, stdin, sh.log)
@@ -47,15 +52,32 @@ func (sh *streamHandler) streamOut(streamWriter io.Writer, streamReader io.Reade
 	}()
 }
 
-func (sh *streamHandler) wait(decoder *json.Decoder) (int, error) {
+func (sh *streamHandler) wait(decoder transport.PayloadDecoder, conn net.Conn, stream *processStream) (int, error) {
 	for {
 		payload := &transport.ProcessPayload{}
 		err := decoder.Decode(payload)
 		if err != nil {
+			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+				// the output streams are likely dead too; don't wait on them
+				return 0, ErrStreamStalled
+			}
+
 			sh.wg.Wait()
 			return 0, fmt.Errorf("connection: decode failed: %s", err)
 		}
 
+		if payload.Heartbeat != nil {
+			conn.SetReadDeadline(time.Now().Add(heartbeatTolerance * *payload.Heartbeat))
+
+			if payload.IdleTimeout != nil {
+				if err := stream.Heartbeat(*payload.Heartbeat); err != nil {
+					sh.log.Error("echoing-heartbeat", err)
+				}
+			}
+
+			continue
+		}
+
 		if payload.Error != nil {
 			sh.wg.Wait()
 			return 0, fmt.Errorf("connection: process error: %s", *payload.Error)
@@ -64,6 +86,11 @@ func (sh *streamHandler) wait(decoder *json.Decoder) (int, error) {
 		if payload.ExitStatus != nil {
 			sh.wg.Wait()
 			status := int(*payload.ExitStatus)
+
+			if payload.TimedOut {
+				return status, garden.ProcessTimedOutError{ProcessID: payload.ProcessID}
+			}
+
 			return status, nil
 		}
 
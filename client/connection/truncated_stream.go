@@ -0,0 +1,70 @@
+package connection
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+// ErrStreamDeadlineExceeded is the cause of a TruncatedStreamError when the
+// stream is abandoned because its deadline passed.
+var ErrStreamDeadlineExceeded = errors.New("stream deadline exceeded")
+
+// TruncatedStreamError is returned when reading a StreamOut stream fails
+// before all of it has been received. Offset is the number of bytes read
+// before the failure; the transfer may be resumed from the last complete tar
+// entry with garden.StreamOutSpec's ResumeFrom.
+type TruncatedStreamError struct {
+	Offset int64
+	Cause  error
+}
+
+func (err TruncatedStreamError) Error() string {
+	return fmt.Sprintf("connection: stream truncated at offset %d: %s", err.Offset, err.Cause)
+}
+
+type truncationDetectingReader struct {
+	stream io.ReadCloser
+	offset int64
+
+	timer            *time.Timer
+	deadlineExceeded int32
+}
+
+func newTruncationDetectingReader(stream io.ReadCloser, deadline time.Time) *truncationDetectingReader {
+	r := &truncationDetectingReader{stream: stream}
+
+	if !deadline.IsZero() {
+		r.timer = time.AfterFunc(time.Until(deadline), func() {
+			atomic.StoreInt32(&r.deadlineExceeded, 1)
+			stream.Close()
+		})
+	}
+
+	return r
+}
+
+func (r *truncationDetectingReader) Read(p []byte) (int, error) {
+	n, err := r.stream.Read(p)
+	r.offset += int64(n)
+
+	if err == nil || err == io.EOF {
+		return n, err
+	}
+
+	if atomic.LoadInt32(&r.deadlineExceeded) == 1 {
+		err = ErrStreamDeadlineExceeded
+	}
+
+	return n, TruncatedStreamError{Offset: r.offset, Cause: err}
+}
+
+func (r *truncationDetectingReader) Close() error {
+	if r.timer != nil {
+		r.timer.Stop()
+	}
+
+	return r.stream.Close()
+}
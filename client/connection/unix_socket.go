@@ -0,0 +1,101 @@
+package connection
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+	"time"
+)
+
+// socketPollInterval is how often UnixSocketDialer checks whether a socket
+// it is waiting for has appeared.
+const socketPollInterval = 100 * time.Millisecond
+
+// UnixSocketError explains why a unix socket could not be dialed, e.g. that
+// the client may not write to it, or that the server which created it has
+// exited.
+type UnixSocketError struct {
+	// Path is the path of the socket.
+	Path string
+
+	// Reason explains the failure.
+	Reason string
+
+	// Exists reports whether there is a file at the path, in which case Mode,
+	// UID and GID describe it. UID and GID are -1 where they are unknown.
+	Exists bool
+	Mode   os.FileMode
+	UID    int
+	GID    int
+
+	// Err is the error returned by the dial.
+	Err error
+}
+
+func (err *UnixSocketError) Error() string {
+	return fmt.Sprintf("dial unix %s: %s", err.Path, err.Reason)
+}
+
+func (err *UnixSocketError) Unwrap() error {
+	return err.Err
+}
+
+// UnixSocketDialer returns a dialer for the unix socket at the path which
+// explains failures with a *UnixSocketError. If the socket does not exist,
+// it is polled for until it appears or the wait elapses, e.g. so that a
+// client started alongside the server does not race it.
+func UnixSocketDialer(path string, wait time.Duration) DialerFunc {
+	return func(string, string) (net.Conn, error) {
+		deadline := time.Now().Add(wait)
+
+		for {
+			conn, err := net.DialTimeout("unix", path, 2*time.Second)
+			if err == nil {
+				return conn, nil
+			}
+
+			if errors.Is(err, syscall.ENOENT) && time.Now().Before(deadline) {
+				time.Sleep(socketPollInterval)
+				continue
+			}
+
+			return nil, explainUnixSocketError(path, err)
+		}
+	}
+}
+
+func explainUnixSocketError(path string, dialErr error) error {
+	socketErr := &UnixSocketError{Path: path, UID: -1, GID: -1, Err: dialErr}
+
+	info, err := os.Stat(path)
+	switch {
+	case os.IsNotExist(err):
+		socketErr.Reason = "socket does not exist: is the server running?"
+		return socketErr
+	case err != nil:
+		socketErr.Reason = fmt.Sprintf("cannot stat socket: %s", err)
+		return socketErr
+	}
+
+	socketErr.Exists = true
+	socketErr.Mode = info.Mode()
+	socketErr.UID, socketErr.GID = fileOwner(info)
+
+	switch {
+	case info.Mode()&os.ModeSocket == 0:
+		socketErr.Reason = fmt.Sprintf("not a socket (mode %s)", info.Mode())
+	case errors.Is(dialErr, syscall.EACCES) || errors.Is(dialErr, syscall.EPERM):
+		socketErr.Reason = fmt.Sprintf(
+			"permission denied: socket is owned by uid %d gid %d with mode %s, and this process runs as uid %d gid %d",
+			socketErr.UID, socketErr.GID, info.Mode(), os.Getuid(), os.Getgid(),
+		)
+	case errors.Is(dialErr, syscall.ECONNREFUSED):
+		socketErr.Reason = "connection refused: nothing is listening, so the socket may be left over from a server which exited"
+	default:
+		socketErr.Reason = dialErr.Error()
+	}
+
+	return socketErr
+}
@@ -0,0 +1,7 @@
+package connection
+
+import "os"
+
+func fileOwner(info os.FileInfo) (uid, gid int) {
+	return -1, -1
+}
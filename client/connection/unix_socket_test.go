@@ -0,0 +1,126 @@
+package connection_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"code.cloudfoundry.org/garden/client/connection"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("UnixSocketDialer", func() {
+	var (
+		tmpdir     string
+		socketPath string
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpdir, err = ioutil.TempDir("", "unix-socket")
+		Expect(err).NotTo(HaveOccurred())
+
+		socketPath = filepath.Join(tmpdir, "garden.sock")
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(tmpdir)).To(Succeed())
+	})
+
+	dialError := func(wait time.Duration) *connection.UnixSocketError {
+		_, err := connection.UnixSocketDialer(socketPath, wait)("unix", "api:80")
+		Expect(err).To(HaveOccurred())
+
+		var socketErr *connection.UnixSocketError
+		Expect(errors.As(err, &socketErr)).To(BeTrue())
+		return socketErr
+	}
+
+	Context("when the socket is listening", func() {
+		It("connects to it", func() {
+			listener, err := net.Listen("unix", socketPath)
+			Expect(err).NotTo(HaveOccurred())
+			defer listener.Close()
+
+			conn, err := connection.UnixSocketDialer(socketPath, 0)("unix", "api:80")
+			Expect(err).NotTo(HaveOccurred())
+			conn.Close()
+		})
+	})
+
+	Context("when the socket does not exist", func() {
+		It("explains that the server may not be running", func() {
+			socketErr := dialError(0)
+			Expect(socketErr.Exists).To(BeFalse())
+			Expect(socketErr.Error()).To(ContainSubstring("socket does not exist"))
+		})
+
+		It("waits for it to appear", func() {
+			go func() {
+				defer GinkgoRecover()
+
+				time.Sleep(300 * time.Millisecond)
+				listener, err := net.Listen("unix", socketPath)
+				Expect(err).NotTo(HaveOccurred())
+				defer listener.Close()
+
+				conn, err := listener.Accept()
+				Expect(err).NotTo(HaveOccurred())
+				conn.Close()
+			}()
+
+			conn, err := connection.UnixSocketDialer(socketPath, 5*time.Second)("unix", "api:80")
+			Expect(err).NotTo(HaveOccurred())
+			conn.Close()
+		})
+
+		It("gives up once the wait elapses", func() {
+			before := time.Now()
+			dialError(300 * time.Millisecond)
+			Expect(time.Since(before)).To(BeNumerically(">=", 300*time.Millisecond))
+		})
+	})
+
+	Context("when nothing is listening on the socket", func() {
+		BeforeEach(func() {
+			listener, err := net.ListenUnix("unix", &net.UnixAddr{Name: socketPath, Net: "unix"})
+			Expect(err).NotTo(HaveOccurred())
+			listener.SetUnlinkOnClose(false)
+			Expect(listener.Close()).To(Succeed())
+		})
+
+		It("explains that the socket may be stale", func() {
+			socketErr := dialError(0)
+			Expect(socketErr.Exists).To(BeTrue())
+			Expect(socketErr.Mode & os.ModeSocket).NotTo(BeZero())
+			Expect(socketErr.UID).To(Equal(os.Getuid()))
+			Expect(socketErr.Error()).To(ContainSubstring("left over from a server which exited"))
+		})
+	})
+
+	Context("when the path is not a socket", func() {
+		BeforeEach(func() {
+			Expect(ioutil.WriteFile(socketPath, []byte("hello"), 0600)).To(Succeed())
+		})
+
+		It("says so", func() {
+			socketErr := dialError(0)
+			Expect(socketErr.Exists).To(BeTrue())
+			Expect(socketErr.Error()).To(ContainSubstring("not a socket"))
+		})
+	})
+
+	Context("when a connection is made over a unix socket which does not exist", func() {
+		It("returns a UnixSocketError", func() {
+			err := connection.New("unix", socketPath).Ping()
+
+			var socketErr *connection.UnixSocketError
+			Expect(errors.As(err, &socketErr)).To(BeTrue())
+			Expect(socketErr.Path).To(Equal(socketPath))
+		})
+	})
+})
@@ -30,6 +30,26 @@ func (container *container) Stop(kill bool) error {
 	return container.connection.Stop(container.handle, kill)
 }
 
+func (container *container) StopWithTimeout(timeout time.Duration) error {
+	return container.connection.StopWithTimeout(container.handle, timeout)
+}
+
+func (container *container) Pause() error {
+	return container.connection.Pause(container.handle)
+}
+
+func (container *container) Resume() error {
+	return container.connection.Resume(container.handle)
+}
+
+func (container *container) Checkpoint(destination string) error {
+	return container.connection.Checkpoint(container.handle, destination)
+}
+
+func (container *container) Commit(imageName string) (garden.CachedImage, error) {
+	return container.connection.Commit(container.handle, imageName)
+}
+
 func (container *container) Info() (garden.ContainerInfo, error) {
 	return container.connection.Info(container.handle)
 }
@@ -46,6 +66,10 @@ func (container *container) CurrentBandwidthLimits() (garden.BandwidthLimits, er
 	return container.connection.CurrentBandwidthLimits(container.handle)
 }
 
+func (container *container) LimitBandwidth(limits garden.BandwidthLimits) error {
+	return container.connection.LimitBandwidth(container.handle, limits)
+}
+
 func (container *container) CurrentCPULimits() (garden.CPULimits, error) {
 	return container.connection.CurrentCPULimits(container.handle)
 }
@@ -58,6 +82,38 @@ func (container *container) CurrentMemoryLimits() (garden.MemoryLimits, error) {
 	return container.connection.CurrentMemoryLimits(container.handle)
 }
 
+func (container *container) LimitCPU(limits garden.CPULimits) error {
+	return container.connection.LimitCPU(container.handle, limits)
+}
+
+func (container *container) LimitMemory(limits garden.MemoryLimits) error {
+	return container.connection.LimitMemory(container.handle, limits)
+}
+
+func (container *container) CurrentPidLimits() (garden.PidLimits, error) {
+	return container.connection.CurrentPidLimits(container.handle)
+}
+
+func (container *container) LimitPids(limits garden.PidLimits) error {
+	return container.connection.LimitPids(container.handle, limits)
+}
+
+func (container *container) CurrentBlockIOLimits() (garden.BlockIOLimits, error) {
+	return container.connection.CurrentBlockIOLimits(container.handle)
+}
+
+func (container *container) LimitBlockIO(limits garden.BlockIOLimits) error {
+	return container.connection.LimitBlockIO(container.handle, limits)
+}
+
+func (container *container) CurrentLimits() (garden.Limits, error) {
+	return container.connection.CurrentLimits(container.handle)
+}
+
+func (container *container) LimitAll(limits garden.Limits) error {
+	return container.connection.LimitAll(container.handle, limits)
+}
+
 func (container *container) Run(spec garden.ProcessSpec, io garden.ProcessIO) (garden.Process, error) {
 	return container.connection.Run(container.handle, spec, io)
 }
@@ -74,14 +130,62 @@ func (container *container) NetOut(netOutRule garden.NetOutRule) error {
 	return container.connection.NetOut(container.handle, netOutRule)
 }
 
+func (container *container) BulkNetOut(netOutRules []garden.NetOutRule) error {
+	return container.connection.BulkNetOut(container.handle, netOutRules)
+}
+
+func (container *container) NetInWithHostIP(hostIP string, hostPort, containerPort uint32) (garden.PortMapping, error) {
+	return container.connection.NetInWithHostIP(container.handle, hostIP, hostPort, containerPort)
+}
+
+func (container *container) NetInMappings() ([]garden.PortMapping, error) {
+	return container.connection.NetInMappings(container.handle)
+}
+
+func (container *container) RemoveNetIn(hostPort uint32) error {
+	return container.connection.RemoveNetIn(container.handle, hostPort)
+}
+
+func (container *container) NetOutRules() ([]garden.NetOutRule, error) {
+	return container.connection.NetOutRules(container.handle)
+}
+
+func (container *container) RemoveNetOut(ruleID string) error {
+	return container.connection.RemoveNetOut(container.handle, ruleID)
+}
+
+func (container *container) RemoveAllNetOut() error {
+	return container.connection.RemoveAllNetOut(container.handle)
+}
+
 func (container *container) Metrics() (garden.Metrics, error) {
 	return container.connection.Metrics(container.handle)
 }
 
+func (container *container) DiskUsage(paths []string) ([]garden.PathDiskUsage, error) {
+	return container.connection.DiskUsage(container.handle, paths)
+}
+
+func (container *container) HostResources() (garden.HostResources, error) {
+	return container.connection.HostResources(container.handle)
+}
+
+func (container *container) Processes() ([]garden.ProcessInfo, error) {
+	return container.connection.Processes(container.handle)
+}
+
+func (container *container) ProcessMetrics() (map[string]garden.ProcessMetrics, error) {
+	return container.connection.ProcessMetrics(container.handle)
+}
+
 func (container *container) SetGraceTime(graceTime time.Duration) error {
 	return container.connection.SetGraceTime(container.handle, graceTime)
 }
 
+func (container *container) SetDescription(description string) error {
+	return container.connection.SetDescription(container.handle, description)
+}
+
 func (container *container) Properties() (garden.Properties, error) {
 	return container.connection.Properties(container.handle)
 }
@@ -69,6 +69,112 @@ var _ = Describe("Container", func() {
 		})
 	})
 
+	Describe("StopWithTimeout", func() {
+		It("sends a stop request with the timeout", func() {
+			err := container.StopWithTimeout(30 * time.Second)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			handle, timeout := fakeConnection.StopWithTimeoutArgsForCall(0)
+			Ω(handle).Should(Equal("some-handle"))
+			Ω(timeout).Should(Equal(30 * time.Second))
+		})
+
+		Context("when stopping fails", func() {
+			disaster := errors.New("oh no!")
+
+			BeforeEach(func() {
+				fakeConnection.StopWithTimeoutReturns(disaster)
+			})
+
+			It("returns the error", func() {
+				err := container.StopWithTimeout(time.Second)
+				Ω(err).Should(Equal(disaster))
+			})
+		})
+	})
+
+	Describe("Pause", func() {
+		It("sends a pause request", func() {
+			err := container.Pause()
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(fakeConnection.PauseArgsForCall(0)).Should(Equal("some-handle"))
+		})
+
+		Context("when pause fails", func() {
+			disaster := errors.New("oh no!")
+
+			BeforeEach(func() {
+				fakeConnection.PauseReturns(disaster)
+			})
+
+			It("returns the error", func() {
+				err := container.Pause()
+				Ω(err).Should(Equal(disaster))
+			})
+		})
+	})
+
+	Describe("Resume", func() {
+		It("sends a resume request", func() {
+			err := container.Resume()
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(fakeConnection.ResumeArgsForCall(0)).Should(Equal("some-handle"))
+		})
+
+		Context("when resume fails", func() {
+			disaster := errors.New("oh no!")
+
+			BeforeEach(func() {
+				fakeConnection.ResumeReturns(disaster)
+			})
+
+			It("returns the error", func() {
+				err := container.Resume()
+				Ω(err).Should(Equal(disaster))
+			})
+		})
+	})
+
+	Describe("Checkpoint", func() {
+		It("sends a checkpoint request", func() {
+			err := container.Checkpoint("/path/to/checkpoint")
+			Ω(err).ShouldNot(HaveOccurred())
+
+			handle, destination := fakeConnection.CheckpointArgsForCall(0)
+			Ω(handle).Should(Equal("some-handle"))
+			Ω(destination).Should(Equal("/path/to/checkpoint"))
+		})
+
+		Context("when checkpointing fails", func() {
+			disaster := errors.New("oh no!")
+
+			BeforeEach(func() {
+				fakeConnection.CheckpointReturns(disaster)
+			})
+
+			It("returns the error", func() {
+				err := container.Checkpoint("/path/to/checkpoint")
+				Ω(err).Should(Equal(disaster))
+			})
+		})
+	})
+
+	Describe("Commit", func() {
+		It("sends a commit request", func() {
+			fakeConnection.CommitReturns(garden.CachedImage{URI: "/var/images/base-1.0"}, nil)
+
+			image, err := container.Commit("base:1.0")
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(image.URI).Should(Equal("/var/images/base-1.0"))
+
+			handle, imageName := fakeConnection.CommitArgsForCall(0)
+			Ω(handle).Should(Equal("some-handle"))
+			Ω(imageName).Should(Equal("base:1.0"))
+		})
+	})
+
 	Describe("Info", func() {
 		It("sends an info request", func() {
 			infoToReturn := garden.ContainerInfo{
@@ -318,6 +424,72 @@ var _ = Describe("Container", func() {
 		})
 	})
 
+	Describe("LimitBandwidth", func() {
+		It("sends the limits", func() {
+			Ω(container.LimitBandwidth(garden.BandwidthLimits{RateInBytesPerSecond: 1024, EgressRateInBytesPerSecond: 512})).Should(Succeed())
+
+			handle, limits := fakeConnection.LimitBandwidthArgsForCall(0)
+			Ω(handle).Should(Equal("some-handle"))
+			Ω(limits).Should(Equal(garden.BandwidthLimits{RateInBytesPerSecond: 1024, EgressRateInBytesPerSecond: 512}))
+		})
+
+		Context("when the request fails", func() {
+			disaster := errors.New("oh no!")
+
+			BeforeEach(func() {
+				fakeConnection.LimitBandwidthReturns(disaster)
+			})
+
+			It("returns the error", func() {
+				Ω(container.LimitBandwidth(garden.BandwidthLimits{RateInBytesPerSecond: 1024, EgressRateInBytesPerSecond: 512})).Should(Equal(disaster))
+			})
+		})
+	})
+
+	Describe("LimitCPU", func() {
+		It("sends the limits", func() {
+			Ω(container.LimitCPU(garden.CPULimits{LimitInShares: 2, Cpuset: "0-3"})).Should(Succeed())
+
+			handle, limits := fakeConnection.LimitCPUArgsForCall(0)
+			Ω(handle).Should(Equal("some-handle"))
+			Ω(limits).Should(Equal(garden.CPULimits{LimitInShares: 2, Cpuset: "0-3"}))
+		})
+
+		Context("when the request fails", func() {
+			disaster := errors.New("oh no!")
+
+			BeforeEach(func() {
+				fakeConnection.LimitCPUReturns(disaster)
+			})
+
+			It("returns the error", func() {
+				Ω(container.LimitCPU(garden.CPULimits{LimitInShares: 2})).Should(Equal(disaster))
+			})
+		})
+	})
+
+	Describe("LimitMemory", func() {
+		It("sends the limits", func() {
+			Ω(container.LimitMemory(garden.MemoryLimits{LimitInBytes: 1024})).Should(Succeed())
+
+			handle, limits := fakeConnection.LimitMemoryArgsForCall(0)
+			Ω(handle).Should(Equal("some-handle"))
+			Ω(limits).Should(Equal(garden.MemoryLimits{LimitInBytes: 1024}))
+		})
+
+		Context("when the request fails", func() {
+			disaster := errors.New("oh no!")
+
+			BeforeEach(func() {
+				fakeConnection.LimitMemoryReturns(disaster)
+			})
+
+			It("returns the error", func() {
+				Ω(container.LimitMemory(garden.MemoryLimits{LimitInBytes: 1024})).Should(Equal(disaster))
+			})
+		})
+	})
+
 	Describe("CurrentMemoryLimits", func() {
 		It("gets the current limits", func() {
 			limitsToReturn := garden.MemoryLimits{
@@ -346,6 +518,157 @@ var _ = Describe("Container", func() {
 		})
 	})
 
+	Describe("CurrentPidLimits", func() {
+		It("gets the current limits", func() {
+			fakeConnection.CurrentPidLimitsReturns(garden.PidLimits{Max: 1024}, nil)
+
+			limits, err := container.CurrentPidLimits()
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(limits).Should(Equal(garden.PidLimits{Max: 1024}))
+			Ω(fakeConnection.CurrentPidLimitsArgsForCall(0)).Should(Equal("some-handle"))
+		})
+
+		Context("when the request fails", func() {
+			disaster := errors.New("oh no!")
+
+			BeforeEach(func() {
+				fakeConnection.CurrentPidLimitsReturns(garden.PidLimits{}, disaster)
+			})
+
+			It("returns the error", func() {
+				_, err := container.CurrentPidLimits()
+				Ω(err).Should(Equal(disaster))
+			})
+		})
+	})
+
+	Describe("LimitPids", func() {
+		It("sends the limit", func() {
+			Ω(container.LimitPids(garden.PidLimits{Max: 1024})).Should(Succeed())
+
+			handle, limits := fakeConnection.LimitPidsArgsForCall(0)
+			Ω(handle).Should(Equal("some-handle"))
+			Ω(limits).Should(Equal(garden.PidLimits{Max: 1024}))
+		})
+
+		Context("when the request fails", func() {
+			disaster := errors.New("oh no!")
+
+			BeforeEach(func() {
+				fakeConnection.LimitPidsReturns(disaster)
+			})
+
+			It("returns the error", func() {
+				Ω(container.LimitPids(garden.PidLimits{Max: 1024})).Should(Equal(disaster))
+			})
+		})
+	})
+
+	Describe("CurrentBlockIOLimits", func() {
+		It("gets the current limits", func() {
+			fakeConnection.CurrentBlockIOLimitsReturns(garden.BlockIOLimits{ReadIOPS: 1024}, nil)
+
+			limits, err := container.CurrentBlockIOLimits()
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(limits).Should(Equal(garden.BlockIOLimits{ReadIOPS: 1024}))
+			Ω(fakeConnection.CurrentBlockIOLimitsArgsForCall(0)).Should(Equal("some-handle"))
+		})
+
+		Context("when the request fails", func() {
+			disaster := errors.New("oh no!")
+
+			BeforeEach(func() {
+				fakeConnection.CurrentBlockIOLimitsReturns(garden.BlockIOLimits{}, disaster)
+			})
+
+			It("returns the error", func() {
+				_, err := container.CurrentBlockIOLimits()
+				Ω(err).Should(Equal(disaster))
+			})
+		})
+	})
+
+	Describe("LimitBlockIO", func() {
+		It("sends the limit", func() {
+			Ω(container.LimitBlockIO(garden.BlockIOLimits{ReadIOPS: 1024})).Should(Succeed())
+
+			handle, limits := fakeConnection.LimitBlockIOArgsForCall(0)
+			Ω(handle).Should(Equal("some-handle"))
+			Ω(limits).Should(Equal(garden.BlockIOLimits{ReadIOPS: 1024}))
+		})
+
+		Context("when the request fails", func() {
+			disaster := errors.New("oh no!")
+
+			BeforeEach(func() {
+				fakeConnection.LimitBlockIOReturns(disaster)
+			})
+
+			It("returns the error", func() {
+				Ω(container.LimitBlockIO(garden.BlockIOLimits{ReadIOPS: 1024})).Should(Equal(disaster))
+			})
+		})
+	})
+
+	Describe("CurrentLimits", func() {
+		It("gets the current limits", func() {
+			limitsToReturn := garden.Limits{
+				CPU:    garden.CPULimits{LimitInShares: 1},
+				Memory: garden.MemoryLimits{LimitInBytes: 2},
+			}
+
+			fakeConnection.CurrentLimitsReturns(limitsToReturn, nil)
+
+			limits, err := container.CurrentLimits()
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(limits).Should(Equal(limitsToReturn))
+			Ω(fakeConnection.CurrentLimitsArgsForCall(0)).Should(Equal("some-handle"))
+		})
+
+		Context("when the request fails", func() {
+			disaster := errors.New("oh no!")
+
+			BeforeEach(func() {
+				fakeConnection.CurrentLimitsReturns(garden.Limits{}, disaster)
+			})
+
+			It("returns the error", func() {
+				_, err := container.CurrentLimits()
+				Ω(err).Should(Equal(disaster))
+			})
+		})
+	})
+
+	Describe("LimitAll", func() {
+		It("sends the limits", func() {
+			limits := garden.Limits{
+				Bandwidth: garden.BandwidthLimits{RateInBytesPerSecond: 1},
+				Disk:      garden.DiskLimits{ByteHard: 2},
+			}
+
+			Ω(container.LimitAll(limits)).Should(Succeed())
+
+			handle, actualLimits := fakeConnection.LimitAllArgsForCall(0)
+			Ω(handle).Should(Equal("some-handle"))
+			Ω(actualLimits).Should(Equal(limits))
+		})
+
+		Context("when the request fails", func() {
+			disaster := errors.New("oh no!")
+
+			BeforeEach(func() {
+				fakeConnection.LimitAllReturns(disaster)
+			})
+
+			It("returns the error", func() {
+				Ω(container.LimitAll(garden.Limits{})).Should(Equal(disaster))
+			})
+		})
+	})
+
 	Describe("Run", func() {
 		It("sends a run request and returns the process id and a stream", func() {
 			fakeConnection.RunStub = func(handle string, spec garden.ProcessSpec, io garden.ProcessIO) (garden.Process, error) {
@@ -475,6 +798,162 @@ var _ = Describe("Container", func() {
 		})
 	})
 
+	Describe("NetInWithHostIP", func() {
+		It("sends a net in request with the host IP", func() {
+			mappingToReturn := garden.PortMapping{HostPort: 111, ContainerPort: 222, HostIP: "10.0.0.1"}
+			fakeConnection.NetInWithHostIPReturns(mappingToReturn, nil)
+
+			mapping, err := container.NetInWithHostIP("10.0.0.1", 123, 456)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(mapping).Should(Equal(mappingToReturn))
+
+			h, ip, hp, cp := fakeConnection.NetInWithHostIPArgsForCall(0)
+			Ω(h).Should(Equal("some-handle"))
+			Ω(ip).Should(Equal("10.0.0.1"))
+			Ω(hp).Should(Equal(uint32(123)))
+			Ω(cp).Should(Equal(uint32(456)))
+		})
+	})
+
+	Describe("NetInMappings", func() {
+		It("sends a request to list the port mappings and returns its response", func() {
+			mappingsToReturn := []garden.PortMapping{{HostPort: 111, ContainerPort: 222}}
+			fakeConnection.NetInMappingsReturns(mappingsToReturn, nil)
+
+			mappings, err := container.NetInMappings()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(mappings).Should(Equal(mappingsToReturn))
+			Ω(fakeConnection.NetInMappingsArgsForCall(0)).Should(Equal("some-handle"))
+		})
+	})
+
+	Describe("RemoveNetIn", func() {
+		It("sends a request to remove the port mapping", func() {
+			Ω(container.RemoveNetIn(111)).Should(Succeed())
+
+			handle, hostPort := fakeConnection.RemoveNetInArgsForCall(0)
+			Ω(handle).Should(Equal("some-handle"))
+			Ω(hostPort).Should(Equal(uint32(111)))
+		})
+	})
+
+	Describe("HostResources", func() {
+		It("sends a host resources request and returns its response", func() {
+			resourcesToReturn := garden.HostResources{
+				HostPorts: []uint32{61001},
+			}
+
+			fakeConnection.HostResourcesReturns(resourcesToReturn, nil)
+
+			resources, err := container.HostResources()
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(resources).Should(Equal(resourcesToReturn))
+			Ω(fakeConnection.HostResourcesArgsForCall(0)).Should(Equal("some-handle"))
+		})
+
+		Context("when the request fails", func() {
+			disaster := errors.New("oh no!")
+
+			BeforeEach(func() {
+				fakeConnection.HostResourcesReturns(garden.HostResources{}, disaster)
+			})
+
+			It("returns the error", func() {
+				_, err := container.HostResources()
+				Ω(err).Should(Equal(disaster))
+			})
+		})
+	})
+
+	Describe("Processes", func() {
+		It("sends a list processes request and returns its response", func() {
+			processesToReturn := []garden.ProcessInfo{
+				{ID: "some-process", Path: "/bin/sleep", State: garden.ProcessStateRunning},
+			}
+
+			fakeConnection.ProcessesReturns(processesToReturn, nil)
+
+			processes, err := container.Processes()
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(processes).Should(Equal(processesToReturn))
+			Ω(fakeConnection.ProcessesArgsForCall(0)).Should(Equal("some-handle"))
+		})
+
+		Context("when the request fails", func() {
+			disaster := errors.New("oh no!")
+
+			BeforeEach(func() {
+				fakeConnection.ProcessesReturns(nil, disaster)
+			})
+
+			It("returns the error", func() {
+				_, err := container.Processes()
+				Ω(err).Should(Equal(disaster))
+			})
+		})
+	})
+
+	Describe("ProcessMetrics", func() {
+		It("sends a process metrics request and returns its response", func() {
+			metricsToReturn := map[string]garden.ProcessMetrics{
+				"some-process": {MemoryUsageInBytes: 1024},
+			}
+
+			fakeConnection.ProcessMetricsReturns(metricsToReturn, nil)
+
+			metrics, err := container.ProcessMetrics()
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(metrics).Should(Equal(metricsToReturn))
+			Ω(fakeConnection.ProcessMetricsArgsForCall(0)).Should(Equal("some-handle"))
+		})
+
+		Context("when the request fails", func() {
+			disaster := errors.New("oh no!")
+
+			BeforeEach(func() {
+				fakeConnection.ProcessMetricsReturns(nil, disaster)
+			})
+
+			It("returns the error", func() {
+				_, err := container.ProcessMetrics()
+				Ω(err).Should(Equal(disaster))
+			})
+		})
+	})
+
+	Describe("DiskUsage", func() {
+		It("sends a disk usage request and returns its response", func() {
+			usageToReturn := []garden.PathDiskUsage{{Path: "/tmp", BytesUsed: 1024}}
+
+			fakeConnection.DiskUsageReturns(usageToReturn, nil)
+
+			usage, err := container.DiskUsage([]string{"/tmp"})
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(usage).Should(Equal(usageToReturn))
+
+			handle, paths := fakeConnection.DiskUsageArgsForCall(0)
+			Ω(handle).Should(Equal("some-handle"))
+			Ω(paths).Should(Equal([]string{"/tmp"}))
+		})
+
+		Context("when the request fails", func() {
+			disaster := errors.New("oh no!")
+
+			BeforeEach(func() {
+				fakeConnection.DiskUsageReturns(nil, disaster)
+			})
+
+			It("returns the error", func() {
+				_, err := container.DiskUsage([]string{"/tmp"})
+				Ω(err).Should(Equal(disaster))
+			})
+		})
+	})
+
 	Describe("NetOut", func() {
 		It("sends NetOut requests over the connection", func() {
 			Ω(container.NetOut(garden.NetOutRule{
@@ -498,6 +977,28 @@ var _ = Describe("Container", func() {
 		})
 	})
 
+	Describe("SetDescription", func() {
+		It("sends the description", func() {
+			Ω(container.SetDescription("runs the nightly build")).Should(Succeed())
+
+			handle, description := fakeConnection.SetDescriptionArgsForCall(0)
+			Ω(handle).Should(Equal("some-handle"))
+			Ω(description).Should(Equal("runs the nightly build"))
+		})
+
+		Context("when the request fails", func() {
+			disaster := errors.New("banana")
+
+			BeforeEach(func() {
+				fakeConnection.SetDescriptionReturns(disaster)
+			})
+
+			It("returns the error", func() {
+				Ω(container.SetDescription("runs the nightly build")).Should(Equal(disaster))
+			})
+		})
+	})
+
 	Describe(("GraceTime"), func() {
 		It("send the set grace time request", func() {
 			graceTime := time.Second * 5
@@ -524,6 +1025,46 @@ var _ = Describe("Container", func() {
 		})
 	})
 
+	Describe("BulkNetOut", func() {
+		It("sends BulkNetOut requests over the connection", func() {
+			rules := []garden.NetOutRule{{ID: "some-rule"}, {Protocol: garden.ProtocolTCP}}
+			Ω(container.BulkNetOut(rules)).Should(Succeed())
+
+			handle, sent := fakeConnection.BulkNetOutArgsForCall(0)
+			Ω(handle).Should(Equal("some-handle"))
+			Ω(sent).Should(Equal(rules))
+		})
+	})
+
+	Describe("NetOutRules", func() {
+		It("sends a request to list the NetOut rules and returns its response", func() {
+			rulesToReturn := []garden.NetOutRule{{ID: "some-rule"}}
+			fakeConnection.NetOutRulesReturns(rulesToReturn, nil)
+
+			rules, err := container.NetOutRules()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(rules).Should(Equal(rulesToReturn))
+			Ω(fakeConnection.NetOutRulesArgsForCall(0)).Should(Equal("some-handle"))
+		})
+	})
+
+	Describe("RemoveNetOut", func() {
+		It("sends a request to remove the NetOut rule", func() {
+			Ω(container.RemoveNetOut("some-rule")).Should(Succeed())
+
+			handle, ruleID := fakeConnection.RemoveNetOutArgsForCall(0)
+			Ω(handle).Should(Equal("some-handle"))
+			Ω(ruleID).Should(Equal("some-rule"))
+		})
+	})
+
+	Describe("RemoveAllNetOut", func() {
+		It("sends a request to remove all NetOut rules", func() {
+			Ω(container.RemoveAllNetOut()).Should(Succeed())
+			Ω(fakeConnection.RemoveAllNetOutArgsForCall(0)).Should(Equal("some-handle"))
+		})
+	})
+
 	Context("when the request fails", func() {
 		disaster := errors.New("oh no!")
 
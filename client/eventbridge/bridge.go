@@ -0,0 +1,148 @@
+// Package eventbridge republishes the events streamed by a garden server to
+// other systems, such as NATS or the gRPC streams of downstream consumers, so
+// that those consumers need not each hold a connection to every server.
+package eventbridge
+
+import (
+	"context"
+	"time"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/lager"
+)
+
+// Source streams events resuming after the one with the given sequence
+// number, as client.Client does.
+type Source interface {
+	EventsSince(ctx context.Context, seq uint64) (<-chan garden.Event, error)
+}
+
+// Publisher republishes an event downstream. It should only return once the
+// event has been accepted, as the event is published again if it returns an
+// error.
+type Publisher interface {
+	Publish(event garden.Event) error
+}
+
+// PublisherFunc adapts a function, e.g. one sending to the gRPC streams of
+// subscribed consumers, to a Publisher.
+type PublisherFunc func(event garden.Event) error
+
+func (f PublisherFunc) Publish(event garden.Event) error {
+	return f(event)
+}
+
+// Bridge republishes the events from a source at least once. It saves the
+// sequence number of each event once it is published, and resumes after it
+// when the stream ends or the bridge is restarted. Events which are still
+// received again are skipped. An event may be published twice if the bridge
+// stops between publishing it and saving its sequence number.
+type Bridge struct {
+	source        Source
+	publisher     Publisher
+	cursors       CursorStore
+	retryInterval time.Duration
+	logger        lager.Logger
+}
+
+// New returns a bridge from the source to the publisher which waits for the
+// retry interval before publishing again or resubscribing after a failure.
+func New(source Source, publisher Publisher, cursors CursorStore, retryInterval time.Duration, logger lager.Logger) *Bridge {
+	return &Bridge{
+		source:        source,
+		publisher:     publisher,
+		cursors:       cursors,
+		retryInterval: retryInterval,
+		logger:        logger.Session("event-bridge"),
+	}
+}
+
+// Run republishes events until ctx is done. It only returns an error if the
+// cursor cannot be loaded.
+func (b *Bridge) Run(ctx context.Context) error {
+	cursor, err := b.cursors.Load()
+	if err != nil {
+		return err
+	}
+
+	for {
+		events, err := b.source.EventsSince(ctx, cursor)
+		if err != nil {
+			b.logger.Error("subscribing", err, lager.Data{"cursor": cursor})
+		} else {
+			b.logger.Info("subscribed", lager.Data{"cursor": cursor})
+			cursor = b.forward(ctx, events, cursor)
+		}
+
+		if !b.wait(ctx) {
+			return nil
+		}
+	}
+}
+
+// forward publishes the events until the stream ends or ctx is done, and
+// returns the sequence number of the last one published.
+func (b *Bridge) forward(ctx context.Context, events <-chan garden.Event, cursor uint64) uint64 {
+	for {
+		var event garden.Event
+		select {
+		case e, ok := <-events:
+			if !ok {
+				return cursor
+			}
+			event = e
+		case <-ctx.Done():
+			return cursor
+		}
+
+		if event.Seq != 0 && event.Seq <= cursor {
+			continue
+		}
+
+		if cursor != 0 && event.Seq > cursor+1 {
+			b.logger.Info("missed-events", lager.Data{"after": cursor, "next": event.Seq})
+		}
+
+		if !b.publish(ctx, event) {
+			return cursor
+		}
+
+		if event.Seq == 0 {
+			continue
+		}
+
+		cursor = event.Seq
+		if err := b.cursors.Save(cursor); err != nil {
+			b.logger.Error("saving-cursor", err, lager.Data{"cursor": cursor})
+		}
+	}
+}
+
+// publish publishes the event until it succeeds, returning false if ctx is
+// done first.
+func (b *Bridge) publish(ctx context.Context, event garden.Event) bool {
+	for {
+		err := b.publisher.Publish(event)
+		if err == nil {
+			return true
+		}
+
+		b.logger.Error("publishing", err, lager.Data{"seq": event.Seq})
+
+		if !b.wait(ctx) {
+			return false
+		}
+	}
+}
+
+func (b *Bridge) wait(ctx context.Context) bool {
+	timer := time.NewTimer(b.retryInterval)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
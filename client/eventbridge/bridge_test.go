@@ -0,0 +1,190 @@
+package eventbridge_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/garden/client/eventbridge"
+	"code.cloudfoundry.org/lager/lagertest"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type fakeSource struct {
+	streams chan chan garden.Event
+	since   chan uint64
+}
+
+func (s *fakeSource) EventsSince(ctx context.Context, seq uint64) (<-chan garden.Event, error) {
+	s.since <- seq
+
+	select {
+	case stream := <-s.streams:
+		return stream, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+type fakePublisher struct {
+	published []garden.Event
+	failures  int
+	lock      sync.Mutex
+}
+
+func (p *fakePublisher) Publish(event garden.Event) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.failures > 0 {
+		p.failures--
+		return errors.New("downstream unavailable")
+	}
+
+	p.published = append(p.published, event)
+	return nil
+}
+
+func (p *fakePublisher) Published() []garden.Event {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return append([]garden.Event{}, p.published...)
+}
+
+var _ = Describe("Bridge", func() {
+	var (
+		source    *fakeSource
+		publisher *fakePublisher
+		cursor    *eventbridge.MemoryCursor
+		stream    chan garden.Event
+
+		cancel context.CancelFunc
+		done   chan error
+	)
+
+	BeforeEach(func() {
+		source = &fakeSource{
+			streams: make(chan chan garden.Event, 2),
+			since:   make(chan uint64, 2),
+		}
+		publisher = &fakePublisher{}
+		cursor = &eventbridge.MemoryCursor{}
+
+		stream = make(chan garden.Event)
+		source.streams <- stream
+	})
+
+	JustBeforeEach(func() {
+		bridge := eventbridge.New(source, publisher, cursor, 10*time.Millisecond, lagertest.NewTestLogger("test"))
+
+		var ctx context.Context
+		ctx, cancel = context.WithCancel(context.Background())
+
+		done = make(chan error, 1)
+		go func() {
+			done <- bridge.Run(ctx)
+		}()
+	})
+
+	AfterEach(func() {
+		cancel()
+		Eventually(done).Should(Receive(BeNil()))
+	})
+
+	It("publishes the events and saves the cursor", func() {
+		Eventually(source.since).Should(Receive(Equal(uint64(0))))
+
+		stream <- garden.Event{Kind: garden.EventCreated, Handle: "a", Seq: 10}
+		stream <- garden.Event{Kind: garden.EventDestroyed, Handle: "a", Seq: 11}
+
+		Eventually(publisher.Published).Should(HaveLen(2))
+		Expect(publisher.Published()[1].Seq).To(Equal(uint64(11)))
+		Eventually(cursor.Load).Should(Equal(uint64(11)))
+	})
+
+	Context("when a cursor was saved", func() {
+		BeforeEach(func() {
+			Expect(cursor.Save(10)).To(Succeed())
+		})
+
+		It("resumes after it, skipping events already published", func() {
+			Eventually(source.since).Should(Receive(Equal(uint64(10))))
+
+			stream <- garden.Event{Kind: garden.EventCreated, Handle: "a", Seq: 10}
+			stream <- garden.Event{Kind: garden.EventCreated, Handle: "b", Seq: 11}
+
+			Eventually(publisher.Published).Should(HaveLen(1))
+			Expect(publisher.Published()[0].Handle).To(Equal("b"))
+		})
+	})
+
+	Context("when publishing fails", func() {
+		BeforeEach(func() {
+			publisher.failures = 2
+		})
+
+		It("publishes the event again until it succeeds", func() {
+			stream <- garden.Event{Kind: garden.EventCreated, Handle: "a", Seq: 10}
+
+			Eventually(publisher.Published).Should(ConsistOf(garden.Event{Kind: garden.EventCreated, Handle: "a", Seq: 10}))
+			Eventually(cursor.Load).Should(Equal(uint64(10)))
+		})
+	})
+
+	Context("when the stream ends", func() {
+		It("resubscribes after the last event published", func() {
+			Eventually(source.since).Should(Receive(Equal(uint64(0))))
+
+			stream <- garden.Event{Kind: garden.EventCreated, Handle: "a", Seq: 10}
+			Eventually(publisher.Published).Should(HaveLen(1))
+
+			resumed := make(chan garden.Event)
+			source.streams <- resumed
+			close(stream)
+
+			Eventually(source.since).Should(Receive(Equal(uint64(10))))
+
+			resumed <- garden.Event{Kind: garden.EventCreated, Handle: "b", Seq: 11}
+			Eventually(publisher.Published).Should(HaveLen(2))
+		})
+	})
+})
+
+type fakeNATSConn struct {
+	subject string
+	data    []byte
+	flushed bool
+}
+
+func (c *fakeNATSConn) Publish(subject string, data []byte) error {
+	c.subject = subject
+	c.data = data
+	return nil
+}
+
+func (c *fakeNATSConn) Flush() error {
+	c.flushed = true
+	return nil
+}
+
+var _ = Describe("NATSPublisher", func() {
+	It("publishes the event as JSON to the subject and flushes", func() {
+		conn := &fakeNATSConn{}
+		publisher := eventbridge.NATSPublisher{Conn: conn, Subject: "garden.events"}
+
+		event := garden.Event{Kind: garden.EventCreated, Handle: "a", Seq: 10}
+		Expect(publisher.Publish(event)).To(Succeed())
+
+		Expect(conn.subject).To(Equal("garden.events"))
+		Expect(conn.flushed).To(BeTrue())
+
+		var published garden.Event
+		Expect(json.Unmarshal(conn.data, &published)).To(Succeed())
+		Expect(published).To(Equal(event))
+	})
+})
@@ -0,0 +1,96 @@
+package eventbridge
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// CursorStore keeps the sequence number of the last event which was
+// published, so that a bridge resumes after it.
+type CursorStore interface {
+	// Load returns the saved sequence number, or zero if none was saved.
+	Load() (uint64, error)
+	Save(seq uint64) error
+}
+
+// MemoryCursor is a CursorStore which keeps the cursor in memory, so that a
+// bridge resumes where it left off when it reconnects but not when it is
+// restarted.
+type MemoryCursor struct {
+	seq  uint64
+	lock sync.Mutex
+}
+
+func (c *MemoryCursor) Load() (uint64, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.seq, nil
+}
+
+func (c *MemoryCursor) Save(seq uint64) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.seq = seq
+	return nil
+}
+
+// FileCursor is a CursorStore which persists the cursor to a file, so that a
+// bridge resumes where it left off when it is restarted. The file is
+// rewritten atomically on every save.
+type FileCursor struct {
+	path string
+}
+
+func NewFileCursor(path string) *FileCursor {
+	return &FileCursor{path: path}
+}
+
+func (c *FileCursor) Load() (uint64, error) {
+	content, err := ioutil.ReadFile(c.path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	seq, err := strconv.ParseUint(strings.TrimSpace(string(content)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("corrupt cursor %s: %s", c.path, err)
+	}
+
+	return seq, nil
+}
+
+func (c *FileCursor) Save(seq uint64) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(c.path), filepath.Base(c.path))
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(strconv.FormatUint(seq, 10)); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), c.path)
+}
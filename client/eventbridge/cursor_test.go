@@ -0,0 +1,46 @@
+package eventbridge_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"code.cloudfoundry.org/garden/client/eventbridge"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FileCursor", func() {
+	var (
+		dir  string
+		path string
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "eventbridge")
+		Expect(err).NotTo(HaveOccurred())
+
+		path = filepath.Join(dir, "cursor")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("loads zero when nothing was saved", func() {
+		Expect(eventbridge.NewFileCursor(path).Load()).To(BeZero())
+	})
+
+	It("loads what was saved, also when reopened", func() {
+		Expect(eventbridge.NewFileCursor(path).Save(42)).To(Succeed())
+		Expect(eventbridge.NewFileCursor(path).Load()).To(Equal(uint64(42)))
+	})
+
+	It("fails to load a corrupt cursor", func() {
+		Expect(ioutil.WriteFile(path, []byte("not-a-number"), 0600)).To(Succeed())
+
+		_, err := eventbridge.NewFileCursor(path).Load()
+		Expect(err).To(MatchError(ContainSubstring("corrupt cursor")))
+	})
+})
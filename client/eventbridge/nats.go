@@ -0,0 +1,35 @@
+package eventbridge
+
+import (
+	"encoding/json"
+
+	"code.cloudfoundry.org/garden"
+)
+
+// NATSConn is the part of a NATS connection used to publish events, as
+// implemented by *nats.Conn.
+type NATSConn interface {
+	Publish(subject string, data []byte) error
+	Flush() error
+}
+
+// NATSPublisher publishes events as JSON to a NATS subject. Each publish is
+// flushed, so that an event is only considered published once the NATS
+// server has received it.
+type NATSPublisher struct {
+	Conn    NATSConn
+	Subject string
+}
+
+func (p NATSPublisher) Publish(event garden.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	if err := p.Conn.Publish(p.Subject, data); err != nil {
+		return err
+	}
+
+	return p.Conn.Flush()
+}
@@ -0,0 +1,87 @@
+package client
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+
+	"code.cloudfoundry.org/garden"
+)
+
+// ErrOutputLimitExceeded is returned by RunAndCollect when the process wrote
+// more than the permitted amount of output to stdout or stderr.
+var ErrOutputLimitExceeded = errors.New("process output limit exceeded")
+
+// RunAndCollect runs a process in the container, waits for it to exit, and
+// returns everything it wrote to stdout and stderr along with its exit
+// status.
+//
+// At most maxOutputBytes are kept from each of stdout and stderr; output
+// beyond that is discarded and ErrOutputLimitExceeded is returned once the
+// process has exited, along with the truncated output and the exit status. A
+// maxOutputBytes of zero or less means no limit.
+func RunAndCollect(container garden.Container, spec garden.ProcessSpec, maxOutputBytes int) (stdout, stderr []byte, exitCode int, err error) {
+	stdoutBuf := &cappedBuffer{limit: maxOutputBytes}
+	stderrBuf := &cappedBuffer{limit: maxOutputBytes}
+
+	process, err := container.Run(spec, garden.ProcessIO{
+		Stdout: stdoutBuf,
+		Stderr: stderrBuf,
+	})
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	exitCode, err = process.Wait()
+	if err != nil {
+		return stdoutBuf.Bytes(), stderrBuf.Bytes(), exitCode, err
+	}
+
+	if stdoutBuf.Exceeded() || stderrBuf.Exceeded() {
+		err = ErrOutputLimitExceeded
+	}
+
+	return stdoutBuf.Bytes(), stderrBuf.Bytes(), exitCode, err
+}
+
+// cappedBuffer keeps up to limit bytes written to it and silently discards
+// the rest, so that a chatty process is never blocked on its output.
+type cappedBuffer struct {
+	limit int
+
+	buf      bytes.Buffer
+	exceeded bool
+	lock     sync.Mutex
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	data := p
+	if b.limit > 0 {
+		remaining := b.limit - b.buf.Len()
+		if len(data) > remaining {
+			data = data[:remaining]
+			b.exceeded = true
+		}
+	}
+
+	b.buf.Write(data)
+
+	return len(p), nil
+}
+
+func (b *cappedBuffer) Bytes() []byte {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return append([]byte(nil), b.buf.Bytes()...)
+}
+
+func (b *cappedBuffer) Exceeded() bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return b.exceeded
+}
@@ -0,0 +1,80 @@
+package client_test
+
+import (
+	"errors"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"code.cloudfoundry.org/garden"
+	. "code.cloudfoundry.org/garden/client"
+	"code.cloudfoundry.org/garden/gardenfakes"
+)
+
+var _ = Describe("RunAndCollect", func() {
+	var (
+		fakeContainer *gardenfakes.FakeContainer
+		fakeProcess   *gardenfakes.FakeProcess
+	)
+
+	BeforeEach(func() {
+		fakeProcess = new(gardenfakes.FakeProcess)
+		fakeProcess.WaitReturns(42, nil)
+
+		fakeContainer = new(gardenfakes.FakeContainer)
+		fakeContainer.RunStub = func(spec garden.ProcessSpec, io garden.ProcessIO) (garden.Process, error) {
+			fmt.Fprint(io.Stdout, "hello ")
+			fmt.Fprint(io.Stdout, "world")
+			fmt.Fprint(io.Stderr, "oops")
+			return fakeProcess, nil
+		}
+	})
+
+	It("runs the process and collects its output and exit status", func() {
+		stdout, stderr, exitCode, err := RunAndCollect(fakeContainer, garden.ProcessSpec{Path: "echo"}, 0)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(string(stdout)).Should(Equal("hello world"))
+		Ω(string(stderr)).Should(Equal("oops"))
+		Ω(exitCode).Should(Equal(42))
+
+		spec, _ := fakeContainer.RunArgsForCall(0)
+		Ω(spec.Path).Should(Equal("echo"))
+	})
+
+	Context("when the output exceeds the limit", func() {
+		It("returns the truncated output and ErrOutputLimitExceeded", func() {
+			stdout, stderr, exitCode, err := RunAndCollect(fakeContainer, garden.ProcessSpec{}, 8)
+			Ω(err).Should(Equal(ErrOutputLimitExceeded))
+
+			Ω(string(stdout)).Should(Equal("hello wo"))
+			Ω(string(stderr)).Should(Equal("oops"))
+			Ω(exitCode).Should(Equal(42))
+		})
+	})
+
+	Context("when running the process fails", func() {
+		BeforeEach(func() {
+			fakeContainer.RunStub = nil
+			fakeContainer.RunReturns(nil, errors.New("oh no!"))
+		})
+
+		It("returns the error", func() {
+			_, _, _, err := RunAndCollect(fakeContainer, garden.ProcessSpec{}, 0)
+			Ω(err).Should(MatchError("oh no!"))
+		})
+	})
+
+	Context("when waiting for the process fails", func() {
+		BeforeEach(func() {
+			fakeProcess.WaitReturns(0, errors.New("disconnected"))
+		})
+
+		It("returns the error along with the output collected so far", func() {
+			stdout, _, _, err := RunAndCollect(fakeContainer, garden.ProcessSpec{}, 0)
+			Ω(err).Should(MatchError("disconnected"))
+			Ω(string(stdout)).Should(Equal("hello world"))
+		})
+	})
+})
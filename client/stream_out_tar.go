@@ -0,0 +1,243 @@
+package client
+
+import (
+	"archive/tar"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"code.cloudfoundry.org/garden"
+)
+
+// ErrTarLimitExceeded is returned by WalkTar when the stream holds more
+// entries or bytes than its TarLimits permit.
+var ErrTarLimitExceeded = errors.New("tar stream limit exceeded")
+
+// UnsafeTarPathError is returned by WalkTar for an entry whose name, or whose
+// link target, would resolve outside of the directory the stream is
+// extracted into.
+type UnsafeTarPathError struct {
+	Name     string
+	Linkname string
+}
+
+func (err UnsafeTarPathError) Error() string {
+	if err.Linkname != "" {
+		return fmt.Sprintf("unsafe link in tar stream: %s -> %s", err.Name, err.Linkname)
+	}
+
+	return fmt.Sprintf("unsafe path in tar stream: %s", err.Name)
+}
+
+// TarLimits bounds the tar streams WalkTar will read. A zero value for any
+// field means no limit.
+type TarLimits struct {
+	// MaxEntries is how many entries the stream may hold.
+	MaxEntries int
+
+	// MaxEntryBytes is how large the contents of any one entry may be.
+	MaxEntryBytes int64
+
+	// MaxTotalBytes is how large the contents of all entries together may be.
+	MaxTotalBytes int64
+}
+
+// TarEntryFunc is called by WalkTar for each entry in a tar stream, with the
+// entry's header and a reader over its contents which is only valid until
+// the function returns. Returning an error stops the walk.
+type TarEntryFunc func(header *tar.Header, contents io.Reader) error
+
+// WalkStreamOut streams out the given path from the container and calls fn
+// for each entry in the resulting tar stream, as WalkTar does. The stream is
+// closed before it returns.
+func WalkStreamOut(container garden.Container, spec garden.StreamOutSpec, limits TarLimits, fn TarEntryFunc) error {
+	stream, err := container.StreamOut(spec)
+	if err != nil {
+		return err
+	}
+
+	defer stream.Close()
+
+	return WalkTar(stream, limits, fn)
+}
+
+// WalkTar calls fn for each entry in the tar stream, in order.
+//
+// Entry names are cleaned and made relative before fn sees them, and the walk
+// fails with an UnsafeTarPathError on any entry that would escape the
+// directory it is extracted into, whether by its name or by the target of a
+// hard or symbolic link. It fails with ErrTarLimitExceeded, before calling fn
+// for the offending entry, once the stream holds more than the limits permit.
+func WalkTar(stream io.Reader, limits TarLimits, fn TarEntryFunc) error {
+	tr := tar.NewReader(stream)
+
+	var entries int
+	var totalBytes int64
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if err := sanitizeTarHeader(header); err != nil {
+			return err
+		}
+
+		entries++
+		totalBytes += header.Size
+
+		if limits.MaxEntries > 0 && entries > limits.MaxEntries {
+			return fmt.Errorf("%w: more than %d entries", ErrTarLimitExceeded, limits.MaxEntries)
+		}
+
+		if limits.MaxEntryBytes > 0 && header.Size > limits.MaxEntryBytes {
+			return fmt.Errorf("%w: %s is %d bytes, more than %d", ErrTarLimitExceeded, header.Name, header.Size, limits.MaxEntryBytes)
+		}
+
+		if limits.MaxTotalBytes > 0 && totalBytes > limits.MaxTotalBytes {
+			return fmt.Errorf("%w: more than %d bytes", ErrTarLimitExceeded, limits.MaxTotalBytes)
+		}
+
+		if err := fn(header, tr); err != nil {
+			return err
+		}
+	}
+}
+
+// sanitizeTarHeader rewrites the header's name as a clean relative path,
+// failing if it or its link target escapes the root of the stream.
+func sanitizeTarHeader(header *tar.Header) error {
+	name, ok := withinRoot(header.Name)
+	if !ok {
+		return UnsafeTarPathError{Name: header.Name}
+	}
+
+	switch header.Typeflag {
+	case tar.TypeLink:
+		// hard link targets are named relative to the root of the stream
+		linkname, ok := withinRoot(header.Linkname)
+		if !ok {
+			return UnsafeTarPathError{Name: header.Name, Linkname: header.Linkname}
+		}
+
+		header.Linkname = linkname
+	case tar.TypeSymlink:
+		// symbolic link targets are relative to the link's own directory
+		if path.IsAbs(header.Linkname) {
+			return UnsafeTarPathError{Name: header.Name, Linkname: header.Linkname}
+		}
+
+		if _, ok := withinRoot(path.Join(path.Dir(name), header.Linkname)); !ok {
+			return UnsafeTarPathError{Name: header.Name, Linkname: header.Linkname}
+		}
+	}
+
+	header.Name = name
+
+	return nil
+}
+
+// withinRoot cleans the slash separated name and reports whether it is a
+// relative path which stays beneath the root.
+func withinRoot(name string) (string, bool) {
+	cleaned := path.Clean(name)
+	if path.IsAbs(cleaned) || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", false
+	}
+
+	return cleaned, true
+}
+
+// ExtractTarEntry returns a TarEntryFunc which writes directories, regular
+// files and links beneath dest, which must already exist. Other kinds of
+// entry are skipped. Names are expected to have been sanitized by WalkTar,
+// and entries are never written through a symbolic link extracted earlier, so
+// that a link to a shallower directory cannot be used to escape dest.
+func ExtractTarEntry(dest string) TarEntryFunc {
+	return func(header *tar.Header, contents io.Reader) error {
+		target := filepath.Join(dest, filepath.FromSlash(header.Name))
+		if target == filepath.Clean(dest) {
+			return nil
+		}
+
+		if err := mkdirBeneath(dest, filepath.Dir(target)); err != nil {
+			return err
+		}
+
+		mode := os.FileMode(header.Mode).Perm()
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			return mkdirBeneath(dest, target)
+		case tar.TypeReg, tar.TypeRegA:
+			file, err := os.OpenFile(target, os.O_CREATE|os.O_EXCL|os.O_WRONLY, mode)
+			if err != nil {
+				return err
+			}
+
+			_, err = io.Copy(file, contents)
+			if closeErr := file.Close(); err == nil {
+				err = closeErr
+			}
+
+			return err
+		case tar.TypeSymlink:
+			return os.Symlink(header.Linkname, target)
+		case tar.TypeLink:
+			source := filepath.Join(dest, filepath.FromSlash(header.Linkname))
+			if err := mkdirBeneath(dest, filepath.Dir(source)); err != nil {
+				return err
+			}
+
+			return os.Link(source, target)
+		}
+
+		return nil
+	}
+}
+
+// mkdirBeneath creates the directory dir and any missing parents beneath
+// dest, failing if any of them already exists as something other than a
+// directory, such as a symbolic link.
+func mkdirBeneath(dest, dir string) error {
+	rel, err := filepath.Rel(dest, dir)
+	if err != nil {
+		return err
+	}
+
+	current := dest
+	for _, component := range strings.Split(rel, string(filepath.Separator)) {
+		if component == "." {
+			continue
+		}
+
+		current = filepath.Join(current, component)
+
+		info, err := os.Lstat(current)
+		if os.IsNotExist(err) {
+			if err := os.Mkdir(current, 0755); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if !info.IsDir() {
+			return UnsafeTarPathError{Name: filepath.ToSlash(rel)}
+		}
+	}
+
+	return nil
+}
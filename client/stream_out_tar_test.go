@@ -0,0 +1,236 @@
+package client_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"code.cloudfoundry.org/garden"
+	. "code.cloudfoundry.org/garden/client"
+	"code.cloudfoundry.org/garden/gardenfakes"
+)
+
+var _ = Describe("Walking tar streams", func() {
+	var (
+		buffer *bytes.Buffer
+		tw     *tar.Writer
+	)
+
+	BeforeEach(func() {
+		buffer = new(bytes.Buffer)
+		tw = tar.NewWriter(buffer)
+	})
+
+	addFile := func(name, contents string) {
+		Ω(tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(contents)), Typeflag: tar.TypeReg})).Should(Succeed())
+		_, err := tw.Write([]byte(contents))
+		Ω(err).ShouldNot(HaveOccurred())
+	}
+
+	addLink := func(typeflag byte, name, linkname string) {
+		Ω(tw.WriteHeader(&tar.Header{Name: name, Linkname: linkname, Typeflag: typeflag})).Should(Succeed())
+	}
+
+	walk := func(limits TarLimits) (map[string]string, error) {
+		Ω(tw.Close()).Should(Succeed())
+
+		seen := map[string]string{}
+		err := WalkTar(buffer, limits, func(header *tar.Header, contents io.Reader) error {
+			data, err := ioutil.ReadAll(contents)
+			seen[header.Name] = string(data)
+			return err
+		})
+
+		return seen, err
+	}
+
+	Describe("WalkTar", func() {
+		It("calls the function for each entry with cleaned names", func() {
+			addFile("./some/../some/file", "hello")
+			addFile("other-file", "world")
+
+			seen, err := walk(TarLimits{})
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(seen).Should(Equal(map[string]string{
+				"some/file":  "hello",
+				"other-file": "world",
+			}))
+		})
+
+		It("stops at the first error returned by the function", func() {
+			addFile("a", "")
+			addFile("b", "")
+			Ω(tw.Close()).Should(Succeed())
+
+			calls := 0
+			err := WalkTar(buffer, TarLimits{}, func(*tar.Header, io.Reader) error {
+				calls++
+				return errors.New("oh no!")
+			})
+			Ω(err).Should(MatchError("oh no!"))
+			Ω(calls).Should(Equal(1))
+		})
+
+		Context("when an entry escapes the root", func() {
+			It("rejects parent directory references", func() {
+				addFile("../../etc/passwd", "")
+
+				_, err := walk(TarLimits{})
+				Ω(err).Should(Equal(UnsafeTarPathError{Name: "../../etc/passwd"}))
+			})
+
+			It("rejects absolute paths", func() {
+				addFile("/etc/passwd", "")
+
+				_, err := walk(TarLimits{})
+				Ω(err).Should(Equal(UnsafeTarPathError{Name: "/etc/passwd"}))
+			})
+
+			It("rejects symbolic links to outside the root", func() {
+				addLink(tar.TypeSymlink, "some/link", "../../etc")
+
+				_, err := walk(TarLimits{})
+				Ω(err).Should(Equal(UnsafeTarPathError{Name: "some/link", Linkname: "../../etc"}))
+			})
+
+			It("rejects absolute symbolic links", func() {
+				addLink(tar.TypeSymlink, "link", "/etc")
+
+				_, err := walk(TarLimits{})
+				Ω(err).Should(Equal(UnsafeTarPathError{Name: "link", Linkname: "/etc"}))
+			})
+
+			It("rejects hard links to outside the root", func() {
+				addLink(tar.TypeLink, "link", "../etc/passwd")
+
+				_, err := walk(TarLimits{})
+				Ω(err).Should(Equal(UnsafeTarPathError{Name: "link", Linkname: "../etc/passwd"}))
+			})
+		})
+
+		Context("when the stream exceeds the limits", func() {
+			It("fails on too many entries", func() {
+				addFile("a", "")
+				addFile("b", "")
+
+				seen, err := walk(TarLimits{MaxEntries: 1})
+				Ω(errors.Is(err, ErrTarLimitExceeded)).Should(BeTrue())
+				Ω(seen).Should(HaveLen(1))
+			})
+
+			It("fails on an entry which is too large, without reading it", func() {
+				addFile("a", "12345")
+
+				seen, err := walk(TarLimits{MaxEntryBytes: 4})
+				Ω(errors.Is(err, ErrTarLimitExceeded)).Should(BeTrue())
+				Ω(seen).Should(BeEmpty())
+			})
+
+			It("fails when the entries together are too large", func() {
+				addFile("a", "123")
+				addFile("b", "456")
+
+				seen, err := walk(TarLimits{MaxTotalBytes: 5})
+				Ω(errors.Is(err, ErrTarLimitExceeded)).Should(BeTrue())
+				Ω(seen).Should(HaveKey("a"))
+				Ω(seen).ShouldNot(HaveKey("b"))
+			})
+		})
+	})
+
+	Describe("WalkStreamOut", func() {
+		It("walks and closes the container's stream", func() {
+			addFile("a", "hello")
+			Ω(tw.Close()).Should(Succeed())
+
+			stream := &closeTracker{Reader: buffer}
+			fakeContainer := new(gardenfakes.FakeContainer)
+			fakeContainer.StreamOutReturns(stream, nil)
+
+			var names []string
+			err := WalkStreamOut(fakeContainer, garden.StreamOutSpec{Path: "/some/path"}, TarLimits{}, func(header *tar.Header, contents io.Reader) error {
+				names = append(names, header.Name)
+				return nil
+			})
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(names).Should(Equal([]string{"a"}))
+			Ω(stream.closed).Should(BeTrue())
+			Ω(fakeContainer.StreamOutArgsForCall(0).Path).Should(Equal("/some/path"))
+		})
+
+		It("returns errors from streaming out", func() {
+			fakeContainer := new(gardenfakes.FakeContainer)
+			fakeContainer.StreamOutReturns(nil, errors.New("oh no!"))
+
+			err := WalkStreamOut(fakeContainer, garden.StreamOutSpec{}, TarLimits{}, nil)
+			Ω(err).Should(MatchError("oh no!"))
+		})
+	})
+
+	Describe("ExtractTarEntry", func() {
+		var dest string
+
+		BeforeEach(func() {
+			var err error
+			dest, err = ioutil.TempDir("", "extract")
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(dest)
+		})
+
+		extract := func() error {
+			Ω(tw.Close()).Should(Succeed())
+			return WalkTar(buffer, TarLimits{}, ExtractTarEntry(dest))
+		}
+
+		It("writes files, directories and links beneath the destination", func() {
+			Ω(tw.WriteHeader(&tar.Header{Name: "dir/", Mode: 0755, Typeflag: tar.TypeDir})).Should(Succeed())
+			addFile("dir/file", "hello")
+			addLink(tar.TypeSymlink, "dir/symlink", "file")
+			addLink(tar.TypeLink, "hardlink", "dir/file")
+
+			Ω(extract()).Should(Succeed())
+
+			Ω(ioutil.ReadFile(filepath.Join(dest, "dir", "file"))).Should(Equal([]byte("hello")))
+			Ω(ioutil.ReadFile(filepath.Join(dest, "dir", "symlink"))).Should(Equal([]byte("hello")))
+			Ω(ioutil.ReadFile(filepath.Join(dest, "hardlink"))).Should(Equal([]byte("hello")))
+		})
+
+		It("does not write through symbolic links extracted earlier", func() {
+			addLink(tar.TypeSymlink, "link", ".")
+			addLink(tar.TypeSymlink, "link/escape", "../outside")
+
+			err := extract()
+			Ω(err).Should(BeAssignableToTypeOf(UnsafeTarPathError{}))
+			Ω(filepath.Join(dest, "..", "outside")).ShouldNot(BeAnExistingFile())
+			Ω(filepath.Join(dest, "escape")).ShouldNot(BeAnExistingFile())
+		})
+
+		It("does not overwrite existing files", func() {
+			Ω(ioutil.WriteFile(filepath.Join(dest, "file"), []byte("original"), 0644)).Should(Succeed())
+			addFile("file", "replaced")
+
+			Ω(extract()).ShouldNot(Succeed())
+			Ω(ioutil.ReadFile(filepath.Join(dest, "file"))).Should(Equal([]byte("original")))
+		})
+	})
+})
+
+type closeTracker struct {
+	io.Reader
+	closed bool
+}
+
+func (c *closeTracker) Close() error {
+	c.closed = true
+	return nil
+}
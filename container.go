@@ -26,6 +26,45 @@ type Container interface {
 	// * None.
 	Stop(kill bool) error
 
+	// StopWithTimeout stops a container as Stop does when kill is false, but
+	// waits for the given timeout, rather than 10 seconds, before sending
+	// SIGKILL to any processes which have not terminated.
+	//
+	// Errors:
+	// * None.
+	StopWithTimeout(timeout time.Duration) error
+
+	// Pause freezes all of the processes in a container, e.g. using the cgroup
+	// freezer, so that a workload may be quiesced without destroying it. The
+	// container reports the "paused" state until it is resumed.
+	//
+	// Errors:
+	// * When the container is not active.
+	Pause() error
+
+	// Resume thaws the processes of a paused container.
+	//
+	// Errors:
+	// * When the container is not paused.
+	Resume() error
+
+	// Checkpoint writes a snapshot of the state of the container's processes,
+	// e.g. using CRIU, to the destination path on the host, from which an
+	// identical container may later be restored. The container keeps running.
+	//
+	// Errors:
+	// * When the backend does not support checkpointing.
+	Checkpoint(destination string) error
+
+	// Commit snapshots the container's root file system into an image with
+	// the name, e.g. "buildpacks/base:1.0", in the backend's image cache. The
+	// returned image's URI may be given as the RootFSPath of other
+	// containers. The container keeps running.
+	//
+	// Errors:
+	// * When the backend does not manage images.
+	Commit(imageName string) (CachedImage, error)
+
 	// Returns information about a container.
 	Info() (ContainerInfo, error)
 
@@ -44,15 +83,69 @@ type Container interface {
 	// Returns the current bandwidth limits set for the container.
 	CurrentBandwidthLimits() (BandwidthLimits, error)
 
+	// LimitBandwidth replaces the container's bandwidth limits, which may
+	// differ for traffic into and out of the container.
+	//
+	// Errors:
+	// * PermissionDeniedError, if the backend does not allow the limits to change.
+	LimitBandwidth(limits BandwidthLimits) error
+
 	// Returns the current CPU limts set for the container.
 	CurrentCPULimits() (CPULimits, error)
 
+	// LimitCPU replaces the container's CPU limits: its shares, its quota of
+	// CPU time in each period, and the CPUs it may run on.
+	//
+	// Errors:
+	// * PermissionDeniedError, if the backend does not allow the limits to change.
+	LimitCPU(limits CPULimits) error
+
 	// Returns the current disk limts set for the container.
 	CurrentDiskLimits() (DiskLimits, error)
 
 	// Returns the current memory limts set for the container.
 	CurrentMemoryLimits() (MemoryLimits, error)
 
+	// LimitMemory replaces the container's memory limits, including how much
+	// it may swap.
+	//
+	// Errors:
+	// * PermissionDeniedError, if the backend does not allow the limits to change.
+	LimitMemory(limits MemoryLimits) error
+
+	// Returns the current limit on the number of processes in the container.
+	CurrentPidLimits() (PidLimits, error)
+
+	// LimitPids caps the number of processes and threads in the container,
+	// e.g. to stop a fork bomb from exhausting the host's pids. Processes
+	// already over the new limit are not killed, but no more may start.
+	//
+	// Errors:
+	// * PermissionDeniedError, if the backend does not allow the limit to change.
+	LimitPids(limits PidLimits) error
+
+	// Returns the current block IO limits set for the container.
+	CurrentBlockIOLimits() (BlockIOLimits, error)
+
+	// LimitBlockIO throttles the container's reads from and writes to block
+	// devices, e.g. so that one container's disk access cannot starve its
+	// neighbours'.
+	//
+	// Errors:
+	// * PermissionDeniedError, if the backend does not allow the limit to change.
+	LimitBlockIO(limits BlockIOLimits) error
+
+	// CurrentLimits returns all of the current limits set for the container at
+	// once.
+	CurrentLimits() (Limits, error)
+
+	// LimitAll replaces all of the limits set for the container at once, as
+	// given in the same form as in its ContainerSpec.
+	//
+	// Errors:
+	// * PermissionDeniedError, if the backend does not allow a limit to change.
+	LimitAll(limits Limits) error
+
 	// Map a port on the host to a port in the container so that traffic to the
 	// host port is forwarded to the container port.
 	//
@@ -66,8 +159,34 @@ type Container interface {
 	//
 	// Errors:
 	// * When no port can be acquired from the server's port pool.
+	// * PortUnavailableError, when the host port is quarantined or outside the
+	//   container's port range, or no port in the range is free.
 	NetIn(hostPort, containerPort uint32) (uint32, uint32, error)
 
+	// NetInWithHostIP maps a port as NetIn does, but only for traffic to the
+	// given IP of one of the host's interfaces, e.g. an internal one, rather
+	// than to any of them. The IP may be IPv4 or IPv6. The mapping is returned
+	// with the IP in its canonical form.
+	//
+	// Errors:
+	// * When no port can be acquired from the server's port pool.
+	// * When the host has no interface with the IP.
+	NetInWithHostIP(hostIP string, hostPort, containerPort uint32) (PortMapping, error)
+
+	// NetInMappings returns the container's port mappings, in the order they
+	// were added.
+	//
+	// Errors:
+	// * None.
+	NetInMappings() ([]PortMapping, error)
+
+	// RemoveNetIn removes the mapping of the given host port, so that traffic
+	// to it is no longer forwarded to the container.
+	//
+	// Errors:
+	// * hostPort is not mapped to the container.
+	RemoveNetIn(hostPort uint32) error
+
 	// Whitelist outbound network traffic.
 	//
 	// If the configuration directive deny_networks is not used,
@@ -76,10 +195,44 @@ type Container interface {
 	// Later NetOut calls take precedence over earlier calls, which is
 	// significant only in relation to logging.
 	//
+	// A rule without an ID is assigned one, which identifies it in the rules
+	// returned by NetOutRules.
+	//
 	// Errors:
 	// * An error is returned if the NetOut call fails.
+	// * MalformedRequestError, if the rule's ID is that of an active rule.
 	NetOut(netOutRule NetOutRule) error
 
+	// BulkNetOut whitelists outbound network traffic for each of the rules,
+	// which are applied all together or not at all, as NetOut does for a
+	// single rule. Rules without an ID are assigned one.
+	//
+	// Errors:
+	// * An error is returned, and none of the rules applied, if any rule fails.
+	// * MalformedRequestError, if two of the rules have the same ID, or one has
+	//   the ID of an active rule.
+	BulkNetOut(netOutRules []NetOutRule) error
+
+	// NetOutRules returns the active outbound rules of the container, with
+	// their IDs, in the order they were added.
+	//
+	// Errors:
+	// * None.
+	NetOutRules() ([]NetOutRule, error)
+
+	// RemoveNetOut removes the outbound rule with the given ID, so that the
+	// traffic it whitelisted is no longer permitted unless by another rule.
+	//
+	// Errors:
+	// * ruleID does not refer to an active rule.
+	RemoveNetOut(ruleID string) error
+
+	// RemoveAllNetOut removes all of the container's outbound rules.
+	//
+	// Errors:
+	// * None.
+	RemoveAllNetOut() error
+
 	// Run a script inside a container.
 	//
 	// The root user will be mapped to a non-root UID in the host unless the container (not this process) was created with 'privileged' true.
@@ -88,6 +241,23 @@ type Container interface {
 	// * TODO.
 	Run(ProcessSpec, ProcessIO) (Process, error)
 
+	// Processes returns the processes that have been run in the container and
+	// are still known to the backend, so that they may be attached to or
+	// signalled.
+	//
+	// Errors:
+	// * None.
+	Processes() ([]ProcessInfo, error)
+
+	// ProcessMetrics returns the resources used by each running process in
+	// the container, by process ID, e.g. to find which of several processes
+	// is using the most memory. The usage of a process includes that of its
+	// descendants.
+	//
+	// Errors:
+	// * None.
+	ProcessMetrics() (map[string]ProcessMetrics, error)
+
 	// Attach starts streaming the output back to the client from a specified process.
 	//
 	// Errors:
@@ -97,9 +267,36 @@ type Container interface {
 	// Metrics returns the current set of metrics for a container
 	Metrics() (Metrics, error)
 
-	// Sets the grace time.
+	// DiskUsage returns how much disk is used beneath each of the given
+	// paths in the container, in the order given, e.g. to tell whether logs,
+	// temporary files or the application are consuming the disk quota.
+	//
+	// Errors:
+	// * No paths are given, or a path is not absolute.
+	DiskUsage(paths []string) ([]PathDiskUsage, error)
+
+	// HostResources returns the host-level resources the container is using,
+	// i.e. what will be released or break when it is destroyed.
+	//
+	// Errors:
+	// * None.
+	HostResources() (HostResources, error)
+
+	// SetGraceTime replaces the grace time given in the container's spec, and
+	// restarts the countdown to the container's destruction from now, e.g. to
+	// keep a container being debugged around for longer. A grace time of zero
+	// means the container is never destroyed for being idle.
+	//
+	// Errors:
+	// * None.
 	SetGraceTime(graceTime time.Duration) error
 
+	// SetDescription replaces the description given in the container's spec.
+	//
+	// Errors:
+	// * MalformedRequestError, if the description is longer than MaxDescriptionLength.
+	SetDescription(description string) error
+
 	// Properties returns the current set of properties
 	Properties() (Properties, error)
 
@@ -124,6 +321,11 @@ type Container interface {
 
 // ProcessSpec contains parameters for running a script inside a container.
 type ProcessSpec struct {
+	// Name, if specified, is a name chosen by the client, e.g. "web", by
+	// which the process may be attached to and signalled instead of by its
+	// ID. No two running processes in a container may have the same name.
+	Name string `json:"name,omitempty"`
+
 	// Path to command to execute.
 	Path string `json:"path,omitempty"`
 
@@ -139,11 +341,108 @@ type ProcessSpec struct {
 	// The name of a user in the container to run the process as.
 	User string `json:"user,omitempty"`
 
-	// Resource limits
+	// SupplementaryGroups are the names or numeric IDs of groups in the
+	// container the process is a member of, beyond the user's primary group,
+	// e.g. so that it may use a group-owned bind mount without being root.
+	SupplementaryGroups []string `json:"supplementary_groups,omitempty"`
+
+	// Umask, if specified, is the file mode creation mask of the process,
+	// rather than the backend's default, e.g. 0002 to create group-writable
+	// files.
+	Umask *uint32 `json:"umask,omitempty"`
+
+	// Resource limits. Limits.Core bounds the size of the core files the
+	// process may dump, and is zero, disabling core dumps, by default on most
+	// hosts.
 	Limits ResourceLimits `json:"rlimits,omitempty"`
 
+	// OOMScoreAdj, if specified, adjusts how readily the kernel kills the
+	// process when the container is out of memory, from MinOOMScoreAdj, never,
+	// to MaxOOMScoreAdj, first, e.g. so that a critical sidecar outlives the
+	// main process.
+	OOMScoreAdj *int `json:"oom_score_adj,omitempty"`
+
+	// Timeout, if non-zero, is how long the process may run before the server
+	// kills it with SignalKill, e.g. so that a hung health check does not run
+	// forever. The exit of a process killed this way is TimedOut.
+	Timeout time.Duration `json:"timeout,omitempty"`
+
 	// Execute with a TTY for stdio.
 	TTY *TTYSpec `json:"tty,omitempty"`
+
+	// Capabilities adjusts the capabilities of the process, within those the
+	// container's processes may hold.
+	Capabilities Capabilities `json:"capabilities,omitempty"`
+}
+
+// MinOOMScoreAdj and MaxOOMScoreAdj bound ProcessSpec.OOMScoreAdj, matching
+// the kernel's bounds on oom_score_adj.
+const (
+	MinOOMScoreAdj = -1000
+	MaxOOMScoreAdj = 1000
+)
+
+// States of a ProcessInfo.
+const (
+	ProcessStateRunning = "running"
+	ProcessStateExited  = "exited"
+)
+
+// ProcessInfo describes a process that has been run in a container.
+type ProcessInfo struct {
+	ID string `json:"id"`
+
+	// Name as given in the process's ProcessSpec, if any.
+	Name string `json:"name,omitempty"`
+
+	// Path and Args as given in the process's ProcessSpec.
+	Path string   `json:"path"`
+	Args []string `json:"args,omitempty"`
+
+	StartedAt time.Time `json:"started_at"`
+
+	// Either ProcessStateRunning or ProcessStateExited.
+	State string `json:"state"`
+}
+
+// ProcessMetrics describes the resources used by a process.
+type ProcessMetrics struct {
+	CPUStat ContainerCPUStat `json:"cpu_stat"`
+
+	// MemoryUsageInBytes is the resident memory of the process.
+	MemoryUsageInBytes uint64 `json:"memory_usage_in_bytes"`
+}
+
+// ProcessExit records how a process exited, so that it may be queried after
+// the process has finished without attaching to it.
+type ProcessExit struct {
+	ProcessID string `json:"process_id"`
+
+	// ExitStatus is the status the process exited with, unless waiting for it
+	// failed, in which case Error is set instead.
+	ExitStatus int    `json:"exit_status"`
+	Error      string `json:"error,omitempty"`
+
+	ExitedAt time.Time `json:"exited_at"`
+
+	// TimedOut is set if the server killed the process because it ran for
+	// longer than the Timeout in its ProcessSpec.
+	TimedOut bool `json:"timed_out,omitempty"`
+
+	// Usage is set if the backend's process implements ProcessUsageReporter.
+	Usage *ProcessUsage `json:"usage,omitempty"`
+}
+
+// ProcessUsage is the resources used by a process over its lifetime.
+type ProcessUsage struct {
+	CPUTime        time.Duration `json:"cpu_time"`
+	MaxMemoryBytes uint64        `json:"max_memory_bytes"`
+}
+
+// ProcessUsageReporter may be implemented by a Process whose backend can
+// report the resources it used once it has exited.
+type ProcessUsageReporter interface {
+	Usage() (ProcessUsage, error)
 }
 
 type TTYSpec struct {
@@ -159,6 +458,12 @@ type ProcessIO struct {
 	Stdin  io.Reader
 	Stdout io.Writer
 	Stderr io.Writer
+
+	// Interleave, if true, asks the server to merge the process's stdout and
+	// stderr into a single stream written to Stdout, with each line prefixed
+	// by an RFC 3339 timestamp and the name of the stream it was written to.
+	// Stderr is not used.
+	Interleave bool
 }
 
 //go:generate counterfeiter . Process
@@ -168,6 +473,11 @@ type Process interface {
 	Wait() (int, error)
 	SetTTY(TTYSpec) error
 	Signal(Signal) error
+
+	// CloseStdin closes the standard input of the process, so that it sees
+	// EOF, while leaving its output streams and the process itself running.
+	// Any data still being copied from ProcessIO.Stdin is discarded.
+	CloseStdin() error
 }
 
 type Signal int
@@ -175,11 +485,22 @@ type Signal int
 const (
 	SignalTerminate Signal = iota
 	SignalKill
+	SignalHangup
+	SignalUser1
+	SignalUser2
+	SignalInterrupt
+	SignalQuit
 )
 
+// Valid reports whether the signal is one that may be delivered to a process.
+func (s Signal) Valid() bool {
+	return s >= SignalTerminate && s <= SignalQuit
+}
+
 type PortMapping struct {
 	HostPort      uint32
 	ContainerPort uint32
+	HostIP        string // The host IP the mapping is restricted to, if any.
 }
 
 type StreamInSpec struct {
@@ -191,24 +512,87 @@ type StreamInSpec struct {
 type StreamOutSpec struct {
 	Path string
 	User string
+
+	// ResumeFrom, if set, is the name of an entry in the tar stream. Entries
+	// before it are skipped, so that a truncated transfer may be resumed
+	// without starting from scratch.
+	ResumeFrom string
+
+	// Deadline, if set, is when the client abandons the transfer. It is not
+	// sent to the server.
+	Deadline time.Time
 }
 
 // ContainerInfo holds information about a container.
 type ContainerInfo struct {
-	State         string        // Either "active" or "stopped".
+	State         string        // Either "active", "paused" or "stopped".
 	Events        []string      // List of events that occurred for the container. It currently includes only "oom" (Out Of Memory) event if it occurred.
 	HostIP        string        // The IP address of the gateway which controls the host side of the container's virtual ethernet pair.
 	ContainerIP   string        // The IP address of the container side of the container's virtual ethernet pair.
 	ExternalIP    string        //
+	ContainerIPv6 string        // The IPv6 address of the container side of the container's virtual ethernet pair, if it has one.
+	ExternalIPv6  string        // The IPv6 address by which the container is reached externally, if it has one.
 	ContainerPath string        // The path to the directory holding the container's files (both its control scripts and filesystem).
 	ProcessIDs    []string      // List of running processes.
 	Properties    Properties    // List of properties defined for the container.
 	MappedPorts   []PortMapping //
+	OOMCount      int           // Number of times the container has run out of memory.
+	LastOOM       time.Time     // When the container last ran out of memory, if it has.
+	Description   string        // What the container is for, as given in its spec or by SetDescription.
+	Hostname      string        // The container's hostname.
+	LSMProfile    string        // The AppArmor profile or SELinux label confining the container's processes.
+	ImageDigest   string        // The digest of the OCI image the container was created from, if it was created from one.
+
+	// Health is the result of the container's health check, as reported by
+	// the server, if its spec declared one.
+	Health *HealthStatus `json:",omitempty"`
 }
 
+// ContainerInfoEntry holds either the info for a container or the error that
+// prevented it from being retrieved. Err wraps one of ContainerNotFoundError,
+// BackendTimeoutError or PermissionDeniedError where the backend can
+// classify the failure.
 type ContainerInfoEntry struct {
 	Info ContainerInfo
 	Err  *Error
+
+	// Tombstone is set when the container existed at the point in time a
+	// snapshot bulk request was received but was destroyed before its entry
+	// could be collected.
+	Tombstone bool `json:",omitempty"`
+}
+
+// HostResources describes the resources of the host that a container depends
+// on.
+type HostResources struct {
+	// Ports on the host that are mapped into the container.
+	HostPorts []uint32 `json:"host_ports,omitempty"`
+
+	// Paths on the host that are bind mounted into the container.
+	BindMountSourcePaths []string `json:"bind_mount_source_paths,omitempty"`
+
+	// Ranges of host UIDs and GIDs that the container's users are mapped to.
+	UIDMappings []IDMapping `json:"uid_mappings,omitempty"`
+	GIDMappings []IDMapping `json:"gid_mappings,omitempty"`
+}
+
+// DestroyImpact describes what destroying a container would clean up.
+type DestroyImpact struct {
+	// Processes that would be killed.
+	ProcessIDs []string `json:"process_ids,omitempty"`
+
+	// Host ports that would be released.
+	HostPorts []uint32 `json:"host_ports,omitempty"`
+
+	// Disk space, in bytes, that would be reclaimed.
+	DiskBytes uint64 `json:"disk_bytes"`
+}
+
+// IDMapping maps a contiguous range of IDs in a container to IDs on the host.
+type IDMapping struct {
+	HostID      uint32 `json:"host_id"`
+	ContainerID uint32 `json:"container_id"`
+	Size        uint32 `json:"size"`
 }
 
 type Metrics struct {
@@ -218,9 +602,79 @@ type Metrics struct {
 	NetworkStat ContainerNetworkStat
 }
 
+// MetricsSnapshot is one of the snapshots of the metrics of containers sent
+// periodically by the StreamMetrics route.
+type MetricsSnapshot struct {
+	// Time is when the metrics were collected.
+	Time time.Time `json:"time"`
+
+	// Entries holds the metrics of each container, by handle, as returned by
+	// BulkMetrics.
+	Entries map[string]ContainerMetricsEntry `json:"entries"`
+}
+
+// BulkMetricsDelta is the response to a differential BulkMetrics request,
+// holding only the entries which changed since the request whose token was
+// given.
+type BulkMetricsDelta struct {
+	// Token is given with the next request to receive only the entries which
+	// changed since this one.
+	Token string `json:"token"`
+
+	// Full is set if every entry is included, because no token was given or
+	// the server no longer knew it.
+	Full bool `json:"full"`
+
+	// Entries holds the entries which changed, by handle.
+	Entries map[string]ContainerMetricsEntry `json:"entries"`
+}
+
+// MetricsThresholds are how far the metrics of a container must move before
+// a differential BulkMetrics request reports them as changed. Changes in
+// metrics other than these, such as the details of memory usage, are not
+// reported on their own. A zero threshold reports any change.
+type MetricsThresholds struct {
+	// MemoryBytes applies to MemoryStat.TotalUsageTowardLimit.
+	MemoryBytes uint64
+
+	// CPUNanoseconds applies to CPUStat.Usage.
+	CPUNanoseconds uint64
+
+	// DiskBytes applies to DiskStat.TotalBytesUsed.
+	DiskBytes uint64
+
+	// NetworkBytes applies to each of NetworkStat.RxBytes and TxBytes.
+	NetworkBytes uint64
+}
+
+// ContainerMetricsEntry holds either the metrics for a container or the error
+// that prevented them from being retrieved, classified as for
+// ContainerInfoEntry.
 type ContainerMetricsEntry struct {
 	Metrics Metrics
 	Err     *Error
+
+	// Tombstone is set as for ContainerInfoEntry.
+	Tombstone bool `json:",omitempty"`
+}
+
+// ContainerCreateEntry holds either the handle of a container created by a
+// bulk create or the error that prevented it from being created.
+type ContainerCreateEntry struct {
+	Handle string
+	Err    *Error
+}
+
+// ContainerDestroyEntry holds the error that prevented a container from being
+// destroyed by a bulk destroy, if any.
+type ContainerDestroyEntry struct {
+	Err *Error
+}
+
+// ContainerStopEntry holds the error that prevented a container from being
+// stopped by a bulk stop, if any.
+type ContainerStopEntry struct {
+	Err *Error
 }
 
 type ContainerMemoryStat struct {
@@ -270,6 +724,12 @@ type ContainerDiskStat struct {
 	ExclusiveInodesUsed uint64
 }
 
+// PathDiskUsage is the disk used beneath a path in a container.
+type PathDiskUsage struct {
+	Path      string `json:"path"`
+	BytesUsed uint64 `json:"bytes_used"`
+}
+
 type ContainerBandwidthStat struct {
 	InRate   uint64
 	InBurst  uint64
@@ -277,14 +737,59 @@ type ContainerBandwidthStat struct {
 	OutBurst uint64
 }
 
+// ContainerNetworkStat counts the traffic through the container's network
+// interface since it was created. Rx counts traffic into the container and Tx
+// traffic out of it.
 type ContainerNetworkStat struct {
 	RxBytes uint64
 	TxBytes uint64
+
+	RxPackets uint64
+	TxPackets uint64
+
+	// Dropped counts packets discarded, e.g. by bandwidth limits, rather than
+	// delivered.
+	RxDropped uint64
+	TxDropped uint64
 }
 
 type BandwidthLimits struct {
+	// RateInBytesPerSecond and BurstRateInBytesPerSecond limit traffic in
+	// both directions, unless overridden for a direction below.
 	RateInBytesPerSecond      uint64 `json:"rate,omitempty"`
 	BurstRateInBytesPerSecond uint64 `json:"burst,omitempty"`
+
+	// Ingress limits override the rate and burst of traffic into the
+	// container where they are non-zero.
+	IngressRateInBytesPerSecond      uint64 `json:"ingress_rate,omitempty"`
+	IngressBurstRateInBytesPerSecond uint64 `json:"ingress_burst,omitempty"`
+
+	// Egress limits override the rate and burst of traffic out of the
+	// container where they are non-zero.
+	EgressRateInBytesPerSecond      uint64 `json:"egress_rate,omitempty"`
+	EgressBurstRateInBytesPerSecond uint64 `json:"egress_burst,omitempty"`
+}
+
+// Ingress returns the rate and burst to limit traffic into the container
+// to, falling back to the limits for both directions.
+func (limits BandwidthLimits) Ingress() (rate, burst uint64) {
+	return orDefault(limits.IngressRateInBytesPerSecond, limits.RateInBytesPerSecond),
+		orDefault(limits.IngressBurstRateInBytesPerSecond, limits.BurstRateInBytesPerSecond)
+}
+
+// Egress returns the rate and burst to limit traffic out of the container
+// to, falling back to the limits for both directions.
+func (limits BandwidthLimits) Egress() (rate, burst uint64) {
+	return orDefault(limits.EgressRateInBytesPerSecond, limits.RateInBytesPerSecond),
+		orDefault(limits.EgressBurstRateInBytesPerSecond, limits.BurstRateInBytesPerSecond)
+}
+
+func orDefault(value, def uint64) uint64 {
+	if value == 0 {
+		return def
+	}
+
+	return value
 }
 
 type DiskLimits struct {
@@ -300,10 +805,50 @@ type DiskLimits struct {
 type MemoryLimits struct {
 	//	Memory usage limit in bytes.
 	LimitInBytes uint64 `json:"limit_in_bytes,omitempty"`
+
+	// SwapLimitInBytes caps how much swap the container may use beyond its
+	// memory limit. Zero prevents the container from swapping at all, and
+	// nil leaves it to the backend.
+	SwapLimitInBytes *uint64 `json:"swap_limit_in_bytes,omitempty"`
+
+	// Swappiness is how readily, from 0 to 100, the kernel swaps out the
+	// container's memory, or nil to leave it to the backend.
+	Swappiness *uint64 `json:"swappiness,omitempty"`
 }
 
 type CPULimits struct {
 	LimitInShares uint64 `json:"limit_in_shares,omitempty"`
+
+	// QuotaInMicroseconds is how much CPU time the container may use in each
+	// period, hard capping its usage even when the host is idle, or zero for
+	// no cap.
+	QuotaInMicroseconds uint64 `json:"quota_in_microseconds,omitempty"`
+
+	// PeriodInMicroseconds is the period over which the quota applies, or
+	// zero for the backend's default.
+	PeriodInMicroseconds uint64 `json:"period_in_microseconds,omitempty"`
+
+	// Cpuset lists the CPUs the container may run on in the kernel's list
+	// format, e.g. "0-3,6", or is empty for all of them.
+	Cpuset string `json:"cpuset,omitempty"`
+}
+
+type PidLimits struct {
+	// Max is the largest number of processes and threads which may exist in
+	// the container at once, or zero for no limit.
+	Max uint64 `json:"max,omitempty"`
+}
+
+type BlockIOLimits struct {
+	// ReadIOPS and WriteIOPS cap the read and write operations per second,
+	// or are zero for no limit.
+	ReadIOPS  uint64 `json:"read_iops,omitempty"`
+	WriteIOPS uint64 `json:"write_iops,omitempty"`
+
+	// ReadBytesPerSecond and WriteBytesPerSecond cap the bytes read and
+	// written per second, or are zero for no limit.
+	ReadBytesPerSecond  uint64 `json:"read_bytes_per_second,omitempty"`
+	WriteBytesPerSecond uint64 `json:"write_bytes_per_second,omitempty"`
 }
 
 // Resource limits.
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 )
 
 type errType string
@@ -13,6 +14,19 @@ const (
 	unrecoverableErrType      = "UnrecoverableError"
 	serviceUnavailableErrType = "ServiceUnavailableError"
 	containerNotFoundErrType  = "ContainerNotFoundError"
+	backendTimeoutErrType     = "BackendTimeoutError"
+	permissionDeniedErrType   = "PermissionDeniedError"
+	networkSetupErrType       = "NetworkSetupError"
+	processNotFoundErrType    = "ProcessNotFoundError"
+	handleTakenErrType        = "HandleTakenError"
+	ipTakenErrType            = "IPTakenError"
+	imageNotFoundErrType      = "ImageNotFoundError"
+	processNameTakenErrType   = "ProcessNameTakenError"
+	propertyConflictErrType   = "PropertyConflictError"
+	malformedRequestErrType   = "MalformedRequestError"
+	tooManyRequestsErrType    = "TooManyRequestsError"
+	payloadTooLargeErrType    = "PayloadTooLargeError"
+	portUnavailableErrType    = "PortUnavailableError"
 )
 
 type Error struct {
@@ -24,9 +38,18 @@ func NewError(err string) *Error {
 }
 
 type marshalledError struct {
-	Type    errType
-	Message string
-	Handle  string
+	Type       errType
+	Message    string
+	Handle     string
+	RetryAfter time.Duration `json:",omitempty"`
+	Phase      string        `json:",omitempty"`
+	ProcessID  string        `json:",omitempty"`
+	IP         string        `json:",omitempty"`
+	Image      string        `json:",omitempty"`
+	Name       string        `json:",omitempty"`
+	Value      string        `json:",omitempty"`
+	Limit      int64         `json:",omitempty"`
+	HostPort   uint32        `json:",omitempty"`
 }
 
 func (m Error) Error() string {
@@ -35,8 +58,22 @@ func (m Error) Error() string {
 
 func (m Error) StatusCode() int {
 	switch m.Err.(type) {
-	case ContainerNotFoundError:
+	case ContainerNotFoundError, ProcessNotFoundError, ImageNotFoundError:
 		return http.StatusNotFound
+	case BackendTimeoutError:
+		return http.StatusGatewayTimeout
+	case PermissionDeniedError:
+		return http.StatusForbidden
+	case ServiceUnavailableError:
+		return http.StatusServiceUnavailable
+	case TooManyRequestsError:
+		return http.StatusTooManyRequests
+	case HandleTakenError, IPTakenError, ProcessNameTakenError, PropertyConflictError, PortUnavailableError:
+		return http.StatusConflict
+	case MalformedRequestError:
+		return http.StatusBadRequest
+	case PayloadTooLargeError:
+		return http.StatusRequestEntityTooLarge
 	}
 
 	return http.StatusInternalServerError
@@ -44,18 +81,89 @@ func (m Error) StatusCode() int {
 
 func (m Error) MarshalJSON() ([]byte, error) {
 	var errorType errType
+	message := m.Err.Error()
 	handle := ""
+	var retryAfter time.Duration
+	phase := ""
+	processID := ""
+	ip := ""
+	image := ""
+	name := ""
+	value := ""
+	var limit int64
+	var hostPort uint32
 	switch err := m.Err.(type) {
 	case ContainerNotFoundError:
 		errorType = containerNotFoundErrType
 		handle = err.Handle
 	case ServiceUnavailableError:
 		errorType = serviceUnavailableErrType
+		retryAfter = err.RetryAfter
+	case TooManyRequestsError:
+		errorType = tooManyRequestsErrType
+		retryAfter = err.RetryAfter
 	case UnrecoverableError:
 		errorType = unrecoverableErrType
+	case BackendTimeoutError:
+		errorType = backendTimeoutErrType
+		handle = err.Handle
+	case PermissionDeniedError:
+		errorType = permissionDeniedErrType
+		handle = err.Handle
+	case NetworkSetupError:
+		errorType = networkSetupErrType
+		message = err.Cause
+		handle = err.Handle
+		phase = err.Phase
+	case ProcessNotFoundError:
+		errorType = processNotFoundErrType
+		handle = err.Handle
+		processID = err.ProcessID
+	case HandleTakenError:
+		errorType = handleTakenErrType
+		handle = err.Handle
+	case IPTakenError:
+		errorType = ipTakenErrType
+		handle = err.Handle
+		ip = err.IP
+	case ImageNotFoundError:
+		errorType = imageNotFoundErrType
+		image = err.URI
+	case ProcessNameTakenError:
+		errorType = processNameTakenErrType
+		handle = err.Handle
+		name = err.Name
+	case PropertyConflictError:
+		errorType = propertyConflictErrType
+		handle = err.Handle
+		name = err.Name
+		value = err.Value
+	case MalformedRequestError:
+		errorType = malformedRequestErrType
+		message = err.Cause
+	case PayloadTooLargeError:
+		errorType = payloadTooLargeErrType
+		limit = err.Limit
+	case PortUnavailableError:
+		errorType = portUnavailableErrType
+		message = err.Cause
+		hostPort = err.HostPort
 	}
 
-	return json.Marshal(marshalledError{errorType, m.Err.Error(), handle})
+	return json.Marshal(marshalledError{
+		Type:       errorType,
+		Message:    message,
+		Handle:     handle,
+		RetryAfter: retryAfter,
+		Phase:      phase,
+		ProcessID:  processID,
+		IP:         ip,
+		Image:      image,
+		Name:       name,
+		Value:      value,
+		Limit:      limit,
+		HostPort:   hostPort,
+	})
 }
 
 func (m *Error) UnmarshalJSON(data []byte) error {
@@ -69,9 +177,35 @@ func (m *Error) UnmarshalJSON(data []byte) error {
 	case unrecoverableErrType:
 		m.Err = UnrecoverableError{result.Message}
 	case serviceUnavailableErrType:
-		m.Err = ServiceUnavailableError{result.Message}
+		m.Err = ServiceUnavailableError{Cause: result.Message, RetryAfter: result.RetryAfter}
+	case tooManyRequestsErrType:
+		m.Err = TooManyRequestsError{Cause: result.Message, RetryAfter: result.RetryAfter}
 	case containerNotFoundErrType:
 		m.Err = ContainerNotFoundError{result.Handle}
+	case backendTimeoutErrType:
+		m.Err = BackendTimeoutError{Handle: result.Handle, Cause: result.Message}
+	case permissionDeniedErrType:
+		m.Err = PermissionDeniedError{Handle: result.Handle, Cause: result.Message}
+	case networkSetupErrType:
+		m.Err = NetworkSetupError{Handle: result.Handle, Phase: result.Phase, Cause: result.Message}
+	case processNotFoundErrType:
+		m.Err = ProcessNotFoundError{Handle: result.Handle, ProcessID: result.ProcessID}
+	case handleTakenErrType:
+		m.Err = HandleTakenError{Handle: result.Handle}
+	case ipTakenErrType:
+		m.Err = IPTakenError{IP: result.IP, Handle: result.Handle}
+	case imageNotFoundErrType:
+		m.Err = ImageNotFoundError{URI: result.Image}
+	case processNameTakenErrType:
+		m.Err = ProcessNameTakenError{Handle: result.Handle, Name: result.Name}
+	case propertyConflictErrType:
+		m.Err = PropertyConflictError{Handle: result.Handle, Name: result.Name, Value: result.Value}
+	case malformedRequestErrType:
+		m.Err = MalformedRequestError{Cause: result.Message}
+	case payloadTooLargeErrType:
+		m.Err = PayloadTooLargeError{Limit: result.Limit}
+	case portUnavailableErrType:
+		m.Err = PortUnavailableError{HostPort: result.HostPort, Cause: result.Message}
 	default:
 		m.Err = errors.New(result.Message)
 	}
@@ -101,16 +235,221 @@ func (err ContainerNotFoundError) Error() string {
 	return fmt.Sprintf("unknown handle: %s", err.Handle)
 }
 
+// HandleTakenError is returned by Create when a container with the requested
+// handle already exists.
+type HandleTakenError struct {
+	Handle string
+}
+
+func (err HandleTakenError) Error() string {
+	return fmt.Sprintf("handle already taken: %s", err.Handle)
+}
+
+// IPTakenError is returned by Create when the requested container IP is
+// already assigned to the container with the handle.
+type IPTakenError struct {
+	IP     string
+	Handle string
+}
+
+func (err IPTakenError) Error() string {
+	return fmt.Sprintf("container IP %s already taken by %s", err.IP, err.Handle)
+}
+
+// ImageNotFoundError is returned by RemoveImage when no image with the URI is
+// cached.
+type ImageNotFoundError struct {
+	URI string
+}
+
+func (err ImageNotFoundError) Error() string {
+	return fmt.Sprintf("unknown image: %s", err.URI)
+}
+
+// ProcessNotFoundError indicates that no process with the ID is known in the
+// container.
+type ProcessNotFoundError struct {
+	Handle    string
+	ProcessID string
+}
+
+func (err ProcessNotFoundError) Error() string {
+	return fmt.Sprintf("unknown process: %s", err.ProcessID)
+}
+
+// ProcessNameTakenError is returned by Run when a running process in the
+// container already has the requested name.
+type ProcessNameTakenError struct {
+	Handle string
+	Name   string
+}
+
+func (err ProcessNameTakenError) Error() string {
+	return fmt.Sprintf("process name already taken: %s", err.Name)
+}
+
+// PropertyConflictError is returned by a compare-and-swap of a property when
+// the property's value is not the one expected. Value is its current value,
+// which is empty if the property is not set.
+type PropertyConflictError struct {
+	Handle string
+	Name   string
+	Value  string
+}
+
+func (err PropertyConflictError) Error() string {
+	return fmt.Sprintf("property has a different value: %s", err.Name)
+}
+
+// MalformedRequestError is returned by a server decoding requests strictly
+// when a request has unknown fields or values out of bounds. Retrying the
+// same request will not succeed.
+type MalformedRequestError struct {
+	Cause string
+}
+
+func (err MalformedRequestError) Error() string {
+	return fmt.Sprintf("malformed request: %s", err.Cause)
+}
+
+// PayloadTooLargeError is returned by a server when the body of a request is
+// larger than its limit for the route, in bytes. Retrying the same request
+// will not succeed.
+type PayloadTooLargeError struct {
+	Limit int64
+}
+
+func (err PayloadTooLargeError) Error() string {
+	return fmt.Sprintf("request body is larger than the limit of %d bytes", err.Limit)
+}
+
+// PortUnavailableError is returned by NetIn when the host port cannot be
+// mapped, such as when it is quarantined after being released by another
+// container or is reserved for another namespace, or when no port is free in
+// the range of the container's namespace, in which case HostPort is zero.
+type PortUnavailableError struct {
+	HostPort uint32
+	Cause    string
+}
+
+func (err PortUnavailableError) Error() string {
+	return err.Cause
+}
+
 func NewServiceUnavailableError(cause string) error {
 	return ServiceUnavailableError{
 		Cause: cause,
 	}
 }
 
+// NewServiceUnavailableErrorWithRetry returns a ServiceUnavailableError
+// suggesting that the client waits for retryAfter before trying again.
+func NewServiceUnavailableErrorWithRetry(cause string, retryAfter time.Duration) error {
+	return ServiceUnavailableError{
+		Cause:      cause,
+		RetryAfter: retryAfter,
+	}
+}
+
 type ServiceUnavailableError struct {
 	Cause string
+
+	// RetryAfter is how long the server suggests waiting before retrying, or
+	// zero if it made no suggestion.
+	RetryAfter time.Duration
 }
 
 func (err ServiceUnavailableError) Error() string {
 	return err.Cause
 }
+
+// TooManyRequestsError indicates that the server refused a request because
+// the client has made too many, at once or recently. The request may succeed
+// if retried later.
+type TooManyRequestsError struct {
+	Cause string
+
+	// RetryAfter is how long the server suggests waiting before retrying, or
+	// zero if it made no suggestion.
+	RetryAfter time.Duration
+}
+
+func (err TooManyRequestsError) Error() string {
+	return err.Cause
+}
+
+func NewBackendTimeoutError(handle, cause string) error {
+	return BackendTimeoutError{
+		Handle: handle,
+		Cause:  cause,
+	}
+}
+
+// BackendTimeoutError indicates that the backend did not respond in time for
+// an operation on a container. The operation may succeed if retried.
+type BackendTimeoutError struct {
+	Handle string
+	Cause  string
+}
+
+func (err BackendTimeoutError) Error() string {
+	return err.Cause
+}
+
+func NewPermissionDeniedError(handle, cause string) error {
+	return PermissionDeniedError{
+		Handle: handle,
+		Cause:  cause,
+	}
+}
+
+// PermissionDeniedError indicates that the backend refused an operation on a
+// container, or that the server refused the client the request. Retrying the operation will not succeed.
+type PermissionDeniedError struct {
+	Handle string
+	Cause  string
+}
+
+func (err PermissionDeniedError) Error() string {
+	return err.Cause
+}
+
+// Phases of setting up a container's network, as reported by
+// NetworkSetupError.
+const (
+	NetworkPhaseSubnetAllocation = "subnet_allocation"
+	NetworkPhaseBridge           = "bridge"
+	NetworkPhaseNATRule          = "nat_rule"
+)
+
+func NewNetworkSetupError(handle, phase, cause string) error {
+	return NetworkSetupError{
+		Handle: handle,
+		Phase:  phase,
+		Cause:  cause,
+	}
+}
+
+// NetworkSetupError indicates that setting up a container's network failed,
+// and identifies the phase which failed, e.g. NetworkPhaseSubnetAllocation,
+// so that clients may decide whether retrying is worthwhile.
+type NetworkSetupError struct {
+	Handle string
+	Phase  string
+	Cause  string
+}
+
+func (err NetworkSetupError) Error() string {
+	return fmt.Sprintf("network setup failed during %s: %s", err.Phase, err.Cause)
+}
+
+// ProcessTimedOutError is returned along with its exit status by the Wait of
+// a process started through a client if the server killed the process
+// because it ran for longer than the Timeout in its ProcessSpec.
+type ProcessTimedOutError struct {
+	ProcessID string
+}
+
+func (err ProcessTimedOutError) Error() string {
+	return fmt.Sprintf("process timed out: %s", err.ProcessID)
+}
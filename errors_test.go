@@ -0,0 +1,125 @@
+package garden_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"code.cloudfoundry.org/garden"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Error", func() {
+	roundTrip := func(err error) *garden.Error {
+		payload, marshalErr := json.Marshal(&garden.Error{Err: err})
+		Ω(marshalErr).ShouldNot(HaveOccurred())
+
+		var result garden.Error
+		Ω(json.Unmarshal(payload, &result)).Should(Succeed())
+
+		return &result
+	}
+
+	It("preserves a ContainerNotFoundError over the wire", func() {
+		result := roundTrip(garden.ContainerNotFoundError{Handle: "some-handle"})
+		Ω(result.Err).Should(Equal(garden.ContainerNotFoundError{Handle: "some-handle"}))
+		Ω(result.StatusCode()).Should(Equal(http.StatusNotFound))
+	})
+
+	It("preserves a BackendTimeoutError over the wire", func() {
+		result := roundTrip(garden.NewBackendTimeoutError("some-handle", "took too long"))
+		Ω(result.Err).Should(Equal(garden.BackendTimeoutError{Handle: "some-handle", Cause: "took too long"}))
+		Ω(result.StatusCode()).Should(Equal(http.StatusGatewayTimeout))
+	})
+
+	It("preserves a PermissionDeniedError over the wire", func() {
+		result := roundTrip(garden.NewPermissionDeniedError("some-handle", "not allowed"))
+		Ω(result.Err).Should(Equal(garden.PermissionDeniedError{Handle: "some-handle", Cause: "not allowed"}))
+		Ω(result.StatusCode()).Should(Equal(http.StatusForbidden))
+	})
+
+	It("preserves a ServiceUnavailableError and its retry hint over the wire", func() {
+		result := roundTrip(garden.NewServiceUnavailableErrorWithRetry("busy", 5*time.Second))
+		Ω(result.Err).Should(Equal(garden.ServiceUnavailableError{Cause: "busy", RetryAfter: 5 * time.Second}))
+		Ω(result.StatusCode()).Should(Equal(http.StatusServiceUnavailable))
+	})
+
+	It("preserves a TooManyRequestsError and its retry hint over the wire", func() {
+		result := roundTrip(garden.TooManyRequestsError{Cause: "slow down", RetryAfter: 2 * time.Second})
+		Ω(result.Err).Should(Equal(garden.TooManyRequestsError{Cause: "slow down", RetryAfter: 2 * time.Second}))
+		Ω(result.StatusCode()).Should(Equal(http.StatusTooManyRequests))
+	})
+
+	It("preserves a NetworkSetupError over the wire", func() {
+		result := roundTrip(garden.NewNetworkSetupError("some-handle", garden.NetworkPhaseNATRule, "iptables: exit status 1"))
+		Ω(result.Err).Should(Equal(garden.NetworkSetupError{Handle: "some-handle", Phase: garden.NetworkPhaseNATRule, Cause: "iptables: exit status 1"}))
+		Ω(result.Err).Should(MatchError("network setup failed during nat_rule: iptables: exit status 1"))
+		Ω(result.StatusCode()).Should(Equal(http.StatusInternalServerError))
+	})
+
+	It("preserves a HandleTakenError over the wire", func() {
+		result := roundTrip(garden.HandleTakenError{Handle: "some-handle"})
+		Ω(result.Err).Should(Equal(garden.HandleTakenError{Handle: "some-handle"}))
+		Ω(result.StatusCode()).Should(Equal(http.StatusConflict))
+	})
+
+	It("preserves an IPTakenError over the wire", func() {
+		result := roundTrip(garden.IPTakenError{IP: "10.0.0.5", Handle: "some-handle"})
+		Ω(result.Err).Should(Equal(garden.IPTakenError{IP: "10.0.0.5", Handle: "some-handle"}))
+		Ω(result.Err).Should(MatchError("container IP 10.0.0.5 already taken by some-handle"))
+		Ω(result.StatusCode()).Should(Equal(http.StatusConflict))
+	})
+
+	It("preserves an ImageNotFoundError over the wire", func() {
+		result := roundTrip(garden.ImageNotFoundError{URI: "docker:///busybox"})
+		Ω(result.Err).Should(Equal(garden.ImageNotFoundError{URI: "docker:///busybox"}))
+		Ω(result.Err).Should(MatchError("unknown image: docker:///busybox"))
+		Ω(result.StatusCode()).Should(Equal(http.StatusNotFound))
+	})
+
+	It("preserves a MalformedRequestError over the wire", func() {
+		result := roundTrip(garden.MalformedRequestError{Cause: "unknown field \"bogus\""})
+		Ω(result.Err).Should(Equal(garden.MalformedRequestError{Cause: "unknown field \"bogus\""}))
+		Ω(result.StatusCode()).Should(Equal(http.StatusBadRequest))
+	})
+
+	It("preserves a ProcessNotFoundError over the wire", func() {
+		result := roundTrip(garden.ProcessNotFoundError{Handle: "some-handle", ProcessID: "some-process"})
+		Ω(result.Err).Should(Equal(garden.ProcessNotFoundError{Handle: "some-handle", ProcessID: "some-process"}))
+		Ω(result.StatusCode()).Should(Equal(http.StatusNotFound))
+	})
+
+	It("preserves a ProcessNameTakenError over the wire", func() {
+		result := roundTrip(garden.ProcessNameTakenError{Handle: "some-handle", Name: "web"})
+		Ω(result.Err).Should(Equal(garden.ProcessNameTakenError{Handle: "some-handle", Name: "web"}))
+		Ω(result.StatusCode()).Should(Equal(http.StatusConflict))
+	})
+
+	It("preserves a PropertyConflictError over the wire", func() {
+		result := roundTrip(garden.PropertyConflictError{Handle: "some-handle", Name: "owner", Value: "some-owner"})
+		Ω(result.Err).Should(Equal(garden.PropertyConflictError{Handle: "some-handle", Name: "owner", Value: "some-owner"}))
+		Ω(result.StatusCode()).Should(Equal(http.StatusConflict))
+	})
+
+	It("preserves a PayloadTooLargeError and its limit over the wire", func() {
+		result := roundTrip(garden.PayloadTooLargeError{Limit: 1024})
+		Ω(result.Err).Should(Equal(garden.PayloadTooLargeError{Limit: 1024}))
+		Ω(result.Err).Should(MatchError("request body is larger than the limit of 1024 bytes"))
+		Ω(result.StatusCode()).Should(Equal(http.StatusRequestEntityTooLarge))
+	})
+
+	It("preserves a PortUnavailableError and its host port over the wire", func() {
+		result := roundTrip(garden.PortUnavailableError{HostPort: 61001, Cause: "host port 61001 is quarantined"})
+		Ω(result.Err).Should(Equal(garden.PortUnavailableError{HostPort: 61001, Cause: "host port 61001 is quarantined"}))
+		Ω(result.Err).Should(MatchError("host port 61001 is quarantined"))
+		Ω(result.StatusCode()).Should(Equal(http.StatusConflict))
+	})
+
+	It("falls back to a plain error for unknown types", func() {
+		result := roundTrip(errors.New("boom"))
+		Ω(result.Err).Should(MatchError("boom"))
+		Ω(result.StatusCode()).Should(Equal(http.StatusInternalServerError))
+	})
+})
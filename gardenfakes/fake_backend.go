@@ -92,6 +92,16 @@ type FakeBackend struct {
 	graceTimeReturns struct {
 		result1 time.Duration
 	}
+	RestoreStub        func(spec garden.ContainerSpec, source string) (garden.Container, error)
+	restoreMutex       sync.RWMutex
+	restoreArgsForCall []struct {
+		spec   garden.ContainerSpec
+		source string
+	}
+	restoreReturns struct {
+		result1 garden.Container
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -434,6 +444,41 @@ func (fake *FakeBackend) GraceTimeReturns(result1 time.Duration) {
 	}{result1}
 }
 
+func (fake *FakeBackend) Restore(spec garden.ContainerSpec, source string) (garden.Container, error) {
+	fake.restoreMutex.Lock()
+	fake.restoreArgsForCall = append(fake.restoreArgsForCall, struct {
+		spec   garden.ContainerSpec
+		source string
+	}{spec, source})
+	fake.recordInvocation("Restore", []interface{}{spec, source})
+	fake.restoreMutex.Unlock()
+	if fake.RestoreStub != nil {
+		return fake.RestoreStub(spec, source)
+	} else {
+		return fake.restoreReturns.result1, fake.restoreReturns.result2
+	}
+}
+
+func (fake *FakeBackend) RestoreCallCount() int {
+	fake.restoreMutex.RLock()
+	defer fake.restoreMutex.RUnlock()
+	return len(fake.restoreArgsForCall)
+}
+
+func (fake *FakeBackend) RestoreArgsForCall(i int) (garden.ContainerSpec, string) {
+	fake.restoreMutex.RLock()
+	defer fake.restoreMutex.RUnlock()
+	return fake.restoreArgsForCall[i].spec, fake.restoreArgsForCall[i].source
+}
+
+func (fake *FakeBackend) RestoreReturns(result1 garden.Container, result2 error) {
+	fake.RestoreStub = nil
+	fake.restoreReturns = struct {
+		result1 garden.Container
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeBackend) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -459,6 +504,8 @@ func (fake *FakeBackend) Invocations() map[string][][]interface{} {
 	defer fake.stopMutex.RUnlock()
 	fake.graceTimeMutex.RLock()
 	defer fake.graceTimeMutex.RUnlock()
+	fake.restoreMutex.RLock()
+	defer fake.restoreMutex.RUnlock()
 	return fake.invocations
 }
 
@@ -163,6 +163,205 @@ type FakeContainer struct {
 	removePropertyReturns struct {
 		result1 error
 	}
+	HostResourcesStub        func() (garden.HostResources, error)
+	hostResourcesMutex       sync.RWMutex
+	hostResourcesArgsForCall []struct{}
+	hostResourcesReturns     struct {
+		result1 garden.HostResources
+		result2 error
+	}
+	PauseStub        func() error
+	pauseMutex       sync.RWMutex
+	pauseArgsForCall []struct{}
+	pauseReturns     struct {
+		result1 error
+	}
+	ResumeStub        func() error
+	resumeMutex       sync.RWMutex
+	resumeArgsForCall []struct{}
+	resumeReturns     struct {
+		result1 error
+	}
+	CheckpointStub        func(destination string) error
+	checkpointMutex       sync.RWMutex
+	checkpointArgsForCall []struct {
+		destination string
+	}
+	checkpointReturns struct {
+		result1 error
+	}
+	ProcessesStub        func() ([]garden.ProcessInfo, error)
+	processesMutex       sync.RWMutex
+	processesArgsForCall []struct{}
+	processesReturns     struct {
+		result1 []garden.ProcessInfo
+		result2 error
+	}
+	StopWithTimeoutStub        func(timeout time.Duration) error
+	stopWithTimeoutMutex       sync.RWMutex
+	stopWithTimeoutArgsForCall []struct {
+		timeout time.Duration
+	}
+	stopWithTimeoutReturns struct {
+		result1 error
+	}
+	CurrentLimitsStub        func() (garden.Limits, error)
+	currentLimitsMutex       sync.RWMutex
+	currentLimitsArgsForCall []struct{}
+	currentLimitsReturns     struct {
+		result1 garden.Limits
+		result2 error
+	}
+	LimitAllStub        func(limits garden.Limits) error
+	limitAllMutex       sync.RWMutex
+	limitAllArgsForCall []struct {
+		limits garden.Limits
+	}
+	limitAllReturns struct {
+		result1 error
+	}
+	CurrentPidLimitsStub        func() (garden.PidLimits, error)
+	currentPidLimitsMutex       sync.RWMutex
+	currentPidLimitsArgsForCall []struct{}
+	currentPidLimitsReturns     struct {
+		result1 garden.PidLimits
+		result2 error
+	}
+	LimitPidsStub        func(limits garden.PidLimits) error
+	limitPidsMutex       sync.RWMutex
+	limitPidsArgsForCall []struct {
+		limits garden.PidLimits
+	}
+	limitPidsReturns struct {
+		result1 error
+	}
+	CurrentBlockIOLimitsStub        func() (garden.BlockIOLimits, error)
+	currentBlockIOLimitsMutex       sync.RWMutex
+	currentBlockIOLimitsArgsForCall []struct{}
+	currentBlockIOLimitsReturns     struct {
+		result1 garden.BlockIOLimits
+		result2 error
+	}
+	LimitBlockIOStub        func(limits garden.BlockIOLimits) error
+	limitBlockIOMutex       sync.RWMutex
+	limitBlockIOArgsForCall []struct {
+		limits garden.BlockIOLimits
+	}
+	limitBlockIOReturns struct {
+		result1 error
+	}
+	SetDescriptionStub        func(description string) error
+	setDescriptionMutex       sync.RWMutex
+	setDescriptionArgsForCall []struct {
+		description string
+	}
+	setDescriptionReturns struct {
+		result1 error
+	}
+	LimitCPUStub        func(limits garden.CPULimits) error
+	limitCPUMutex       sync.RWMutex
+	limitCPUArgsForCall []struct {
+		limits garden.CPULimits
+	}
+	limitCPUReturns struct {
+		result1 error
+	}
+	LimitMemoryStub        func(limits garden.MemoryLimits) error
+	limitMemoryMutex       sync.RWMutex
+	limitMemoryArgsForCall []struct {
+		limits garden.MemoryLimits
+	}
+	limitMemoryReturns struct {
+		result1 error
+	}
+	LimitBandwidthStub        func(limits garden.BandwidthLimits) error
+	limitBandwidthMutex       sync.RWMutex
+	limitBandwidthArgsForCall []struct {
+		limits garden.BandwidthLimits
+	}
+	limitBandwidthReturns struct {
+		result1 error
+	}
+	ProcessMetricsStub        func() (map[string]garden.ProcessMetrics, error)
+	processMetricsMutex       sync.RWMutex
+	processMetricsArgsForCall []struct{}
+	processMetricsReturns     struct {
+		result1 map[string]garden.ProcessMetrics
+		result2 error
+	}
+	DiskUsageStub        func(paths []string) ([]garden.PathDiskUsage, error)
+	diskUsageMutex       sync.RWMutex
+	diskUsageArgsForCall []struct {
+		paths []string
+	}
+	diskUsageReturns struct {
+		result1 []garden.PathDiskUsage
+		result2 error
+	}
+	NetOutRulesStub        func() ([]garden.NetOutRule, error)
+	netOutRulesMutex       sync.RWMutex
+	netOutRulesArgsForCall []struct{}
+	netOutRulesReturns     struct {
+		result1 []garden.NetOutRule
+		result2 error
+	}
+	RemoveNetOutStub        func(ruleID string) error
+	removeNetOutMutex       sync.RWMutex
+	removeNetOutArgsForCall []struct {
+		ruleID string
+	}
+	removeNetOutReturns struct {
+		result1 error
+	}
+	RemoveAllNetOutStub        func() error
+	removeAllNetOutMutex       sync.RWMutex
+	removeAllNetOutArgsForCall []struct{}
+	removeAllNetOutReturns     struct {
+		result1 error
+	}
+	BulkNetOutStub        func(netOutRules []garden.NetOutRule) error
+	bulkNetOutMutex       sync.RWMutex
+	bulkNetOutArgsForCall []struct {
+		netOutRules []garden.NetOutRule
+	}
+	bulkNetOutReturns struct {
+		result1 error
+	}
+	NetInMappingsStub        func() ([]garden.PortMapping, error)
+	netInMappingsMutex       sync.RWMutex
+	netInMappingsArgsForCall []struct{}
+	netInMappingsReturns     struct {
+		result1 []garden.PortMapping
+		result2 error
+	}
+	RemoveNetInStub        func(hostPort uint32) error
+	removeNetInMutex       sync.RWMutex
+	removeNetInArgsForCall []struct {
+		hostPort uint32
+	}
+	removeNetInReturns struct {
+		result1 error
+	}
+	NetInWithHostIPStub        func(hostIP string, hostPort uint32, containerPort uint32) (garden.PortMapping, error)
+	netInWithHostIPMutex       sync.RWMutex
+	netInWithHostIPArgsForCall []struct {
+		hostIP        string
+		hostPort      uint32
+		containerPort uint32
+	}
+	netInWithHostIPReturns struct {
+		result1 garden.PortMapping
+		result2 error
+	}
+	CommitStub        func(imageName string) (garden.CachedImage, error)
+	commitMutex       sync.RWMutex
+	commitArgsForCall []struct {
+		imageName string
+	}
+	commitReturns struct {
+		result1 garden.CachedImage
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -747,6 +946,799 @@ func (fake *FakeContainer) RemovePropertyReturns(result1 error) {
 	}{result1}
 }
 
+func (fake *FakeContainer) HostResources() (garden.HostResources, error) {
+	fake.hostResourcesMutex.Lock()
+	fake.hostResourcesArgsForCall = append(fake.hostResourcesArgsForCall, struct{}{})
+	fake.recordInvocation("HostResources", []interface{}{})
+	fake.hostResourcesMutex.Unlock()
+	if fake.HostResourcesStub != nil {
+		return fake.HostResourcesStub()
+	} else {
+		return fake.hostResourcesReturns.result1, fake.hostResourcesReturns.result2
+	}
+}
+
+func (fake *FakeContainer) HostResourcesCallCount() int {
+	fake.hostResourcesMutex.RLock()
+	defer fake.hostResourcesMutex.RUnlock()
+	return len(fake.hostResourcesArgsForCall)
+}
+
+func (fake *FakeContainer) HostResourcesReturns(result1 garden.HostResources, result2 error) {
+	fake.HostResourcesStub = nil
+	fake.hostResourcesReturns = struct {
+		result1 garden.HostResources
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeContainer) Pause() error {
+	fake.pauseMutex.Lock()
+	fake.pauseArgsForCall = append(fake.pauseArgsForCall, struct{}{})
+	fake.recordInvocation("Pause", []interface{}{})
+	fake.pauseMutex.Unlock()
+	if fake.PauseStub != nil {
+		return fake.PauseStub()
+	} else {
+		return fake.pauseReturns.result1
+	}
+}
+
+func (fake *FakeContainer) PauseCallCount() int {
+	fake.pauseMutex.RLock()
+	defer fake.pauseMutex.RUnlock()
+	return len(fake.pauseArgsForCall)
+}
+
+func (fake *FakeContainer) PauseReturns(result1 error) {
+	fake.PauseStub = nil
+	fake.pauseReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeContainer) Resume() error {
+	fake.resumeMutex.Lock()
+	fake.resumeArgsForCall = append(fake.resumeArgsForCall, struct{}{})
+	fake.recordInvocation("Resume", []interface{}{})
+	fake.resumeMutex.Unlock()
+	if fake.ResumeStub != nil {
+		return fake.ResumeStub()
+	} else {
+		return fake.resumeReturns.result1
+	}
+}
+
+func (fake *FakeContainer) ResumeCallCount() int {
+	fake.resumeMutex.RLock()
+	defer fake.resumeMutex.RUnlock()
+	return len(fake.resumeArgsForCall)
+}
+
+func (fake *FakeContainer) ResumeReturns(result1 error) {
+	fake.ResumeStub = nil
+	fake.resumeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeContainer) Checkpoint(destination string) error {
+	fake.checkpointMutex.Lock()
+	fake.checkpointArgsForCall = append(fake.checkpointArgsForCall, struct {
+		destination string
+	}{destination})
+	fake.recordInvocation("Checkpoint", []interface{}{destination})
+	fake.checkpointMutex.Unlock()
+	if fake.CheckpointStub != nil {
+		return fake.CheckpointStub(destination)
+	} else {
+		return fake.checkpointReturns.result1
+	}
+}
+
+func (fake *FakeContainer) CheckpointCallCount() int {
+	fake.checkpointMutex.RLock()
+	defer fake.checkpointMutex.RUnlock()
+	return len(fake.checkpointArgsForCall)
+}
+
+func (fake *FakeContainer) CheckpointArgsForCall(i int) string {
+	fake.checkpointMutex.RLock()
+	defer fake.checkpointMutex.RUnlock()
+	return fake.checkpointArgsForCall[i].destination
+}
+
+func (fake *FakeContainer) CheckpointReturns(result1 error) {
+	fake.CheckpointStub = nil
+	fake.checkpointReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeContainer) Processes() ([]garden.ProcessInfo, error) {
+	fake.processesMutex.Lock()
+	fake.processesArgsForCall = append(fake.processesArgsForCall, struct{}{})
+	fake.recordInvocation("Processes", []interface{}{})
+	fake.processesMutex.Unlock()
+	if fake.ProcessesStub != nil {
+		return fake.ProcessesStub()
+	} else {
+		return fake.processesReturns.result1, fake.processesReturns.result2
+	}
+}
+
+func (fake *FakeContainer) ProcessesCallCount() int {
+	fake.processesMutex.RLock()
+	defer fake.processesMutex.RUnlock()
+	return len(fake.processesArgsForCall)
+}
+
+func (fake *FakeContainer) ProcessesReturns(result1 []garden.ProcessInfo, result2 error) {
+	fake.ProcessesStub = nil
+	fake.processesReturns = struct {
+		result1 []garden.ProcessInfo
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeContainer) StopWithTimeout(timeout time.Duration) error {
+	fake.stopWithTimeoutMutex.Lock()
+	fake.stopWithTimeoutArgsForCall = append(fake.stopWithTimeoutArgsForCall, struct {
+		timeout time.Duration
+	}{timeout})
+	fake.recordInvocation("StopWithTimeout", []interface{}{timeout})
+	fake.stopWithTimeoutMutex.Unlock()
+	if fake.StopWithTimeoutStub != nil {
+		return fake.StopWithTimeoutStub(timeout)
+	} else {
+		return fake.stopWithTimeoutReturns.result1
+	}
+}
+
+func (fake *FakeContainer) StopWithTimeoutCallCount() int {
+	fake.stopWithTimeoutMutex.RLock()
+	defer fake.stopWithTimeoutMutex.RUnlock()
+	return len(fake.stopWithTimeoutArgsForCall)
+}
+
+func (fake *FakeContainer) StopWithTimeoutArgsForCall(i int) time.Duration {
+	fake.stopWithTimeoutMutex.RLock()
+	defer fake.stopWithTimeoutMutex.RUnlock()
+	return fake.stopWithTimeoutArgsForCall[i].timeout
+}
+
+func (fake *FakeContainer) StopWithTimeoutReturns(result1 error) {
+	fake.StopWithTimeoutStub = nil
+	fake.stopWithTimeoutReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeContainer) CurrentLimits() (garden.Limits, error) {
+	fake.currentLimitsMutex.Lock()
+	fake.currentLimitsArgsForCall = append(fake.currentLimitsArgsForCall, struct{}{})
+	fake.recordInvocation("CurrentLimits", []interface{}{})
+	fake.currentLimitsMutex.Unlock()
+	if fake.CurrentLimitsStub != nil {
+		return fake.CurrentLimitsStub()
+	} else {
+		return fake.currentLimitsReturns.result1, fake.currentLimitsReturns.result2
+	}
+}
+
+func (fake *FakeContainer) CurrentLimitsCallCount() int {
+	fake.currentLimitsMutex.RLock()
+	defer fake.currentLimitsMutex.RUnlock()
+	return len(fake.currentLimitsArgsForCall)
+}
+
+func (fake *FakeContainer) CurrentLimitsReturns(result1 garden.Limits, result2 error) {
+	fake.CurrentLimitsStub = nil
+	fake.currentLimitsReturns = struct {
+		result1 garden.Limits
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeContainer) LimitAll(limits garden.Limits) error {
+	fake.limitAllMutex.Lock()
+	fake.limitAllArgsForCall = append(fake.limitAllArgsForCall, struct {
+		limits garden.Limits
+	}{limits})
+	fake.recordInvocation("LimitAll", []interface{}{limits})
+	fake.limitAllMutex.Unlock()
+	if fake.LimitAllStub != nil {
+		return fake.LimitAllStub(limits)
+	} else {
+		return fake.limitAllReturns.result1
+	}
+}
+
+func (fake *FakeContainer) LimitAllCallCount() int {
+	fake.limitAllMutex.RLock()
+	defer fake.limitAllMutex.RUnlock()
+	return len(fake.limitAllArgsForCall)
+}
+
+func (fake *FakeContainer) LimitAllArgsForCall(i int) garden.Limits {
+	fake.limitAllMutex.RLock()
+	defer fake.limitAllMutex.RUnlock()
+	return fake.limitAllArgsForCall[i].limits
+}
+
+func (fake *FakeContainer) LimitAllReturns(result1 error) {
+	fake.LimitAllStub = nil
+	fake.limitAllReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeContainer) CurrentPidLimits() (garden.PidLimits, error) {
+	fake.currentPidLimitsMutex.Lock()
+	fake.currentPidLimitsArgsForCall = append(fake.currentPidLimitsArgsForCall, struct{}{})
+	fake.recordInvocation("CurrentPidLimits", []interface{}{})
+	fake.currentPidLimitsMutex.Unlock()
+	if fake.CurrentPidLimitsStub != nil {
+		return fake.CurrentPidLimitsStub()
+	} else {
+		return fake.currentPidLimitsReturns.result1, fake.currentPidLimitsReturns.result2
+	}
+}
+
+func (fake *FakeContainer) CurrentPidLimitsCallCount() int {
+	fake.currentPidLimitsMutex.RLock()
+	defer fake.currentPidLimitsMutex.RUnlock()
+	return len(fake.currentPidLimitsArgsForCall)
+}
+
+func (fake *FakeContainer) CurrentPidLimitsReturns(result1 garden.PidLimits, result2 error) {
+	fake.CurrentPidLimitsStub = nil
+	fake.currentPidLimitsReturns = struct {
+		result1 garden.PidLimits
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeContainer) LimitPids(limits garden.PidLimits) error {
+	fake.limitPidsMutex.Lock()
+	fake.limitPidsArgsForCall = append(fake.limitPidsArgsForCall, struct {
+		limits garden.PidLimits
+	}{limits})
+	fake.recordInvocation("LimitPids", []interface{}{limits})
+	fake.limitPidsMutex.Unlock()
+	if fake.LimitPidsStub != nil {
+		return fake.LimitPidsStub(limits)
+	} else {
+		return fake.limitPidsReturns.result1
+	}
+}
+
+func (fake *FakeContainer) LimitPidsCallCount() int {
+	fake.limitPidsMutex.RLock()
+	defer fake.limitPidsMutex.RUnlock()
+	return len(fake.limitPidsArgsForCall)
+}
+
+func (fake *FakeContainer) LimitPidsArgsForCall(i int) garden.PidLimits {
+	fake.limitPidsMutex.RLock()
+	defer fake.limitPidsMutex.RUnlock()
+	return fake.limitPidsArgsForCall[i].limits
+}
+
+func (fake *FakeContainer) LimitPidsReturns(result1 error) {
+	fake.LimitPidsStub = nil
+	fake.limitPidsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeContainer) CurrentBlockIOLimits() (garden.BlockIOLimits, error) {
+	fake.currentBlockIOLimitsMutex.Lock()
+	fake.currentBlockIOLimitsArgsForCall = append(fake.currentBlockIOLimitsArgsForCall, struct{}{})
+	fake.recordInvocation("CurrentBlockIOLimits", []interface{}{})
+	fake.currentBlockIOLimitsMutex.Unlock()
+	if fake.CurrentBlockIOLimitsStub != nil {
+		return fake.CurrentBlockIOLimitsStub()
+	} else {
+		return fake.currentBlockIOLimitsReturns.result1, fake.currentBlockIOLimitsReturns.result2
+	}
+}
+
+func (fake *FakeContainer) CurrentBlockIOLimitsCallCount() int {
+	fake.currentBlockIOLimitsMutex.RLock()
+	defer fake.currentBlockIOLimitsMutex.RUnlock()
+	return len(fake.currentBlockIOLimitsArgsForCall)
+}
+
+func (fake *FakeContainer) CurrentBlockIOLimitsReturns(result1 garden.BlockIOLimits, result2 error) {
+	fake.CurrentBlockIOLimitsStub = nil
+	fake.currentBlockIOLimitsReturns = struct {
+		result1 garden.BlockIOLimits
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeContainer) LimitBlockIO(limits garden.BlockIOLimits) error {
+	fake.limitBlockIOMutex.Lock()
+	fake.limitBlockIOArgsForCall = append(fake.limitBlockIOArgsForCall, struct {
+		limits garden.BlockIOLimits
+	}{limits})
+	fake.recordInvocation("LimitBlockIO", []interface{}{limits})
+	fake.limitBlockIOMutex.Unlock()
+	if fake.LimitBlockIOStub != nil {
+		return fake.LimitBlockIOStub(limits)
+	} else {
+		return fake.limitBlockIOReturns.result1
+	}
+}
+
+func (fake *FakeContainer) LimitBlockIOCallCount() int {
+	fake.limitBlockIOMutex.RLock()
+	defer fake.limitBlockIOMutex.RUnlock()
+	return len(fake.limitBlockIOArgsForCall)
+}
+
+func (fake *FakeContainer) LimitBlockIOArgsForCall(i int) garden.BlockIOLimits {
+	fake.limitBlockIOMutex.RLock()
+	defer fake.limitBlockIOMutex.RUnlock()
+	return fake.limitBlockIOArgsForCall[i].limits
+}
+
+func (fake *FakeContainer) LimitBlockIOReturns(result1 error) {
+	fake.LimitBlockIOStub = nil
+	fake.limitBlockIOReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeContainer) SetDescription(description string) error {
+	fake.setDescriptionMutex.Lock()
+	fake.setDescriptionArgsForCall = append(fake.setDescriptionArgsForCall, struct {
+		description string
+	}{description})
+	fake.recordInvocation("SetDescription", []interface{}{description})
+	fake.setDescriptionMutex.Unlock()
+	if fake.SetDescriptionStub != nil {
+		return fake.SetDescriptionStub(description)
+	} else {
+		return fake.setDescriptionReturns.result1
+	}
+}
+
+func (fake *FakeContainer) SetDescriptionCallCount() int {
+	fake.setDescriptionMutex.RLock()
+	defer fake.setDescriptionMutex.RUnlock()
+	return len(fake.setDescriptionArgsForCall)
+}
+
+func (fake *FakeContainer) SetDescriptionArgsForCall(i int) string {
+	fake.setDescriptionMutex.RLock()
+	defer fake.setDescriptionMutex.RUnlock()
+	return fake.setDescriptionArgsForCall[i].description
+}
+
+func (fake *FakeContainer) SetDescriptionReturns(result1 error) {
+	fake.SetDescriptionStub = nil
+	fake.setDescriptionReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeContainer) LimitCPU(limits garden.CPULimits) error {
+	fake.limitCPUMutex.Lock()
+	fake.limitCPUArgsForCall = append(fake.limitCPUArgsForCall, struct {
+		limits garden.CPULimits
+	}{limits})
+	fake.recordInvocation("LimitCPU", []interface{}{limits})
+	fake.limitCPUMutex.Unlock()
+	if fake.LimitCPUStub != nil {
+		return fake.LimitCPUStub(limits)
+	} else {
+		return fake.limitCPUReturns.result1
+	}
+}
+
+func (fake *FakeContainer) LimitCPUCallCount() int {
+	fake.limitCPUMutex.RLock()
+	defer fake.limitCPUMutex.RUnlock()
+	return len(fake.limitCPUArgsForCall)
+}
+
+func (fake *FakeContainer) LimitCPUArgsForCall(i int) garden.CPULimits {
+	fake.limitCPUMutex.RLock()
+	defer fake.limitCPUMutex.RUnlock()
+	return fake.limitCPUArgsForCall[i].limits
+}
+
+func (fake *FakeContainer) LimitCPUReturns(result1 error) {
+	fake.LimitCPUStub = nil
+	fake.limitCPUReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeContainer) LimitMemory(limits garden.MemoryLimits) error {
+	fake.limitMemoryMutex.Lock()
+	fake.limitMemoryArgsForCall = append(fake.limitMemoryArgsForCall, struct {
+		limits garden.MemoryLimits
+	}{limits})
+	fake.recordInvocation("LimitMemory", []interface{}{limits})
+	fake.limitMemoryMutex.Unlock()
+	if fake.LimitMemoryStub != nil {
+		return fake.LimitMemoryStub(limits)
+	} else {
+		return fake.limitMemoryReturns.result1
+	}
+}
+
+func (fake *FakeContainer) LimitMemoryCallCount() int {
+	fake.limitMemoryMutex.RLock()
+	defer fake.limitMemoryMutex.RUnlock()
+	return len(fake.limitMemoryArgsForCall)
+}
+
+func (fake *FakeContainer) LimitMemoryArgsForCall(i int) garden.MemoryLimits {
+	fake.limitMemoryMutex.RLock()
+	defer fake.limitMemoryMutex.RUnlock()
+	return fake.limitMemoryArgsForCall[i].limits
+}
+
+func (fake *FakeContainer) LimitMemoryReturns(result1 error) {
+	fake.LimitMemoryStub = nil
+	fake.limitMemoryReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeContainer) LimitBandwidth(limits garden.BandwidthLimits) error {
+	fake.limitBandwidthMutex.Lock()
+	fake.limitBandwidthArgsForCall = append(fake.limitBandwidthArgsForCall, struct {
+		limits garden.BandwidthLimits
+	}{limits})
+	fake.recordInvocation("LimitBandwidth", []interface{}{limits})
+	fake.limitBandwidthMutex.Unlock()
+	if fake.LimitBandwidthStub != nil {
+		return fake.LimitBandwidthStub(limits)
+	} else {
+		return fake.limitBandwidthReturns.result1
+	}
+}
+
+func (fake *FakeContainer) LimitBandwidthCallCount() int {
+	fake.limitBandwidthMutex.RLock()
+	defer fake.limitBandwidthMutex.RUnlock()
+	return len(fake.limitBandwidthArgsForCall)
+}
+
+func (fake *FakeContainer) LimitBandwidthArgsForCall(i int) garden.BandwidthLimits {
+	fake.limitBandwidthMutex.RLock()
+	defer fake.limitBandwidthMutex.RUnlock()
+	return fake.limitBandwidthArgsForCall[i].limits
+}
+
+func (fake *FakeContainer) LimitBandwidthReturns(result1 error) {
+	fake.LimitBandwidthStub = nil
+	fake.limitBandwidthReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeContainer) ProcessMetrics() (map[string]garden.ProcessMetrics, error) {
+	fake.processMetricsMutex.Lock()
+	fake.processMetricsArgsForCall = append(fake.processMetricsArgsForCall, struct{}{})
+	fake.recordInvocation("ProcessMetrics", []interface{}{})
+	fake.processMetricsMutex.Unlock()
+	if fake.ProcessMetricsStub != nil {
+		return fake.ProcessMetricsStub()
+	} else {
+		return fake.processMetricsReturns.result1, fake.processMetricsReturns.result2
+	}
+}
+
+func (fake *FakeContainer) ProcessMetricsCallCount() int {
+	fake.processMetricsMutex.RLock()
+	defer fake.processMetricsMutex.RUnlock()
+	return len(fake.processMetricsArgsForCall)
+}
+
+func (fake *FakeContainer) ProcessMetricsReturns(result1 map[string]garden.ProcessMetrics, result2 error) {
+	fake.ProcessMetricsStub = nil
+	fake.processMetricsReturns = struct {
+		result1 map[string]garden.ProcessMetrics
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeContainer) DiskUsage(paths []string) ([]garden.PathDiskUsage, error) {
+	var pathsCopy []string
+	if paths != nil {
+		pathsCopy = make([]string, len(paths))
+		copy(pathsCopy, paths)
+	}
+	fake.diskUsageMutex.Lock()
+	fake.diskUsageArgsForCall = append(fake.diskUsageArgsForCall, struct {
+		paths []string
+	}{pathsCopy})
+	fake.recordInvocation("DiskUsage", []interface{}{pathsCopy})
+	fake.diskUsageMutex.Unlock()
+	if fake.DiskUsageStub != nil {
+		return fake.DiskUsageStub(paths)
+	} else {
+		return fake.diskUsageReturns.result1, fake.diskUsageReturns.result2
+	}
+}
+
+func (fake *FakeContainer) DiskUsageCallCount() int {
+	fake.diskUsageMutex.RLock()
+	defer fake.diskUsageMutex.RUnlock()
+	return len(fake.diskUsageArgsForCall)
+}
+
+func (fake *FakeContainer) DiskUsageArgsForCall(i int) []string {
+	fake.diskUsageMutex.RLock()
+	defer fake.diskUsageMutex.RUnlock()
+	return fake.diskUsageArgsForCall[i].paths
+}
+
+func (fake *FakeContainer) DiskUsageReturns(result1 []garden.PathDiskUsage, result2 error) {
+	fake.DiskUsageStub = nil
+	fake.diskUsageReturns = struct {
+		result1 []garden.PathDiskUsage
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeContainer) NetOutRules() ([]garden.NetOutRule, error) {
+	fake.netOutRulesMutex.Lock()
+	fake.netOutRulesArgsForCall = append(fake.netOutRulesArgsForCall, struct{}{})
+	fake.recordInvocation("NetOutRules", []interface{}{})
+	fake.netOutRulesMutex.Unlock()
+	if fake.NetOutRulesStub != nil {
+		return fake.NetOutRulesStub()
+	} else {
+		return fake.netOutRulesReturns.result1, fake.netOutRulesReturns.result2
+	}
+}
+
+func (fake *FakeContainer) NetOutRulesCallCount() int {
+	fake.netOutRulesMutex.RLock()
+	defer fake.netOutRulesMutex.RUnlock()
+	return len(fake.netOutRulesArgsForCall)
+}
+
+func (fake *FakeContainer) NetOutRulesReturns(result1 []garden.NetOutRule, result2 error) {
+	fake.NetOutRulesStub = nil
+	fake.netOutRulesReturns = struct {
+		result1 []garden.NetOutRule
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeContainer) RemoveNetOut(ruleID string) error {
+	fake.removeNetOutMutex.Lock()
+	fake.removeNetOutArgsForCall = append(fake.removeNetOutArgsForCall, struct {
+		ruleID string
+	}{ruleID})
+	fake.recordInvocation("RemoveNetOut", []interface{}{ruleID})
+	fake.removeNetOutMutex.Unlock()
+	if fake.RemoveNetOutStub != nil {
+		return fake.RemoveNetOutStub(ruleID)
+	} else {
+		return fake.removeNetOutReturns.result1
+	}
+}
+
+func (fake *FakeContainer) RemoveNetOutCallCount() int {
+	fake.removeNetOutMutex.RLock()
+	defer fake.removeNetOutMutex.RUnlock()
+	return len(fake.removeNetOutArgsForCall)
+}
+
+func (fake *FakeContainer) RemoveNetOutArgsForCall(i int) string {
+	fake.removeNetOutMutex.RLock()
+	defer fake.removeNetOutMutex.RUnlock()
+	return fake.removeNetOutArgsForCall[i].ruleID
+}
+
+func (fake *FakeContainer) RemoveNetOutReturns(result1 error) {
+	fake.RemoveNetOutStub = nil
+	fake.removeNetOutReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeContainer) RemoveAllNetOut() error {
+	fake.removeAllNetOutMutex.Lock()
+	fake.removeAllNetOutArgsForCall = append(fake.removeAllNetOutArgsForCall, struct{}{})
+	fake.recordInvocation("RemoveAllNetOut", []interface{}{})
+	fake.removeAllNetOutMutex.Unlock()
+	if fake.RemoveAllNetOutStub != nil {
+		return fake.RemoveAllNetOutStub()
+	} else {
+		return fake.removeAllNetOutReturns.result1
+	}
+}
+
+func (fake *FakeContainer) RemoveAllNetOutCallCount() int {
+	fake.removeAllNetOutMutex.RLock()
+	defer fake.removeAllNetOutMutex.RUnlock()
+	return len(fake.removeAllNetOutArgsForCall)
+}
+
+func (fake *FakeContainer) RemoveAllNetOutReturns(result1 error) {
+	fake.RemoveAllNetOutStub = nil
+	fake.removeAllNetOutReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeContainer) BulkNetOut(netOutRules []garden.NetOutRule) error {
+	var netOutRulesCopy []garden.NetOutRule
+	if netOutRules != nil {
+		netOutRulesCopy = make([]garden.NetOutRule, len(netOutRules))
+		copy(netOutRulesCopy, netOutRules)
+	}
+	fake.bulkNetOutMutex.Lock()
+	fake.bulkNetOutArgsForCall = append(fake.bulkNetOutArgsForCall, struct {
+		netOutRules []garden.NetOutRule
+	}{netOutRulesCopy})
+	fake.recordInvocation("BulkNetOut", []interface{}{netOutRulesCopy})
+	fake.bulkNetOutMutex.Unlock()
+	if fake.BulkNetOutStub != nil {
+		return fake.BulkNetOutStub(netOutRules)
+	} else {
+		return fake.bulkNetOutReturns.result1
+	}
+}
+
+func (fake *FakeContainer) BulkNetOutCallCount() int {
+	fake.bulkNetOutMutex.RLock()
+	defer fake.bulkNetOutMutex.RUnlock()
+	return len(fake.bulkNetOutArgsForCall)
+}
+
+func (fake *FakeContainer) BulkNetOutArgsForCall(i int) []garden.NetOutRule {
+	fake.bulkNetOutMutex.RLock()
+	defer fake.bulkNetOutMutex.RUnlock()
+	return fake.bulkNetOutArgsForCall[i].netOutRules
+}
+
+func (fake *FakeContainer) BulkNetOutReturns(result1 error) {
+	fake.BulkNetOutStub = nil
+	fake.bulkNetOutReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeContainer) NetInMappings() ([]garden.PortMapping, error) {
+	fake.netInMappingsMutex.Lock()
+	fake.netInMappingsArgsForCall = append(fake.netInMappingsArgsForCall, struct{}{})
+	fake.recordInvocation("NetInMappings", []interface{}{})
+	fake.netInMappingsMutex.Unlock()
+	if fake.NetInMappingsStub != nil {
+		return fake.NetInMappingsStub()
+	} else {
+		return fake.netInMappingsReturns.result1, fake.netInMappingsReturns.result2
+	}
+}
+
+func (fake *FakeContainer) NetInMappingsCallCount() int {
+	fake.netInMappingsMutex.RLock()
+	defer fake.netInMappingsMutex.RUnlock()
+	return len(fake.netInMappingsArgsForCall)
+}
+
+func (fake *FakeContainer) NetInMappingsReturns(result1 []garden.PortMapping, result2 error) {
+	fake.NetInMappingsStub = nil
+	fake.netInMappingsReturns = struct {
+		result1 []garden.PortMapping
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeContainer) RemoveNetIn(hostPort uint32) error {
+	fake.removeNetInMutex.Lock()
+	fake.removeNetInArgsForCall = append(fake.removeNetInArgsForCall, struct {
+		hostPort uint32
+	}{hostPort})
+	fake.recordInvocation("RemoveNetIn", []interface{}{hostPort})
+	fake.removeNetInMutex.Unlock()
+	if fake.RemoveNetInStub != nil {
+		return fake.RemoveNetInStub(hostPort)
+	} else {
+		return fake.removeNetInReturns.result1
+	}
+}
+
+func (fake *FakeContainer) RemoveNetInCallCount() int {
+	fake.removeNetInMutex.RLock()
+	defer fake.removeNetInMutex.RUnlock()
+	return len(fake.removeNetInArgsForCall)
+}
+
+func (fake *FakeContainer) RemoveNetInArgsForCall(i int) uint32 {
+	fake.removeNetInMutex.RLock()
+	defer fake.removeNetInMutex.RUnlock()
+	return fake.removeNetInArgsForCall[i].hostPort
+}
+
+func (fake *FakeContainer) RemoveNetInReturns(result1 error) {
+	fake.RemoveNetInStub = nil
+	fake.removeNetInReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeContainer) NetInWithHostIP(hostIP string, hostPort uint32, containerPort uint32) (garden.PortMapping, error) {
+	fake.netInWithHostIPMutex.Lock()
+	fake.netInWithHostIPArgsForCall = append(fake.netInWithHostIPArgsForCall, struct {
+		hostIP        string
+		hostPort      uint32
+		containerPort uint32
+	}{hostIP, hostPort, containerPort})
+	fake.recordInvocation("NetInWithHostIP", []interface{}{hostIP, hostPort, containerPort})
+	fake.netInWithHostIPMutex.Unlock()
+	if fake.NetInWithHostIPStub != nil {
+		return fake.NetInWithHostIPStub(hostIP, hostPort, containerPort)
+	} else {
+		return fake.netInWithHostIPReturns.result1, fake.netInWithHostIPReturns.result2
+	}
+}
+
+func (fake *FakeContainer) NetInWithHostIPCallCount() int {
+	fake.netInWithHostIPMutex.RLock()
+	defer fake.netInWithHostIPMutex.RUnlock()
+	return len(fake.netInWithHostIPArgsForCall)
+}
+
+func (fake *FakeContainer) NetInWithHostIPArgsForCall(i int) (string, uint32, uint32) {
+	fake.netInWithHostIPMutex.RLock()
+	defer fake.netInWithHostIPMutex.RUnlock()
+	return fake.netInWithHostIPArgsForCall[i].hostIP, fake.netInWithHostIPArgsForCall[i].hostPort, fake.netInWithHostIPArgsForCall[i].containerPort
+}
+
+func (fake *FakeContainer) NetInWithHostIPReturns(result1 garden.PortMapping, result2 error) {
+	fake.NetInWithHostIPStub = nil
+	fake.netInWithHostIPReturns = struct {
+		result1 garden.PortMapping
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeContainer) Commit(imageName string) (garden.CachedImage, error) {
+	fake.commitMutex.Lock()
+	fake.commitArgsForCall = append(fake.commitArgsForCall, struct {
+		imageName string
+	}{imageName})
+	fake.recordInvocation("Commit", []interface{}{imageName})
+	fake.commitMutex.Unlock()
+	if fake.CommitStub != nil {
+		return fake.CommitStub(imageName)
+	} else {
+		return fake.commitReturns.result1, fake.commitReturns.result2
+	}
+}
+
+func (fake *FakeContainer) CommitCallCount() int {
+	fake.commitMutex.RLock()
+	defer fake.commitMutex.RUnlock()
+	return len(fake.commitArgsForCall)
+}
+
+func (fake *FakeContainer) CommitArgsForCall(i int) string {
+	fake.commitMutex.RLock()
+	defer fake.commitMutex.RUnlock()
+	return fake.commitArgsForCall[i].imageName
+}
+
+func (fake *FakeContainer) CommitReturns(result1 garden.CachedImage, result2 error) {
+	fake.CommitStub = nil
+	fake.commitReturns = struct {
+		result1 garden.CachedImage
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeContainer) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -788,6 +1780,58 @@ func (fake *FakeContainer) Invocations() map[string][][]interface{} {
 	defer fake.setPropertyMutex.RUnlock()
 	fake.removePropertyMutex.RLock()
 	defer fake.removePropertyMutex.RUnlock()
+	fake.hostResourcesMutex.RLock()
+	defer fake.hostResourcesMutex.RUnlock()
+	fake.pauseMutex.RLock()
+	defer fake.pauseMutex.RUnlock()
+	fake.resumeMutex.RLock()
+	defer fake.resumeMutex.RUnlock()
+	fake.checkpointMutex.RLock()
+	defer fake.checkpointMutex.RUnlock()
+	fake.processesMutex.RLock()
+	defer fake.processesMutex.RUnlock()
+	fake.stopWithTimeoutMutex.RLock()
+	defer fake.stopWithTimeoutMutex.RUnlock()
+	fake.currentLimitsMutex.RLock()
+	defer fake.currentLimitsMutex.RUnlock()
+	fake.limitAllMutex.RLock()
+	defer fake.limitAllMutex.RUnlock()
+	fake.currentPidLimitsMutex.RLock()
+	defer fake.currentPidLimitsMutex.RUnlock()
+	fake.limitPidsMutex.RLock()
+	defer fake.limitPidsMutex.RUnlock()
+	fake.currentBlockIOLimitsMutex.RLock()
+	defer fake.currentBlockIOLimitsMutex.RUnlock()
+	fake.limitBlockIOMutex.RLock()
+	defer fake.limitBlockIOMutex.RUnlock()
+	fake.setDescriptionMutex.RLock()
+	defer fake.setDescriptionMutex.RUnlock()
+	fake.limitCPUMutex.RLock()
+	defer fake.limitCPUMutex.RUnlock()
+	fake.limitMemoryMutex.RLock()
+	defer fake.limitMemoryMutex.RUnlock()
+	fake.limitBandwidthMutex.RLock()
+	defer fake.limitBandwidthMutex.RUnlock()
+	fake.processMetricsMutex.RLock()
+	defer fake.processMetricsMutex.RUnlock()
+	fake.diskUsageMutex.RLock()
+	defer fake.diskUsageMutex.RUnlock()
+	fake.netOutRulesMutex.RLock()
+	defer fake.netOutRulesMutex.RUnlock()
+	fake.removeNetOutMutex.RLock()
+	defer fake.removeNetOutMutex.RUnlock()
+	fake.removeAllNetOutMutex.RLock()
+	defer fake.removeAllNetOutMutex.RUnlock()
+	fake.bulkNetOutMutex.RLock()
+	defer fake.bulkNetOutMutex.RUnlock()
+	fake.netInMappingsMutex.RLock()
+	defer fake.netInMappingsMutex.RUnlock()
+	fake.removeNetInMutex.RLock()
+	defer fake.removeNetInMutex.RUnlock()
+	fake.netInWithHostIPMutex.RLock()
+	defer fake.netInWithHostIPMutex.RUnlock()
+	fake.commitMutex.RLock()
+	defer fake.commitMutex.RUnlock()
 	return fake.invocations
 }
 
@@ -37,6 +37,12 @@ type FakeProcess struct {
 	signalReturns struct {
 		result1 error
 	}
+	CloseStdinStub        func() error
+	closeStdinMutex       sync.RWMutex
+	closeStdinArgsForCall []struct{}
+	closeStdinReturns     struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -158,6 +164,31 @@ func (fake *FakeProcess) SignalReturns(result1 error) {
 	}{result1}
 }
 
+func (fake *FakeProcess) CloseStdin() error {
+	fake.closeStdinMutex.Lock()
+	fake.closeStdinArgsForCall = append(fake.closeStdinArgsForCall, struct{}{})
+	fake.recordInvocation("CloseStdin", []interface{}{})
+	fake.closeStdinMutex.Unlock()
+	if fake.CloseStdinStub != nil {
+		return fake.CloseStdinStub()
+	} else {
+		return fake.closeStdinReturns.result1
+	}
+}
+
+func (fake *FakeProcess) CloseStdinCallCount() int {
+	fake.closeStdinMutex.RLock()
+	defer fake.closeStdinMutex.RUnlock()
+	return len(fake.closeStdinArgsForCall)
+}
+
+func (fake *FakeProcess) CloseStdinReturns(result1 error) {
+	fake.CloseStdinStub = nil
+	fake.closeStdinReturns = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeProcess) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -169,6 +200,8 @@ func (fake *FakeProcess) Invocations() map[string][][]interface{} {
 	defer fake.setTTYMutex.RUnlock()
 	fake.signalMutex.RLock()
 	defer fake.signalMutex.RUnlock()
+	fake.closeStdinMutex.RLock()
+	defer fake.closeStdinMutex.RUnlock()
 	return fake.invocations
 }
 
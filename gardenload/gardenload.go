@@ -0,0 +1,235 @@
+// Package gardenload drives create, run, stream and destroy workloads against
+// a garden server and measures how long each operation takes and how often it
+// fails, e.g. to qualify a cell before it takes traffic or to catch
+// performance regressions under a realistic load.
+package gardenload
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/lager"
+)
+
+// The operations measured in a Report.
+const (
+	OperationCreate    = "create"
+	OperationRun       = "run"
+	OperationStreamOut = "stream-out"
+	OperationDestroy   = "destroy"
+)
+
+// DefaultHandlePrefix prefixes the handles of containers created by profiles
+// which do not give a prefix.
+const DefaultHandlePrefix = "gardenload-"
+
+// Profile describes a workload. Each iteration creates a container from Spec,
+// optionally runs Process in it to completion and streams StreamOutPath out
+// of it, and then destroys it.
+type Profile struct {
+	// Workers is how many iterations run at once. It defaults to 1.
+	Workers int
+
+	// Iterations is how many iterations to run in total, or zero to run until
+	// Duration elapses.
+	Iterations int
+
+	// Duration is how long to start iterations for, or zero to run until
+	// Iterations have run. A run with neither runs until its context is done.
+	Duration time.Duration
+
+	// Spec is the spec of the containers created. Its handle is replaced with
+	// one made from HandlePrefix, the worker and the iteration.
+	Spec         garden.ContainerSpec
+	HandlePrefix string
+
+	// Process, if given, is run in each container and waited for.
+	Process *garden.ProcessSpec
+
+	// StreamOutPath, if given, is streamed out of each container and
+	// discarded.
+	StreamOutPath string
+}
+
+// Report is the result of a run.
+type Report struct {
+	// Iterations is how many iterations were run.
+	Iterations int
+
+	// Elapsed is how long the run took.
+	Elapsed time.Duration
+
+	// Operations holds the stats of each operation, by the names above.
+	Operations map[string]*Stats
+}
+
+// Runner runs a profile against a server.
+type Runner struct {
+	client  garden.Client
+	profile Profile
+	logger  lager.Logger
+
+	mu     sync.Mutex
+	report Report
+}
+
+// New returns a runner of the profile against the server the client talks
+// to.
+func New(client garden.Client, profile Profile, logger lager.Logger) *Runner {
+	if profile.Workers <= 0 {
+		profile.Workers = 1
+	}
+
+	if profile.HandlePrefix == "" {
+		profile.HandlePrefix = DefaultHandlePrefix
+	}
+
+	return &Runner{
+		client:  client,
+		profile: profile,
+		logger:  logger.Session("gardenload"),
+	}
+}
+
+// Run runs the profile until it completes or ctx is done, and reports on the
+// iterations run. Iterations under way when ctx is done still destroy their
+// containers.
+func (r *Runner) Run(ctx context.Context) Report {
+	if r.profile.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.profile.Duration)
+		defer cancel()
+	}
+
+	r.report = Report{Operations: map[string]*Stats{}}
+	for _, op := range []string{OperationCreate, OperationRun, OperationStreamOut, OperationDestroy} {
+		r.report.Operations[op] = &Stats{}
+	}
+
+	iterations := make(chan int)
+	go func() {
+		defer close(iterations)
+
+		for i := 0; r.profile.Iterations == 0 || i < r.profile.Iterations; i++ {
+			select {
+			case iterations <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	started := time.Now()
+
+	wg := new(sync.WaitGroup)
+	for worker := 0; worker < r.profile.Workers; worker++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+
+			for i := range iterations {
+				r.iterate(worker, i)
+			}
+		}(worker)
+	}
+
+	wg.Wait()
+
+	r.report.Elapsed = time.Since(started)
+	for _, stats := range r.report.Operations {
+		stats.sort()
+	}
+
+	return r.report
+}
+
+func (r *Runner) iterate(worker, i int) {
+	spec := r.profile.Spec
+	spec.Handle = fmt.Sprintf("%s%d-%d", r.profile.HandlePrefix, worker, i)
+
+	var container garden.Container
+	err := r.measure(OperationCreate, func() error {
+		var err error
+		container, err = r.client.Create(spec)
+		return err
+	})
+
+	if err == nil {
+		if r.profile.Process != nil {
+			r.measure(OperationRun, func() error {
+				return runToCompletion(container, *r.profile.Process)
+			})
+		}
+
+		if r.profile.StreamOutPath != "" {
+			r.measure(OperationStreamOut, func() error {
+				return streamOut(container, r.profile.StreamOutPath)
+			})
+		}
+
+		r.measure(OperationDestroy, func() error {
+			return r.client.Destroy(spec.Handle)
+		})
+	}
+
+	r.mu.Lock()
+	r.report.Iterations++
+	r.mu.Unlock()
+}
+
+func (r *Runner) measure(op string, call func() error) error {
+	started := time.Now()
+	err := call()
+	latency := time.Since(started)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats := r.report.Operations[op]
+	if err != nil {
+		stats.Errors++
+		r.logger.Debug("failed", lager.Data{"operation": op, "error": err.Error()})
+	} else {
+		stats.Latencies = append(stats.Latencies, latency)
+	}
+
+	return err
+}
+
+func runToCompletion(container garden.Container, spec garden.ProcessSpec) error {
+	process, err := container.Run(spec, garden.ProcessIO{
+		Stdout: ioutil.Discard,
+		Stderr: ioutil.Discard,
+	})
+	if err != nil {
+		return err
+	}
+
+	status, err := process.Wait()
+	if err != nil {
+		return err
+	}
+
+	if status != 0 {
+		return fmt.Errorf("process exited with status %d", status)
+	}
+
+	return nil
+}
+
+func streamOut(container garden.Container, path string) error {
+	stream, err := container.StreamOut(garden.StreamOutSpec{Path: path})
+	if err != nil {
+		return err
+	}
+
+	defer stream.Close()
+
+	_, err = io.Copy(ioutil.Discard, stream)
+	return err
+}
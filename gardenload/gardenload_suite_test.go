@@ -0,0 +1,13 @@
+package gardenload_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestGardenload(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Gardenload Suite")
+}
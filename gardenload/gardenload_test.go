@@ -0,0 +1,173 @@
+package gardenload_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/garden/gardenfakes"
+	"code.cloudfoundry.org/garden/gardenload"
+	"code.cloudfoundry.org/lager/lagertest"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Runner", func() {
+	var (
+		fakeClient    *gardenfakes.FakeClient
+		fakeContainer *gardenfakes.FakeContainer
+		fakeProcess   *gardenfakes.FakeProcess
+		profile       gardenload.Profile
+	)
+
+	BeforeEach(func() {
+		fakeProcess = new(gardenfakes.FakeProcess)
+
+		fakeContainer = new(gardenfakes.FakeContainer)
+		fakeContainer.RunReturns(fakeProcess, nil)
+		fakeContainer.StreamOutStub = func(garden.StreamOutSpec) (io.ReadCloser, error) {
+			return ioutil.NopCloser(strings.NewReader("some-logs")), nil
+		}
+
+		fakeClient = new(gardenfakes.FakeClient)
+		fakeClient.CreateReturns(fakeContainer, nil)
+
+		profile = gardenload.Profile{
+			Workers:       3,
+			Iterations:    10,
+			Spec:          garden.ContainerSpec{RootFSPath: "docker:///busybox"},
+			Process:       &garden.ProcessSpec{Path: "true"},
+			StreamOutPath: "/var/log",
+		}
+	})
+
+	run := func() gardenload.Report {
+		return gardenload.New(fakeClient, profile, lagertest.NewTestLogger("test")).Run(context.Background())
+	}
+
+	It("runs each iteration of the workload", func() {
+		report := run()
+
+		Expect(report.Iterations).To(Equal(10))
+		Expect(fakeClient.CreateCallCount()).To(Equal(10))
+		Expect(fakeContainer.RunCallCount()).To(Equal(10))
+		Expect(fakeContainer.StreamOutCallCount()).To(Equal(10))
+		Expect(fakeClient.DestroyCallCount()).To(Equal(10))
+
+		Expect(fakeClient.CreateArgsForCall(0).RootFSPath).To(Equal("docker:///busybox"))
+		Expect(fakeContainer.StreamOutArgsForCall(0).Path).To(Equal("/var/log"))
+	})
+
+	It("creates and destroys containers with distinct handles", func() {
+		run()
+
+		handles := map[string]bool{}
+		for i := 0; i < fakeClient.CreateCallCount(); i++ {
+			handle := fakeClient.CreateArgsForCall(i).Handle
+			Expect(handle).To(HavePrefix(gardenload.DefaultHandlePrefix))
+			handles[handle] = true
+		}
+
+		Expect(handles).To(HaveLen(10))
+		for i := 0; i < fakeClient.DestroyCallCount(); i++ {
+			Expect(handles).To(HaveKey(fakeClient.DestroyArgsForCall(i)))
+		}
+	})
+
+	It("measures the latency of each operation", func() {
+		fakeClient.CreateStub = func(garden.ContainerSpec) (garden.Container, error) {
+			time.Sleep(10 * time.Millisecond)
+			return fakeContainer, nil
+		}
+
+		report := run()
+
+		create := report.Operations[gardenload.OperationCreate]
+		Expect(create.Count()).To(Equal(10))
+		Expect(create.ErrorRate()).To(BeZero())
+		Expect(create.Percentile(50)).To(BeNumerically(">=", 10*time.Millisecond))
+		Expect(create.Mean()).To(BeNumerically(">=", 10*time.Millisecond))
+
+		Expect(report.Operations[gardenload.OperationDestroy].Count()).To(Equal(10))
+	})
+
+	Context("when an operation fails", func() {
+		BeforeEach(func() {
+			fakeProcess.WaitReturns(1, nil)
+			fakeClient.DestroyReturns(errors.New("oh no!"))
+		})
+
+		It("counts the errors and carries on", func() {
+			report := run()
+
+			Expect(report.Iterations).To(Equal(10))
+			Expect(report.Operations[gardenload.OperationRun].ErrorRate()).To(Equal(1.0))
+			Expect(report.Operations[gardenload.OperationStreamOut].ErrorRate()).To(BeZero())
+			Expect(report.Operations[gardenload.OperationDestroy].Errors).To(Equal(10))
+		})
+	})
+
+	Context("when creating fails", func() {
+		BeforeEach(func() {
+			fakeClient.CreateReturns(nil, errors.New("oh no!"))
+		})
+
+		It("does not go on to use the container", func() {
+			report := run()
+
+			Expect(report.Operations[gardenload.OperationCreate].Errors).To(Equal(10))
+			Expect(fakeContainer.RunCallCount()).To(BeZero())
+			Expect(fakeClient.DestroyCallCount()).To(BeZero())
+		})
+	})
+
+	Context("when running for a duration", func() {
+		BeforeEach(func() {
+			profile.Iterations = 0
+			profile.Duration = 200 * time.Millisecond
+			fakeClient.CreateStub = func(garden.ContainerSpec) (garden.Container, error) {
+				time.Sleep(10 * time.Millisecond)
+				return fakeContainer, nil
+			}
+		})
+
+		It("starts iterations until the duration elapses", func() {
+			report := run()
+
+			Expect(report.Iterations).To(BeNumerically(">", 0))
+			Expect(report.Elapsed).To(BeNumerically(">=", 200*time.Millisecond))
+			Expect(report.Elapsed).To(BeNumerically("<", time.Second))
+			Expect(fakeClient.DestroyCallCount()).To(Equal(report.Iterations))
+		})
+	})
+})
+
+var _ = Describe("Stats", func() {
+	stats := &gardenload.Stats{
+		Latencies: []time.Duration{1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+		Errors:    5,
+	}
+
+	It("reports percentiles of the latencies", func() {
+		Expect(stats.Percentile(50)).To(Equal(time.Duration(5)))
+		Expect(stats.Percentile(90)).To(Equal(time.Duration(9)))
+		Expect(stats.Percentile(100)).To(Equal(time.Duration(10)))
+		Expect(stats.Percentile(0)).To(Equal(time.Duration(1)))
+	})
+
+	It("reports the error rate", func() {
+		Expect(stats.Count()).To(Equal(15))
+		Expect(stats.ErrorRate()).To(BeNumerically("~", 1.0/3, 0.001))
+	})
+
+	It("reports zero for no calls", func() {
+		empty := &gardenload.Stats{}
+		Expect(empty.Percentile(99)).To(BeZero())
+		Expect(empty.Mean()).To(BeZero())
+		Expect(empty.ErrorRate()).To(BeZero())
+	})
+})
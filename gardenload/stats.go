@@ -0,0 +1,68 @@
+package gardenload
+
+import (
+	"sort"
+	"time"
+)
+
+// Stats describes the calls made for one kind of operation during a run.
+type Stats struct {
+	// Latencies of the calls which succeeded, in ascending order.
+	Latencies []time.Duration
+
+	// Errors is how many calls failed.
+	Errors int
+}
+
+// Count returns how many calls were made.
+func (s *Stats) Count() int {
+	return len(s.Latencies) + s.Errors
+}
+
+// ErrorRate returns the fraction of calls which failed, or zero if none
+// were made.
+func (s *Stats) ErrorRate() float64 {
+	if s.Count() == 0 {
+		return 0
+	}
+
+	return float64(s.Errors) / float64(s.Count())
+}
+
+// Percentile returns the latency below which the given percentage, from 0
+// to 100, of successful calls completed, or zero if none succeeded.
+func (s *Stats) Percentile(p float64) time.Duration {
+	if len(s.Latencies) == 0 {
+		return 0
+	}
+
+	i := int(p/100*float64(len(s.Latencies)) + 0.5)
+	if i > 0 {
+		i--
+	}
+
+	if i >= len(s.Latencies) {
+		i = len(s.Latencies) - 1
+	}
+
+	return s.Latencies[i]
+}
+
+// Mean returns the mean latency of successful calls, or zero if none
+// succeeded.
+func (s *Stats) Mean() time.Duration {
+	if len(s.Latencies) == 0 {
+		return 0
+	}
+
+	var total time.Duration
+	for _, latency := range s.Latencies {
+		total += latency
+	}
+
+	return total / time.Duration(len(s.Latencies))
+}
+
+func (s *Stats) sort() {
+	sort.Slice(s.Latencies, func(i, j int) bool { return s.Latencies[i] < s.Latencies[j] })
+}
@@ -0,0 +1,31 @@
+package garden
+
+import "strings"
+
+// ImageRef refers to an OCI image from which a container's root file system
+// is created, as an alternative to a RootFSPath.
+type ImageRef struct {
+	// Reference is an OCI image reference: an optional registry host,
+	// followed by a repository, an optional ":tag" and an optional
+	// "@digest", e.g. "registry.example.com/team/app:1.2" or
+	// "busybox@sha256:...". A reference with a digest is pinned: the
+	// backend fails to create the container unless the image it fetches has
+	// that digest.
+	Reference string `json:"reference"`
+}
+
+// Digest returns the digest the reference is pinned to, e.g.
+// "sha256:...", or the empty string if it is not pinned.
+func (r ImageRef) Digest() string {
+	i := strings.LastIndex(r.Reference, "@")
+	if i < 0 {
+		return ""
+	}
+
+	return r.Reference[i+1:]
+}
+
+// Pinned reports whether the reference is pinned to a digest.
+func (r ImageRef) Pinned() bool {
+	return r.Digest() != ""
+}
@@ -0,0 +1,23 @@
+package garden_test
+
+import (
+	"code.cloudfoundry.org/garden"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ImageRef", func() {
+	It("returns the digest of a pinned reference", func() {
+		ref := garden.ImageRef{Reference: "registry.example.com:5000/team/app:1.2@sha256:" + sha256Hex}
+		Ω(ref.Pinned()).Should(BeTrue())
+		Ω(ref.Digest()).Should(Equal("sha256:" + sha256Hex))
+	})
+
+	It("returns no digest for an unpinned reference", func() {
+		ref := garden.ImageRef{Reference: "registry.example.com:5000/team/app:1.2"}
+		Ω(ref.Pinned()).Should(BeFalse())
+		Ω(ref.Digest()).Should(BeEmpty())
+	})
+})
+
+const sha256Hex = "6d8f1b2e4c3a5b7d9e0f1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d7e8f9a0b1c2d"
@@ -3,16 +3,19 @@ package garden
 import "net"
 
 type NetOutRule struct {
+	// identifies the rule within its container; assigned by the server if empty
+	ID string `json:"id,omitempty"`
+
 	// the protocol to be whitelisted
 	Protocol Protocol `json:"protocol,omitempty"`
 
-	// a list of ranges of IP addresses to whitelist; Start to End inclusive; default all
+	// a list of ranges of IPv4 or IPv6 addresses to whitelist; Start to End inclusive; default all
 	Networks []IPRange `json:"networks,omitempty"`
 
-	// a list of ranges of ports to whitelist; Start to End inclusive; ignored if Protocol is ICMP; default all
+	// a list of ranges of ports to whitelist; Start to End inclusive; ignored if Protocol is ICMP or ICMPv6; default all
 	Ports []PortRange `json:"ports,omitempty"`
 
-	// specifying which ICMP codes to whitelist; ignored if Protocol is not ICMP; default all
+	// specifying which ICMP codes to whitelist; ignored if Protocol is not ICMP or ICMPv6; default all
 	ICMPs *ICMPControl `json:"icmps,omitempty"`
 
 	// if true, logging is enabled; ignored if Protocol is not TCP or All; default false
@@ -26,6 +29,7 @@ const (
 	ProtocolTCP
 	ProtocolUDP
 	ProtocolICMP
+	ProtocolICMPv6
 )
 
 type IPRange struct {
@@ -33,6 +37,17 @@ type IPRange struct {
 	End   net.IP `json:"end,omitempty"`
 }
 
+// IPv6 reports whether the range holds IPv6 addresses, judged by whichever of
+// its bounds is set.
+func (r IPRange) IPv6() bool {
+	ip := r.Start
+	if ip == nil {
+		ip = r.End
+	}
+
+	return ip != nil && ip.To4() == nil
+}
+
 type PortRange struct {
 	Start uint16 `json:"start,omitempty"`
 	End   uint16 `json:"end,omitempty"`
@@ -71,6 +86,10 @@ func ICMPControlCode(code uint8) *ICMPCode {
 func lastIP(n *net.IPNet) net.IP {
 	mask := n.Mask
 	ip := n.IP
+	if len(mask) == net.IPv4len {
+		// an IPv4 network's address may be held in its 16 byte form
+		ip = ip.To4()
+	}
 	lastip := make(net.IP, len(ip))
 	// set bits zero in the mask to ones in ip
 	for i, m := range mask {
@@ -26,6 +26,29 @@ var _ = Describe("NetOutRule helper functions", func() {
 			Ω(r.Start.String()).Should(Equal(ip.String()))
 			Ω(r.End.String()).Should(Equal("1.2.3.255"))
 		})
+
+		It("Works for IPv6 networks", func() {
+			_, cidr, err := net.ParseCIDR("2001:db8::/64")
+			Ω(err).Should(Succeed())
+
+			r := garden.IPRangeFromIPNet(cidr)
+			Ω(r.Start.String()).Should(Equal("2001:db8::"))
+			Ω(r.End.String()).Should(Equal("2001:db8::ffff:ffff:ffff:ffff"))
+		})
+
+		It("Works for IPv4 networks whose address is in its 16 byte form", func() {
+			r := garden.IPRangeFromIPNet(&net.IPNet{IP: net.ParseIP("1.2.3.0"), Mask: net.CIDRMask(24, 32)})
+			Ω(r.End.String()).Should(Equal("1.2.3.255"))
+		})
+	})
+
+	Describe("IPRange.IPv6", func() {
+		It("reports whether the range holds IPv6 addresses", func() {
+			Ω(garden.IPRangeFromIP(net.ParseIP("2001:db8::1")).IPv6()).Should(BeTrue())
+			Ω(garden.IPRangeFromIP(net.ParseIP("1.2.3.4")).IPv6()).Should(BeFalse())
+			Ω(garden.IPRange{End: net.ParseIP("::1")}.IPv6()).Should(BeTrue())
+			Ω(garden.IPRange{}.IPv6()).Should(BeFalse())
+		})
 	})
 
 	Describe("PortRangeFromPort", func() {
@@ -0,0 +1,161 @@
+package garden
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// RedactedValue replaces the value of any environment variable or property
+// whose name matches a redaction pattern, and any sensitive text found in a
+// message.
+const RedactedValue = "[REDACTED]"
+
+// DefaultRedactedEnvPatterns match the names of environment variables which
+// commonly hold credentials.
+var DefaultRedactedEnvPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)passw(or)?d`),
+	regexp.MustCompile(`(?i)secret`),
+	regexp.MustCompile(`(?i)token`),
+	regexp.MustCompile(`(?i)credential`),
+	regexp.MustCompile(`(?i)(api|access|private)_?key`),
+}
+
+// DefaultRedactedValuePatterns match text which commonly holds credentials
+// wherever it appears, such as HTTP authorization schemes and the userinfo of
+// URLs.
+var DefaultRedactedValuePatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)\b(bearer|basic)\s+[A-Za-z0-9._~+/=-]+`),
+	regexp.MustCompile(`[A-Za-z][A-Za-z0-9+.-]*://[^/\s@]+@`),
+}
+
+// Redactor removes sensitive values from data before it is logged or returned
+// in an error.
+type Redactor struct {
+	// EnvPatterns match the names of environment variables whose values are
+	// redacted.
+	EnvPatterns []*regexp.Regexp
+
+	// PropertyPatterns match the names of properties whose values are
+	// redacted.
+	PropertyPatterns []*regexp.Regexp
+
+	// ValuePatterns match sensitive text which is redacted wherever it
+	// appears in a message.
+	ValuePatterns []*regexp.Regexp
+}
+
+// DefaultRedactor redacts environment variables and properties with names
+// matching DefaultRedactedEnvPatterns, and text in messages matching
+// DefaultRedactedValuePatterns.
+func DefaultRedactor() Redactor {
+	return Redactor{
+		EnvPatterns:      DefaultRedactedEnvPatterns,
+		PropertyPatterns: DefaultRedactedEnvPatterns,
+		ValuePatterns:    DefaultRedactedValuePatterns,
+	}
+}
+
+// Env returns a copy of env with the values of matching variables redacted,
+// as RedactEnv does.
+func (r Redactor) Env(env []string) []string {
+	return RedactEnv(env, r.EnvPatterns)
+}
+
+// Properties returns a copy of properties with the values of those whose
+// names match the redactor's PropertyPatterns replaced by RedactedValue.
+func (r Redactor) Properties(properties Properties) Properties {
+	if properties == nil {
+		return nil
+	}
+
+	redacted := make(Properties, len(properties))
+	for name, value := range properties {
+		redacted[name] = value
+
+		for _, pattern := range r.PropertyPatterns {
+			if pattern.MatchString(name) {
+				redacted[name] = RedactedValue
+				break
+			}
+		}
+	}
+
+	return redacted
+}
+
+// Message returns the message with all text matching the redactor's
+// ValuePatterns replaced by RedactedValue.
+func (r Redactor) Message(message string) string {
+	for _, pattern := range r.ValuePatterns {
+		message = pattern.ReplaceAllLiteralString(message, RedactedValue)
+	}
+
+	return message
+}
+
+// Error returns the error with its message redacted as Message does. Errors
+// of the garden error types keep their type, so that they are still reported
+// with the right status, with only their free-form cause redacted.
+func (r Redactor) Error(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	switch e := err.(type) {
+	case UnrecoverableError:
+		e.Symptom = r.Message(e.Symptom)
+		return e
+	case ServiceUnavailableError:
+		e.Cause = r.Message(e.Cause)
+		return e
+	case TooManyRequestsError:
+		e.Cause = r.Message(e.Cause)
+		return e
+	case BackendTimeoutError:
+		e.Cause = r.Message(e.Cause)
+		return e
+	case PermissionDeniedError:
+		e.Cause = r.Message(e.Cause)
+		return e
+	case NetworkSetupError:
+		e.Cause = r.Message(e.Cause)
+		return e
+	case MalformedRequestError:
+		e.Cause = r.Message(e.Cause)
+		return e
+	case ContainerNotFoundError, ProcessNotFoundError, HandleTakenError, IPTakenError, ImageNotFoundError, ProcessNameTakenError, PropertyConflictError, PayloadTooLargeError, PortUnavailableError:
+		return e
+	}
+
+	message := err.Error()
+	if redacted := r.Message(message); redacted != message {
+		return errors.New(redacted)
+	}
+
+	return err
+}
+
+// RedactEnv returns a copy of env, given as KEY=VALUE pairs, with the value of
+// every variable whose name matches one of the patterns replaced by
+// RedactedValue. It is intended for preparing environments to be logged.
+func RedactEnv(env []string, patterns []*regexp.Regexp) []string {
+	if env == nil {
+		return nil
+	}
+
+	redacted := make([]string, len(env))
+	for i, pair := range env {
+		redacted[i] = pair
+
+		name := strings.SplitN(pair, "=", 2)[0]
+		for _, pattern := range patterns {
+			if pattern.MatchString(name) {
+				redacted[i] = name + "=" + RedactedValue
+				break
+			}
+		}
+	}
+
+	return redacted
+}
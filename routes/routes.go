@@ -6,14 +6,23 @@ const (
 	Ping     = "Ping"
 	Capacity = "Capacity"
 
-	List        = "List"
-	Create      = "Create"
-	Info        = "Info"
-	BulkInfo    = "BulkInfo"
-	BulkMetrics = "BulkMetrics"
-	Destroy     = "Destroy"
+	List             = "List"
+	Create           = "Create"
+	BulkCreate       = "BulkCreate"
+	Restore          = "Restore"
+	Info             = "Info"
+	BulkInfo         = "BulkInfo"
+	BulkMetrics      = "BulkMetrics"
+	BulkMetricsDelta = "BulkMetricsDelta"
+	Destroy          = "Destroy"
+	BulkDestroy      = "BulkDestroy"
 
-	Stop = "Stop"
+	Stop       = "Stop"
+	BulkStop   = "BulkStop"
+	Pause      = "Pause"
+	Resume     = "Resume"
+	Checkpoint = "Checkpoint"
+	Commit     = "Commit"
 
 	StreamIn  = "StreamIn"
 	StreamOut = "StreamOut"
@@ -22,25 +31,134 @@ const (
 	Stderr = "Stderr"
 
 	CurrentBandwidthLimits = "CurrentBandwidthLimits"
+	LimitBandwidth         = "LimitBandwidth"
 	CurrentCPULimits       = "CurrentCPULimits"
+	LimitCPU               = "LimitCPU"
 	CurrentDiskLimits      = "CurrentDiskLimits"
 	CurrentMemoryLimits    = "CurrentMemoryLimits"
+	LimitMemory            = "LimitMemory"
+	CurrentPidLimits       = "CurrentPidLimits"
+	LimitPids              = "LimitPids"
+	CurrentBlockIOLimits   = "CurrentBlockIOLimits"
+	LimitBlockIO           = "LimitBlockIO"
+	CurrentLimits          = "CurrentLimits"
+	LimitAll               = "LimitAll"
 
-	NetIn  = "NetIn"
-	NetOut = "NetOut"
+	NetIn       = "NetIn"
+	ListNetIn   = "ListNetIn"
+	RemoveNetIn = "RemoveNetIn"
+	NetOut      = "NetOut"
+	BulkNetOut  = "BulkNetOut"
+	ListNetOut  = "ListNetOut"
 
-	Run    = "Run"
-	Attach = "Attach"
+	RemoveNetOut    = "RemoveNetOut"
+	RemoveAllNetOut = "RemoveAllNetOut"
 
-	SetGraceTime = "SetGraceTime"
+	PortAllocations = "PortAllocations"
+
+	Run            = "Run"
+	RunDetached    = "RunDetached"
+	Processes      = "Processes"
+	ProcessMetrics = "ProcessMetrics"
+	Attach         = "Attach"
+
+	SetProcessTTY = "SetProcessTTY"
+	SignalProcess = "SignalProcess"
+	ProcessExit   = "ProcessExit"
+	ProcessEnv    = "ProcessEnv"
+
+	SetGraceTime   = "SetGraceTime"
+	SetDescription = "SetDescription"
+	Expirations    = "Expirations"
+	HandleScheme   = "HandleScheme"
+
+	Events        = "Events"
+	StreamMetrics = "StreamMetrics"
+
+	Reconciliation = "Reconciliation"
+	Pressure       = "Pressure"
 
 	Properties  = "Properties"
 	Property    = "Property"
 	SetProperty = "SetProperty"
 
-	Metrics = "Metrics"
+	Metrics       = "Metrics"
+	DiskUsage     = "DiskUsage"
+	HostResources = "HostResources"
 
 	RemoveProperty = "RemoveProperty"
+	CASProperty    = "CASProperty"
+
+	SetProperties    = "SetProperties"
+	RemoveProperties = "RemoveProperties"
+
+	ServerMetrics = "ServerMetrics"
+
+	Drain       = "Drain"
+	Undrain     = "Undrain"
+	DrainStatus = "DrainStatus"
+
+	WarmImage   = "WarmImage"
+	ListImages  = "ListImages"
+	RemoveImage = "RemoveImage"
+)
+
+// Query parameters of the List route which are not property filters. They
+// are namespaced so as not to collide with property names.
+const (
+	ListOrderParam         = "garden.order"
+	ListCreatedBeforeParam = "garden.created_before"
+	ListCreatedAfterParam  = "garden.created_after"
+
+	ListOrderByCreation = "created_at"
+
+	// ListFilterParamPrefix prefixes the query parameters which give property
+	// filters, as "garden.filter.<operator>.<name>", with one value per
+	// operand. The operator of a negated filter is prefixed by
+	// ListFilterNegation, e.g. "garden.filter.not_exists.owner".
+	ListFilterParamPrefix = "garden.filter."
+	ListFilterNegation    = "not_"
+)
+
+// EventsSinceParam is the query parameter of the Events route giving the
+// sequence number of the event after which to resume streaming.
+const EventsSinceParam = "since"
+
+// BulkMetricsDeltaSinceParam is the query parameter of the BulkMetricsDelta
+// route giving the token of the previous response, against which changes are
+// reported.
+const BulkMetricsDeltaSinceParam = "since"
+
+// ProcessByNameParam is the query parameter of the Attach, SignalProcess and
+// ProcessExit routes which, when "true", names the process by the name given
+// in its ProcessSpec rather than by its ID.
+const ProcessByNameParam = "by_name"
+
+// PayloadContentTypeParam is the query parameter of the Run and Attach routes
+// giving the content type in which to encode the process payloads exchanged
+// on the hijacked connection, e.g. transport.BinaryPayloadContentType. They
+// are encoded as JSON if it is not given.
+const PayloadContentTypeParam = "payload_content_type"
+
+// ImageURIParam is the query parameter of the RemoveImage route giving the
+// rootfs URI of the image to remove.
+const ImageURIParam = "uri"
+
+// PropertyKeyParam is the query parameter of the RemoveProperties route giving
+// the name of a property to remove. It is repeated for each property.
+const PropertyKeyParam = "key"
+
+// DiskUsagePathParam is the query parameter of the DiskUsage route giving a
+// path whose disk usage to report. It is repeated for each path.
+const DiskUsagePathParam = "path"
+
+// StreamMetricsHandlesParam and StreamMetricsIntervalParam are the query
+// parameters of the StreamMetrics route giving the comma separated handles of
+// the containers whose metrics to stream, or none for all containers, and how
+// often to send them, as a duration such as "15s".
+const (
+	StreamMetricsHandlesParam  = "handles"
+	StreamMetricsIntervalParam = "interval"
 )
 
 var Routes = rata.Routes{
@@ -49,36 +167,91 @@ var Routes = rata.Routes{
 
 	{Path: "/containers", Method: "GET", Name: List},
 	{Path: "/containers", Method: "POST", Name: Create},
+	{Path: "/containers/bulk_create", Method: "POST", Name: BulkCreate},
+	{Path: "/containers/restore", Method: "POST", Name: Restore},
 
 	{Path: "/containers/:handle/info", Method: "GET", Name: Info},
 	{Path: "/containers/bulk_info", Method: "GET", Name: BulkInfo},
 	{Path: "/containers/bulk_metrics", Method: "GET", Name: BulkMetrics},
+	{Path: "/containers/bulk_metrics/delta", Method: "GET", Name: BulkMetricsDelta},
 
 	{Path: "/containers/:handle", Method: "DELETE", Name: Destroy},
+	{Path: "/containers/bulk_destroy", Method: "POST", Name: BulkDestroy},
 	{Path: "/containers/:handle/stop", Method: "PUT", Name: Stop},
+	{Path: "/containers/bulk_stop", Method: "PUT", Name: BulkStop},
+	{Path: "/containers/:handle/pause", Method: "PUT", Name: Pause},
+	{Path: "/containers/:handle/resume", Method: "PUT", Name: Resume},
+	{Path: "/containers/:handle/checkpoint", Method: "PUT", Name: Checkpoint},
+	{Path: "/containers/:handle/commit", Method: "POST", Name: Commit},
 
 	{Path: "/containers/:handle/files", Method: "PUT", Name: StreamIn},
 	{Path: "/containers/:handle/files", Method: "GET", Name: StreamOut},
 
 	{Path: "/containers/:handle/limits/bandwidth", Method: "GET", Name: CurrentBandwidthLimits},
+	{Path: "/containers/:handle/limits/bandwidth", Method: "PUT", Name: LimitBandwidth},
 	{Path: "/containers/:handle/limits/cpu", Method: "GET", Name: CurrentCPULimits},
+	{Path: "/containers/:handle/limits/cpu", Method: "PUT", Name: LimitCPU},
 	{Path: "/containers/:handle/limits/disk", Method: "GET", Name: CurrentDiskLimits},
 	{Path: "/containers/:handle/limits/memory", Method: "GET", Name: CurrentMemoryLimits},
+	{Path: "/containers/:handle/limits/memory", Method: "PUT", Name: LimitMemory},
+	{Path: "/containers/:handle/limits/pid", Method: "GET", Name: CurrentPidLimits},
+	{Path: "/containers/:handle/limits/pid", Method: "PUT", Name: LimitPids},
+	{Path: "/containers/:handle/limits/block_io", Method: "GET", Name: CurrentBlockIOLimits},
+	{Path: "/containers/:handle/limits/block_io", Method: "PUT", Name: LimitBlockIO},
+	{Path: "/containers/:handle/limits", Method: "GET", Name: CurrentLimits},
+	{Path: "/containers/:handle/limits", Method: "PUT", Name: LimitAll},
 
 	{Path: "/containers/:handle/net/in", Method: "POST", Name: NetIn},
+	{Path: "/containers/:handle/net/in", Method: "GET", Name: ListNetIn},
+	{Path: "/containers/:handle/net/in/:host_port", Method: "DELETE", Name: RemoveNetIn},
 	{Path: "/containers/:handle/net/out", Method: "POST", Name: NetOut},
+	{Path: "/containers/:handle/net/out/bulk", Method: "POST", Name: BulkNetOut},
+	{Path: "/containers/:handle/net/out", Method: "GET", Name: ListNetOut},
+	{Path: "/containers/:handle/net/out", Method: "DELETE", Name: RemoveAllNetOut},
+	{Path: "/containers/:handle/net/out/:rule_id", Method: "DELETE", Name: RemoveNetOut},
+	{Path: "/ports", Method: "GET", Name: PortAllocations},
 
 	{Path: "/containers/:handle/processes/:pid/attaches/:streamid/stdout", Method: "GET", Name: Stdout},
 	{Path: "/containers/:handle/processes/:pid/attaches/:streamid/stderr", Method: "GET", Name: Stderr},
 	{Path: "/containers/:handle/processes", Method: "POST", Name: Run},
+	{Path: "/containers/:handle/processes/detached", Method: "POST", Name: RunDetached},
+	{Path: "/containers/:handle/processes", Method: "GET", Name: Processes},
+	{Path: "/containers/:handle/processes/metrics", Method: "GET", Name: ProcessMetrics},
 	{Path: "/containers/:handle/processes/:pid", Method: "GET", Name: Attach},
+	{Path: "/containers/:handle/processes/:pid/tty", Method: "PUT", Name: SetProcessTTY},
+	{Path: "/containers/:handle/processes/:pid/signal", Method: "PUT", Name: SignalProcess},
+	{Path: "/containers/:handle/processes/:pid/exit", Method: "GET", Name: ProcessExit},
+	{Path: "/containers/:handle/processes/:pid/env", Method: "GET", Name: ProcessEnv},
 
 	{Path: "/containers/:handle/grace_time", Method: "PUT", Name: SetGraceTime},
+	{Path: "/containers/:handle/description", Method: "PUT", Name: SetDescription},
+	{Path: "/expirations", Method: "GET", Name: Expirations},
+	{Path: "/handle_scheme", Method: "GET", Name: HandleScheme},
+
+	{Path: "/events", Method: "GET", Name: Events},
+	{Path: "/metrics/stream", Method: "GET", Name: StreamMetrics},
+	{Path: "/metrics", Method: "GET", Name: ServerMetrics},
+
+	{Path: "/drain", Method: "POST", Name: Drain},
+	{Path: "/drain", Method: "DELETE", Name: Undrain},
+	{Path: "/drain", Method: "GET", Name: DrainStatus},
+
+	{Path: "/reconciliation", Method: "GET", Name: Reconciliation},
+	{Path: "/pressure", Method: "GET", Name: Pressure},
 
 	{Path: "/containers/:handle/properties", Method: "GET", Name: Properties},
 	{Path: "/containers/:handle/properties/:key", Method: "GET", Name: Property},
 	{Path: "/containers/:handle/properties/:key", Method: "PUT", Name: SetProperty},
 	{Path: "/containers/:handle/properties/:key", Method: "DELETE", Name: RemoveProperty},
+	{Path: "/containers/:handle/properties/:key/cas", Method: "PUT", Name: CASProperty},
+	{Path: "/containers/:handle/properties", Method: "PUT", Name: SetProperties},
+	{Path: "/containers/:handle/properties", Method: "DELETE", Name: RemoveProperties},
 
 	{Path: "/containers/:handle/metrics", Method: "GET", Name: Metrics},
+	{Path: "/containers/:handle/disk_usage", Method: "GET", Name: DiskUsage},
+	{Path: "/containers/:handle/host_resources", Method: "GET", Name: HostResources},
+
+	{Path: "/images", Method: "POST", Name: WarmImage},
+	{Path: "/images", Method: "GET", Name: ListImages},
+	{Path: "/images", Method: "DELETE", Name: RemoveImage},
 }
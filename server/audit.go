@@ -0,0 +1,132 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/garden/routes"
+	"code.cloudfoundry.org/garden/server/audit"
+	"code.cloudfoundry.org/lager"
+)
+
+// maxAuditedBodyLength is how much of a request's body is recorded in its
+// audit entry.
+const maxAuditedBodyLength = 4096
+
+// auditedRoutes are the routes whose requests are recorded in the audit sink:
+// those destroying or stopping containers, or changing their limits, network
+// or properties.
+var auditedRoutes = map[string]bool{
+	routes.Destroy:          true,
+	routes.BulkDestroy:      true,
+	routes.Stop:             true,
+	routes.BulkStop:         true,
+	routes.LimitBandwidth:   true,
+	routes.LimitCPU:         true,
+	routes.LimitMemory:      true,
+	routes.LimitPids:        true,
+	routes.LimitBlockIO:     true,
+	routes.LimitAll:         true,
+	routes.NetIn:            true,
+	routes.RemoveNetIn:      true,
+	routes.NetOut:           true,
+	routes.BulkNetOut:       true,
+	routes.RemoveNetOut:     true,
+	routes.RemoveAllNetOut:  true,
+	routes.SetProperty:      true,
+	routes.RemoveProperty:   true,
+	routes.CASProperty:      true,
+	routes.SetProperties:    true,
+	routes.RemoveProperties: true,
+}
+
+// audit wraps the handler of a route so that each request to it is recorded
+// in the audit sink once it has been handled.
+func (s *GardenServer) audit(route string, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		entry := audit.Entry{
+			Time:   time.Now(),
+			Client: clientCommonName(r),
+			Route:  route,
+			Params: url.Values{},
+		}
+
+		if entry.Client == "" {
+			entry.Client = r.RemoteAddr
+		}
+
+		for name, values := range r.URL.Query() {
+			if name == ":handle" {
+				entry.Handle = values[0]
+				continue
+			}
+
+			entry.Params[strings.TrimPrefix(name, ":")] = values
+		}
+
+		if r.Body != nil {
+			start, _ := ioutil.ReadAll(io.LimitReader(r.Body, maxAuditedBodyLength))
+			r.Body = struct {
+				io.Reader
+				io.Closer
+			}{io.MultiReader(bytes.NewReader(start), r.Body), r.Body}
+
+			entry.Body = s.redactAuditedBody(route, r.URL.Query().Get(":key"), string(start))
+		}
+
+		recorder := &statusRecorder{ResponseWriter: w}
+
+		defer func() {
+			entry.Status = recorder.status()
+			entry.Duration = time.Since(entry.Time)
+
+			if entry.Status >= 400 {
+				entry.Error = recordedErrorMessage(recorder.errorBody)
+			}
+
+			if err := s.auditSink.Record(entry); err != nil {
+				s.logger.Session("audit", lager.Data{"route": route}).Error("failed-to-record", err)
+			}
+		}()
+
+		handler.ServeHTTP(recorder, r)
+	})
+}
+
+// redactAuditedBody redacts the body of a request as the server's errors are
+// redacted, and also the values of properties the redactor's
+// PropertyPatterns match.
+func (s *GardenServer) redactAuditedBody(route, key, body string) string {
+	switch route {
+	case routes.SetProperty, routes.CASProperty:
+		if s.redactor.Properties(garden.Properties{key: ""})[key] == garden.RedactedValue {
+			return garden.RedactedValue
+		}
+	case routes.SetProperties:
+		var properties garden.Properties
+		if json.Unmarshal([]byte(body), &properties) == nil {
+			redacted, _ := json.Marshal(s.redactor.Properties(properties))
+			body = string(redacted)
+		}
+	}
+
+	return s.redactor.Message(body)
+}
+
+// recordedErrorMessage returns the message of the garden.Error in the body of
+// an error response, or else the body itself.
+func recordedErrorMessage(body []byte) string {
+	var merr garden.Error
+	if json.Unmarshal(body, &merr) == nil && merr.Err != nil {
+		return merr.Err.Error()
+	}
+
+	return strings.TrimSpace(string(body))
+}
@@ -0,0 +1,96 @@
+package audit
+
+import (
+	"encoding/json"
+	"io"
+	"net/url"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/lager"
+)
+
+// Entry records a request which changed, or tried to change, containers.
+type Entry struct {
+	// Time is when the request was received.
+	Time time.Time `json:"time"`
+
+	// Client identifies who made the request: the common name of the
+	// client's TLS certificate, or else its remote address.
+	Client string `json:"client"`
+
+	// Route is the name of the route requested, as in the routes package.
+	Route string `json:"route"`
+
+	// Handle is the handle of the container requested, if any.
+	Handle string `json:"handle,omitempty"`
+
+	// Params are the request's other path and query parameters.
+	Params url.Values `json:"params,omitempty"`
+
+	// Body is the start of the request's body, redacted as the server's
+	// errors are.
+	Body string `json:"body,omitempty"`
+
+	// Status is the status code of the response, and Error the message of
+	// the error it carried, if any.
+	Status int    `json:"status"`
+	Error  string `json:"error,omitempty"`
+
+	// Duration is how long the request took to handle.
+	Duration time.Duration `json:"duration"`
+}
+
+// Sink records audit entries, e.g. to durable storage.
+type Sink interface {
+	Record(Entry) error
+}
+
+type loggerSink struct {
+	logger lager.Logger
+}
+
+// NewLoggerSink returns a Sink which logs each entry at info level.
+func NewLoggerSink(logger lager.Logger) Sink {
+	return &loggerSink{logger: logger.Session("audit")}
+}
+
+func (s *loggerSink) Record(entry Entry) error {
+	s.logger.Info("request", lager.Data{
+		"time":     entry.Time,
+		"client":   entry.Client,
+		"route":    entry.Route,
+		"handle":   entry.Handle,
+		"params":   entry.Params,
+		"body":     entry.Body,
+		"status":   entry.Status,
+		"error":    entry.Error,
+		"duration": entry.Duration.String(),
+	})
+
+	return nil
+}
+
+type writerSink struct {
+	w    io.Writer
+	lock sync.Mutex
+}
+
+// NewWriterSink returns a Sink which writes each entry to the writer as a
+// line of JSON, e.g. to an append-only file.
+func NewWriterSink(w io.Writer) Sink {
+	return &writerSink{w: w}
+}
+
+func (s *writerSink) Record(entry Entry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	_, err = s.w.Write(append(line, '\n'))
+	return err
+}
@@ -0,0 +1,67 @@
+package audit_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/lager/lagertest"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"code.cloudfoundry.org/garden/server/audit"
+)
+
+var _ = Describe("Sinks", func() {
+	entry := audit.Entry{
+		Time:     time.Date(2016, 1, 2, 3, 4, 5, 0, time.UTC),
+		Client:   "admin",
+		Route:    "SetProperty",
+		Handle:   "some-handle",
+		Params:   url.Values{"key": []string{"owner"}},
+		Body:     `{"value":"some-owner"}`,
+		Status:   404,
+		Error:    "unknown handle: some-handle",
+		Duration: time.Second,
+	}
+
+	Describe("the writer sink", func() {
+		It("writes each entry as a line of JSON", func() {
+			buf := new(bytes.Buffer)
+			sink := audit.NewWriterSink(buf)
+
+			Ω(sink.Record(entry)).Should(Succeed())
+			Ω(sink.Record(entry)).Should(Succeed())
+
+			lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+			Ω(lines).Should(HaveLen(2))
+
+			var recorded audit.Entry
+			Ω(json.Unmarshal([]byte(lines[0]), &recorded)).Should(Succeed())
+			Ω(recorded).Should(Equal(entry))
+		})
+	})
+
+	Describe("the logger sink", func() {
+		It("logs each entry", func() {
+			logger := lagertest.NewTestLogger("test")
+			sink := audit.NewLoggerSink(logger)
+
+			Ω(sink.Record(entry)).Should(Succeed())
+
+			logs := logger.LogMessages()
+			Ω(logs).Should(Equal([]string{"test.audit.request"}))
+
+			data := logger.Logs()[0].Data
+			Ω(logger.Logs()[0].LogLevel).Should(Equal(lager.INFO))
+			Ω(data["client"]).Should(Equal("admin"))
+			Ω(data["route"]).Should(Equal("SetProperty"))
+			Ω(data["handle"]).Should(Equal("some-handle"))
+			Ω(data["status"]).Should(BeEquivalentTo(404))
+			Ω(data["error"]).Should(Equal("unknown handle: some-handle"))
+		})
+	})
+})
@@ -0,0 +1,73 @@
+package server
+
+import (
+	"io"
+	"net/http"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/lager"
+)
+
+// limitBody wraps the handler of a route so that requests whose bodies are
+// larger than the maximum fail with a garden.PayloadTooLargeError. Requests
+// declaring a larger Content-Length are refused before they are handled.
+func (s *GardenServer) limitBody(route string, max int64, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ContentLength > max {
+			s.writeError(w, garden.PayloadTooLargeError{Limit: max}, s.logger.Session("body-limit", lager.Data{
+				"route":          route,
+				"content-length": r.ContentLength,
+			}))
+			return
+		}
+
+		if r.Body != nil {
+			r.Body = &limitedBody{ReadCloser: r.Body, remaining: max, limit: max}
+		}
+
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// limitedBody fails reads of a request body beyond its limit with a
+// garden.PayloadTooLargeError.
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+	limit     int64
+	exceeded  bool
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.exceeded {
+		return 0, garden.PayloadTooLargeError{Limit: b.limit}
+	}
+
+	// read one byte more than remains, to tell a body of exactly the limit
+	// from a larger one
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+
+	n, err := b.ReadCloser.Read(p)
+	if int64(n) > b.remaining {
+		b.exceeded = true
+		n = int(b.remaining)
+		err = garden.PayloadTooLargeError{Limit: b.limit}
+	}
+
+	b.remaining -= int64(n)
+	return n, err
+}
+
+// bodyError returns a garden.PayloadTooLargeError in place of the error of
+// handling the request if its body was larger than the route's limit, as the
+// error of reading the body may have been wrapped or replaced by the time it
+// is returned.
+func bodyError(r *http.Request, err error) error {
+	if body, ok := r.Body.(*limitedBody); ok && body.exceeded {
+		return garden.PayloadTooLargeError{Limit: body.limit}
+	}
+
+	return err
+}
@@ -1,6 +1,8 @@
 package bomberman
 
 import (
+	"time"
+
 	"code.cloudfoundry.org/garden"
 	"code.cloudfoundry.org/garden/server/timebomb"
 )
@@ -23,10 +25,11 @@ type Bomberman struct {
 
 	detonate func(garden.Container)
 
-	pause   chan string
-	unpause chan string
-	cleanup chan string
-	bomb    chan bomb
+	pause     chan string
+	unpause   chan string
+	cleanup   chan string
+	bomb      chan bomb
+	deadlines chan chan map[string]time.Time
 }
 
 func New(backend garden.Backend, detonate func(garden.Container)) *Bomberman {
@@ -38,6 +41,8 @@ func New(backend garden.Backend, detonate func(garden.Container)) *Bomberman {
 		pause:   make(chan string),
 		unpause: make(chan string),
 		cleanup: make(chan string),
+
+		deadlines: make(chan chan map[string]time.Time),
 	}
 
 	go b.manageBombs()
@@ -61,6 +66,14 @@ func (b *Bomberman) Defuse(name string) {
 	b.bomb <- bomb{Action: defuse, DefuseHandle: name}
 }
 
+// Deadlines returns the time at which each container's bomb will detonate,
+// excluding bombs that are currently paused.
+func (b *Bomberman) Deadlines() map[string]time.Time {
+	result := make(chan map[string]time.Time)
+	b.deadlines <- result
+	return <-result
+}
+
 func (b *Bomberman) manageBombs() {
 	timeBombs := map[string]*timebomb.TimeBomb{}
 
@@ -114,6 +127,16 @@ func (b *Bomberman) manageBombs() {
 
 		case handle := <-b.cleanup:
 			delete(timeBombs, handle)
+
+		case result := <-b.deadlines:
+			deadlines := map[string]time.Time{}
+			for handle, bomb := range timeBombs {
+				if deadline, ok := bomb.Deadline(); ok {
+					deadlines[handle] = deadline
+				}
+			}
+
+			result <- deadlines
 		}
 	}
 }
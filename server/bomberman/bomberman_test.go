@@ -166,4 +166,32 @@ var _ = Describe("Bomberman", func() {
 			})
 		})
 	})
+
+	Describe("listing deadlines", func() {
+		It("returns the deadline of each unpaused timebomb", func() {
+			backend := new(fakes.FakeBackend)
+			backend.GraceTimeReturns(time.Hour)
+
+			bomberman := bomberman.New(backend, func(container garden.Container) {})
+
+			doomed := new(fakes.FakeContainer)
+			doomed.HandleReturns("doomed")
+
+			paused := new(fakes.FakeContainer)
+			paused.HandleReturns("paused")
+
+			before := time.Now()
+
+			bomberman.Strap(doomed)
+			bomberman.Strap(paused)
+			bomberman.Pause("paused")
+
+			deadlines := bomberman.Deadlines()
+			Ω(deadlines).Should(HaveLen(1))
+			Ω(deadlines["doomed"]).Should(BeTemporally(">=", before.Add(time.Hour)))
+
+			bomberman.Defuse("doomed")
+			bomberman.Defuse("paused")
+		})
+	})
 })
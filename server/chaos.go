@@ -0,0 +1,74 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/garden/routes"
+	"code.cloudfoundry.org/lager"
+)
+
+// injectChaos wraps the handler of a route so that requests to it suffer the
+// route's fault, if it has one when the request is received.
+func (s *GardenServer) injectChaos(route string, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fault, found := s.chaos.Fault(route)
+		if !found {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		hLog := s.logger.Session("chaos", lager.Data{"route": route})
+
+		if fault.Latency > 0 {
+			select {
+			case <-time.After(fault.Latency):
+			case <-s.stopping:
+			}
+		}
+
+		if s.chaos.Roll(fault.DropRate) {
+			hLog.Info("dropping-connection")
+
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				conn.Close()
+			}
+
+			return
+		}
+
+		if s.chaos.Roll(fault.UnavailableRate) {
+			s.writeError(w, garden.NewServiceUnavailableError(fmt.Sprintf("chaos: injected failure of %s", route)), hLog)
+			return
+		}
+
+		if fault.PartialRate > 0 && (route == routes.BulkInfo || route == routes.BulkMetrics) {
+			s.dropHandles(r, fault.PartialRate, hLog)
+		}
+
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// dropHandles leaves each handle out of a bulk request with the given
+// probability.
+func (s *GardenServer) dropHandles(r *http.Request, rate float64, hLog lager.Logger) {
+	query := r.URL.Query()
+
+	kept := []string{}
+	for _, handle := range splitHandles(query.Get("handles")) {
+		if s.chaos.Roll(rate) {
+			hLog.Info("dropping-handle", lager.Data{"handle": handle})
+			continue
+		}
+
+		kept = append(kept, handle)
+	}
+
+	query.Set("handles", strings.Join(kept, ","))
+	r.URL.RawQuery = query.Encode()
+}
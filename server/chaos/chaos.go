@@ -0,0 +1,92 @@
+// Package chaos configures faults which a server injects into requests to
+// chosen routes, e.g. so that the resilience of a consumer to an overloaded
+// or flaky server can be tested against a server with a fake backend.
+package chaos
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Fault describes the faults injected into requests to a route. Rates are
+// probabilities from 0 to 1.
+type Fault struct {
+	// Latency delays each request before it is handled.
+	Latency time.Duration
+
+	// UnavailableRate is how often a request fails with a
+	// garden.ServiceUnavailableError.
+	UnavailableRate float64
+
+	// DropRate is how often a request's connection is closed without a
+	// response, as when a hijacked process connection is lost.
+	DropRate float64
+
+	// PartialRate is how often each handle is left out of a BulkInfo or
+	// BulkMetrics request, so that the response is missing its entry.
+	PartialRate float64
+}
+
+// Chaos holds the faults for each route. Faults may be changed while the
+// server is running.
+type Chaos struct {
+	mu     sync.Mutex
+	faults map[string]Fault
+	rand   *rand.Rand
+}
+
+// New returns a Chaos with no faults, rolling for them with the given seed
+// so that runs can be reproduced.
+func New(seed int64) *Chaos {
+	return &Chaos{
+		faults: map[string]Fault{},
+		rand:   rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Set injects the fault into requests to the route, named as in the routes
+// package, replacing any fault it had.
+func (c *Chaos) Set(route string, fault Fault) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.faults[route] = fault
+}
+
+// Clear stops injecting faults into requests to the route.
+func (c *Chaos) Clear(route string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.faults, route)
+}
+
+// Reset stops injecting faults into requests to every route.
+func (c *Chaos) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.faults = map[string]Fault{}
+}
+
+// Fault returns the fault for the route, if it has one.
+func (c *Chaos) Fault(route string) (Fault, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	fault, found := c.faults[route]
+	return fault, found
+}
+
+// Roll reports whether an event with the given probability happens.
+func (c *Chaos) Roll(probability float64) bool {
+	if probability <= 0 {
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.rand.Float64() < probability
+}
@@ -0,0 +1,87 @@
+package chaos_test
+
+import (
+	"time"
+
+	"code.cloudfoundry.org/garden/server/chaos"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Chaos", func() {
+	var c *chaos.Chaos
+
+	BeforeEach(func() {
+		c = chaos.New(42)
+	})
+
+	It("has no faults to begin with", func() {
+		_, found := c.Fault("Ping")
+		Expect(found).To(BeFalse())
+	})
+
+	It("returns the fault set for a route", func() {
+		c.Set("Ping", chaos.Fault{Latency: time.Second})
+
+		fault, found := c.Fault("Ping")
+		Expect(found).To(BeTrue())
+		Expect(fault.Latency).To(Equal(time.Second))
+
+		_, found = c.Fault("Create")
+		Expect(found).To(BeFalse())
+	})
+
+	It("clears the fault of a route", func() {
+		c.Set("Ping", chaos.Fault{DropRate: 1})
+		c.Set("Create", chaos.Fault{DropRate: 1})
+		c.Clear("Ping")
+
+		_, found := c.Fault("Ping")
+		Expect(found).To(BeFalse())
+		_, found = c.Fault("Create")
+		Expect(found).To(BeTrue())
+	})
+
+	It("resets the faults of every route", func() {
+		c.Set("Ping", chaos.Fault{DropRate: 1})
+		c.Set("Create", chaos.Fault{DropRate: 1})
+		c.Reset()
+
+		_, found := c.Fault("Ping")
+		Expect(found).To(BeFalse())
+		_, found = c.Fault("Create")
+		Expect(found).To(BeFalse())
+	})
+
+	Describe("Roll", func() {
+		It("never happens with a probability of zero", func() {
+			for i := 0; i < 100; i++ {
+				Expect(c.Roll(0)).To(BeFalse())
+			}
+		})
+
+		It("always happens with a probability of one", func() {
+			for i := 0; i < 100; i++ {
+				Expect(c.Roll(1)).To(BeTrue())
+			}
+		})
+
+		It("happens about as often as its probability", func() {
+			happened := 0
+			for i := 0; i < 10000; i++ {
+				if c.Roll(0.3) {
+					happened++
+				}
+			}
+
+			Expect(happened).To(BeNumerically("~", 3000, 300))
+		})
+
+		It("is reproducible from the seed", func() {
+			other := chaos.New(42)
+			for i := 0; i < 100; i++ {
+				Expect(c.Roll(0.5)).To(Equal(other.Roll(0.5)))
+			}
+		})
+	})
+})
@@ -0,0 +1,197 @@
+package server
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/lager"
+)
+
+// clientLimitsPruneInterval is how often the state of clients which have
+// been idle long enough to be back at their full burst is forgotten.
+const clientLimitsPruneInterval = time.Minute
+
+// ClientLimit caps the requests of each client, as identified by the common
+// name of its TLS certificate or else by its source address, so that one
+// misbehaving client cannot starve the others. Clients of a unix socket have
+// no source address to tell them apart, so unless they present a certificate
+// they are not limited; access to them is governed by the socket's
+// permissions instead.
+type ClientLimit struct {
+	// RequestsPerSecond is the rate at which a client may make requests, or
+	// zero not to limit the rate. Burst is how many requests it may make at
+	// once beyond the rate, and defaults to a second's worth.
+	RequestsPerSecond float64
+	Burst             int
+
+	// Concurrency is how many of a client's requests are handled at once, or
+	// zero not to cap them. Streams, e.g. of process output, are not counted,
+	// as they are held open for long.
+	Concurrency int
+
+	// RetryAfter is how long clients over their concurrency cap are told to
+	// wait before retrying, or zero to make no suggestion. Clients over their
+	// rate are told when they may make their next request.
+	RetryAfter time.Duration
+}
+
+// clientLimiter applies a ClientLimit to each client, with a token bucket for
+// its rate and a count of its requests in flight.
+type clientLimiter struct {
+	limit ClientLimit
+	burst float64
+
+	clients  map[string]*clientState
+	prunedAt time.Time
+	lock     sync.Mutex
+}
+
+type clientState struct {
+	tokens   float64
+	filledAt time.Time
+	inFlight int
+}
+
+func newClientLimiter(limit ClientLimit) *clientLimiter {
+	burst := float64(limit.Burst)
+	if burst <= 0 {
+		burst = math.Max(1, math.Ceil(limit.RequestsPerSecond))
+	}
+
+	return &clientLimiter{
+		limit:    limit,
+		burst:    burst,
+		clients:  map[string]*clientState{},
+		prunedAt: time.Now(),
+	}
+}
+
+// admit admits a request from the client, counting it as in flight if it
+// does not stream, failing with a garden.TooManyRequestsError if the client
+// is over its cap or its rate. Admitted requests must be released once
+// handled.
+func (l *clientLimiter) admit(client string, streams bool, now time.Time) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.prune(now)
+
+	state, found := l.clients[client]
+	if !found {
+		state = &clientState{tokens: l.burst, filledAt: now}
+		l.clients[client] = state
+	}
+
+	if !streams && l.limit.Concurrency > 0 && state.inFlight >= l.limit.Concurrency {
+		return garden.TooManyRequestsError{
+			Cause:      fmt.Sprintf("too many concurrent requests from %s: limit is %d", client, l.limit.Concurrency),
+			RetryAfter: l.limit.RetryAfter,
+		}
+	}
+
+	if l.limit.RequestsPerSecond > 0 {
+		l.refill(state, now)
+
+		if state.tokens < 1 {
+			wait := time.Duration((1 - state.tokens) / l.limit.RequestsPerSecond * float64(time.Second))
+			return garden.TooManyRequestsError{
+				Cause:      fmt.Sprintf("too many requests from %s: limit is %g per second", client, l.limit.RequestsPerSecond),
+				RetryAfter: wait,
+			}
+		}
+
+		state.tokens--
+	}
+
+	if !streams {
+		state.inFlight++
+	}
+
+	return nil
+}
+
+// release releases an admitted request from the client.
+func (l *clientLimiter) release(client string, streams bool) {
+	if streams {
+		return
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if state, found := l.clients[client]; found {
+		state.inFlight--
+	}
+}
+
+func (l *clientLimiter) refill(state *clientState, now time.Time) {
+	elapsed := now.Sub(state.filledAt).Seconds()
+	state.tokens = math.Min(l.burst, state.tokens+elapsed*l.limit.RequestsPerSecond)
+	state.filledAt = now
+}
+
+func (l *clientLimiter) prune(now time.Time) {
+	if now.Sub(l.prunedAt) < clientLimitsPruneInterval {
+		return
+	}
+
+	l.prunedAt = now
+
+	for client, state := range l.clients {
+		if l.limit.RequestsPerSecond > 0 {
+			l.refill(state, now)
+		}
+
+		if state.inFlight == 0 && state.tokens >= l.burst {
+			delete(l.clients, client)
+		}
+	}
+}
+
+// limitClients wraps the handler of a route so that requests from clients
+// over their limits are refused with a garden.TooManyRequestsError.
+func (s *GardenServer) limitClients(route string, handler http.Handler) http.Handler {
+	_, streams := streamKinds[route]
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		client := clientIdentity(r)
+		if client == "" {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		if err := s.clientLimiter.admit(client, streams, time.Now()); err != nil {
+			s.writeError(w, err, s.logger.Session("client-limit", lager.Data{"route": route, "client": client}))
+			return
+		}
+
+		defer s.clientLimiter.release(client, streams)
+
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// clientIdentity identifies the client which made the request by the common
+// name of its TLS certificate, or else by its source address. Clients of a
+// unix socket, whose address is empty or "@", are not identified.
+func clientIdentity(r *http.Request) string {
+	if commonName := clientCommonName(r); commonName != "" {
+		return commonName
+	}
+
+	if r.RemoteAddr == "" || r.RemoteAddr == "@" {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
@@ -0,0 +1,113 @@
+package server
+
+import (
+	"fmt"
+	"net"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/lager"
+)
+
+// requestedContainerIP returns the IP the spec asks for the container to be
+// given, either as its ContainerIP or as the address in its Network, or nil
+// if it asks for none.
+func requestedContainerIP(spec garden.ContainerSpec) (net.IP, error) {
+	malformed := func(format string, args ...interface{}) error {
+		return garden.MalformedRequestError{Cause: fmt.Sprintf(format, args...)}
+	}
+
+	var subnet *net.IPNet
+	var networkIP net.IP
+	if spec.Network != "" {
+		ip, ipNet, err := net.ParseCIDR(spec.Network)
+		if err != nil {
+			if spec.ContainerIP == "" {
+				// the backend rejects networks it cannot parse itself
+				return nil, nil
+			}
+
+			return nil, malformed("network: %q is not a CIDR", spec.Network)
+		}
+
+		subnet = ipNet
+		if !ip.Equal(ipNet.IP) {
+			networkIP = ip
+		}
+	}
+
+	if spec.ContainerIP == "" {
+		return networkIP, nil
+	}
+
+	ip := net.ParseIP(spec.ContainerIP)
+	if ip == nil {
+		return nil, malformed("container_ip: %q is not an IP", spec.ContainerIP)
+	}
+
+	if networkIP != nil && !networkIP.Equal(ip) {
+		return nil, malformed("container_ip: %s disagrees with network %s", ip, spec.Network)
+	}
+
+	if subnet != nil {
+		if !subnet.Contains(ip) {
+			return nil, malformed("container_ip: %s is not within network %s", ip, spec.Network)
+		}
+
+		if ip.Equal(subnet.IP) || ip.Equal(garden.IPRangeFromIPNet(subnet).End) {
+			return nil, malformed("container_ip: %s is reserved in network %s", ip, spec.Network)
+		}
+	}
+
+	return ip, nil
+}
+
+// reserveContainerIP fails with a garden.IPTakenError if another container
+// has the IP the spec asks for. Otherwise it returns a function to call once
+// the container has been created or restored, until which other requests for
+// an IP wait, so that concurrent requests for the same IP cannot both find it
+// free.
+func (s *GardenServer) reserveContainerIP(spec garden.ContainerSpec, logger lager.Logger) (func(), error) {
+	containerIP, err := requestedContainerIP(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	if containerIP == nil {
+		return func() {}, nil
+	}
+
+	s.containerIPsL.Lock()
+
+	if err := s.ensureContainerIPFree(containerIP, logger); err != nil {
+		s.containerIPsL.Unlock()
+		return nil, err
+	}
+
+	return s.containerIPsL.Unlock, nil
+}
+
+// ensureContainerIPFree fails with a garden.IPTakenError if a container has
+// already been given the IP. Containers whose info cannot be retrieved are
+// skipped.
+func (s *GardenServer) ensureContainerIPFree(ip net.IP, logger lager.Logger) error {
+	containers, err := s.backend.Containers(nil)
+	if err != nil {
+		return err
+	}
+
+	for _, container := range containers {
+		info, err := container.Info()
+		if err != nil {
+			logger.Error("failed-to-get-info", err, lager.Data{"handle": container.Handle()})
+			continue
+		}
+
+		for _, assigned := range []string{info.ContainerIP, info.ContainerIPv6} {
+			if ip.Equal(net.ParseIP(assigned)) {
+				return garden.IPTakenError{IP: ip.String(), Handle: container.Handle()}
+			}
+		}
+	}
+
+	return nil
+}
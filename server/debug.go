@@ -0,0 +1,38 @@
+package server
+
+import (
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"os"
+
+	"code.cloudfoundry.org/lager"
+)
+
+// startDebugServer serves net/http/pprof's profiles on the debug listener.
+func (s *GardenServer) startDebugServer() error {
+	if s.debugNetwork == "unix" {
+		if err := os.Remove(s.debugAddr); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	listener, err := net.Listen(s.debugNetwork, s.debugAddr)
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	s.logger.Info("serving-debug", lager.Data{"network": s.debugNetwork, "addr": listener.Addr().String()})
+
+	s.debugServer = &http.Server{Handler: mux}
+	go s.debugServer.Serve(listener)
+
+	return nil
+}
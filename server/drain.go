@@ -0,0 +1,144 @@
+package server
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/garden/routes"
+	"code.cloudfoundry.org/lager"
+)
+
+// creationRoutes are the routes creating containers, which are refused while
+// the server drains.
+var creationRoutes = map[string]bool{
+	routes.Create:     true,
+	routes.BulkCreate: true,
+	routes.Restore:    true,
+}
+
+// drainedStreamRoutes are the routes streaming into or out of containers,
+// whose requests a drain waits for. Subscriptions to events and metrics are
+// not waited for, as they do not end by themselves.
+var drainedStreamRoutes = map[string]bool{
+	routes.StreamIn:  true,
+	routes.StreamOut: true,
+}
+
+// attachedRoutes are the routes attaching to processes and streaming their
+// output, whose requests last as long as the processes run. They are counted
+// apart from the requests in flight and not waited for, so that a server
+// whose processes are attached to can still be drained.
+var attachedRoutes = map[string]bool{
+	routes.Run:    true,
+	routes.Attach: true,
+	routes.Stdout: true,
+	routes.Stderr: true,
+}
+
+// drainState tracks whether the server is draining, the requests which a
+// drain waits for, and the connections attached to processes.
+type drainState struct {
+	since    time.Time
+	inFlight int
+	attached int
+	lock     sync.Mutex
+}
+
+// start counts a request to the route, failing if it would create containers
+// while the server drains.
+func (d *drainState) start(route string) bool {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if creationRoutes[route] && !d.since.IsZero() {
+		return false
+	}
+
+	if attachedRoutes[route] {
+		d.attached++
+	} else {
+		d.inFlight++
+	}
+
+	return true
+}
+
+func (d *drainState) finish(route string) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if attachedRoutes[route] {
+		d.attached--
+	} else {
+		d.inFlight--
+	}
+}
+
+func (d *drainState) status() garden.DrainStatus {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	return garden.DrainStatus{
+		Draining: !d.since.IsZero(),
+		Since:    d.since,
+		InFlight: d.inFlight,
+		Attached: d.attached,
+		Drained:  !d.since.IsZero() && d.inFlight == 0,
+	}
+}
+
+// Drain makes the server refuse to create containers, while it finishes the
+// requests in flight and keeps serving others, so that its containers may be
+// evacuated. The returned status reports when it is drained.
+func (s *GardenServer) Drain() garden.DrainStatus {
+	s.drain.lock.Lock()
+	if s.drain.since.IsZero() {
+		s.drain.since = time.Now()
+		s.logger.Info("draining", lager.Data{"in-flight": s.drain.inFlight})
+	}
+	s.drain.lock.Unlock()
+
+	return s.drain.status()
+}
+
+// Undrain makes a draining server create containers again.
+func (s *GardenServer) Undrain() garden.DrainStatus {
+	s.drain.lock.Lock()
+	if !s.drain.since.IsZero() {
+		s.drain.since = time.Time{}
+		s.logger.Info("undrained")
+	}
+	s.drain.lock.Unlock()
+
+	return s.drain.status()
+}
+
+// trackDrain wraps the handler of a route creating containers, streaming or
+// attaching to processes, so that its requests are counted, and creations are
+// refused with a garden.ServiceUnavailableError while the server drains.
+func (s *GardenServer) trackDrain(route string, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.drain.start(route) {
+			s.writeError(w, garden.NewServiceUnavailableError("server is draining"), s.logger.Session("drain", lager.Data{"route": route}))
+			return
+		}
+
+		defer s.drain.finish(route)
+
+		handler.ServeHTTP(w, r)
+	})
+}
+
+func (s *GardenServer) handleDrain(w http.ResponseWriter, r *http.Request) {
+	s.writeResponse(w, s.Drain())
+}
+
+func (s *GardenServer) handleUndrain(w http.ResponseWriter, r *http.Request) {
+	s.writeResponse(w, s.Undrain())
+}
+
+func (s *GardenServer) handleDrainStatus(w http.ResponseWriter, r *http.Request) {
+	s.writeResponse(w, s.drain.status())
+}
@@ -0,0 +1,102 @@
+package events
+
+import (
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/garden"
+)
+
+// SubscriberBuffer is how many events may be waiting to be read by a
+// subscriber before it is considered too slow and is unsubscribed. It is also
+// how many of the latest events are retained for subscribers resuming after
+// an event they saw before.
+const SubscriberBuffer = 1024
+
+// Hub fans out published events to every subscriber.
+type Hub struct {
+	subscribers map[chan garden.Event]struct{}
+	history     []garden.Event
+	seq         uint64
+	lock        *sync.Mutex
+}
+
+// NewHub returns a hub which numbers events from the current time in
+// nanoseconds, so that their sequence numbers keep increasing across
+// restarts.
+func NewHub() *Hub {
+	return &Hub{
+		subscribers: map[chan garden.Event]struct{}{},
+		seq:         uint64(time.Now().UnixNano()),
+		lock:        new(sync.Mutex),
+	}
+}
+
+// Subscribe returns a channel on which every event published from now on is
+// received, and a function which unsubscribes. The channel is closed when
+// unsubscribed, including when the subscriber falls more than
+// SubscriberBuffer events behind, so that it can tell events were missed.
+func (h *Hub) Subscribe() (<-chan garden.Event, func()) {
+	return h.SubscribeSince(0)
+}
+
+// SubscribeSince is like Subscribe, but the channel first receives those
+// retained events which were published after the event with the given
+// sequence number. A subscriber which resumes after an event which is no
+// longer retained may tell that it missed events by a gap in their sequence
+// numbers. A sequence number of zero resumes after no event, so only events
+// published from now on are received.
+func (h *Hub) SubscribeSince(seq uint64) (<-chan garden.Event, func()) {
+	events := make(chan garden.Event, SubscriberBuffer)
+
+	h.lock.Lock()
+	if seq != 0 {
+		for _, event := range h.history {
+			if event.Seq > seq {
+				events <- event
+			}
+		}
+	}
+
+	h.subscribers[events] = struct{}{}
+	h.lock.Unlock()
+
+	return events, func() {
+		h.lock.Lock()
+		defer h.lock.Unlock()
+
+		h.unsubscribe(events)
+	}
+}
+
+// Publish numbers the event with the next sequence number, retains it, and
+// sends it to every subscriber without blocking.
+func (h *Hub) Publish(event garden.Event) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.seq++
+	event.Seq = h.seq
+
+	h.history = append(h.history, event)
+	if len(h.history) > SubscriberBuffer {
+		h.history = h.history[len(h.history)-SubscriberBuffer:]
+	}
+
+	for subscriber := range h.subscribers {
+		select {
+		case subscriber <- event:
+		default:
+			h.unsubscribe(subscriber)
+		}
+	}
+}
+
+func (h *Hub) unsubscribe(subscriber chan garden.Event) {
+	if _, found := h.subscribers[subscriber]; !found {
+		return
+	}
+
+	delete(h.subscribers, subscriber)
+	close(subscriber)
+}
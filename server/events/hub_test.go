@@ -0,0 +1,131 @@
+package events_test
+
+import (
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/garden/server/events"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Hub", func() {
+	var hub *events.Hub
+
+	BeforeEach(func() {
+		hub = events.NewHub()
+	})
+
+	It("sends published events to every subscriber", func() {
+		first, unsubscribeFirst := hub.Subscribe()
+		defer unsubscribeFirst()
+
+		second, unsubscribeSecond := hub.Subscribe()
+		defer unsubscribeSecond()
+
+		hub.Publish(garden.Event{Kind: garden.EventCreated, Handle: "some-handle"})
+
+		event := <-first
+		Expect(event.Kind).To(Equal(garden.EventCreated))
+		Expect(event.Handle).To(Equal("some-handle"))
+
+		Expect(<-second).To(Equal(event))
+	})
+
+	It("numbers events in increasing order", func() {
+		subscription, unsubscribe := hub.Subscribe()
+		defer unsubscribe()
+
+		hub.Publish(garden.Event{Kind: garden.EventCreated})
+		hub.Publish(garden.Event{Kind: garden.EventDestroyed})
+
+		first := <-subscription
+		second := <-subscription
+		Expect(first.Seq).NotTo(BeZero())
+		Expect(second.Seq).To(Equal(first.Seq + 1))
+	})
+
+	It("numbers events after those of hubs created earlier", func() {
+		subscription, unsubscribe := hub.Subscribe()
+		defer unsubscribe()
+		hub.Publish(garden.Event{Kind: garden.EventCreated})
+		earlier := <-subscription
+
+		later := events.NewHub()
+		laterSubscription, unsubscribeLater := later.Subscribe()
+		defer unsubscribeLater()
+		later.Publish(garden.Event{Kind: garden.EventCreated})
+
+		Expect((<-laterSubscription).Seq).To(BeNumerically(">", earlier.Seq))
+	})
+
+	Describe("resuming after an event", func() {
+		var published []garden.Event
+
+		BeforeEach(func() {
+			subscription, unsubscribe := hub.Subscribe()
+			defer unsubscribe()
+
+			published = nil
+			for _, handle := range []string{"a", "b", "c"} {
+				hub.Publish(garden.Event{Kind: garden.EventCreated, Handle: handle})
+				published = append(published, <-subscription)
+			}
+		})
+
+		It("first sends the retained events published after it", func() {
+			subscription, unsubscribe := hub.SubscribeSince(published[0].Seq)
+			defer unsubscribe()
+
+			hub.Publish(garden.Event{Kind: garden.EventCreated, Handle: "d"})
+
+			Expect((<-subscription).Handle).To(Equal("b"))
+			Expect((<-subscription).Handle).To(Equal("c"))
+			Expect((<-subscription).Handle).To(Equal("d"))
+		})
+
+		It("sends only new events when resuming after no event", func() {
+			subscription, unsubscribe := hub.SubscribeSince(0)
+			defer unsubscribe()
+
+			hub.Publish(garden.Event{Kind: garden.EventCreated, Handle: "d"})
+
+			Expect((<-subscription).Handle).To(Equal("d"))
+		})
+
+		It("retains only the latest events", func() {
+			for i := 0; i < events.SubscriberBuffer; i++ {
+				hub.Publish(garden.Event{Kind: garden.EventCreated})
+			}
+
+			subscription, unsubscribe := hub.SubscribeSince(published[0].Seq)
+			defer unsubscribe()
+
+			Expect((<-subscription).Seq).To(Equal(published[2].Seq + 1))
+		})
+	})
+
+	It("closes the channel when unsubscribed", func() {
+		subscription, unsubscribe := hub.Subscribe()
+		unsubscribe()
+		unsubscribe()
+
+		Expect(subscription).To(BeClosed())
+
+		hub.Publish(garden.Event{Kind: garden.EventCreated})
+	})
+
+	It("unsubscribes subscribers which fall too far behind", func() {
+		slow, unsubscribeSlow := hub.Subscribe()
+		defer unsubscribeSlow()
+
+		for i := 0; i <= events.SubscriberBuffer; i++ {
+			hub.Publish(garden.Event{Kind: garden.EventCreated})
+		}
+
+		received := 0
+		for range slow {
+			received++
+		}
+
+		Expect(received).To(Equal(events.SubscriberBuffer))
+	})
+})
@@ -0,0 +1,65 @@
+package exits
+
+import (
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/garden"
+)
+
+// Exits retains how processes exited for a period, so that clients which
+// lost their connection to a process may still learn its exit status.
+type Exits struct {
+	retention time.Duration
+
+	exits map[key]garden.ProcessExit
+	lock  *sync.Mutex
+}
+
+type key struct {
+	handle    string
+	processID string
+}
+
+func New(retention time.Duration) *Exits {
+	return &Exits{
+		retention: retention,
+
+		exits: map[key]garden.ProcessExit{},
+		lock:  new(sync.Mutex),
+	}
+}
+
+// Record retains the exit of a process in the container until the retention
+// period has passed since it exited.
+func (e *Exits) Record(handle string, exit garden.ProcessExit) {
+	if e.retention == 0 {
+		return
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	e.expire()
+	e.exits[key{handle, exit.ProcessID}] = exit
+}
+
+// Get returns the retained exit of the process in the container, and whether
+// one is retained.
+func (e *Exits) Get(handle, processID string) (garden.ProcessExit, bool) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	e.expire()
+	exit, found := e.exits[key{handle, processID}]
+	return exit, found
+}
+
+func (e *Exits) expire() {
+	cutoff := time.Now().Add(-e.retention)
+	for k, exit := range e.exits {
+		if exit.ExitedAt.Before(cutoff) {
+			delete(e.exits, k)
+		}
+	}
+}
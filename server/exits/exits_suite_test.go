@@ -0,0 +1,13 @@
+package exits_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestExits(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Exits Suite")
+}
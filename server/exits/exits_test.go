@@ -0,0 +1,55 @@
+package exits_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/garden/server/exits"
+)
+
+var _ = Describe("Exits", func() {
+	It("returns recorded exits", func() {
+		e := exits.New(time.Minute)
+
+		exit := garden.ProcessExit{ProcessID: "some-process", ExitStatus: 42, ExitedAt: time.Now()}
+		e.Record("some-handle", exit)
+
+		recorded, found := e.Get("some-handle", "some-process")
+		Ω(found).Should(BeTrue())
+		Ω(recorded).Should(Equal(exit))
+	})
+
+	It("does not confuse processes of different containers", func() {
+		e := exits.New(time.Minute)
+
+		e.Record("some-handle", garden.ProcessExit{ProcessID: "some-process", ExitedAt: time.Now()})
+
+		_, found := e.Get("other-handle", "some-process")
+		Ω(found).Should(BeFalse())
+	})
+
+	It("forgets exits once the retention period has passed", func() {
+		e := exits.New(50 * time.Millisecond)
+
+		e.Record("some-handle", garden.ProcessExit{ProcessID: "some-process", ExitedAt: time.Now()})
+
+		Eventually(func() bool {
+			_, found := e.Get("some-handle", "some-process")
+			return found
+		}).Should(BeFalse())
+	})
+
+	Context("when the retention period is zero", func() {
+		It("does not record exits", func() {
+			e := exits.New(0)
+
+			e.Record("some-handle", garden.ProcessExit{ProcessID: "some-process", ExitedAt: time.Now()})
+
+			_, found := e.Get("some-handle", "some-process")
+			Ω(found).Should(BeFalse())
+		})
+	})
+})
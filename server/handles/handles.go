@@ -0,0 +1,157 @@
+// Package handles generates container handles following a
+// garden.HandleScheme, so that a server and systems which pre-compute handles
+// for it generate handles of the same form.
+package handles
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/garden"
+)
+
+// DefaultCharset is the charset of schemes which do not give one.
+const DefaultCharset = "abcdefghijklmnopqrstuvwxyz0123456789"
+
+// Generator generates handles following a scheme.
+type Generator struct {
+	scheme garden.HandleScheme
+	now    func() time.Time
+}
+
+// New returns a generator for the scheme, which must either be for UUIDs or
+// have a positive length, and whose charset, if given, must not repeat a
+// character.
+func New(scheme garden.HandleScheme) (*Generator, error) {
+	if scheme.UUIDv7 {
+		if scheme.Length != 0 || scheme.Charset != "" {
+			return nil, errors.New("handle scheme: length and charset do not apply to UUIDs")
+		}
+	} else {
+		if scheme.Length <= 0 {
+			return nil, fmt.Errorf("handle scheme: length %d is not positive", scheme.Length)
+		}
+
+		if scheme.Charset == "" {
+			scheme.Charset = DefaultCharset
+		}
+
+		for i, c := range scheme.Charset {
+			if strings.IndexRune(scheme.Charset[i+len(string(c)):], c) != -1 {
+				return nil, fmt.Errorf("handle scheme: charset repeats %q", c)
+			}
+		}
+	}
+
+	return &Generator{scheme: scheme, now: time.Now}, nil
+}
+
+// Scheme returns the generator's scheme, with any defaults filled in.
+func (g *Generator) Scheme() garden.HandleScheme {
+	return g.scheme
+}
+
+// Generate returns a new handle.
+func (g *Generator) Generate() (string, error) {
+	if g.scheme.UUIDv7 {
+		uuid, err := newUUIDv7(g.now())
+		if err != nil {
+			return "", err
+		}
+
+		return g.scheme.Prefix + uuid, nil
+	}
+
+	charset := []rune(g.scheme.Charset)
+	max := big.NewInt(int64(len(charset)))
+
+	handle := []rune(g.scheme.Prefix)
+	for i := 0; i < g.scheme.Length; i++ {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+
+		handle = append(handle, charset[n.Int64()])
+	}
+
+	return string(handle), nil
+}
+
+// Conforms reports whether the handle could have been generated following
+// the scheme.
+func (g *Generator) Conforms(handle string) bool {
+	if !strings.HasPrefix(handle, g.scheme.Prefix) {
+		return false
+	}
+
+	rest := handle[len(g.scheme.Prefix):]
+
+	if g.scheme.UUIDv7 {
+		return isUUIDv7(rest)
+	}
+
+	if len([]rune(rest)) != g.scheme.Length {
+		return false
+	}
+
+	for _, c := range rest {
+		if !strings.ContainsRune(g.scheme.Charset, c) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// newUUIDv7 returns a version 7 UUID, as described by RFC 9562: the time in
+// milliseconds since the Unix epoch, followed by random bits.
+func newUUIDv7(now time.Time) (string, error) {
+	var uuid [16]byte
+	if _, err := rand.Read(uuid[6:]); err != nil {
+		return "", err
+	}
+
+	var millis [8]byte
+	binary.BigEndian.PutUint64(millis[:], uint64(now.UnixNano()/int64(time.Millisecond)))
+	copy(uuid[:6], millis[2:])
+
+	uuid[6] = uuid[6]&0x0f | 0x70
+	uuid[8] = uuid[8]&0x3f | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", uuid[0:4], uuid[4:6], uuid[6:8], uuid[8:10], uuid[10:16]), nil
+}
+
+func isUUIDv7(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+
+	for i, c := range s {
+		switch i {
+		case 8, 13, 18, 23:
+			if c != '-' {
+				return false
+			}
+		case 14:
+			if c != '7' {
+				return false
+			}
+		case 19:
+			if !strings.ContainsRune("89ab", c) {
+				return false
+			}
+		default:
+			if !strings.ContainsRune("0123456789abcdef", c) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,98 @@
+package handles_test
+
+import (
+	"sort"
+	"time"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/garden/server/handles"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generator", func() {
+	var scheme garden.HandleScheme
+
+	Describe("New", func() {
+		It("defaults the charset", func() {
+			generator, err := handles.New(garden.HandleScheme{Length: 4})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(generator.Scheme().Charset).To(Equal(handles.DefaultCharset))
+		})
+
+		It("rejects schemes without a positive length", func() {
+			_, err := handles.New(garden.HandleScheme{Prefix: "app-"})
+			Expect(err).To(MatchError("handle scheme: length 0 is not positive"))
+		})
+
+		It("rejects charsets which repeat a character", func() {
+			_, err := handles.New(garden.HandleScheme{Length: 4, Charset: "abca"})
+			Expect(err).To(MatchError(`handle scheme: charset repeats 'a'`))
+		})
+
+		It("rejects UUID schemes with a length or charset", func() {
+			_, err := handles.New(garden.HandleScheme{UUIDv7: true, Length: 4})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("with random characters", func() {
+		BeforeEach(func() {
+			scheme = garden.HandleScheme{Prefix: "app-", Length: 12, Charset: "xyz"}
+		})
+
+		It("generates conforming handles", func() {
+			generator, err := handles.New(scheme)
+			Expect(err).NotTo(HaveOccurred())
+
+			handle, err := generator.Generate()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(handle).To(MatchRegexp(`^app-[xyz]{12}$`))
+			Expect(generator.Conforms(handle)).To(BeTrue())
+		})
+
+		It("does not accept handles of another form", func() {
+			generator, err := handles.New(scheme)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(generator.Conforms("app-xyz")).To(BeFalse())
+			Expect(generator.Conforms("app-xyzxyzxyzxya")).To(BeFalse())
+			Expect(generator.Conforms("web-xyzxyzxyzxyz")).To(BeFalse())
+		})
+	})
+
+	Context("with UUIDs", func() {
+		BeforeEach(func() {
+			scheme = garden.HandleScheme{Prefix: "app-", UUIDv7: true}
+		})
+
+		It("generates conforming version 7 UUIDs", func() {
+			generator, err := handles.New(scheme)
+			Expect(err).NotTo(HaveOccurred())
+
+			handle, err := generator.Generate()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(handle).To(MatchRegexp(`^app-[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`))
+			Expect(generator.Conforms(handle)).To(BeTrue())
+			Expect(generator.Conforms("app-not-a-uuid")).To(BeFalse())
+		})
+
+		It("generates handles which sort in the order they were generated", func() {
+			generator, err := handles.New(scheme)
+			Expect(err).NotTo(HaveOccurred())
+
+			var generated []string
+			for i := 0; i < 3; i++ {
+				handle, err := generator.Generate()
+				Expect(err).NotTo(HaveOccurred())
+				generated = append(generated, handle)
+
+				// the ordering is by millisecond
+				time.Sleep(2 * time.Millisecond)
+			}
+
+			Expect(sort.StringsAreSorted(generated)).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,266 @@
+package server
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/lager"
+)
+
+// healthChecks holds the health of the containers whose specs declared a
+// health check, as found by the server probing them.
+type healthChecks struct {
+	checks map[string]*healthCheck
+	lock   sync.Mutex
+}
+
+type healthCheck struct {
+	status garden.HealthStatus
+	stop   chan struct{}
+}
+
+func newHealthChecks() *healthChecks {
+	return &healthChecks{checks: map[string]*healthCheck{}}
+}
+
+// start marks the container's health as garden.HealthStarting, and returns a
+// channel which is closed when probing it is to stop.
+func (h *healthChecks) start(handle string) <-chan struct{} {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	check := &healthCheck{
+		status: garden.HealthStatus{State: garden.HealthStarting},
+		stop:   make(chan struct{}),
+	}
+
+	h.checks[handle] = check
+	return check.stop
+}
+
+// record updates the container's health with the result of a probe, and
+// returns its state and whether the state changed.
+func (h *healthChecks) record(handle string, healthy bool, output string, failureThreshold int) (string, bool) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	check, found := h.checks[handle]
+	if !found {
+		return "", false
+	}
+
+	status := &check.status
+	status.LastProbedAt = time.Now().UTC()
+	status.LastOutput = output
+
+	previous := status.State
+	if healthy {
+		status.ConsecutiveFailures = 0
+		status.State = garden.HealthHealthy
+	} else {
+		status.ConsecutiveFailures++
+		if status.ConsecutiveFailures >= failureThreshold {
+			status.State = garden.HealthUnhealthy
+		}
+	}
+
+	return status.State, status.State != previous
+}
+
+// status returns the container's health, and whether it has a health check.
+func (h *healthChecks) status(handle string) (garden.HealthStatus, bool) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	check, found := h.checks[handle]
+	if !found {
+		return garden.HealthStatus{}, false
+	}
+
+	return check.status, true
+}
+
+// stop stops probing the container and forgets its health.
+func (h *healthChecks) stop(handle string) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if check, found := h.checks[handle]; found {
+		close(check.stop)
+		delete(h.checks, handle)
+	}
+}
+
+// validateHealthCheck rejects a health check which does not say how to probe
+// the container.
+func validateHealthCheck(check *garden.HealthCheck) error {
+	if check == nil {
+		return nil
+	}
+
+	probes := 0
+	for _, set := range []bool{check.Process != nil, check.TCP != nil, check.HTTP != nil} {
+		if set {
+			probes++
+		}
+	}
+
+	if probes != 1 {
+		return garden.MalformedRequestError{Cause: "health_check: exactly one of process, tcp and http is required"}
+	}
+
+	return nil
+}
+
+// checkHealth probes the container at the health check's interval until stop
+// is closed or the server stops, streaming EventHealthChanged events as its
+// health changes.
+func (s *GardenServer) checkHealth(container garden.Container, check garden.HealthCheck, stop <-chan struct{}) {
+	handle := container.Handle()
+
+	logger := s.logger.Session("health-check", lager.Data{
+		"handle": handle,
+	})
+
+	interval := check.Interval
+	if interval == 0 {
+		interval = garden.DefaultHealthCheckInterval
+	}
+
+	timeout := check.Timeout
+	if timeout == 0 {
+		timeout = garden.DefaultHealthCheckTimeout
+	}
+
+	failureThreshold := check.FailureThreshold
+	if failureThreshold == 0 {
+		failureThreshold = garden.DefaultHealthCheckFailureThreshold
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return
+		case <-s.stopping:
+			return
+		}
+
+		healthy, output := s.probe(logger, container, check, timeout)
+
+		state, changed := s.healthChecks.record(handle, healthy, output, failureThreshold)
+		if changed {
+			logger.Info("health-changed", lager.Data{"health": state})
+			s.publishEvent(garden.Event{Kind: garden.EventHealthChanged, Handle: handle, Health: state})
+		}
+	}
+}
+
+// probe runs the health check once, returning whether the container is
+// healthy and the probe's output.
+func (s *GardenServer) probe(logger lager.Logger, container garden.Container, check garden.HealthCheck, timeout time.Duration) (bool, string) {
+	if check.Process != nil {
+		return s.probeProcess(logger, container, *check.Process, timeout)
+	}
+
+	info, err := container.Info()
+	if err != nil {
+		return false, err.Error()
+	}
+
+	if check.TCP != nil {
+		address := net.JoinHostPort(info.ContainerIP, strconv.FormatUint(uint64(check.TCP.Port), 10))
+
+		conn, err := net.DialTimeout("tcp", address, timeout)
+		if err != nil {
+			return false, truncateProbeOutput(err.Error())
+		}
+
+		conn.Close()
+		return true, ""
+	}
+
+	address := net.JoinHostPort(info.ContainerIP, strconv.FormatUint(uint64(check.HTTP.Port), 10))
+
+	client := &http.Client{Timeout: timeout}
+
+	response, err := client.Get("http://" + address + check.HTTP.Path)
+	if err != nil {
+		return false, truncateProbeOutput(err.Error())
+	}
+
+	response.Body.Close()
+
+	healthy := response.StatusCode >= 200 && response.StatusCode < 400
+	return healthy, response.Status
+}
+
+func (s *GardenServer) probeProcess(logger lager.Logger, container garden.Container, spec garden.ProcessSpec, timeout time.Duration) (bool, string) {
+	if spec.Timeout == 0 {
+		spec.Timeout = timeout
+	}
+
+	output := &probeOutput{}
+
+	process, err := container.Run(spec, garden.ProcessIO{Stdout: output, Stderr: output})
+	if err != nil {
+		return false, truncateProbeOutput(err.Error())
+	}
+
+	s.processTimeouts.start(logger, container.Handle(), process, spec.Timeout)
+
+	status, timedOut, err := s.waitForExit(logger, container.Handle(), process)
+	switch {
+	case err != nil:
+		return false, truncateProbeOutput(err.Error())
+	case timedOut:
+		return false, truncateProbeOutput(fmt.Sprintf("timed out after %s: %s", spec.Timeout, output.String()))
+	default:
+		return status == 0, output.String()
+	}
+}
+
+// probeOutput keeps the first garden.MaxHealthCheckOutputLength bytes written
+// to it and discards the rest.
+type probeOutput struct {
+	buf  []byte
+	lock sync.Mutex
+}
+
+func (o *probeOutput) Write(p []byte) (int, error) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	if remaining := garden.MaxHealthCheckOutputLength - len(o.buf); remaining > 0 {
+		if len(p) > remaining {
+			o.buf = append(o.buf, p[:remaining]...)
+		} else {
+			o.buf = append(o.buf, p...)
+		}
+	}
+
+	return len(p), nil
+}
+
+func (o *probeOutput) String() string {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	return string(o.buf)
+}
+
+func truncateProbeOutput(output string) string {
+	if len(output) > garden.MaxHealthCheckOutputLength {
+		return output[:garden.MaxHealthCheckOutputLength]
+	}
+
+	return output
+}
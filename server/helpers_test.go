@@ -1,5 +1,130 @@
 package server_test
 
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"sync"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"code.cloudfoundry.org/garden/server/audit"
+)
+
 func uint64ptr(n uint64) *uint64 {
 	return &n
 }
+
+func uint32ptr(n uint32) *uint32 {
+	return &n
+}
+
+func intptr(n int) *int {
+	return &n
+}
+
+// testCA issues certificates for testing TLS.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA() *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Ω(err).ShouldNot(HaveOccurred())
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	Ω(err).ShouldNot(HaveOccurred())
+
+	cert, err := x509.ParseCertificate(der)
+	Ω(err).ShouldNot(HaveOccurred())
+
+	return &testCA{
+		cert: cert,
+		key:  key,
+		pem:  pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}
+}
+
+// issue returns the PEM encoded certificate and key of a certificate for the
+// common name, valid for 127.0.0.1.
+func (ca *testCA) issue(commonName string) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Ω(err).ShouldNot(HaveOccurred())
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	Ω(err).ShouldNot(HaveOccurred())
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	Ω(err).ShouldNot(HaveOccurred())
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+// clientTLSConfig returns a TLS configuration for a client trusting the CA and
+// presenting a certificate it issued for the common name, if any.
+func (ca *testCA) clientTLSConfig(commonName string) *tls.Config {
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+
+	config := &tls.Config{RootCAs: roots}
+	if commonName != "" {
+		certPEM, keyPEM := ca.issue(commonName)
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config
+}
+
+// recordingSink is an audit.Sink which keeps the entries it is given.
+type recordingSink struct {
+	entries []audit.Entry
+	lock    sync.Mutex
+}
+
+func (s *recordingSink) Record(entry audit.Entry) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.entries = append(s.entries, entry)
+	return nil
+}
+
+func (s *recordingSink) Entries() []audit.Entry {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return append([]audit.Entry{}, s.entries...)
+}
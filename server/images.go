@@ -0,0 +1,105 @@
+package server
+
+import (
+	"net/http"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/garden/routes"
+	"code.cloudfoundry.org/garden/transport"
+	"code.cloudfoundry.org/lager"
+)
+
+// imageManager returns the backend as a garden.ImageManager, failing with
+// garden.ErrImagesUnsupported if it does not manage images.
+func (s *GardenServer) imageManager() (garden.ImageManager, error) {
+	manager, ok := s.backend.(garden.ImageManager)
+	if !ok {
+		return nil, garden.ErrImagesUnsupported
+	}
+
+	return manager, nil
+}
+
+func (s *GardenServer) handleWarmImage(w http.ResponseWriter, r *http.Request) {
+	var request transport.WarmImageRequest
+	if !s.readRequest(&request, w, r) {
+		return
+	}
+
+	data := lager.Data{"uri": request.URI}
+	if request.RegistryCredentials != nil {
+		data["registry-username"] = request.RegistryCredentials.Username
+	}
+
+	hLog := s.logger.Session("warm-image", data)
+
+	manager, err := s.imageManager()
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	hLog.Debug("warming")
+
+	image, err := manager.WarmImage(request.URI, request.RegistryCredentials)
+	if err != nil {
+		if request.RegistryCredentials != nil {
+			err = request.RegistryCredentials.Redactor().Error(err)
+		}
+
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	hLog.Info("warmed", lager.Data{"size-in-bytes": image.SizeInBytes})
+
+	s.writeResponse(w, image)
+}
+
+func (s *GardenServer) handleListImages(w http.ResponseWriter, r *http.Request) {
+	hLog := s.logger.Session("list-images")
+
+	manager, err := s.imageManager()
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	images, err := manager.Images()
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	if images == nil {
+		images = []garden.CachedImage{}
+	}
+
+	s.writeResponse(w, images)
+}
+
+func (s *GardenServer) handleRemoveImage(w http.ResponseWriter, r *http.Request) {
+	uri := r.URL.Query().Get(routes.ImageURIParam)
+
+	hLog := s.logger.Session("remove-image", lager.Data{"uri": uri})
+
+	if uri == "" {
+		s.writeError(w, garden.MalformedRequestError{Cause: "uri: is required"}, hLog)
+		return
+	}
+
+	manager, err := s.imageManager()
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	if err := manager.RemoveImage(uri); err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	hLog.Info("removed")
+
+	s.writeSuccess(w)
+}
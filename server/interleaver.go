@@ -0,0 +1,103 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// maxPartialLineLength is the most of an unterminated line buffered for a
+// stream, matching the size of a pipe. Longer lines are written out in pieces
+// of this length, so that a process writing no newlines cannot grow the
+// buffer without bound.
+const maxPartialLineLength = 64 * 1024
+
+// interleaver merges the output streams of a process into one, prefixing each
+// line with the time it was written and the name of the stream it came from.
+type interleaver struct {
+	out io.Writer
+
+	partial map[string][]byte
+	lock    *sync.Mutex
+}
+
+func newInterleaver(out io.Writer) *interleaver {
+	return &interleaver{
+		out:     out,
+		partial: map[string][]byte{},
+		lock:    new(sync.Mutex),
+	}
+}
+
+func (i *interleaver) Writer(stream string) io.Writer {
+	return &interleavedWriter{interleaver: i, stream: stream}
+}
+
+// Flush writes out any lines that have not yet been terminated by a newline.
+func (i *interleaver) Flush() {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	for stream := range i.partial {
+		i.flushStream(stream)
+	}
+}
+
+func (i *interleaver) flushStream(stream string) {
+	if line := i.partial[stream]; len(line) > 0 {
+		i.writeLine(stream, line)
+	}
+
+	delete(i.partial, stream)
+}
+
+func (i *interleaver) write(stream string, data []byte) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	buf := append(i.partial[stream], data...)
+
+	for {
+		newline := bytes.IndexByte(buf, '\n')
+		if newline == -1 {
+			break
+		}
+
+		i.writeLine(stream, buf[:newline])
+		buf = buf[newline+1:]
+	}
+
+	for len(buf) >= maxPartialLineLength {
+		i.writeLine(stream, buf[:maxPartialLineLength])
+		buf = buf[maxPartialLineLength:]
+	}
+
+	i.partial[stream] = append([]byte(nil), buf...)
+}
+
+func (i *interleaver) writeLine(stream string, line []byte) {
+	fmt.Fprintf(i.out, "%s %s %s\n", time.Now().UTC().Format(time.RFC3339Nano), stream, line)
+}
+
+type interleavedWriter struct {
+	interleaver *interleaver
+	stream      string
+}
+
+func (w *interleavedWriter) Write(d []byte) (int, error) {
+	w.interleaver.write(w.stream, d)
+	return len(d), nil
+}
+
+// Close writes out the stream's unterminated line, if any, for backends which
+// close a process's output streams once it has finished writing to them.
+func (w *interleavedWriter) Close() error {
+	w.interleaver.lock.Lock()
+	defer w.interleaver.lock.Unlock()
+
+	w.interleaver.flushStream(w.stream)
+
+	return nil
+}
@@ -0,0 +1,157 @@
+package server
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/garden/routes"
+)
+
+// listOptions extracts the list options from the query, removing them so
+// that the remaining values may be used as property filters.
+func listOptions(query url.Values) (garden.ListOptions, error) {
+	var opts garden.ListOptions
+
+	opts.OrderByCreation = query.Get(routes.ListOrderParam) == routes.ListOrderByCreation
+	query.Del(routes.ListOrderParam)
+
+	for param, dst := range map[string]*time.Time{
+		routes.ListCreatedBeforeParam: &opts.CreatedBefore,
+		routes.ListCreatedAfterParam:  &opts.CreatedAfter,
+	} {
+		value := query.Get(param)
+		query.Del(param)
+
+		if value == "" {
+			continue
+		}
+
+		t, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return garden.ListOptions{}, garden.MalformedRequestError{
+				Cause: fmt.Sprintf("%s: %q is not an RFC 3339 time", param, value),
+			}
+		}
+
+		*dst = t
+	}
+
+	for param, values := range query {
+		if !strings.HasPrefix(param, routes.ListFilterParamPrefix) {
+			continue
+		}
+
+		query.Del(param)
+
+		filter, err := propertyFilter(strings.TrimPrefix(param, routes.ListFilterParamPrefix), values)
+		if err != nil {
+			return garden.ListOptions{}, err
+		}
+
+		opts.PropertyFilters = append(opts.PropertyFilters, filter)
+	}
+
+	return opts, nil
+}
+
+// propertyFilter parses a property filter given as "<operator>.<name>", with
+// the operands as the values.
+func propertyFilter(param string, values []string) (garden.PropertyFilter, error) {
+	parts := strings.SplitN(param, ".", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return garden.PropertyFilter{}, garden.MalformedRequestError{
+			Cause: fmt.Sprintf("%s%s: property filter has no property name", routes.ListFilterParamPrefix, param),
+		}
+	}
+
+	filter := garden.PropertyFilter{
+		Name:     parts[1],
+		Operator: garden.PropertyOperator(strings.TrimPrefix(parts[0], routes.ListFilterNegation)),
+		Negate:   strings.HasPrefix(parts[0], routes.ListFilterNegation),
+	}
+
+	switch filter.Operator {
+	case garden.PropertyExists:
+	case garden.PropertyHasPrefix, garden.PropertyIn:
+		filter.Values = values
+	default:
+		return garden.PropertyFilter{}, garden.MalformedRequestError{
+			Cause: fmt.Sprintf("%s%s: unknown property operator %q", routes.ListFilterParamPrefix, param, parts[0]),
+		}
+	}
+
+	return filter, nil
+}
+
+// filteredByProperties returns those containers whose properties match all
+// of the filters. Containers whose properties cannot be retrieved, e.g.
+// because they have since been destroyed, are left out.
+func filteredByProperties(containers []garden.Container, filters []garden.PropertyFilter) []garden.Container {
+	filtered := []garden.Container{}
+	for _, container := range containers {
+		properties, err := container.Properties()
+		if err != nil {
+			continue
+		}
+
+		matched := true
+		for _, filter := range filters {
+			matched = matched && filter.Matches(properties)
+		}
+
+		if matched {
+			filtered = append(filtered, container)
+		}
+	}
+
+	return filtered
+}
+
+type createdContainer struct {
+	container garden.Container
+	createdAt time.Time
+}
+
+type byCreation []createdContainer
+
+func (c byCreation) Len() int           { return len(c) }
+func (c byCreation) Swap(i, j int)      { c[i], c[j] = c[j], c[i] }
+func (c byCreation) Less(i, j int) bool { return c[i].createdAt.Before(c[j].createdAt) }
+
+// orderedByCreation filters and orders the containers by the time recorded
+// in their CreatedAtProperty. Containers with no recorded creation time are
+// treated as created at the zero time.
+func orderedByCreation(containers []garden.Container, opts garden.ListOptions) []garden.Container {
+	filtered := byCreation{}
+	for _, container := range containers {
+		var createdAt time.Time
+		if value, err := container.Property(garden.CreatedAtProperty); err == nil {
+			createdAt, _ = time.Parse(time.RFC3339Nano, value)
+		}
+
+		if !opts.CreatedBefore.IsZero() && (createdAt.IsZero() || !createdAt.Before(opts.CreatedBefore)) {
+			continue
+		}
+
+		if !opts.CreatedAfter.IsZero() && (createdAt.IsZero() || !createdAt.After(opts.CreatedAfter)) {
+			continue
+		}
+
+		filtered = append(filtered, createdContainer{container, createdAt})
+	}
+
+	if opts.OrderByCreation {
+		sort.Stable(filtered)
+	}
+
+	result := make([]garden.Container, len(filtered))
+	for i, c := range filtered {
+		result[i] = c.container
+	}
+
+	return result
+}
@@ -0,0 +1,130 @@
+package server
+
+import (
+	"sync"
+
+	"code.cloudfoundry.org/garden"
+)
+
+// DefaultMetricsThresholds are how far metrics must move to be reported by a
+// differential BulkMetrics request unless configured otherwise.
+var DefaultMetricsThresholds = garden.MetricsThresholds{
+	MemoryBytes:    1024 * 1024,
+	CPUNanoseconds: uint64(1e9),
+	DiskBytes:      1024 * 1024,
+	NetworkBytes:   1024 * 1024,
+}
+
+// DefaultMetricsDeltaTokens is how many tokens of differential BulkMetrics
+// requests are remembered unless configured otherwise. A token is forgotten
+// once a request gives it and is handed the next one, and otherwise the
+// oldest are forgotten first. Requests with forgotten tokens receive every
+// entry.
+const DefaultMetricsDeltaTokens = 1024
+
+// metricsBaselines remembers, for each token handed out, the entries last
+// reported to its holder, against which the next request's entries are
+// compared.
+type metricsBaselines struct {
+	thresholds garden.MetricsThresholds
+	maxTokens  int
+
+	baselines map[string]map[string]garden.ContainerMetricsEntry
+	order     []string
+	lock      sync.Mutex
+}
+
+func newMetricsBaselines(thresholds garden.MetricsThresholds, maxTokens int) *metricsBaselines {
+	return &metricsBaselines{
+		thresholds: thresholds,
+		maxTokens:  maxTokens,
+		baselines:  map[string]map[string]garden.ContainerMetricsEntry{},
+	}
+}
+
+// delta returns the entries which changed since the baseline of the given
+// token, and remembers a new baseline under a new token in place of it.
+func (m *metricsBaselines) delta(since string, entries map[string]garden.ContainerMetricsEntry) (garden.BulkMetricsDelta, error) {
+	token, err := newRandomID()
+	if err != nil {
+		return garden.BulkMetricsDelta{}, err
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	previous, found := m.baselines[since]
+
+	delta := garden.BulkMetricsDelta{
+		Token:   token,
+		Full:    !found,
+		Entries: map[string]garden.ContainerMetricsEntry{},
+	}
+
+	baseline := make(map[string]garden.ContainerMetricsEntry, len(entries))
+	for handle, entry := range entries {
+		last, reported := previous[handle]
+		if !reported || m.changed(last, entry) {
+			delta.Entries[handle] = entry
+			last = entry
+		}
+
+		baseline[handle] = last
+	}
+
+	if found {
+		m.forget(since)
+	}
+
+	m.remember(token, baseline)
+
+	return delta, nil
+}
+
+func (m *metricsBaselines) forget(token string) {
+	delete(m.baselines, token)
+
+	for i, remembered := range m.order {
+		if remembered == token {
+			m.order = append(m.order[:i], m.order[i+1:]...)
+			break
+		}
+	}
+}
+
+func (m *metricsBaselines) remember(token string, baseline map[string]garden.ContainerMetricsEntry) {
+	m.baselines[token] = baseline
+	m.order = append(m.order, token)
+
+	for len(m.order) > m.maxTokens {
+		delete(m.baselines, m.order[0])
+		m.order = m.order[1:]
+	}
+}
+
+func (m *metricsBaselines) changed(last, current garden.ContainerMetricsEntry) bool {
+	if last.Tombstone != current.Tombstone || (last.Err == nil) != (current.Err == nil) {
+		return true
+	}
+
+	if current.Err != nil {
+		return last.Err.Error() != current.Err.Error()
+	}
+
+	before, after := last.Metrics, current.Metrics
+	return beyond(before.MemoryStat.TotalUsageTowardLimit, after.MemoryStat.TotalUsageTowardLimit, m.thresholds.MemoryBytes) ||
+		beyond(before.CPUStat.Usage, after.CPUStat.Usage, m.thresholds.CPUNanoseconds) ||
+		beyond(before.DiskStat.TotalBytesUsed, after.DiskStat.TotalBytesUsed, m.thresholds.DiskBytes) ||
+		beyond(before.NetworkStat.RxBytes, after.NetworkStat.RxBytes, m.thresholds.NetworkBytes) ||
+		beyond(before.NetworkStat.TxBytes, after.NetworkStat.TxBytes, m.thresholds.NetworkBytes)
+}
+
+// beyond reports whether the value moved from before to after by more than
+// the threshold.
+func beyond(before, after, threshold uint64) bool {
+	if before > after {
+		return before-after > threshold
+	}
+
+	return after-before > threshold
+}
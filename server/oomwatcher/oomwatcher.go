@@ -0,0 +1,105 @@
+package oomwatcher
+
+import (
+	"time"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/lager"
+)
+
+// Watcher periodically compares the OOMCount in containers' info with that
+// seen by its previous scan, and publishes a garden.EventOOM for each
+// container whose count has grown. Containers which already had OOMs when the
+// watcher started are not reported until they run out of memory again.
+type Watcher struct {
+	backend  garden.Backend
+	interval time.Duration
+	publish  func(garden.Event)
+	logger   lager.Logger
+
+	counts map[string]int
+	stop   chan struct{}
+}
+
+func New(backend garden.Backend, interval time.Duration, publish func(garden.Event), logger lager.Logger) *Watcher {
+	return &Watcher{
+		backend:  backend,
+		interval: interval,
+		publish:  publish,
+		logger:   logger.Session("oom-watcher"),
+
+		stop: make(chan struct{}),
+	}
+}
+
+func (w *Watcher) Start() {
+	go func() {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		w.scan()
+
+		for {
+			select {
+			case <-ticker.C:
+				w.scan()
+			case <-w.stop:
+				return
+			}
+		}
+	}()
+}
+
+func (w *Watcher) Stop() {
+	close(w.stop)
+}
+
+func (w *Watcher) scan() {
+	containers, err := w.backend.Containers(nil)
+	if err != nil {
+		w.logger.Error("failed-to-list-containers", err)
+		return
+	}
+
+	handles := make([]string, len(containers))
+	for i, container := range containers {
+		handles[i] = container.Handle()
+	}
+
+	infos, err := w.backend.BulkInfo(handles)
+	if err != nil {
+		w.logger.Error("failed-to-get-info", err)
+		return
+	}
+
+	counts := map[string]int{}
+	for handle, entry := range infos {
+		if entry.Err != nil {
+			if previous, found := w.counts[handle]; found {
+				counts[handle] = previous
+			}
+
+			continue
+		}
+
+		counts[handle] = entry.Info.OOMCount
+
+		// on the first scan, only record the counts
+		if w.counts == nil || entry.Info.OOMCount <= w.counts[handle] {
+			continue
+		}
+
+		w.logger.Info("oom", lager.Data{
+			"handle":    handle,
+			"oom-count": entry.Info.OOMCount,
+		})
+
+		w.publish(garden.Event{
+			Kind:   garden.EventOOM,
+			Handle: handle,
+			Time:   entry.Info.LastOOM,
+		})
+	}
+
+	w.counts = counts
+}
@@ -0,0 +1,13 @@
+package oomwatcher_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestOOMWatcher(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "OOM Watcher Suite")
+}
@@ -0,0 +1,115 @@
+package oomwatcher_test
+
+import (
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"code.cloudfoundry.org/garden"
+	fakes "code.cloudfoundry.org/garden/gardenfakes"
+	"code.cloudfoundry.org/garden/server/oomwatcher"
+)
+
+var _ = Describe("Watcher", func() {
+	var (
+		backend   *fakes.FakeBackend
+		infos     map[string]garden.ContainerInfoEntry
+		lock      *sync.Mutex
+		published chan garden.Event
+		w         *oomwatcher.Watcher
+	)
+
+	setOOMs := func(handle string, count int, last time.Time) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		infos[handle] = garden.ContainerInfoEntry{
+			Info: garden.ContainerInfo{OOMCount: count, LastOOM: last},
+		}
+	}
+
+	BeforeEach(func() {
+		// the stubs use locals so that a watcher still scanning after the
+		// previous test stopped it cannot see this test's state
+		current, currentLock := map[string]garden.ContainerInfoEntry{}, new(sync.Mutex)
+		infos, lock = current, currentLock
+
+		backend = new(fakes.FakeBackend)
+		backend.ContainersStub = func(garden.Properties) ([]garden.Container, error) {
+			currentLock.Lock()
+			defer currentLock.Unlock()
+
+			containers := []garden.Container{}
+			for handle := range current {
+				container := new(fakes.FakeContainer)
+				container.HandleReturns(handle)
+				containers = append(containers, container)
+			}
+
+			return containers, nil
+		}
+		backend.BulkInfoStub = func(handles []string) (map[string]garden.ContainerInfoEntry, error) {
+			currentLock.Lock()
+			defer currentLock.Unlock()
+
+			result := map[string]garden.ContainerInfoEntry{}
+			for _, handle := range handles {
+				result[handle] = current[handle]
+			}
+
+			return result, nil
+		}
+
+		events := make(chan garden.Event, 10)
+		published = events
+
+		w = oomwatcher.New(backend, 10*time.Millisecond, func(event garden.Event) {
+			events <- event
+		}, lagertest.NewTestLogger("test"))
+	})
+
+	AfterEach(func() {
+		w.Stop()
+	})
+
+	It("publishes an event when a container's oom count grows", func() {
+		setOOMs("some-handle", 0, time.Time{})
+
+		w.Start()
+		Eventually(backend.BulkInfoCallCount).Should(BeNumerically(">=", 1))
+
+		lastOOM := time.Now().Add(-time.Second)
+		setOOMs("some-handle", 1, lastOOM)
+
+		var event garden.Event
+		Eventually(published).Should(Receive(&event))
+		Ω(event.Kind).Should(Equal(garden.EventOOM))
+		Ω(event.Handle).Should(Equal("some-handle"))
+		Ω(event.Time).Should(BeTemporally("==", lastOOM))
+		Consistently(published).ShouldNot(Receive())
+	})
+
+	It("publishes an event for a container which runs out of memory before it is first scanned", func() {
+		setOOMs("some-handle", 0, time.Time{})
+
+		w.Start()
+		Eventually(backend.BulkInfoCallCount).Should(BeNumerically(">=", 1))
+
+		setOOMs("new-handle", 1, time.Now())
+
+		var event garden.Event
+		Eventually(published).Should(Receive(&event))
+		Ω(event.Handle).Should(Equal("new-handle"))
+	})
+
+	It("does not publish events for ooms which happened before it started", func() {
+		setOOMs("some-handle", 3, time.Now().Add(-time.Hour))
+
+		w.Start()
+
+		Consistently(published).ShouldNot(Receive())
+	})
+})
@@ -0,0 +1,166 @@
+package server
+
+import (
+	"fmt"
+	"sync"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/lager"
+)
+
+// PortRange is an inclusive range of host ports.
+type PortRange struct {
+	First uint32
+	Last  uint32
+}
+
+func (r PortRange) Contains(port uint32) bool {
+	return port >= r.First && port <= r.Last
+}
+
+// hostPortInRange checks that the requested host port is in the range of the
+// container's namespace, or in no range if the namespace has none. If no port
+// was requested by a container with a range, the first free port of the range
+// is chosen and reserved until the returned release is called, by which time
+// it should have been mapped.
+func (s *GardenServer) hostPortInRange(container garden.Container, requested uint32) (uint32, func(), error) {
+	namespace, err := container.Property(garden.NamespaceProperty)
+	if err != nil {
+		namespace = ""
+	}
+
+	portRange, found := s.portRanges[namespace]
+	if !found {
+		for name, other := range s.portRanges {
+			if other.Contains(requested) {
+				return 0, nil, garden.PortUnavailableError{
+					HostPort: requested,
+					Cause:    fmt.Sprintf("host port %d is reserved for namespace '%s'", requested, name),
+				}
+			}
+		}
+
+		return requested, func() {}, nil
+	}
+
+	if requested != 0 {
+		if !portRange.Contains(requested) {
+			return 0, nil, garden.PortUnavailableError{
+				HostPort: requested,
+				Cause:    fmt.Sprintf("host port %d is outside the range of namespace '%s'", requested, namespace),
+			}
+		}
+
+		return requested, func() {}, nil
+	}
+
+	port, err := s.usedPorts.reserveFree(portRange, func(port uint32) bool {
+		_, quarantined := s.ports.Until(port)
+		return quarantined
+	}, func() ([]garden.PortAllocation, error) {
+		return s.portAllocations(s.logger.Session("port-ranges"))
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if port == 0 {
+		return 0, nil, garden.PortUnavailableError{
+			Cause: fmt.Sprintf("no free host ports in the range of namespace '%s'", namespace),
+		}
+	}
+
+	s.logger.Debug("chose-port-from-range", lager.Data{
+		"handle":    container.Handle(),
+		"namespace": namespace,
+		"host-port": port,
+	})
+
+	return port, func() { s.usedPorts.unreserve(port) }, nil
+}
+
+// usedPorts caches the host ports mapped to containers, so that a free port
+// in a range can be chosen without getting the info of every container. It is
+// loaded from the backend when first needed, and then kept up to date as the
+// server maps and removes ports and destroys containers.
+type usedPorts struct {
+	lock     sync.Mutex
+	loaded   bool
+	mapped   map[uint32]string
+	reserved map[uint32]bool
+}
+
+func newUsedPorts() *usedPorts {
+	return &usedPorts{
+		mapped:   map[uint32]string{},
+		reserved: map[uint32]bool{},
+	}
+}
+
+// reserveFree reserves the first port of the range which is neither mapped,
+// reserved nor skipped, loading the mapped ports if they have not been yet.
+// It returns 0 if every port of the range is taken.
+func (u *usedPorts) reserveFree(portRange PortRange, skip func(uint32) bool, load func() ([]garden.PortAllocation, error)) (uint32, error) {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+
+	if !u.loaded {
+		allocations, err := load()
+		if err != nil {
+			return 0, err
+		}
+
+		for _, allocation := range allocations {
+			u.mapped[allocation.HostPort] = allocation.Handle
+		}
+
+		u.loaded = true
+	}
+
+	for port := uint64(portRange.First); port <= uint64(portRange.Last); port++ {
+		if _, mapped := u.mapped[uint32(port)]; port == 0 || mapped || u.reserved[uint32(port)] || skip(uint32(port)) {
+			continue
+		}
+
+		u.reserved[uint32(port)] = true
+		return uint32(port), nil
+	}
+
+	return 0, nil
+}
+
+func (u *usedPorts) unreserve(port uint32) {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+
+	delete(u.reserved, port)
+}
+
+// add records that the port is mapped to the container. Until the cache is
+// loaded there is nothing to do, as loading it finds the port.
+func (u *usedPorts) add(handle string, port uint32) {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+
+	if u.loaded && port != 0 {
+		u.mapped[port] = handle
+	}
+}
+
+func (u *usedPorts) remove(port uint32) {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+
+	delete(u.mapped, port)
+}
+
+func (u *usedPorts) forgetContainer(handle string) {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+
+	for port, mappedTo := range u.mapped {
+		if mappedTo == handle {
+			delete(u.mapped, port)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+// Package pressure samples indicators of how heavily loaded the server's
+// machine is from procfs and the filesystem.
+package pressure
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/garden"
+)
+
+// DefaultProcRoot is where procfs is mounted.
+const DefaultProcRoot = "/proc"
+
+// Sampler samples the pressure on the machine.
+type Sampler struct {
+	// ProcRoot is where procfs is mounted, DefaultProcRoot if empty.
+	ProcRoot string
+
+	// GraphPath is a path on the volume holding container images. The graph
+	// disk is not sampled if it is empty.
+	GraphPath string
+}
+
+// Sample returns the current pressure on the machine. Indicators which cannot
+// be read, e.g. pressure stall information on kernels without it, are
+// omitted rather than failing the sample.
+func (s Sampler) Sample() garden.CellPressure {
+	var pressure garden.CellPressure
+
+	pressure.CPU, _ = readPressureStall(s.proc("pressure", "cpu"))
+	pressure.Memory, _ = readPressureStall(s.proc("pressure", "memory"))
+	pressure.IO, _ = readPressureStall(s.proc("pressure", "io"))
+
+	if loadAverage, err := readLoadAverage(s.proc("loadavg")); err == nil {
+		pressure.LoadAverage = &loadAverage
+	}
+
+	if s.GraphPath != "" {
+		if space, err := diskSpace(s.GraphPath); err == nil {
+			pressure.GraphDisk = &space
+		}
+	}
+
+	if usage, err := s.fileDescriptors(); err == nil {
+		pressure.FileDescriptors = &usage
+	}
+
+	return pressure
+}
+
+func (s Sampler) proc(elem ...string) string {
+	root := s.ProcRoot
+	if root == "" {
+		root = DefaultProcRoot
+	}
+
+	return filepath.Join(append([]string{root}, elem...)...)
+}
+
+func (s Sampler) fileDescriptors() (garden.FileDescriptorUsage, error) {
+	fds, err := ioutil.ReadDir(s.proc("self", "fd"))
+	if err != nil {
+		return garden.FileDescriptorUsage{}, err
+	}
+
+	limit, err := openFileLimit()
+	if err != nil {
+		return garden.FileDescriptorUsage{}, err
+	}
+
+	return garden.FileDescriptorUsage{Open: uint64(len(fds)), Limit: limit}, nil
+}
+
+// readPressureStall parses a pressure file such as /proc/pressure/memory:
+//
+//	some avg10=0.00 avg60=0.00 avg300=0.00 total=0
+//	full avg10=0.00 avg60=0.00 avg300=0.00 total=0
+func readPressureStall(path string) (*garden.PressureStall, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var stall garden.PressureStall
+	var some bool
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		averages, err := parsePressureAverages(fields[1:])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", path, err)
+		}
+
+		switch fields[0] {
+		case "some":
+			stall.Some = averages
+			some = true
+		case "full":
+			stall.Full = &averages
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if !some {
+		return nil, fmt.Errorf("%s: no some line", path)
+	}
+
+	return &stall, nil
+}
+
+func parsePressureAverages(fields []string) (garden.PressureAverages, error) {
+	var averages garden.PressureAverages
+
+	for _, field := range fields {
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) != 2 {
+			return garden.PressureAverages{}, fmt.Errorf("malformed field %q", field)
+		}
+
+		var err error
+		switch kv[0] {
+		case "avg10":
+			averages.Avg10, err = strconv.ParseFloat(kv[1], 64)
+		case "avg60":
+			averages.Avg60, err = strconv.ParseFloat(kv[1], 64)
+		case "avg300":
+			averages.Avg300, err = strconv.ParseFloat(kv[1], 64)
+		case "total":
+			var micros uint64
+			micros, err = strconv.ParseUint(kv[1], 10, 64)
+			averages.Total = time.Duration(micros) * time.Microsecond
+		}
+
+		if err != nil {
+			return garden.PressureAverages{}, fmt.Errorf("malformed field %q", field)
+		}
+	}
+
+	return averages, nil
+}
+
+// readLoadAverage parses /proc/loadavg, e.g. "0.52 0.58 0.59 1/467 12345".
+func readLoadAverage(path string) ([3]float64, error) {
+	var loadAverage [3]float64
+
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return loadAverage, err
+	}
+
+	fields := strings.Fields(string(contents))
+	if len(fields) < 3 {
+		return loadAverage, fmt.Errorf("%s: malformed load average %q", path, contents)
+	}
+
+	for i := range loadAverage {
+		loadAverage[i], err = strconv.ParseFloat(fields[i], 64)
+		if err != nil {
+			return loadAverage, fmt.Errorf("%s: malformed load average %q", path, contents)
+		}
+	}
+
+	return loadAverage, nil
+}
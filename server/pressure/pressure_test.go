@@ -0,0 +1,92 @@
+package pressure_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/garden/server/pressure"
+)
+
+var _ = Describe("Sampler", func() {
+	var (
+		procRoot string
+		sampler  pressure.Sampler
+	)
+
+	writeProc := func(path, contents string) {
+		path = filepath.Join(procRoot, path)
+		Expect(os.MkdirAll(filepath.Dir(path), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(path, []byte(contents), 0644)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		var err error
+		procRoot, err = ioutil.TempDir("", "pressure")
+		Expect(err).NotTo(HaveOccurred())
+
+		sampler = pressure.Sampler{ProcRoot: procRoot}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(procRoot)
+	})
+
+	It("samples pressure stall information", func() {
+		writeProc("pressure/cpu", "some avg10=1.50 avg60=2.25 avg300=0.75 total=1234\n")
+		writeProc("pressure/memory", "some avg10=0.00 avg60=0.10 avg300=0.20 total=10\nfull avg10=0.00 avg60=0.05 avg300=0.10 total=5\n")
+
+		sample := sampler.Sample()
+
+		Expect(sample.CPU).To(Equal(&garden.PressureStall{
+			Some: garden.PressureAverages{Avg10: 1.5, Avg60: 2.25, Avg300: 0.75, Total: 1234 * time.Microsecond},
+		}))
+		Expect(sample.Memory).To(Equal(&garden.PressureStall{
+			Some: garden.PressureAverages{Avg60: 0.1, Avg300: 0.2, Total: 10 * time.Microsecond},
+			Full: &garden.PressureAverages{Avg60: 0.05, Avg300: 0.1, Total: 5 * time.Microsecond},
+		}))
+		Expect(sample.IO).To(BeNil())
+	})
+
+	It("omits malformed pressure stall information", func() {
+		writeProc("pressure/cpu", "some avg10=banana\n")
+
+		Expect(sampler.Sample().CPU).To(BeNil())
+	})
+
+	It("samples the load average", func() {
+		writeProc("loadavg", "0.52 0.58 0.59 1/467 12345\n")
+
+		Expect(sampler.Sample().LoadAverage).To(Equal(&[3]float64{0.52, 0.58, 0.59}))
+	})
+
+	It("samples the file descriptors of the server", func() {
+		Expect(os.MkdirAll(filepath.Join(procRoot, "self", "fd"), 0755)).To(Succeed())
+		writeProc("self/fd/0", "")
+		writeProc("self/fd/1", "")
+
+		usage := sampler.Sample().FileDescriptors
+		Expect(usage).NotTo(BeNil())
+		Expect(usage.Open).To(Equal(uint64(2)))
+		Expect(usage.Limit).To(BeNumerically(">", 0))
+	})
+
+	It("samples the graph disk when its path is given", func() {
+		Expect(sampler.Sample().GraphDisk).To(BeNil())
+
+		sampler.GraphPath = procRoot
+		space := sampler.Sample().GraphDisk
+		Expect(space).NotTo(BeNil())
+		Expect(space.TotalBytes).To(BeNumerically(">", 0))
+		Expect(space.FreeBytes).To(BeNumerically("<=", space.TotalBytes))
+	})
+
+	It("omits indicators which cannot be read", func() {
+		Expect(sampler.Sample()).To(BeZero())
+	})
+})
@@ -0,0 +1,31 @@
+//go:build !windows
+// +build !windows
+
+package pressure
+
+import (
+	"syscall"
+
+	"code.cloudfoundry.org/garden"
+)
+
+func diskSpace(path string) (garden.DiskSpace, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return garden.DiskSpace{}, err
+	}
+
+	return garden.DiskSpace{
+		FreeBytes:  uint64(stat.Bavail) * uint64(stat.Bsize),
+		TotalBytes: uint64(stat.Blocks) * uint64(stat.Bsize),
+	}, nil
+}
+
+func openFileLimit() (uint64, error) {
+	var limit syscall.Rlimit
+	if err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, &limit); err != nil {
+		return 0, err
+	}
+
+	return uint64(limit.Cur), nil
+}
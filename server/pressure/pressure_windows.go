@@ -0,0 +1,17 @@
+package pressure
+
+import (
+	"errors"
+
+	"code.cloudfoundry.org/garden"
+)
+
+var errUnsupported = errors.New("not supported on windows")
+
+func diskSpace(path string) (garden.DiskSpace, error) {
+	return garden.DiskSpace{}, errUnsupported
+}
+
+func openFileLimit() (uint64, error) {
+	return 0, errUnsupported
+}
@@ -0,0 +1,96 @@
+package server
+
+import (
+	"net/http"
+	"sync"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/lager"
+)
+
+// ProcessEnvAccess is whether clients may retrieve the environments which
+// processes were started with.
+type ProcessEnvAccess int
+
+const (
+	// ProcessEnvDenied refuses to return environments. It is the default.
+	ProcessEnvDenied ProcessEnvAccess = iota
+
+	// ProcessEnvRedacted returns environments with the values of sensitive
+	// variables redacted by the server's redactor.
+	ProcessEnvRedacted
+
+	// ProcessEnvFull returns environments as they were given.
+	ProcessEnvFull
+)
+
+// processEnvs retains the environments of running processes started through
+// the server, so that they may be retrieved for debugging.
+type processEnvs struct {
+	envs map[processKey][]string
+	lock sync.Mutex
+}
+
+type processKey struct {
+	handle    string
+	processID string
+}
+
+func newProcessEnvs() *processEnvs {
+	return &processEnvs{envs: map[processKey][]string{}}
+}
+
+func (p *processEnvs) record(handle, processID string, env []string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.envs[processKey{handle, processID}] = env
+}
+
+func (p *processEnvs) forget(handle, processID string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	delete(p.envs, processKey{handle, processID})
+}
+
+func (p *processEnvs) get(handle, processID string) ([]string, bool) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	env, found := p.envs[processKey{handle, processID}]
+	return env, found
+}
+
+func (s *GardenServer) handleProcessEnv(w http.ResponseWriter, r *http.Request) {
+	handle := r.FormValue(":handle")
+	processID := r.FormValue(":pid")
+
+	hLog := s.logger.Session("process-env", lager.Data{
+		"handle": handle,
+		"id":     processID,
+	})
+
+	if s.processEnvAccess == ProcessEnvDenied {
+		s.writeError(w, garden.NewPermissionDeniedError(handle, "retrieving the environments of processes is not permitted"), hLog)
+		return
+	}
+
+	env, found := s.processEnvs.get(handle, processID)
+	if !found {
+		s.writeError(w, garden.ProcessNotFoundError{Handle: handle, ProcessID: processID}, hLog)
+		return
+	}
+
+	if s.processEnvAccess == ProcessEnvRedacted {
+		env = s.redactor.Env(env)
+	}
+
+	hLog.Info("retrieved", lager.Data{"redacted": s.processEnvAccess == ProcessEnvRedacted})
+
+	if env == nil {
+		env = []string{}
+	}
+
+	s.writeResponse(w, env)
+}
@@ -0,0 +1,177 @@
+package server
+
+import (
+	"net/http"
+	"sync"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/garden/routes"
+)
+
+// processNames maps the names given to processes started through the server
+// to their IDs. A name stays mapped to its process once the process has
+// exited, so that its exit may be retrieved by name, until the name is
+// reused or the container is destroyed.
+type processNames struct {
+	names map[processNameKey]*namedProcess
+	lock  sync.Mutex
+}
+
+type processNameKey struct {
+	handle string
+	name   string
+}
+
+type namedProcess struct {
+	// processID is empty while the process is being started.
+	processID string
+	running   bool
+}
+
+func newProcessNames() *processNames {
+	return &processNames{names: map[processNameKey]*namedProcess{}}
+}
+
+// reserve claims the name for a process about to be started in the
+// container, failing with a garden.ProcessNameTakenError if a running
+// process, or one being started, has it.
+func (p *processNames) reserve(handle, name string) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	key := processNameKey{handle, name}
+	if named, found := p.names[key]; found && named.running {
+		return garden.ProcessNameTakenError{Handle: handle, Name: name}
+	}
+
+	p.names[key] = &namedProcess{running: true}
+	return nil
+}
+
+// started maps the reserved name to the ID of the process which was started.
+func (p *processNames) started(handle, name, processID string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if named, found := p.names[processNameKey{handle, name}]; found {
+		named.processID = processID
+	}
+}
+
+// release gives up a reserved name whose process failed to start.
+func (p *processNames) release(handle, name string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	key := processNameKey{handle, name}
+	if named, found := p.names[key]; found && named.processID == "" {
+		delete(p.names, key)
+	}
+}
+
+// exited frees the name of the process, if it has one, for reuse.
+func (p *processNames) exited(handle, processID string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	for key, named := range p.names {
+		if key.handle == handle && named.processID == processID {
+			named.running = false
+		}
+	}
+}
+
+// forgetContainer forgets the names of the container's processes.
+func (p *processNames) forgetContainer(handle string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	for key := range p.names {
+		if key.handle == handle {
+			delete(p.names, key)
+		}
+	}
+}
+
+// processID returns the ID of the process with the name.
+func (p *processNames) processID(handle, name string) (string, bool) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	named, found := p.names[processNameKey{handle, name}]
+	if !found || named.processID == "" {
+		return "", false
+	}
+
+	return named.processID, true
+}
+
+// name returns the name of the process with the ID, if it has one.
+func (p *processNames) name(handle, processID string) string {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	for key, named := range p.names {
+		if key.handle == handle && named.processID == processID {
+			return key.name
+		}
+	}
+
+	return ""
+}
+
+// requestedProcessID returns the ID of the process given by the request's pid
+// parameter, which names the process rather than giving its ID if the
+// request is by name. Names the server does not know, e.g. of processes
+// started before it was restarted, are looked up in the container's
+// processes, if it is given.
+func (s *GardenServer) requestedProcessID(r *http.Request, container garden.Container) (string, error) {
+	handle := r.FormValue(":handle")
+	processID := r.FormValue(":pid")
+
+	if r.URL.Query().Get(routes.ProcessByNameParam) != "true" {
+		return processID, nil
+	}
+
+	if id, found := s.processNames.processID(handle, processID); found {
+		return id, nil
+	}
+
+	if container != nil {
+		processes, err := container.Processes()
+		if err != nil {
+			return "", err
+		}
+
+		for _, process := range processes {
+			if process.Name == processID && process.State == garden.ProcessStateRunning {
+				return process.ID, nil
+			}
+		}
+	}
+
+	return "", garden.ProcessNotFoundError{Handle: handle, ProcessID: processID}
+}
+
+// runNamed runs the process in the container, giving it the name in its spec,
+// if any, once the name has been reserved.
+func (s *GardenServer) runNamed(container garden.Container, spec garden.ProcessSpec, processIO garden.ProcessIO) (garden.Process, error) {
+	if spec.Name == "" {
+		return container.Run(spec, processIO)
+	}
+
+	handle := container.Handle()
+	if err := s.processNames.reserve(handle, spec.Name); err != nil {
+		return nil, err
+	}
+
+	process, err := container.Run(spec, processIO)
+	if err != nil {
+		s.processNames.release(handle, spec.Name)
+		return nil, err
+	}
+
+	s.processNames.started(handle, spec.Name, process.ID())
+
+	return process, nil
+}
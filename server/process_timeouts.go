@@ -0,0 +1,88 @@
+package server
+
+import (
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/lager"
+)
+
+// processTimeouts kills processes started through the server which run for
+// longer than the Timeout in their ProcessSpec, and remembers which were
+// killed so that their exits may be reported as TimedOut.
+type processTimeouts struct {
+	timeouts map[processKey]*processTimeout
+	lock     sync.Mutex
+}
+
+type processTimeout struct {
+	timer    *time.Timer
+	timedOut bool
+}
+
+func newProcessTimeouts() *processTimeouts {
+	return &processTimeouts{timeouts: map[processKey]*processTimeout{}}
+}
+
+// start kills the process with garden.SignalKill once the timeout has passed,
+// unless it has exited by then.
+func (p *processTimeouts) start(logger lager.Logger, handle string, process garden.Process, timeout time.Duration) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	t := &processTimeout{}
+	t.timer = time.AfterFunc(timeout, func() {
+		p.lock.Lock()
+		t.timedOut = true
+		p.lock.Unlock()
+
+		logger.Info("timed-out", lager.Data{
+			"id":      process.ID(),
+			"timeout": timeout.String(),
+		})
+
+		if err := process.Signal(garden.SignalKill); err != nil {
+			logger.Error("failed-to-kill", err, lager.Data{"id": process.ID()})
+		}
+	})
+
+	p.timeouts[processKey{handle, process.ID()}] = t
+}
+
+// exited stops the timeout of the process, if it has one, and returns whether
+// the process was killed for running for longer than it. The processes which
+// were killed are remembered until their container is forgotten, as each
+// client attached to a process waits for it separately.
+func (p *processTimeouts) exited(handle, processID string) bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	key := processKey{handle, processID}
+
+	t, found := p.timeouts[key]
+	if !found {
+		return false
+	}
+
+	t.timer.Stop()
+	if !t.timedOut {
+		delete(p.timeouts, key)
+	}
+
+	return t.timedOut
+}
+
+// forgetContainer stops the timeouts of the container's processes and forgets
+// which were killed.
+func (p *processTimeouts) forgetContainer(handle string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	for key, t := range p.timeouts {
+		if key.handle == handle {
+			t.timer.Stop()
+			delete(p.timeouts, key)
+		}
+	}
+}
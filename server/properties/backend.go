@@ -0,0 +1,289 @@
+package properties
+
+import (
+	"time"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/lager"
+)
+
+// NewBackend wraps the backend so that the properties of its containers are
+// read from the store. Properties are still written through to the backend's
+// containers, which may act on them, but the store is authoritative once it
+// knows a container.
+//
+// When the backend is started, the store is reconciled with the containers
+// which are still running: those the store does not know are rehydrated with
+// the properties the backend reports for them, and those which no longer
+// exist are forgotten. Clients therefore see consistent properties as soon
+// as the server is serving again after a restart.
+func NewBackend(backend garden.Backend, store Store, logger lager.Logger) garden.Backend {
+	return &storeBackend{
+		Backend: backend,
+		store:   store,
+		logger:  logger.Session("property-store"),
+	}
+}
+
+type storeBackend struct {
+	garden.Backend
+
+	store  Store
+	logger lager.Logger
+}
+
+func (b *storeBackend) Start() error {
+	if err := b.Backend.Start(); err != nil {
+		return err
+	}
+
+	return b.rehydrate()
+}
+
+// Reconciliation reports the reconciliation of the wrapped backend, if it
+// reports one.
+func (b *storeBackend) Reconciliation() garden.Reconciliation {
+	if reporter, ok := b.Backend.(garden.ReconciliationReporter); ok {
+		return reporter.Reconciliation()
+	}
+
+	return garden.Reconciliation{}
+}
+
+// WarmImage, Images and RemoveImage manage the images of the wrapped backend,
+// failing with garden.ErrImagesUnsupported if it does not manage images.
+func (b *storeBackend) WarmImage(uri string, credentials *garden.RegistryCredentials) (garden.CachedImage, error) {
+	if manager, ok := b.Backend.(garden.ImageManager); ok {
+		return manager.WarmImage(uri, credentials)
+	}
+
+	return garden.CachedImage{}, garden.ErrImagesUnsupported
+}
+
+func (b *storeBackend) Images() ([]garden.CachedImage, error) {
+	if manager, ok := b.Backend.(garden.ImageManager); ok {
+		return manager.Images()
+	}
+
+	return nil, garden.ErrImagesUnsupported
+}
+
+func (b *storeBackend) RemoveImage(uri string) error {
+	if manager, ok := b.Backend.(garden.ImageManager); ok {
+		return manager.RemoveImage(uri)
+	}
+
+	return garden.ErrImagesUnsupported
+}
+
+func (b *storeBackend) rehydrate() error {
+	log := b.logger.Session("rehydrate")
+
+	containers, err := b.Backend.Containers(nil)
+	if err != nil {
+		return err
+	}
+
+	stored, err := b.store.Handles()
+	if err != nil {
+		return err
+	}
+
+	known := map[string]bool{}
+	for _, handle := range stored {
+		known[handle] = true
+	}
+
+	for _, container := range containers {
+		handle := container.Handle()
+		if known[handle] {
+			delete(known, handle)
+			continue
+		}
+
+		properties, err := container.Properties()
+		if err != nil {
+			log.Error("failed-to-get-properties", err, lager.Data{"handle": handle})
+			continue
+		}
+
+		if err := b.store.Set(handle, properties); err != nil {
+			return err
+		}
+
+		log.Info("rehydrated", lager.Data{"handle": handle})
+	}
+
+	for handle := range known {
+		if err := b.store.Destroy(handle); err != nil {
+			return err
+		}
+
+		log.Info("forgot", lager.Data{"handle": handle})
+	}
+
+	return nil
+}
+
+func (b *storeBackend) Create(spec garden.ContainerSpec) (garden.Container, error) {
+	container, err := b.Backend.Create(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.stored(container, spec)
+}
+
+func (b *storeBackend) Restore(spec garden.ContainerSpec, source string) (garden.Container, error) {
+	container, err := b.Backend.Restore(spec, source)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.stored(container, spec)
+}
+
+// stored stores the properties of a container which was created or restored
+// from the spec. If they cannot be stored the container is destroyed, rather
+// than being left running without them.
+func (b *storeBackend) stored(container garden.Container, spec garden.ContainerSpec) (garden.Container, error) {
+	if err := b.store.Set(container.Handle(), spec.Properties); err != nil {
+		if destroyErr := b.Backend.Destroy(container.Handle()); destroyErr != nil {
+			b.logger.Error("failed-to-destroy-container-without-properties", destroyErr, lager.Data{"handle": container.Handle()})
+		}
+
+		return nil, err
+	}
+
+	return b.wrap(container), nil
+}
+
+func (b *storeBackend) Destroy(handle string) error {
+	if err := b.Backend.Destroy(handle); err != nil {
+		return err
+	}
+
+	return b.store.Destroy(handle)
+}
+
+func (b *storeBackend) Lookup(handle string) (garden.Container, error) {
+	container, err := b.Backend.Lookup(handle)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.wrap(container), nil
+}
+
+func (b *storeBackend) Containers(filter garden.Properties) ([]garden.Container, error) {
+	containers, err := b.Backend.Containers(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	matching := []garden.Container{}
+	for _, container := range containers {
+		properties, err := b.store.All(container.Handle())
+		if err != nil {
+			return nil, err
+		}
+
+		if matches(properties, filter) {
+			matching = append(matching, b.wrap(container))
+		}
+	}
+
+	return matching, nil
+}
+
+func (b *storeBackend) BulkInfo(handles []string) (map[string]garden.ContainerInfoEntry, error) {
+	infos, err := b.Backend.BulkInfo(handles)
+	if err != nil {
+		return nil, err
+	}
+
+	for handle, entry := range infos {
+		if entry.Err != nil {
+			continue
+		}
+
+		properties, err := b.store.All(handle)
+		if err != nil {
+			return nil, err
+		}
+
+		entry.Info.Properties = properties
+		infos[handle] = entry
+	}
+
+	return infos, nil
+}
+
+func (b *storeBackend) GraceTime(c garden.Container) time.Duration {
+	if wrapped, ok := c.(*storeContainer); ok {
+		c = wrapped.Container
+	}
+
+	return b.Backend.GraceTime(c)
+}
+
+func (b *storeBackend) wrap(container garden.Container) garden.Container {
+	return &storeContainer{
+		Container: container,
+		store:     b.store,
+	}
+}
+
+func matches(properties, filter garden.Properties) bool {
+	for name, value := range filter {
+		if properties[name] != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+type storeContainer struct {
+	garden.Container
+
+	store Store
+}
+
+func (c *storeContainer) Info() (garden.ContainerInfo, error) {
+	info, err := c.Container.Info()
+	if err != nil {
+		return garden.ContainerInfo{}, err
+	}
+
+	info.Properties, err = c.store.All(c.Handle())
+	if err != nil {
+		return garden.ContainerInfo{}, err
+	}
+
+	return info, nil
+}
+
+func (c *storeContainer) Properties() (garden.Properties, error) {
+	return c.store.All(c.Handle())
+}
+
+func (c *storeContainer) Property(name string) (string, error) {
+	return c.store.Get(c.Handle(), name)
+}
+
+func (c *storeContainer) SetProperty(name string, value string) error {
+	if err := c.store.Set(c.Handle(), garden.Properties{name: value}); err != nil {
+		return err
+	}
+
+	return c.Container.SetProperty(name, value)
+}
+
+func (c *storeContainer) RemoveProperty(name string) error {
+	if err := c.store.Remove(c.Handle(), name); err != nil {
+		return err
+	}
+
+	return c.Container.RemoveProperty(name)
+}
@@ -0,0 +1,266 @@
+package properties_test
+
+import (
+	"errors"
+	"time"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"code.cloudfoundry.org/garden"
+	fakes "code.cloudfoundry.org/garden/gardenfakes"
+	"code.cloudfoundry.org/garden/server/properties"
+)
+
+var _ = Describe("Backend", func() {
+	var (
+		fakeBackend   *fakes.FakeBackend
+		fakeContainer *fakes.FakeContainer
+		store         *properties.MemoryStore
+		backend       garden.Backend
+	)
+
+	BeforeEach(func() {
+		fakeContainer = new(fakes.FakeContainer)
+		fakeContainer.HandleReturns("some-handle")
+
+		fakeBackend = new(fakes.FakeBackend)
+		fakeBackend.CreateReturns(fakeContainer, nil)
+		fakeBackend.LookupReturns(fakeContainer, nil)
+
+		store = properties.NewMemoryStore()
+		backend = properties.NewBackend(fakeBackend, store, lagertest.NewTestLogger("test"))
+	})
+
+	Describe("starting", func() {
+		var (
+			running *fakes.FakeContainer
+			broken  *fakes.FakeContainer
+		)
+
+		BeforeEach(func() {
+			running = new(fakes.FakeContainer)
+			running.HandleReturns("running-handle")
+			running.PropertiesReturns(garden.Properties{"a": "b"}, nil)
+
+			broken = new(fakes.FakeContainer)
+			broken.HandleReturns("broken-handle")
+			broken.PropertiesReturns(nil, errors.New("boom"))
+
+			fakeBackend.ContainersReturns([]garden.Container{fakeContainer, running, broken}, nil)
+			fakeContainer.PropertiesReturns(garden.Properties{"stale": "value"}, nil)
+
+			Ω(store.Set("some-handle", garden.Properties{"c": "d"})).Should(Succeed())
+			Ω(store.Set("destroyed-handle", garden.Properties{"e": "f"})).Should(Succeed())
+		})
+
+		It("starts the backend", func() {
+			Ω(backend.Start()).Should(Succeed())
+			Ω(fakeBackend.StartCallCount()).Should(Equal(1))
+		})
+
+		It("rehydrates running containers which the store does not know", func() {
+			Ω(backend.Start()).Should(Succeed())
+			Ω(store.All("running-handle")).Should(Equal(garden.Properties{"a": "b"}))
+		})
+
+		It("keeps the stored properties of containers which the store knows", func() {
+			Ω(backend.Start()).Should(Succeed())
+			Ω(store.All("some-handle")).Should(Equal(garden.Properties{"c": "d"}))
+		})
+
+		It("forgets containers which no longer exist", func() {
+			Ω(backend.Start()).Should(Succeed())
+			Ω(store.Handles()).Should(Equal([]string{"running-handle", "some-handle"}))
+		})
+
+		Context("when the backend fails to start", func() {
+			It("returns the error without touching the store", func() {
+				fakeBackend.StartReturns(errors.New("boom"))
+
+				Ω(backend.Start()).Should(MatchError("boom"))
+				Ω(store.Handles()).Should(Equal([]string{"destroyed-handle", "some-handle"}))
+			})
+		})
+
+		Context("when listing the containers fails", func() {
+			It("returns the error", func() {
+				fakeBackend.ContainersReturns(nil, errors.New("boom"))
+
+				Ω(backend.Start()).Should(MatchError("boom"))
+			})
+		})
+	})
+
+	Describe("reconciliation", func() {
+		It("reports an empty reconciliation when the backend does not report one", func() {
+			reporter, ok := backend.(garden.ReconciliationReporter)
+			Ω(ok).Should(BeTrue())
+			Ω(reporter.Reconciliation()).Should(Equal(garden.Reconciliation{}))
+		})
+	})
+
+	Describe("images", func() {
+		It("fails when the backend does not manage images", func() {
+			manager, ok := backend.(garden.ImageManager)
+			Ω(ok).Should(BeTrue())
+
+			_, err := manager.Images()
+			Ω(err).Should(Equal(garden.ErrImagesUnsupported))
+		})
+	})
+
+	It("stores the properties of created containers", func() {
+		spec := garden.ContainerSpec{Properties: garden.Properties{"a": "b"}}
+
+		container, err := backend.Create(spec)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(fakeBackend.CreateArgsForCall(0)).Should(Equal(spec))
+
+		Ω(store.All("some-handle")).Should(Equal(garden.Properties{"a": "b"}))
+		Ω(container.Property("a")).Should(Equal("b"))
+	})
+
+	It("stores the properties of restored containers", func() {
+		fakeBackend.RestoreReturns(fakeContainer, nil)
+
+		_, err := backend.Restore(garden.ContainerSpec{Properties: garden.Properties{"a": "b"}}, "/some/checkpoint")
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(store.All("some-handle")).Should(Equal(garden.Properties{"a": "b"}))
+	})
+
+	It("does not store properties when creation fails", func() {
+		fakeBackend.CreateReturns(nil, errors.New("boom"))
+
+		_, err := backend.Create(garden.ContainerSpec{Handle: "some-handle", Properties: garden.Properties{"a": "b"}})
+		Ω(err).Should(MatchError("boom"))
+
+		Ω(store.All("some-handle")).Should(BeEmpty())
+	})
+
+	Context("when the properties cannot be stored", func() {
+		BeforeEach(func() {
+			backend = properties.NewBackend(fakeBackend, failingStore{store}, lagertest.NewTestLogger("test"))
+			fakeBackend.RestoreReturns(fakeContainer, nil)
+		})
+
+		It("destroys the created container", func() {
+			_, err := backend.Create(garden.ContainerSpec{Properties: garden.Properties{"a": "b"}})
+			Ω(err).Should(MatchError("disk full"))
+
+			Ω(fakeBackend.DestroyCallCount()).Should(Equal(1))
+			Ω(fakeBackend.DestroyArgsForCall(0)).Should(Equal("some-handle"))
+		})
+
+		It("destroys the restored container", func() {
+			_, err := backend.Restore(garden.ContainerSpec{Properties: garden.Properties{"a": "b"}}, "/some/checkpoint")
+			Ω(err).Should(MatchError("disk full"))
+
+			Ω(fakeBackend.DestroyCallCount()).Should(Equal(1))
+			Ω(fakeBackend.DestroyArgsForCall(0)).Should(Equal("some-handle"))
+		})
+	})
+
+	It("forgets the properties of destroyed containers", func() {
+		Ω(store.Set("some-handle", garden.Properties{"a": "b"})).Should(Succeed())
+
+		Ω(backend.Destroy("some-handle")).Should(Succeed())
+		Ω(store.All("some-handle")).Should(BeEmpty())
+	})
+
+	It("keeps the properties when destroying fails", func() {
+		fakeBackend.DestroyReturns(errors.New("boom"))
+		Ω(store.Set("some-handle", garden.Properties{"a": "b"})).Should(Succeed())
+
+		Ω(backend.Destroy("some-handle")).Should(MatchError("boom"))
+		Ω(store.All("some-handle")).ShouldNot(BeEmpty())
+	})
+
+	Describe("looked up containers", func() {
+		var container garden.Container
+
+		BeforeEach(func() {
+			Ω(store.Set("some-handle", garden.Properties{"a": "b"})).Should(Succeed())
+			fakeContainer.PropertiesReturns(garden.Properties{"stale": "value"}, nil)
+			fakeContainer.InfoReturns(garden.ContainerInfo{State: "active", Properties: garden.Properties{"stale": "value"}}, nil)
+
+			var err error
+			container, err = backend.Lookup("some-handle")
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		It("reads properties from the store", func() {
+			Ω(container.Properties()).Should(Equal(garden.Properties{"a": "b"}))
+			Ω(container.Property("a")).Should(Equal("b"))
+
+			_, err := container.Property("stale")
+			Ω(err).Should(HaveOccurred())
+		})
+
+		It("reports the stored properties in its info", func() {
+			info, err := container.Info()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(info.State).Should(Equal("active"))
+			Ω(info.Properties).Should(Equal(garden.Properties{"a": "b"}))
+		})
+
+		It("writes properties to both the store and the backend", func() {
+			Ω(container.SetProperty("c", "d")).Should(Succeed())
+			Ω(store.Get("some-handle", "c")).Should(Equal("d"))
+
+			name, value := fakeContainer.SetPropertyArgsForCall(0)
+			Ω(name).Should(Equal("c"))
+			Ω(value).Should(Equal("d"))
+
+			Ω(container.RemoveProperty("a")).Should(Succeed())
+			Ω(store.All("some-handle")).Should(Equal(garden.Properties{"c": "d"}))
+			Ω(fakeContainer.RemovePropertyArgsForCall(0)).Should(Equal("a"))
+		})
+
+		It("passes the backend's own container to GraceTime", func() {
+			fakeBackend.GraceTimeReturns(time.Minute)
+
+			Ω(backend.GraceTime(container)).Should(Equal(time.Minute))
+			Ω(fakeBackend.GraceTimeArgsForCall(0)).Should(Equal(fakeContainer))
+		})
+	})
+
+	It("filters containers by their stored properties", func() {
+		other := new(fakes.FakeContainer)
+		other.HandleReturns("other-handle")
+		fakeBackend.ContainersReturns([]garden.Container{fakeContainer, other}, nil)
+
+		Ω(store.Set("some-handle", garden.Properties{"a": "b"})).Should(Succeed())
+		Ω(store.Set("other-handle", garden.Properties{"a": "c"})).Should(Succeed())
+
+		containers, err := backend.Containers(garden.Properties{"a": "c"})
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(containers).Should(HaveLen(1))
+		Ω(containers[0].Handle()).Should(Equal("other-handle"))
+
+		Ω(fakeBackend.ContainersArgsForCall(0)).Should(BeNil())
+	})
+
+	It("reports the stored properties in bulk info", func() {
+		Ω(store.Set("some-handle", garden.Properties{"a": "b"})).Should(Succeed())
+		fakeBackend.BulkInfoReturns(map[string]garden.ContainerInfoEntry{
+			"some-handle":    {Info: garden.ContainerInfo{State: "active"}},
+			"missing-handle": {Err: garden.NewError("not found")},
+		}, nil)
+
+		infos, err := backend.BulkInfo([]string{"some-handle", "missing-handle"})
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(infos["some-handle"].Info.Properties).Should(Equal(garden.Properties{"a": "b"}))
+		Ω(infos["missing-handle"].Err).Should(HaveOccurred())
+	})
+})
+
+type failingStore struct {
+	*properties.MemoryStore
+}
+
+func (failingStore) Set(string, garden.Properties) error {
+	return errors.New("disk full")
+}
@@ -0,0 +1,235 @@
+package properties
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"code.cloudfoundry.org/garden"
+)
+
+// Store holds the properties of containers on behalf of the server, so that
+// they need not be kept by the backend.
+type Store interface {
+	// All returns the properties of the container, which are empty if none
+	// have been set.
+	All(handle string) (garden.Properties, error)
+
+	// Get returns the value of the named property.
+	//
+	// Errors:
+	// * When the property does not exist on the container.
+	Get(handle, name string) (string, error)
+
+	Set(handle string, properties garden.Properties) error
+	Remove(handle, name string) error
+
+	// Destroy forgets all of the properties of the container.
+	Destroy(handle string) error
+
+	// Handles returns the handles of the containers which have properties.
+	Handles() ([]string, error)
+}
+
+// MemoryStore is a Store which forgets everything when the server exits.
+type MemoryStore struct {
+	containers map[string]garden.Properties
+	lock       *sync.RWMutex
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		containers: map[string]garden.Properties{},
+		lock:       new(sync.RWMutex),
+	}
+}
+
+func (s *MemoryStore) All(handle string) (garden.Properties, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	properties := garden.Properties{}
+	for name, value := range s.containers[handle] {
+		properties[name] = value
+	}
+
+	return properties, nil
+}
+
+func (s *MemoryStore) Get(handle, name string) (string, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	value, found := s.containers[handle][name]
+	if !found {
+		return "", fmt.Errorf("property does not exist: %s", name)
+	}
+
+	return value, nil
+}
+
+func (s *MemoryStore) Set(handle string, properties garden.Properties) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.set(handle, properties)
+	return nil
+}
+
+func (s *MemoryStore) Remove(handle, name string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.remove(handle, name)
+	return nil
+}
+
+func (s *MemoryStore) Destroy(handle string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	delete(s.containers, handle)
+	return nil
+}
+
+func (s *MemoryStore) Handles() ([]string, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	handles := make([]string, 0, len(s.containers))
+	for handle := range s.containers {
+		handles = append(handles, handle)
+	}
+
+	sort.Strings(handles)
+
+	return handles, nil
+}
+
+func (s *MemoryStore) set(handle string, properties garden.Properties) {
+	if s.containers[handle] == nil {
+		s.containers[handle] = garden.Properties{}
+	}
+
+	for name, value := range properties {
+		s.containers[handle][name] = value
+	}
+}
+
+func (s *MemoryStore) remove(handle, name string) {
+	delete(s.containers[handle], name)
+	if len(s.containers[handle]) == 0 {
+		delete(s.containers, handle)
+	}
+}
+
+// FileStore is a Store which persists the properties to a JSON file, so that
+// they survive restarts of the server. The file is rewritten atomically on
+// every change, so it is suited to the modest rate at which properties are
+// usually changed. A change which cannot be saved is not made.
+type FileStore struct {
+	*MemoryStore
+
+	path string
+}
+
+// NewFileStore loads the properties from the file at path, which need not
+// exist yet.
+func NewFileStore(path string) (*FileStore, error) {
+	s := &FileStore{
+		MemoryStore: NewMemoryStore(),
+		path:        path,
+	}
+
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(content, &s.containers); err != nil {
+		return nil, fmt.Errorf("corrupt property store %s: %s", path, err)
+	}
+
+	return s, nil
+}
+
+func (s *FileStore) Set(handle string, properties garden.Properties) error {
+	return s.update(func(updated *MemoryStore) {
+		updated.set(handle, properties)
+	})
+}
+
+func (s *FileStore) Remove(handle, name string) error {
+	return s.update(func(updated *MemoryStore) {
+		updated.remove(handle, name)
+	})
+}
+
+func (s *FileStore) Destroy(handle string) error {
+	return s.update(func(updated *MemoryStore) {
+		delete(updated.containers, handle)
+	})
+}
+
+// update makes the change to a copy of the properties and saves it, only
+// then replacing the properties in memory, so that they are unchanged if
+// saving fails.
+func (s *FileStore) update(change func(*MemoryStore)) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	updated := &MemoryStore{containers: make(map[string]garden.Properties, len(s.containers))}
+	for handle, properties := range s.containers {
+		updated.containers[handle] = garden.Properties{}
+		for name, value := range properties {
+			updated.containers[handle][name] = value
+		}
+	}
+
+	change(updated)
+
+	if err := s.save(updated.containers); err != nil {
+		return err
+	}
+
+	s.containers = updated.containers
+	return nil
+}
+
+func (s *FileStore) save(containers map[string]garden.Properties) error {
+	content, err := json.Marshal(containers)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(s.path), filepath.Base(s.path))
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), s.path)
+}
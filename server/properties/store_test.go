@@ -0,0 +1,153 @@
+package properties_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/garden/server/properties"
+)
+
+var _ = Describe("Stores", func() {
+	itStoresProperties := func(newStore func() properties.Store) {
+		var store properties.Store
+
+		BeforeEach(func() {
+			store = newStore()
+		})
+
+		It("returns the properties which have been set", func() {
+			Ω(store.Set("some-handle", garden.Properties{"a": "b", "c": "d"})).Should(Succeed())
+			Ω(store.Set("some-handle", garden.Properties{"c": "e"})).Should(Succeed())
+
+			Ω(store.All("some-handle")).Should(Equal(garden.Properties{"a": "b", "c": "e"}))
+			Ω(store.Get("some-handle", "c")).Should(Equal("e"))
+		})
+
+		It("keeps the properties of each container separate", func() {
+			Ω(store.Set("some-handle", garden.Properties{"a": "b"})).Should(Succeed())
+			Ω(store.Set("other-handle", garden.Properties{"a": "c"})).Should(Succeed())
+
+			Ω(store.Get("some-handle", "a")).Should(Equal("b"))
+			Ω(store.Get("other-handle", "a")).Should(Equal("c"))
+		})
+
+		It("returns empty properties for an unknown container", func() {
+			Ω(store.All("unknown-handle")).Should(BeEmpty())
+		})
+
+		It("fails to get a property which does not exist", func() {
+			_, err := store.Get("some-handle", "a")
+			Ω(err).Should(MatchError("property does not exist: a"))
+		})
+
+		It("removes properties", func() {
+			Ω(store.Set("some-handle", garden.Properties{"a": "b", "c": "d"})).Should(Succeed())
+			Ω(store.Remove("some-handle", "a")).Should(Succeed())
+
+			Ω(store.All("some-handle")).Should(Equal(garden.Properties{"c": "d"}))
+		})
+
+		It("forgets destroyed containers", func() {
+			Ω(store.Set("some-handle", garden.Properties{"a": "b"})).Should(Succeed())
+			Ω(store.Destroy("some-handle")).Should(Succeed())
+
+			Ω(store.All("some-handle")).Should(BeEmpty())
+			Ω(store.Handles()).Should(BeEmpty())
+		})
+
+		It("lists the handles of containers with properties", func() {
+			Ω(store.Set("some-handle", garden.Properties{"a": "b"})).Should(Succeed())
+			Ω(store.Set("other-handle", garden.Properties{"a": "c"})).Should(Succeed())
+
+			Ω(store.Handles()).Should(Equal([]string{"other-handle", "some-handle"}))
+		})
+
+		It("does not let callers modify the stored properties", func() {
+			Ω(store.Set("some-handle", garden.Properties{"a": "b"})).Should(Succeed())
+
+			all, err := store.All("some-handle")
+			Ω(err).ShouldNot(HaveOccurred())
+			all["a"] = "c"
+
+			Ω(store.Get("some-handle", "a")).Should(Equal("b"))
+		})
+	}
+
+	Describe("MemoryStore", func() {
+		itStoresProperties(func() properties.Store {
+			return properties.NewMemoryStore()
+		})
+	})
+
+	Describe("FileStore", func() {
+		var dir string
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "properties")
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(dir)
+		})
+
+		itStoresProperties(func() properties.Store {
+			store, err := properties.NewFileStore(filepath.Join(dir, "properties.json"))
+			Ω(err).ShouldNot(HaveOccurred())
+			return store
+		})
+
+		It("keeps the properties when reloaded", func() {
+			path := filepath.Join(dir, "properties.json")
+
+			store, err := properties.NewFileStore(path)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(store.Set("some-handle", garden.Properties{"a": "b", "c": "d"})).Should(Succeed())
+			Ω(store.Set("other-handle", garden.Properties{"e": "f"})).Should(Succeed())
+			Ω(store.Remove("some-handle", "c")).Should(Succeed())
+			Ω(store.Destroy("other-handle")).Should(Succeed())
+
+			reloaded, err := properties.NewFileStore(path)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(reloaded.All("some-handle")).Should(Equal(garden.Properties{"a": "b"}))
+			Ω(reloaded.All("other-handle")).Should(BeEmpty())
+		})
+
+		It("does not leave temporary files behind", func() {
+			store, err := properties.NewFileStore(filepath.Join(dir, "properties.json"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(store.Set("some-handle", garden.Properties{"a": "b"})).Should(Succeed())
+
+			entries, err := ioutil.ReadDir(dir)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(entries).Should(HaveLen(1))
+		})
+
+		Context("when the properties cannot be saved", func() {
+			It("does not change them", func() {
+				store, err := properties.NewFileStore(filepath.Join(dir, "missing", "properties.json"))
+				Ω(err).ShouldNot(HaveOccurred())
+
+				Ω(store.Set("some-handle", garden.Properties{"a": "b"})).ShouldNot(Succeed())
+				Ω(store.All("some-handle")).Should(BeEmpty())
+				Ω(store.Handles()).Should(BeEmpty())
+			})
+		})
+
+		Context("when the file is corrupt", func() {
+			It("fails to load", func() {
+				path := filepath.Join(dir, "properties.json")
+				Ω(ioutil.WriteFile(path, []byte("{"), 0600)).Should(Succeed())
+
+				_, err := properties.NewFileStore(path)
+				Ω(err).Should(MatchError(ContainSubstring("corrupt property store")))
+			})
+		})
+	})
+})
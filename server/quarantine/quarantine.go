@@ -0,0 +1,98 @@
+package quarantine
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/garden"
+)
+
+// Quarantine tracks host ports released by destroyed containers, so that they
+// are not reused until a grace period has passed. This stops traffic meant for
+// a destroyed container from reaching whichever container is given its port
+// next.
+type Quarantine struct {
+	period time.Duration
+
+	released map[uint32]time.Time
+	lock     *sync.Mutex
+}
+
+func New(period time.Duration) *Quarantine {
+	return &Quarantine{
+		period: period,
+
+		released: map[uint32]time.Time{},
+		lock:     new(sync.Mutex),
+	}
+}
+
+// Release quarantines the ports for the grace period, starting now.
+func (q *Quarantine) Release(ports ...uint32) {
+	if q.period == 0 {
+		return
+	}
+
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	until := time.Now().Add(q.period)
+	for _, port := range ports {
+		q.released[port] = until
+	}
+}
+
+// Until returns when the port leaves quarantine, and whether it is currently
+// quarantined.
+func (q *Quarantine) Until(port uint32) (time.Time, bool) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	until, found := q.released[port]
+	if !found {
+		return time.Time{}, false
+	}
+
+	if !time.Now().Before(until) {
+		delete(q.released, port)
+		return time.Time{}, false
+	}
+
+	return until, true
+}
+
+// Ports returns the currently quarantined ports, in the order they leave
+// quarantine.
+func (q *Quarantine) Ports() []garden.QuarantinedPort {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	now := time.Now()
+
+	ports := []garden.QuarantinedPort{}
+	for port, until := range q.released {
+		if !now.Before(until) {
+			delete(q.released, port)
+			continue
+		}
+
+		ports = append(ports, garden.QuarantinedPort{HostPort: port, Until: until})
+	}
+
+	sort.Sort(byUntil(ports))
+
+	return ports
+}
+
+type byUntil []garden.QuarantinedPort
+
+func (p byUntil) Len() int      { return len(p) }
+func (p byUntil) Swap(i, j int) { p[i], p[j] = p[j], p[i] }
+func (p byUntil) Less(i, j int) bool {
+	if p[i].Until.Equal(p[j].Until) {
+		return p[i].HostPort < p[j].HostPort
+	}
+
+	return p[i].Until.Before(p[j].Until)
+}
@@ -0,0 +1,60 @@
+package quarantine_test
+
+import (
+	"time"
+
+	"code.cloudfoundry.org/garden/server/quarantine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Quarantine", func() {
+	It("quarantines released ports for the grace period", func() {
+		q := quarantine.New(100 * time.Millisecond)
+
+		_, quarantined := q.Until(61001)
+		Expect(quarantined).To(BeFalse())
+
+		before := time.Now()
+		q.Release(61001, 61002)
+
+		until, quarantined := q.Until(61001)
+		Expect(quarantined).To(BeTrue())
+		Expect(until).To(BeTemporally("~", before.Add(100*time.Millisecond), 50*time.Millisecond))
+
+		ports := q.Ports()
+		Expect(ports).To(HaveLen(2))
+		Expect(ports[0].HostPort).To(Equal(uint32(61001)))
+		Expect(ports[1].HostPort).To(Equal(uint32(61002)))
+
+		Eventually(func() bool {
+			_, quarantined := q.Until(61001)
+			return quarantined
+		}).Should(BeFalse())
+		Expect(q.Ports()).To(BeEmpty())
+	})
+
+	It("orders ports by when they leave quarantine", func() {
+		q := quarantine.New(time.Minute)
+
+		q.Release(61002)
+		time.Sleep(time.Millisecond)
+		q.Release(61001)
+
+		ports := q.Ports()
+		Expect(ports).To(HaveLen(2))
+		Expect(ports[0].HostPort).To(Equal(uint32(61002)))
+		Expect(ports[1].HostPort).To(Equal(uint32(61001)))
+	})
+
+	Context("when the grace period is zero", func() {
+		It("never quarantines ports", func() {
+			q := quarantine.New(0)
+			q.Release(61001)
+
+			_, quarantined := q.Until(61001)
+			Expect(quarantined).To(BeFalse())
+			Expect(q.Ports()).To(BeEmpty())
+		})
+	})
+})
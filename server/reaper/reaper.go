@@ -0,0 +1,146 @@
+package reaper
+
+import (
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/lager"
+)
+
+// Reaper periodically destroys containers whose garden.TTLProperty has
+// elapsed since they were created. Containers without a
+// garden.CreatedAtProperty are timed from when the reaper first sees them.
+type Reaper struct {
+	backend  garden.Backend
+	interval time.Duration
+	reap     func(garden.Container)
+	logger   lager.Logger
+
+	firstSeen map[string]time.Time
+	upcoming  []garden.Expiration
+	lock      *sync.Mutex
+
+	stop chan struct{}
+}
+
+func New(backend garden.Backend, interval time.Duration, reap func(garden.Container), logger lager.Logger) *Reaper {
+	return &Reaper{
+		backend:  backend,
+		interval: interval,
+		reap:     reap,
+		logger:   logger.Session("reaper"),
+
+		firstSeen: map[string]time.Time{},
+		lock:      new(sync.Mutex),
+
+		stop: make(chan struct{}),
+	}
+}
+
+func (r *Reaper) Start() {
+	go func() {
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				r.scan()
+			case <-r.stop:
+				return
+			}
+		}
+	}()
+}
+
+func (r *Reaper) Stop() {
+	close(r.stop)
+}
+
+// Upcoming returns the TTL expirations found by the most recent scan.
+func (r *Reaper) Upcoming() []garden.Expiration {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	upcoming := make([]garden.Expiration, len(r.upcoming))
+	copy(upcoming, r.upcoming)
+
+	return upcoming
+}
+
+func (r *Reaper) scan() {
+	containers, err := r.backend.Containers(nil)
+	if err != nil {
+		r.logger.Error("failed-to-list-containers", err)
+		return
+	}
+
+	now := time.Now()
+	firstSeen := map[string]time.Time{}
+	upcoming := []garden.Expiration{}
+
+	for _, container := range containers {
+		handle := container.Handle()
+
+		value, err := container.Property(garden.TTLProperty)
+		if err != nil {
+			continue
+		}
+
+		ttl, err := time.ParseDuration(value)
+		if err != nil {
+			r.logger.Error("invalid-ttl", err, lager.Data{"handle": handle, "ttl": value})
+			continue
+		}
+
+		createdAt, ok := r.createdAt(container)
+		if !ok {
+			createdAt = now
+			if seen, found := r.firstSeen[handle]; found {
+				createdAt = seen
+			}
+
+			firstSeen[handle] = createdAt
+		}
+
+		expiresAt := createdAt.Add(ttl)
+		if now.Before(expiresAt) {
+			upcoming = append(upcoming, garden.Expiration{
+				Handle:    handle,
+				ExpiresAt: expiresAt,
+				Reason:    garden.ExpirationReasonTTL,
+			})
+
+			continue
+		}
+
+		r.logger.Info("expired", lager.Data{
+			"handle":     handle,
+			"ttl":        ttl.String(),
+			"expires-at": expiresAt,
+		})
+
+		delete(firstSeen, handle)
+		r.reap(container)
+	}
+
+	r.lock.Lock()
+	r.firstSeen = firstSeen
+	r.upcoming = upcoming
+	r.lock.Unlock()
+}
+
+func (r *Reaper) createdAt(container garden.Container) (time.Time, bool) {
+	value, err := container.Property(garden.CreatedAtProperty)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return createdAt, true
+}
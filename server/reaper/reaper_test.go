@@ -0,0 +1,148 @@
+package reaper_test
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"code.cloudfoundry.org/garden"
+	fakes "code.cloudfoundry.org/garden/gardenfakes"
+	"code.cloudfoundry.org/garden/server/reaper"
+)
+
+var _ = Describe("Reaper", func() {
+	var (
+		backend    *fakes.FakeBackend
+		containers []garden.Container
+		lock       *sync.Mutex
+		reaped     chan garden.Container
+		r          *reaper.Reaper
+	)
+
+	containerWithProperties := func(handle string, properties garden.Properties) *fakes.FakeContainer {
+		container := new(fakes.FakeContainer)
+		container.HandleReturns(handle)
+		container.PropertyStub = func(name string) (string, error) {
+			value, ok := properties[name]
+			if !ok {
+				return "", errors.New("no such property")
+			}
+
+			return value, nil
+		}
+
+		return container
+	}
+
+	BeforeEach(func() {
+		containers = nil
+		lock = new(sync.Mutex)
+
+		backend = new(fakes.FakeBackend)
+		backend.ContainersStub = func(garden.Properties) ([]garden.Container, error) {
+			lock.Lock()
+			defer lock.Unlock()
+
+			return containers, nil
+		}
+
+		reaped = make(chan garden.Container, 10)
+
+		r = reaper.New(backend, 10*time.Millisecond, func(container garden.Container) {
+			lock.Lock()
+			defer lock.Unlock()
+
+			remaining := []garden.Container{}
+			for _, c := range containers {
+				if c.Handle() != container.Handle() {
+					remaining = append(remaining, c)
+				}
+			}
+			containers = remaining
+
+			reaped <- container
+		}, lagertest.NewTestLogger("test"))
+	})
+
+	AfterEach(func() {
+		r.Stop()
+	})
+
+	It("reaps containers whose ttl has elapsed since they were created", func() {
+		containers = []garden.Container{
+			containerWithProperties("expired", garden.Properties{
+				garden.TTLProperty:       "1m",
+				garden.CreatedAtProperty: time.Now().Add(-time.Hour).Format(time.RFC3339Nano),
+			}),
+			containerWithProperties("alive", garden.Properties{
+				garden.TTLProperty:       "2h",
+				garden.CreatedAtProperty: time.Now().Add(-time.Hour).Format(time.RFC3339Nano),
+			}),
+			containerWithProperties("immortal", garden.Properties{}),
+		}
+
+		r.Start()
+
+		var container garden.Container
+		Eventually(reaped).Should(Receive(&container))
+		Ω(container.Handle()).Should(Equal("expired"))
+		Consistently(reaped).ShouldNot(Receive())
+	})
+
+	It("reports upcoming expirations", func() {
+		createdAt := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+		containers = []garden.Container{
+			containerWithProperties("alive", garden.Properties{
+				garden.TTLProperty:       "2h",
+				garden.CreatedAtProperty: createdAt.Format(time.RFC3339Nano),
+			}),
+		}
+
+		r.Start()
+
+		Eventually(r.Upcoming).Should(HaveLen(1))
+
+		expiration := r.Upcoming()[0]
+		Ω(expiration.Handle).Should(Equal("alive"))
+		Ω(expiration.Reason).Should(Equal(garden.ExpirationReasonTTL))
+		Ω(expiration.ExpiresAt).Should(BeTemporally("==", createdAt.Add(2*time.Hour)))
+	})
+
+	Context("when a container has no creation time", func() {
+		It("times its ttl from when it was first seen", func() {
+			containers = []garden.Container{
+				containerWithProperties("unknown", garden.Properties{
+					garden.TTLProperty: "50ms",
+				}),
+			}
+
+			before := time.Now()
+			r.Start()
+
+			var container garden.Container
+			Eventually(reaped).Should(Receive(&container))
+			Ω(container.Handle()).Should(Equal("unknown"))
+			Ω(time.Since(before)).Should(BeNumerically(">=", 50*time.Millisecond))
+		})
+	})
+
+	Context("when a container has an invalid ttl", func() {
+		It("does not reap it", func() {
+			containers = []garden.Container{
+				containerWithProperties("invalid", garden.Properties{
+					garden.TTLProperty:       "forever",
+					garden.CreatedAtProperty: time.Now().Add(-time.Hour).Format(time.RFC3339Nano),
+				}),
+			}
+
+			r.Start()
+
+			Consistently(reaped).ShouldNot(Receive())
+		})
+	})
+})
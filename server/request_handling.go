@@ -1,38 +1,127 @@
 package server
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
+	"math"
 	"net"
 	"net/http"
+	"path"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/garden/routes"
 	"code.cloudfoundry.org/garden/transport"
 	"code.cloudfoundry.org/lager"
 )
 
 type processDebugInfo struct {
-	Path   string
-	Dir    string
-	User   string
-	Limits garden.ResourceLimits
-	TTY    *garden.TTYSpec
+	Path                string
+	Dir                 string
+	User                string
+	SupplementaryGroups []string
+	Umask               *uint32
+	Limits              garden.ResourceLimits
+	OOMScoreAdj         *int
+	Timeout             time.Duration
+	TTY                 *garden.TTYSpec
+	Capabilities        garden.Capabilities
 }
 
 type containerDebugInfo struct {
-	Handle     string
-	GraceTime  time.Duration
-	RootFSPath string
-	BindMounts []garden.BindMount
-	Network    string
-	Privileged bool
-	Limits     garden.Limits
+	Handle           string
+	Hostname         string
+	GraceTime        time.Duration
+	RootFSPath       string
+	Image            string
+	RegistryUsername string
+	BindMounts       []garden.BindMount
+	Network          string
+	Privileged       bool
+	Limits           garden.Limits
+	Seccomp          string
+	LSMProfile       string
+	RestartMode      string
+}
+
+func newProcessDebugInfo(spec garden.ProcessSpec) processDebugInfo {
+	return processDebugInfo{
+		Path:                spec.Path,
+		Dir:                 spec.Dir,
+		User:                spec.User,
+		SupplementaryGroups: spec.SupplementaryGroups,
+		Umask:               spec.Umask,
+		Limits:              spec.Limits,
+		OOMScoreAdj:         spec.OOMScoreAdj,
+		Timeout:             spec.Timeout,
+		TTY:                 spec.TTY,
+		Capabilities:        spec.Capabilities,
+	}
+}
+
+func newContainerDebugInfo(spec garden.ContainerSpec) containerDebugInfo {
+	info := containerDebugInfo{
+		Handle:     spec.Handle,
+		Hostname:   spec.Hostname,
+		GraceTime:  spec.GraceTime,
+		RootFSPath: spec.RootFSPath,
+		BindMounts: spec.BindMounts,
+		Network:    spec.Network,
+		Privileged: spec.Privileged,
+		Limits:     spec.Limits,
+		Seccomp:    seccompProfileName(spec.SeccompProfile),
+		LSMProfile: spec.LSMProfile,
+	}
+
+	if spec.Image != nil {
+		info.Image = spec.Image.Reference
+	}
+
+	// only the username is logged, never the password or token
+	if spec.RegistryCredentials != nil {
+		info.RegistryUsername = spec.RegistryCredentials.Username
+	}
+
+	if spec.RestartPolicy != nil {
+		info.RestartMode = spec.RestartPolicy.Mode
+	}
+
+	return info
+}
+
+// redactRegistryCredentials removes the secrets of any registry credentials
+// in the spec from a backend error, in case the image fetcher echoed them.
+func redactRegistryCredentials(spec garden.ContainerSpec, err error) error {
+	if spec.RegistryCredentials == nil {
+		return err
+	}
+
+	return spec.RegistryCredentials.Redactor().Error(err)
+}
+
+// seccompProfileName describes the profile for logging without including an
+// inline profile.
+func seccompProfileName(profile *garden.SeccompProfile) string {
+	switch {
+	case profile == nil:
+		return ""
+	case profile.Name != "":
+		return profile.Name
+	default:
+		return "inline"
+	}
 }
 
 var ErrConcurrentDestroy = errors.New("container already being destroyed")
+var ErrUnknownSignal = errors.New("unknown signal")
 
 func (s *GardenServer) handlePing(w http.ResponseWriter, r *http.Request) {
 	hLog := s.logger.Session("ping")
@@ -65,335 +154,432 @@ func (s *GardenServer) handleCreate(w http.ResponseWriter, r *http.Request) {
 	}
 
 	hLog := s.logger.Session("create", lager.Data{
-		"request": containerDebugInfo{
-			Handle:     spec.Handle,
-			GraceTime:  spec.GraceTime,
-			RootFSPath: spec.RootFSPath,
-			BindMounts: spec.BindMounts,
-			Network:    spec.Network,
-			Privileged: spec.Privileged,
-			Limits:     spec.Limits,
-		},
+		"request": newContainerDebugInfo(spec),
 	})
 
-	if spec.GraceTime == 0 {
-		spec.GraceTime = s.containerGraceTime
-	}
-
-	hLog.Debug("creating")
-
-	container, err := s.backend.Create(spec)
+	container, err := s.create(spec, hLog)
 	if err != nil {
 		s.writeError(w, err, hLog)
 		return
 	}
 
-	hLog.Info("created")
-
-	s.bomberman.Strap(container)
-
 	s.writeResponse(w, &struct{ Handle string }{
 		Handle: container.Handle(),
 	})
 }
 
-func (s *GardenServer) handleList(w http.ResponseWriter, r *http.Request) {
-	properties := garden.Properties{}
-	for name, vals := range r.URL.Query() {
-		if len(vals) > 0 {
-			properties[name] = vals[0]
-		}
+func (s *GardenServer) handleBulkCreate(w http.ResponseWriter, r *http.Request) {
+	var request transport.BulkCreateRequest
+	if !s.readRequest(&request, w, r) {
+		return
 	}
 
-	hLog := s.logger.Session("list")
-	hLog.Debug("started")
+	hLog := s.logger.Session("bulk-create", lager.Data{
+		"count": len(request.Specs),
+	})
 
-	containers, err := s.backend.Containers(properties)
+	entries := make([]garden.ContainerCreateEntry, len(request.Specs))
+
+	s.parallel(len(request.Specs), func(i int) {
+		spec := request.Specs[i]
+
+		container, err := s.create(spec, hLog.Session("create", lager.Data{
+			"index":   i,
+			"request": newContainerDebugInfo(spec),
+		}))
+		if err != nil {
+			entries[i] = garden.ContainerCreateEntry{Err: s.bulkError(err)}
+			return
+		}
+
+		entries[i] = garden.ContainerCreateEntry{Handle: container.Handle()}
+	})
+
+	hLog.Info("created")
+
+	s.writeResponse(w, entries)
+}
+
+// create creates a container from the spec, applying the server's defaults,
+// and starts its grace time.
+func (s *GardenServer) create(spec garden.ContainerSpec, hLog lager.Logger) (garden.Container, error) {
+	if err := s.prepareSpec(&spec); err != nil {
+		return nil, err
+	}
+
+	release, err := s.reserveContainerIP(spec, hLog)
 	if err != nil {
-		s.writeError(w, err, hLog)
-		return
+		return nil, err
 	}
+	defer release()
 
-	handles := []string{}
+	hLog.Debug("creating")
 
-	for _, container := range containers {
-		handles = append(handles, container.Handle())
+	container, err := s.backend.Create(spec)
+	if err != nil {
+		return nil, redactRegistryCredentials(spec, s.handleTaken(spec, err))
 	}
 
-	hLog.Debug("ending", lager.Data{"handles": handles})
+	hLog.Info("created")
 
-	s.writeResponse(w, &struct{ Handles []string }{handles})
+	s.containerCreated(container, spec)
+
+	return container, nil
 }
 
-func (s *GardenServer) handleDestroy(w http.ResponseWriter, r *http.Request) {
-	handle := r.FormValue(":handle")
+// prepareSpec validates the spec of a container about to be created or
+// restored, and applies the server's defaults to it.
+func (s *GardenServer) prepareSpec(spec *garden.ContainerSpec) error {
+	if spec.GraceTime == 0 {
+		spec.GraceTime = s.containerGraceTime
+	}
 
-	hLog := s.logger.Session("destroy", lager.Data{
-		"handle": handle,
-	})
+	if err := validateDescription(spec.Description); err != nil {
+		return err
+	}
 
-	s.destroysL.Lock()
+	if spec.Hostname != "" && !validHostname(spec.Hostname) {
+		return garden.MalformedRequestError{
+			Cause: fmt.Sprintf("hostname: %q is not a hostname of at most %d bytes", spec.Hostname, garden.MaxHostnameLength),
+		}
+	}
 
-	_, alreadyDestroying := s.destroys[handle]
-	if !alreadyDestroying {
-		s.destroys[handle] = struct{}{}
+	if err := validateDNS(*spec); err != nil {
+		return err
 	}
 
-	s.destroysL.Unlock()
+	if err := validateCapabilities(spec.Capabilities); err != nil {
+		return err
+	}
 
-	if alreadyDestroying {
-		s.writeError(w, ErrConcurrentDestroy, hLog)
-		return
+	if err := validateRestartPolicy(spec.RestartPolicy); err != nil {
+		return err
 	}
 
-	hLog.Debug("destroying")
+	if err := validateHealthCheck(spec.HealthCheck); err != nil {
+		return err
+	}
 
-	err := s.backend.Destroy(handle)
+	if spec.HealthCheck != nil && spec.HealthCheck.Process != nil {
+		if err := validateOOMScoreAdj("health_check.process.oom_score_adj", spec.HealthCheck.Process.OOMScoreAdj); err != nil {
+			return err
+		}
+	}
 
-	if !alreadyDestroying {
-		s.destroysL.Lock()
-		delete(s.destroys, handle)
-		s.destroysL.Unlock()
+	if _, err := requestedContainerIP(*spec); err != nil {
+		return err
 	}
 
-	if err != nil {
-		s.writeError(w, err, hLog)
-		return
+	if err := s.generateHandle(spec); err != nil {
+		return err
 	}
 
-	hLog.Info("destroyed")
+	if spec.Hostname == "" && validHostname(spec.Handle) {
+		spec.Hostname = spec.Handle
+	}
 
-	s.bomberman.Defuse(handle)
+	properties := garden.Properties{}
+	for name, value := range spec.Properties {
+		properties[name] = value
+	}
 
-	s.writeSuccess(w)
+	if createdAt, found := properties[garden.CreatedAtProperty]; found {
+		if _, err := time.Parse(time.RFC3339Nano, createdAt); err != nil {
+			return garden.MalformedRequestError{
+				Cause: fmt.Sprintf("properties: %s: %q is not an RFC 3339 time", garden.CreatedAtProperty, createdAt),
+			}
+		}
+	} else {
+		properties[garden.CreatedAtProperty] = time.Now().UTC().Format(time.RFC3339Nano)
+	}
+	spec.Properties = properties
+
+	return nil
 }
 
-func (s *GardenServer) handleStop(w http.ResponseWriter, r *http.Request) {
-	handle := r.FormValue(":handle")
+// containerCreated starts the grace time of a container which was created or
+// restored from the spec, announces it, and starts supervising its main
+// process and probing its health.
+func (s *GardenServer) containerCreated(container garden.Container, spec garden.ContainerSpec) {
+	s.bomberman.Strap(container)
+	s.publishEvent(garden.Event{Kind: garden.EventCreated, Handle: container.Handle()})
 
-	hLog := s.logger.Session("stop", lager.Data{
-		"handle": handle,
-	})
+	if spec.RestartPolicy != nil {
+		go s.supervise(container, *spec.RestartPolicy, s.supervisors.start(container.Handle()))
+	}
 
-	var request struct {
-		Kill bool `json:"kill"`
+	if spec.HealthCheck != nil {
+		go s.checkHealth(container, *spec.HealthCheck, s.healthChecks.start(container.Handle()))
 	}
-	if !s.readRequest(&request, w, r) {
-		return
+}
+
+// handleTaken returns a HandleTakenError in place of the error with which
+// creating or restoring a container from the spec failed, if a container with
+// its handle exists, as backends do not all report a duplicate handle as one.
+func (s *GardenServer) handleTaken(spec garden.ContainerSpec, err error) error {
+	if _, ok := err.(garden.HandleTakenError); ok || spec.Handle == "" {
+		return err
 	}
 
-	container, err := s.backend.Lookup(handle)
-	if err != nil {
-		s.writeError(w, err, hLog)
-		return
+	if container, lookupErr := s.backend.Lookup(spec.Handle); lookupErr == nil && container != nil {
+		return garden.HandleTakenError{Handle: spec.Handle}
 	}
 
-	s.bomberman.Pause(container.Handle())
-	defer s.bomberman.Unpause(container.Handle())
+	return err
+}
 
-	hLog.Debug("stopping")
+// generateHandle fills in the handle of a spec without one, if the server
+// has a handle generator.
+func (s *GardenServer) generateHandle(spec *garden.ContainerSpec) error {
+	if spec.Handle != "" || s.handleGenerator == nil {
+		return nil
+	}
 
-	err = container.Stop(request.Kill)
+	handle, err := s.handleGenerator.Generate()
 	if err != nil {
-		s.writeError(w, err, hLog)
-		return
+		return err
 	}
 
-	hLog.Info("stopped")
-
-	s.writeSuccess(w)
+	spec.Handle = handle
+	return nil
 }
 
-func (s *GardenServer) handleStreamIn(w http.ResponseWriter, r *http.Request) {
-	handle := r.FormValue(":handle")
+func (s *GardenServer) handleRestore(w http.ResponseWriter, r *http.Request) {
+	var request transport.RestoreRequest
+	if !s.readRequest(&request, w, r) {
+		return
+	}
 
-	user := r.URL.Query().Get("user")
-	dstPath := r.URL.Query().Get("destination")
+	spec := request.Spec
 
-	hLog := s.logger.Session("stream-in", lager.Data{
-		"handle":      handle,
-		"user":        user,
-		"destination": dstPath,
+	hLog := s.logger.Session("restore", lager.Data{
+		"source":  request.Source,
+		"request": newContainerDebugInfo(spec),
 	})
 
-	container, err := s.backend.Lookup(handle)
-	if err != nil {
+	if err := s.prepareSpec(&spec); err != nil {
 		s.writeError(w, err, hLog)
 		return
 	}
 
-	s.bomberman.Pause(container.Handle())
-	defer s.bomberman.Unpause(container.Handle())
+	release, err := s.reserveContainerIP(spec, hLog)
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+	defer release()
 
-	hLog.Debug("streaming-in")
+	hLog.Debug("restoring")
 
-	err = container.StreamIn(garden.StreamInSpec{
-		User:      user,
-		Path:      dstPath,
-		TarStream: r.Body,
-	})
+	container, err := s.backend.Restore(spec, request.Source)
 	if err != nil {
-		s.writeError(w, err, hLog)
+		s.writeError(w, redactRegistryCredentials(spec, s.handleTaken(spec, err)), hLog)
 		return
 	}
 
-	hLog.Info("streamed-in")
+	hLog.Info("restored")
 
-	s.writeSuccess(w)
-}
+	s.containerCreated(container, spec)
 
-func (s *GardenServer) writeSuccess(w http.ResponseWriter) {
-	s.writeResponse(w, &struct{}{})
+	s.writeResponse(w, &struct{ Handle string }{
+		Handle: container.Handle(),
+	})
 }
 
-func (s *GardenServer) handleStreamOut(w http.ResponseWriter, r *http.Request) {
-	handle := r.FormValue(":handle")
-
-	user := r.URL.Query().Get("user")
-	srcPath := r.URL.Query().Get("source")
+func (s *GardenServer) handleList(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
 
-	hLog := s.logger.Session("stream-out", lager.Data{
-		"handle": handle,
-		"user":   user,
-		"source": srcPath,
-	})
+	hLog := s.logger.Session("list")
+	hLog.Debug("started")
 
-	container, err := s.backend.Lookup(handle)
+	opts, err := listOptions(query)
 	if err != nil {
 		s.writeError(w, err, hLog)
 		return
 	}
 
-	s.bomberman.Pause(container.Handle())
-	defer s.bomberman.Unpause(container.Handle())
-
-	hLog.Debug("streaming-out")
+	properties := garden.Properties{}
+	for name, vals := range query {
+		if len(vals) > 0 {
+			properties[name] = vals[0]
+		}
+	}
 
-	reader, err := container.StreamOut(garden.StreamOutSpec{
-		User: user,
-		Path: srcPath,
-	})
+	containers, err := s.backend.Containers(properties)
 	if err != nil {
 		s.writeError(w, err, hLog)
 		return
 	}
 
-	n, err := io.Copy(w, reader)
-	if err != nil {
-		if err := reader.Close(); err != nil {
-			hLog.Error("failed-to-close", err)
-		}
+	if len(opts.PropertyFilters) > 0 {
+		containers = filteredByProperties(containers, opts.PropertyFilters)
+	}
 
-		if n == 0 {
-			s.writeError(w, err, hLog)
-		}
+	if opts.OrderByCreation || !opts.CreatedBefore.IsZero() || !opts.CreatedAfter.IsZero() {
+		containers = orderedByCreation(containers, opts)
+	}
 
-		return
+	handles := []string{}
+
+	for _, container := range containers {
+		handles = append(handles, container.Handle())
 	}
 
-	hLog.Info("streamed-out")
+	hLog.Debug("ending", lager.Data{"handles": handles})
+
+	s.writeResponse(w, &struct{ Handles []string }{handles})
 }
 
-func (s *GardenServer) handleCurrentBandwidthLimits(w http.ResponseWriter, r *http.Request) {
+func (s *GardenServer) handleDestroy(w http.ResponseWriter, r *http.Request) {
 	handle := r.FormValue(":handle")
 
-	hLog := s.logger.Session("current-bandwidth-limits", lager.Data{
+	hLog := s.logger.Session("destroy", lager.Data{
 		"handle": handle,
 	})
 
-	container, err := s.backend.Lookup(handle)
-	if err != nil {
-		s.writeError(w, err, hLog)
+	if r.URL.Query().Get("dry_run") == "true" {
+		s.destroyDryRun(w, handle, hLog)
 		return
 	}
 
-	s.bomberman.Pause(container.Handle())
-	defer s.bomberman.Unpause(container.Handle())
-
-	hLog.Debug("getting")
-
-	limits, err := container.CurrentBandwidthLimits()
-	if err != nil {
+	if err := s.destroy(handle, hLog); err != nil {
 		s.writeError(w, err, hLog)
 		return
 	}
 
-	hLog.Info("got", lager.Data{
-		"limits": limits,
-	})
-
-	s.writeResponse(w, limits)
+	s.writeSuccess(w)
 }
 
-func (s *GardenServer) handleCurrentMemoryLimits(w http.ResponseWriter, r *http.Request) {
-	handle := r.FormValue(":handle")
+func (s *GardenServer) handleBulkDestroy(w http.ResponseWriter, r *http.Request) {
+	var request transport.BulkDestroyRequest
+	if !s.readRequest(&request, w, r) {
+		return
+	}
 
-	hLog := s.logger.Session("current-memory-limits", lager.Data{
-		"handle": handle,
+	hLog := s.logger.Session("bulk-destroy", lager.Data{
+		"handles": request.Handles,
 	})
 
-	container, err := s.backend.Lookup(handle)
-	if err != nil {
-		s.writeError(w, err, hLog)
-		return
+	errs := s.forEachHandle(request.Handles, func(handle string) error {
+		return s.destroy(handle, hLog.Session("destroy", lager.Data{"handle": handle}))
+	})
+
+	entries := make(map[string]garden.ContainerDestroyEntry, len(errs))
+	for handle, err := range errs {
+		entries[handle] = garden.ContainerDestroyEntry{Err: s.bulkError(err)}
 	}
 
-	s.bomberman.Pause(container.Handle())
-	defer s.bomberman.Unpause(container.Handle())
+	hLog.Info("destroyed")
 
-	hLog.Debug("getting")
+	s.writeResponse(w, entries)
+}
 
-	limits, err := container.CurrentMemoryLimits()
-	if err != nil {
-		s.writeError(w, err, hLog)
-		return
+// forEachHandle calls op once for each distinct handle, as for parallel, and
+// returns the error from each call.
+func (s *GardenServer) forEachHandle(handles []string, op func(handle string) error) map[string]error {
+	distinct := []string{}
+	errs := make(map[string]error, len(handles))
+	for _, handle := range handles {
+		if _, duplicate := errs[handle]; !duplicate {
+			distinct = append(distinct, handle)
+			errs[handle] = nil
+		}
 	}
 
-	hLog.Info("got", lager.Data{
-		"limits": limits,
+	errsL := new(sync.Mutex)
+	s.parallel(len(distinct), func(i int) {
+		err := op(distinct[i])
+
+		errsL.Lock()
+		errs[distinct[i]] = err
+		errsL.Unlock()
 	})
 
-	s.writeResponse(w, limits)
+	return errs
 }
 
-func (s *GardenServer) handleCurrentDiskLimits(w http.ResponseWriter, r *http.Request) {
-	handle := r.FormValue(":handle")
+// parallel calls op for each index up to n, with at most the server's bulk
+// parallelism calls at once, and returns once they have all returned.
+func (s *GardenServer) parallel(n int, op func(i int)) {
+	parallelism := s.bulkParallelism
+	if parallelism < 1 {
+		parallelism = 1
+	}
 
-	hLog := s.logger.Session("current-disk-limits", lager.Data{
-		"handle": handle,
-	})
+	slots := make(chan struct{}, parallelism)
+	wg := new(sync.WaitGroup)
 
-	container, err := s.backend.Lookup(handle)
-	if err != nil {
-		s.writeError(w, err, hLog)
-		return
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		slots <- struct{}{}
+
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			op(i)
+		}(i)
 	}
 
-	s.bomberman.Pause(container.Handle())
-	defer s.bomberman.Unpause(container.Handle())
+	wg.Wait()
+}
 
-	hLog.Debug("getting")
+func (s *GardenServer) bulkError(err error) *garden.Error {
+	if err == nil {
+		return nil
+	}
+
+	return &garden.Error{Err: s.redact(err)}
+}
+
+// destroy destroys the container, refusing to do so if it is already being
+// destroyed, and cleans up after it.
+func (s *GardenServer) destroy(handle string, hLog lager.Logger) error {
+	s.destroysL.Lock()
+
+	_, alreadyDestroying := s.destroys[handle]
+	if !alreadyDestroying {
+		s.destroys[handle] = struct{}{}
+	}
+
+	s.destroysL.Unlock()
+
+	if alreadyDestroying {
+		return ErrConcurrentDestroy
+	}
+
+	hLog.Debug("destroying")
+
+	ports := s.hostPortsToQuarantine(handle)
+
+	err := s.backend.Destroy(handle)
+
+	s.destroysL.Lock()
+	delete(s.destroys, handle)
+	s.destroysL.Unlock()
 
-	limits, err := container.CurrentDiskLimits()
 	if err != nil {
-		s.writeError(w, err, hLog)
-		return
+		return err
 	}
 
-	hLog.Info("got", lager.Data{
-		"limits": limits,
-	})
+	hLog.Info("destroyed")
 
-	s.writeResponse(w, limits)
-}
+	// a container which failed to be destroyed keeps running its main
+	// process, which is still to be restarted and probed
+	s.supervisors.stop(handle)
+	s.healthChecks.stop(handle)
 
-func (s *GardenServer) handleCurrentCPULimits(w http.ResponseWriter, r *http.Request) {
-	handle := r.FormValue(":handle")
+	s.ports.Release(ports...)
+	s.usedPorts.forgetContainer(handle)
+	s.processNames.forgetContainer(handle)
+	s.processTimeouts.forgetContainer(handle)
+	s.bomberman.Defuse(handle)
+	s.publishEvent(garden.Event{Kind: garden.EventDestroyed, Handle: handle})
 
-	hLog := s.logger.Session("current-cpu-limits", lager.Data{
-		"handle": handle,
-	})
+	return nil
+}
 
+func (s *GardenServer) destroyDryRun(w http.ResponseWriter, handle string, hLog lager.Logger) {
 	container, err := s.backend.Lookup(handle)
 	if err != nil {
 		s.writeError(w, err, hLog)
@@ -403,110 +589,119 @@ func (s *GardenServer) handleCurrentCPULimits(w http.ResponseWriter, r *http.Req
 	s.bomberman.Pause(container.Handle())
 	defer s.bomberman.Unpause(container.Handle())
 
-	hLog.Debug("getting")
+	info, err := container.Info()
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
 
-	limits, err := container.CurrentCPULimits()
+	resources, err := container.HostResources()
 	if err != nil {
 		s.writeError(w, err, hLog)
 		return
 	}
 
-	hLog.Info("got", lager.Data{
-		"limits": limits,
-	})
+	metrics, err := container.Metrics()
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
 
-	s.writeResponse(w, limits)
+	impact := garden.DestroyImpact{
+		ProcessIDs: info.ProcessIDs,
+		HostPorts:  resources.HostPorts,
+		DiskBytes:  metrics.DiskStat.ExclusiveBytesUsed,
+	}
+
+	hLog.Info("dry-run", lager.Data{"impact": impact})
+
+	s.writeResponse(w, impact)
 }
 
-func (s *GardenServer) handleNetIn(w http.ResponseWriter, r *http.Request) {
+func (s *GardenServer) handleStop(w http.ResponseWriter, r *http.Request) {
 	handle := r.FormValue(":handle")
 
-	hLog := s.logger.Session("net-in", lager.Data{
+	hLog := s.logger.Session("stop", lager.Data{
 		"handle": handle,
 	})
 
-	var request transport.NetInRequest
+	var request transport.StopRequest
 	if !s.readRequest(&request, w, r) {
 		return
 	}
 
-	hostPort := request.HostPort
-	containerPort := request.ContainerPort
-
-	container, err := s.backend.Lookup(handle)
-	if err != nil {
+	if err := s.stop(handle, request.Kill, request.Timeout, hLog); err != nil {
 		s.writeError(w, err, hLog)
 		return
 	}
 
-	s.bomberman.Pause(container.Handle())
-	defer s.bomberman.Unpause(container.Handle())
-
-	hLog.Debug("port-mapping", lager.Data{
-		"host-port":      hostPort,
-		"container-port": containerPort,
-	})
+	s.writeSuccess(w)
+}
 
-	hostPort, containerPort, err = container.NetIn(hostPort, containerPort)
-	if err != nil {
-		s.writeError(w, err, hLog)
+func (s *GardenServer) handleBulkStop(w http.ResponseWriter, r *http.Request) {
+	var request transport.BulkStopRequest
+	if !s.readRequest(&request, w, r) {
 		return
 	}
 
-	hLog.Info("port-mapped", lager.Data{
-		"host-port":      hostPort,
-		"container-port": containerPort,
+	hLog := s.logger.Session("bulk-stop", lager.Data{
+		"handles": request.Handles,
+		"kill":    request.Kill,
 	})
 
-	s.writeResponse(w, &transport.NetInResponse{
-		HostPort:      hostPort,
-		ContainerPort: containerPort,
+	errs := s.forEachHandle(request.Handles, func(handle string) error {
+		return s.stop(handle, request.Kill, 0, hLog.Session("stop", lager.Data{"handle": handle}))
 	})
-}
 
-func (s *GardenServer) handleNetOut(w http.ResponseWriter, r *http.Request) {
-	handle := r.FormValue(":handle")
+	entries := make(map[string]garden.ContainerStopEntry, len(errs))
+	for handle, err := range errs {
+		entries[handle] = garden.ContainerStopEntry{Err: s.bulkError(err)}
+	}
 
-	hLog := s.logger.Session("net-out", lager.Data{
-		"handle": handle,
-	})
+	hLog.Info("stopped")
 
-	var rule garden.NetOutRule
-	if !s.readRequest(&rule, w, r) {
-		return
-	}
+	s.writeResponse(w, entries)
+}
 
+// stop stops the container, using the timeout rather than the backend's own
+// before killing its processes if one is given and kill is false.
+func (s *GardenServer) stop(handle string, kill bool, timeout time.Duration, hLog lager.Logger) error {
 	container, err := s.backend.Lookup(handle)
 	if err != nil {
-		s.writeError(w, err, hLog)
-		return
+		return err
 	}
 
 	s.bomberman.Pause(container.Handle())
 	defer s.bomberman.Unpause(container.Handle())
 
-	hLog.Debug("allowing-out", lager.Data{
-		"rule": rule,
+	hLog.Debug("stopping", lager.Data{
+		"kill":    kill,
+		"timeout": timeout.String(),
 	})
 
-	err = container.NetOut(rule)
+	s.supervisors.stop(container.Handle())
+
+	if timeout > 0 && !kill {
+		err = container.StopWithTimeout(timeout)
+	} else {
+		err = container.Stop(kill)
+	}
 
 	if err != nil {
-		s.writeError(w, err, hLog)
-		return
+		return err
 	}
 
-	hLog.Debug("allowed", lager.Data{
-		"rule": rule,
-	})
+	hLog.Info("stopped")
 
-	s.writeSuccess(w)
+	s.publishEvent(garden.Event{Kind: garden.EventStopped, Handle: container.Handle()})
+
+	return nil
 }
 
-func (s *GardenServer) handleMetrics(w http.ResponseWriter, r *http.Request) {
+func (s *GardenServer) handlePause(w http.ResponseWriter, r *http.Request) {
 	handle := r.FormValue(":handle")
 
-	hLog := s.logger.Session("get-metrics", lager.Data{
+	hLog := s.logger.Session("pause", lager.Data{
 		"handle": handle,
 	})
 
@@ -519,19 +714,23 @@ func (s *GardenServer) handleMetrics(w http.ResponseWriter, r *http.Request) {
 	s.bomberman.Pause(container.Handle())
 	defer s.bomberman.Unpause(container.Handle())
 
-	metrics, err := container.Metrics()
+	hLog.Debug("pausing")
+
+	err = container.Pause()
 	if err != nil {
 		s.writeError(w, err, hLog)
 		return
 	}
 
-	s.writeResponse(w, metrics)
+	hLog.Info("paused")
+
+	s.writeSuccess(w)
 }
 
-func (s *GardenServer) handleProperties(w http.ResponseWriter, r *http.Request) {
+func (s *GardenServer) handleResume(w http.ResponseWriter, r *http.Request) {
 	handle := r.FormValue(":handle")
 
-	hLog := s.logger.Session("get-properties", lager.Data{
+	hLog := s.logger.Session("resume", lager.Data{
 		"handle": handle,
 	})
 
@@ -544,25 +743,31 @@ func (s *GardenServer) handleProperties(w http.ResponseWriter, r *http.Request)
 	s.bomberman.Pause(container.Handle())
 	defer s.bomberman.Unpause(container.Handle())
 
-	properties, err := container.Properties()
+	hLog.Debug("resuming")
+
+	err = container.Resume()
 	if err != nil {
 		s.writeError(w, err, hLog)
 		return
 	}
 
-	hLog.Info("got-properties")
+	hLog.Info("resumed")
 
-	s.writeResponse(w, properties)
+	s.writeSuccess(w)
 }
 
-func (s *GardenServer) handleProperty(w http.ResponseWriter, r *http.Request) {
+func (s *GardenServer) handleCheckpoint(w http.ResponseWriter, r *http.Request) {
 	handle := r.FormValue(":handle")
-	key := r.FormValue(":key")
 
-	hLog := s.logger.Session("get-property", lager.Data{
+	hLog := s.logger.Session("checkpoint", lager.Data{
 		"handle": handle,
 	})
 
+	var request transport.CheckpointRequest
+	if !s.readRequest(&request, w, r) {
+		return
+	}
+
 	container, err := s.backend.Lookup(handle)
 	if err != nil {
 		s.writeError(w, err, hLog)
@@ -572,38 +777,31 @@ func (s *GardenServer) handleProperty(w http.ResponseWriter, r *http.Request) {
 	s.bomberman.Pause(container.Handle())
 	defer s.bomberman.Unpause(container.Handle())
 
-	hLog.Debug("get-property", lager.Data{})
+	hLog.Debug("checkpointing", lager.Data{"destination": request.Destination})
 
-	value, err := container.Property(key)
+	err = container.Checkpoint(request.Destination)
 	if err != nil {
 		s.writeError(w, err, hLog)
 		return
 	}
 
-	hLog.Debug("got-property", lager.Data{})
+	hLog.Info("checkpointed", lager.Data{"destination": request.Destination})
 
-	s.writeResponse(w, map[string]string{
-		"value": value,
-	})
+	s.writeSuccess(w)
 }
 
-func (s *GardenServer) handleSetProperty(w http.ResponseWriter, r *http.Request) {
+func (s *GardenServer) handleCommit(w http.ResponseWriter, r *http.Request) {
 	handle := r.FormValue(":handle")
-	key := r.FormValue(":key")
 
-	hLog := s.logger.Session("set-property", lager.Data{
+	hLog := s.logger.Session("commit", lager.Data{
 		"handle": handle,
 	})
 
-	var request struct {
-		Value string `json:"value"`
-	}
+	var request transport.CommitRequest
 	if !s.readRequest(&request, w, r) {
 		return
 	}
 
-	value := request.Value
-
 	container, err := s.backend.Lookup(handle)
 	if err != nil {
 		s.writeError(w, err, hLog)
@@ -613,25 +811,29 @@ func (s *GardenServer) handleSetProperty(w http.ResponseWriter, r *http.Request)
 	s.bomberman.Pause(container.Handle())
 	defer s.bomberman.Unpause(container.Handle())
 
-	hLog.Debug("set-property", lager.Data{})
+	hLog.Debug("committing", lager.Data{"image-name": request.ImageName})
 
-	err = container.SetProperty(key, value)
+	image, err := container.Commit(request.ImageName)
 	if err != nil {
 		s.writeError(w, err, hLog)
 		return
 	}
 
-	hLog.Debug("set-property-complete", lager.Data{})
+	hLog.Info("committed", lager.Data{"image-name": request.ImageName, "uri": image.URI})
 
-	s.writeSuccess(w)
+	s.writeResponse(w, image)
 }
 
-func (s *GardenServer) handleRemoveProperty(w http.ResponseWriter, r *http.Request) {
+func (s *GardenServer) handleStreamIn(w http.ResponseWriter, r *http.Request) {
 	handle := r.FormValue(":handle")
-	key := r.FormValue(":key")
 
-	hLog := s.logger.Session("remove-property", lager.Data{
-		"handle": handle,
+	user := r.URL.Query().Get("user")
+	dstPath := r.URL.Query().Get("destination")
+
+	hLog := s.logger.Session("stream-in", lager.Data{
+		"handle":      handle,
+		"user":        user,
+		"destination": dstPath,
 	})
 
 	container, err := s.backend.Lookup(handle)
@@ -643,29 +845,39 @@ func (s *GardenServer) handleRemoveProperty(w http.ResponseWriter, r *http.Reque
 	s.bomberman.Pause(container.Handle())
 	defer s.bomberman.Unpause(container.Handle())
 
-	hLog.Debug("remove-property", lager.Data{})
+	hLog.Debug("streaming-in")
 
-	err = container.RemoveProperty(key)
+	err = container.StreamIn(garden.StreamInSpec{
+		User:      user,
+		Path:      dstPath,
+		TarStream: r.Body,
+	})
 	if err != nil {
-		s.writeError(w, err, hLog)
+		s.writeError(w, bodyError(r, err), hLog)
 		return
 	}
 
-	hLog.Info("removed-property", lager.Data{})
+	hLog.Info("streamed-in")
 
 	s.writeSuccess(w)
 }
 
-func (s *GardenServer) handleSetGraceTime(w http.ResponseWriter, r *http.Request) {
+func (s *GardenServer) writeSuccess(w http.ResponseWriter) {
+	s.writeResponse(w, &struct{}{})
+}
+
+func (s *GardenServer) handleStreamOut(w http.ResponseWriter, r *http.Request) {
 	handle := r.FormValue(":handle")
 
-	var graceTime time.Duration
-	if !s.readRequest(&graceTime, w, r) {
-		return
-	}
+	user := r.URL.Query().Get("user")
+	srcPath := r.URL.Query().Get("source")
+	resumeFrom := r.URL.Query().Get("resume_from")
 
-	hLog := s.logger.Session("set-grace-time", lager.Data{
-		"handle": handle,
+	hLog := s.logger.Session("stream-out", lager.Data{
+		"handle":      handle,
+		"user":        user,
+		"source":      srcPath,
+		"resume-from": resumeFrom,
 	})
 
 	container, err := s.backend.Lookup(handle)
@@ -674,34 +886,54 @@ func (s *GardenServer) handleSetGraceTime(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	container.SetGraceTime(graceTime)
+	s.bomberman.Pause(container.Handle())
+	defer s.bomberman.Unpause(container.Handle())
 
-	s.bomberman.Defuse(container.Handle())
-	s.bomberman.Strap(container)
+	hLog.Debug("streaming-out")
 
-	s.writeSuccess(w)
+	reader, err := container.StreamOut(garden.StreamOutSpec{
+		User: user,
+		Path: srcPath,
+	})
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	if resumeFrom != "" {
+		reader = resumeTar(reader, resumeFrom)
+	}
+
+	n, err := io.Copy(w, reader)
+	if err != nil {
+		if err := reader.Close(); err != nil {
+			hLog.Error("failed-to-close", err)
+		}
+
+		if n == 0 {
+			s.writeError(w, err, hLog)
+			return
+		}
+
+		// abort the response rather than ending it cleanly, so that the client
+		// can tell that the stream was truncated
+		hLog.Error("truncated", err, lager.Data{"offset": n})
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+		panic(http.ErrAbortHandler)
+	}
+
+	hLog.Info("streamed-out")
 }
 
-func (s *GardenServer) handleRun(w http.ResponseWriter, r *http.Request) {
+func (s *GardenServer) handleCurrentBandwidthLimits(w http.ResponseWriter, r *http.Request) {
 	handle := r.FormValue(":handle")
 
-	hLog := s.logger.Session("run", lager.Data{
+	hLog := s.logger.Session("current-bandwidth-limits", lager.Data{
 		"handle": handle,
 	})
 
-	var request garden.ProcessSpec
-	if !s.readRequest(&request, w, r) {
-		return
-	}
-
-	info := processDebugInfo{
-		Path:   request.Path,
-		Dir:    request.Dir,
-		User:   request.User,
-		Limits: request.Limits,
-		TTY:    request.TTY,
-	}
-
 	container, err := s.backend.Lookup(handle)
 	if err != nil {
 		s.writeError(w, err, hLog)
@@ -711,56 +943,1951 @@ func (s *GardenServer) handleRun(w http.ResponseWriter, r *http.Request) {
 	s.bomberman.Pause(container.Handle())
 	defer s.bomberman.Unpause(container.Handle())
 
-	hLog.Debug("running", lager.Data{
-		"spec": info,
-	})
+	hLog.Debug("getting")
+
+	limits, err := container.CurrentBandwidthLimits()
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	hLog.Info("got", lager.Data{
+		"limits": limits,
+	})
+
+	s.writeResponse(w, limits)
+}
+
+func (s *GardenServer) handleCurrentMemoryLimits(w http.ResponseWriter, r *http.Request) {
+	handle := r.FormValue(":handle")
+
+	hLog := s.logger.Session("current-memory-limits", lager.Data{
+		"handle": handle,
+	})
+
+	container, err := s.backend.Lookup(handle)
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	s.bomberman.Pause(container.Handle())
+	defer s.bomberman.Unpause(container.Handle())
+
+	hLog.Debug("getting")
+
+	limits, err := container.CurrentMemoryLimits()
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	hLog.Info("got", lager.Data{
+		"limits": limits,
+	})
+
+	s.writeResponse(w, limits)
+}
+
+func (s *GardenServer) handleLimitBandwidth(w http.ResponseWriter, r *http.Request) {
+	handle := r.FormValue(":handle")
+
+	hLog := s.logger.Session("limit-bandwidth", lager.Data{
+		"handle": handle,
+	})
+
+	var limits garden.BandwidthLimits
+	if !s.readRequest(&limits, w, r) {
+		return
+	}
+
+	container, err := s.backend.Lookup(handle)
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	s.bomberman.Pause(container.Handle())
+	defer s.bomberman.Unpause(container.Handle())
+
+	hLog.Debug("limiting", lager.Data{
+		"limits": limits,
+	})
+
+	if err := container.LimitBandwidth(limits); err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	hLog.Info("limited")
+
+	s.writeSuccess(w)
+}
+
+func (s *GardenServer) handleLimitMemory(w http.ResponseWriter, r *http.Request) {
+	handle := r.FormValue(":handle")
+
+	hLog := s.logger.Session("limit-memory", lager.Data{
+		"handle": handle,
+	})
+
+	var limits garden.MemoryLimits
+	if !s.readRequest(&limits, w, r) {
+		return
+	}
+
+	container, err := s.backend.Lookup(handle)
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	s.bomberman.Pause(container.Handle())
+	defer s.bomberman.Unpause(container.Handle())
+
+	hLog.Debug("limiting", lager.Data{
+		"limits": limits,
+	})
+
+	if err := container.LimitMemory(limits); err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	hLog.Info("limited")
+
+	s.writeSuccess(w)
+}
+
+func (s *GardenServer) handleCurrentDiskLimits(w http.ResponseWriter, r *http.Request) {
+	handle := r.FormValue(":handle")
+
+	hLog := s.logger.Session("current-disk-limits", lager.Data{
+		"handle": handle,
+	})
+
+	container, err := s.backend.Lookup(handle)
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	s.bomberman.Pause(container.Handle())
+	defer s.bomberman.Unpause(container.Handle())
+
+	hLog.Debug("getting")
+
+	limits, err := container.CurrentDiskLimits()
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	hLog.Info("got", lager.Data{
+		"limits": limits,
+	})
+
+	s.writeResponse(w, limits)
+}
+
+func (s *GardenServer) handleLimitCPU(w http.ResponseWriter, r *http.Request) {
+	handle := r.FormValue(":handle")
+
+	hLog := s.logger.Session("limit-cpu", lager.Data{
+		"handle": handle,
+	})
+
+	var limits garden.CPULimits
+	if !s.readRequest(&limits, w, r) {
+		return
+	}
+
+	container, err := s.backend.Lookup(handle)
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	s.bomberman.Pause(container.Handle())
+	defer s.bomberman.Unpause(container.Handle())
+
+	hLog.Debug("limiting", lager.Data{
+		"limits": limits,
+	})
+
+	if err := container.LimitCPU(limits); err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	hLog.Info("limited")
+
+	s.writeSuccess(w)
+}
+
+func (s *GardenServer) handleCurrentCPULimits(w http.ResponseWriter, r *http.Request) {
+	handle := r.FormValue(":handle")
+
+	hLog := s.logger.Session("current-cpu-limits", lager.Data{
+		"handle": handle,
+	})
+
+	container, err := s.backend.Lookup(handle)
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	s.bomberman.Pause(container.Handle())
+	defer s.bomberman.Unpause(container.Handle())
+
+	hLog.Debug("getting")
+
+	limits, err := container.CurrentCPULimits()
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	hLog.Info("got", lager.Data{
+		"limits": limits,
+	})
+
+	s.writeResponse(w, limits)
+}
+
+func (s *GardenServer) handleCurrentPidLimits(w http.ResponseWriter, r *http.Request) {
+	handle := r.FormValue(":handle")
+
+	hLog := s.logger.Session("current-pid-limits", lager.Data{
+		"handle": handle,
+	})
+
+	container, err := s.backend.Lookup(handle)
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	s.bomberman.Pause(container.Handle())
+	defer s.bomberman.Unpause(container.Handle())
+
+	hLog.Debug("getting")
+
+	limits, err := container.CurrentPidLimits()
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	hLog.Info("got", lager.Data{
+		"limits": limits,
+	})
+
+	s.writeResponse(w, limits)
+}
+
+func (s *GardenServer) handleLimitPids(w http.ResponseWriter, r *http.Request) {
+	handle := r.FormValue(":handle")
+
+	hLog := s.logger.Session("limit-pids", lager.Data{
+		"handle": handle,
+	})
+
+	var limits garden.PidLimits
+	if !s.readRequest(&limits, w, r) {
+		return
+	}
+
+	container, err := s.backend.Lookup(handle)
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	s.bomberman.Pause(container.Handle())
+	defer s.bomberman.Unpause(container.Handle())
+
+	hLog.Debug("limiting", lager.Data{
+		"limits": limits,
+	})
+
+	if err := container.LimitPids(limits); err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	hLog.Info("limited")
+
+	s.writeSuccess(w)
+}
+
+func (s *GardenServer) handleCurrentBlockIOLimits(w http.ResponseWriter, r *http.Request) {
+	handle := r.FormValue(":handle")
+
+	hLog := s.logger.Session("current-block-io-limits", lager.Data{
+		"handle": handle,
+	})
+
+	container, err := s.backend.Lookup(handle)
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	s.bomberman.Pause(container.Handle())
+	defer s.bomberman.Unpause(container.Handle())
+
+	hLog.Debug("getting")
+
+	limits, err := container.CurrentBlockIOLimits()
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	hLog.Info("got", lager.Data{
+		"limits": limits,
+	})
+
+	s.writeResponse(w, limits)
+}
+
+func (s *GardenServer) handleLimitBlockIO(w http.ResponseWriter, r *http.Request) {
+	handle := r.FormValue(":handle")
+
+	hLog := s.logger.Session("limit-block-io", lager.Data{
+		"handle": handle,
+	})
+
+	var limits garden.BlockIOLimits
+	if !s.readRequest(&limits, w, r) {
+		return
+	}
+
+	container, err := s.backend.Lookup(handle)
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	s.bomberman.Pause(container.Handle())
+	defer s.bomberman.Unpause(container.Handle())
+
+	hLog.Debug("limiting", lager.Data{
+		"limits": limits,
+	})
+
+	if err := container.LimitBlockIO(limits); err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	hLog.Info("limited")
+
+	s.writeSuccess(w)
+}
+
+func (s *GardenServer) handleCurrentLimits(w http.ResponseWriter, r *http.Request) {
+	handle := r.FormValue(":handle")
+
+	hLog := s.logger.Session("current-limits", lager.Data{
+		"handle": handle,
+	})
+
+	container, err := s.backend.Lookup(handle)
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	s.bomberman.Pause(container.Handle())
+	defer s.bomberman.Unpause(container.Handle())
+
+	hLog.Debug("getting")
+
+	limits, err := container.CurrentLimits()
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	hLog.Info("got", lager.Data{
+		"limits": limits,
+	})
+
+	s.writeResponse(w, limits)
+}
+
+func (s *GardenServer) handleLimitAll(w http.ResponseWriter, r *http.Request) {
+	handle := r.FormValue(":handle")
+
+	hLog := s.logger.Session("limit-all", lager.Data{
+		"handle": handle,
+	})
+
+	var limits garden.Limits
+	if !s.readRequest(&limits, w, r) {
+		return
+	}
+
+	container, err := s.backend.Lookup(handle)
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	s.bomberman.Pause(container.Handle())
+	defer s.bomberman.Unpause(container.Handle())
+
+	hLog.Debug("limiting", lager.Data{
+		"limits": limits,
+	})
+
+	if err := container.LimitAll(limits); err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	hLog.Info("limited")
+
+	s.writeSuccess(w)
+}
+
+func (s *GardenServer) handleNetIn(w http.ResponseWriter, r *http.Request) {
+	handle := r.FormValue(":handle")
+
+	hLog := s.logger.Session("net-in", lager.Data{
+		"handle": handle,
+	})
+
+	var request transport.NetInRequest
+	if !s.readRequest(&request, w, r) {
+		return
+	}
+
+	if request.HostIP != "" {
+		ip := net.ParseIP(request.HostIP)
+		if ip == nil {
+			s.writeError(w, garden.MalformedRequestError{Cause: fmt.Sprintf("host_ip: %q is not an IP", request.HostIP)}, hLog)
+			return
+		}
+
+		// IPv6 addresses have many spellings; the backend is given one
+		request.HostIP = ip.String()
+	}
+
+	hostPort := request.HostPort
+	containerPort := request.ContainerPort
+
+	container, err := s.backend.Lookup(handle)
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	s.bomberman.Pause(container.Handle())
+	defer s.bomberman.Unpause(container.Handle())
+
+	if len(s.portRanges) > 0 {
+		var release func()
+		hostPort, release, err = s.hostPortInRange(container, hostPort)
+		if err != nil {
+			s.writeError(w, err, hLog)
+			return
+		}
+		defer release()
+	}
+
+	if until, quarantined := s.ports.Until(hostPort); quarantined {
+		s.writeError(w, portQuarantinedError(hostPort, until), hLog)
+		return
+	}
+
+	hLog.Debug("port-mapping", lager.Data{
+		"host-ip":        request.HostIP,
+		"host-port":      hostPort,
+		"container-port": containerPort,
+	})
+
+	mapping, err := s.netIn(container, request.HostIP, hostPort, containerPort, hLog)
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	s.usedPorts.add(container.Handle(), mapping.HostPort)
+
+	hLog.Info("port-mapped", lager.Data{
+		"host-ip":        mapping.HostIP,
+		"host-port":      mapping.HostPort,
+		"container-port": mapping.ContainerPort,
+	})
+
+	s.writeResponse(w, &transport.NetInResponse{
+		HostPort:      mapping.HostPort,
+		ContainerPort: mapping.ContainerPort,
+		HostIP:        mapping.HostIP,
+	})
+}
+
+// quarantinedPortAttempts is how many host ports netIn lets the backend
+// allocate before giving up, when each one it allocates is quarantined.
+const quarantinedPortAttempts = 5
+
+// netIn maps the port of the container. When the backend allocates a
+// quarantined host port, because none was requested, the mapping is removed
+// and another port is allocated; the quarantined ones stay mapped until then
+// so that the backend does not allocate them again.
+func (s *GardenServer) netIn(container garden.Container, hostIP string, hostPort, containerPort uint32, hLog lager.Logger) (garden.PortMapping, error) {
+	var quarantined []uint32
+	defer func() {
+		for _, port := range quarantined {
+			if err := container.RemoveNetIn(port); err != nil {
+				hLog.Error("failed-to-remove-quarantined-port", err, lager.Data{"host-port": port})
+			}
+		}
+	}()
+
+	var err error
+	for attempt := 0; attempt < quarantinedPortAttempts; attempt++ {
+		mapping := garden.PortMapping{HostIP: hostIP}
+		if hostIP == "" {
+			mapping.HostPort, mapping.ContainerPort, err = container.NetIn(hostPort, containerPort)
+		} else {
+			mapping, err = container.NetInWithHostIP(hostIP, hostPort, containerPort)
+		}
+
+		if err != nil {
+			return garden.PortMapping{}, err
+		}
+
+		until, isQuarantined := s.ports.Until(mapping.HostPort)
+		if !isQuarantined {
+			return mapping, nil
+		}
+
+		hLog.Info("allocated-quarantined-port", lager.Data{"host-port": mapping.HostPort})
+
+		quarantined = append(quarantined, mapping.HostPort)
+		err = portQuarantinedError(mapping.HostPort, until)
+	}
+
+	return garden.PortMapping{}, err
+}
+
+func portQuarantinedError(hostPort uint32, until time.Time) error {
+	return garden.PortUnavailableError{
+		HostPort: hostPort,
+		Cause:    fmt.Sprintf("host port %d is quarantined until %s", hostPort, until.UTC().Format(time.RFC3339)),
+	}
+}
+
+func (s *GardenServer) handleListNetIn(w http.ResponseWriter, r *http.Request) {
+	handle := r.FormValue(":handle")
+
+	hLog := s.logger.Session("list-net-in", lager.Data{
+		"handle": handle,
+	})
+
+	container, err := s.backend.Lookup(handle)
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	s.bomberman.Pause(container.Handle())
+	defer s.bomberman.Unpause(container.Handle())
+
+	mappings, err := container.NetInMappings()
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	if mappings == nil {
+		mappings = []garden.PortMapping{}
+	}
+
+	s.writeResponse(w, mappings)
+}
+
+func (s *GardenServer) handleRemoveNetIn(w http.ResponseWriter, r *http.Request) {
+	handle := r.FormValue(":handle")
+
+	hLog := s.logger.Session("remove-net-in", lager.Data{
+		"handle": handle,
+	})
+
+	hostPort, err := strconv.ParseUint(r.FormValue(":host_port"), 10, 32)
+	if err != nil || hostPort == 0 || hostPort > transport.MaxPort {
+		s.writeError(w, garden.MalformedRequestError{
+			Cause: fmt.Sprintf("host_port: %q is not a port", r.FormValue(":host_port")),
+		}, hLog)
+		return
+	}
+
+	container, err := s.backend.Lookup(handle)
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	s.bomberman.Pause(container.Handle())
+	defer s.bomberman.Unpause(container.Handle())
+
+	err = container.RemoveNetIn(uint32(hostPort))
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	s.usedPorts.remove(uint32(hostPort))
+
+	// the port may still receive traffic meant for this container, as it
+	// would once the container was destroyed
+	s.ports.Release(uint32(hostPort))
+
+	hLog.Info("removed", lager.Data{"host-port": hostPort})
+
+	s.writeSuccess(w)
+}
+
+// hostPortsToQuarantine returns the host ports mapped to a container, so that
+// they may be quarantined once it has been destroyed.
+func (s *GardenServer) hostPortsToQuarantine(handle string) []uint32 {
+	if s.portReuseGracePeriod == 0 {
+		return nil
+	}
+
+	container, err := s.backend.Lookup(handle)
+	if err != nil {
+		return nil
+	}
+
+	info, err := container.Info()
+	if err != nil {
+		s.logger.Error("failed-to-get-ports-to-quarantine", err, lager.Data{"handle": handle})
+		return nil
+	}
+
+	ports := make([]uint32, len(info.MappedPorts))
+	for i, mapping := range info.MappedPorts {
+		ports[i] = mapping.HostPort
+	}
+
+	return ports
+}
+
+func (s *GardenServer) handlePortAllocations(w http.ResponseWriter, r *http.Request) {
+	hLog := s.logger.Session("port-allocations")
+
+	allocations, err := s.portAllocations(hLog)
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	s.writeResponse(w, garden.PortAllocations{
+		Allocations: allocations,
+		Quarantined: s.ports.Ports(),
+	})
+}
+
+// portAllocations returns the host ports mapped to every container. Containers
+// whose info cannot be retrieved are skipped.
+func (s *GardenServer) portAllocations(logger lager.Logger) ([]garden.PortAllocation, error) {
+	containers, err := s.backend.Containers(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	allocations := []garden.PortAllocation{}
+	for _, container := range containers {
+		info, err := container.Info()
+		if err != nil {
+			logger.Error("failed-to-get-info", err, lager.Data{"handle": container.Handle()})
+			continue
+		}
+
+		for _, mapping := range info.MappedPorts {
+			allocations = append(allocations, garden.PortAllocation{
+				Handle:        container.Handle(),
+				HostPort:      mapping.HostPort,
+				ContainerPort: mapping.ContainerPort,
+				HostIP:        mapping.HostIP,
+			})
+		}
+	}
+
+	return allocations, nil
+}
+
+func (s *GardenServer) handleNetOut(w http.ResponseWriter, r *http.Request) {
+	handle := r.FormValue(":handle")
+
+	hLog := s.logger.Session("net-out", lager.Data{
+		"handle": handle,
+	})
+
+	var rule garden.NetOutRule
+	if !s.readRequest(&rule, w, r) {
+		return
+	}
+
+	if err := transport.ValidateNetOutNetworks(rule); err != nil {
+		s.writeError(w, garden.MalformedRequestError{Cause: err.Error()}, hLog)
+		return
+	}
+
+	requestedID := rule.ID
+	if rule.ID == "" {
+		id, err := newRandomID()
+		if err != nil {
+			s.writeError(w, err, hLog)
+			return
+		}
+
+		rule.ID = id
+	}
+
+	container, err := s.backend.Lookup(handle)
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	s.bomberman.Pause(container.Handle())
+	defer s.bomberman.Unpause(container.Handle())
+
+	if requestedID != "" {
+		inUse, err := netOutRuleIDs(container)
+		if err != nil {
+			s.writeError(w, err, hLog)
+			return
+		}
+
+		if inUse[requestedID] {
+			s.writeError(w, garden.MalformedRequestError{Cause: fmt.Sprintf("id: %q is already in use", requestedID)}, hLog)
+			return
+		}
+	}
+
+	hLog.Debug("allowing-out", lager.Data{
+		"rule": rule,
+	})
+
+	err = container.NetOut(rule)
+
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	hLog.Debug("allowed", lager.Data{
+		"rule": rule,
+	})
+
+	s.writeResponse(w, &struct {
+		ID string `json:"id"`
+	}{
+		ID: rule.ID,
+	})
+}
+
+func (s *GardenServer) handleBulkNetOut(w http.ResponseWriter, r *http.Request) {
+	handle := r.FormValue(":handle")
+
+	hLog := s.logger.Session("bulk-net-out", lager.Data{
+		"handle": handle,
+	})
+
+	var request transport.BulkNetOutRequest
+	if !s.readRequest(&request, w, r) {
+		return
+	}
+
+	ids := make([]string, len(request.Rules))
+	seen := make(map[string]bool, len(request.Rules))
+	var requested []int
+	for i := range request.Rules {
+		if err := transport.ValidateNetOutNetworks(request.Rules[i]); err != nil {
+			s.writeError(w, garden.MalformedRequestError{Cause: fmt.Sprintf("rules[%d].%s", i, err)}, hLog)
+			return
+		}
+
+		id := request.Rules[i].ID
+		if id == "" {
+			var err error
+			id, err = newRandomID()
+			if err != nil {
+				s.writeError(w, err, hLog)
+				return
+			}
+
+			request.Rules[i].ID = id
+		} else if seen[id] {
+			s.writeError(w, garden.MalformedRequestError{Cause: fmt.Sprintf("rules[%d].id: %q is repeated", i, id)}, hLog)
+			return
+		} else {
+			requested = append(requested, i)
+		}
+
+		seen[id] = true
+		ids[i] = id
+	}
+
+	container, err := s.backend.Lookup(handle)
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	s.bomberman.Pause(container.Handle())
+	defer s.bomberman.Unpause(container.Handle())
+
+	if len(requested) > 0 {
+		inUse, err := netOutRuleIDs(container)
+		if err != nil {
+			s.writeError(w, err, hLog)
+			return
+		}
+
+		for _, i := range requested {
+			if id := request.Rules[i].ID; inUse[id] {
+				s.writeError(w, garden.MalformedRequestError{Cause: fmt.Sprintf("rules[%d].id: %q is already in use", i, id)}, hLog)
+				return
+			}
+		}
+	}
+
+	hLog.Debug("allowing-out", lager.Data{
+		"rules": len(request.Rules),
+	})
+
+	err = container.BulkNetOut(request.Rules)
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	hLog.Debug("allowed", lager.Data{
+		"rules": len(request.Rules),
+	})
+
+	s.writeResponse(w, &struct {
+		IDs []string `json:"ids"`
+	}{
+		IDs: ids,
+	})
+}
+
+// netOutRuleIDs returns the IDs of the container's active outbound rules, so
+// that a rule given an ID by the client cannot take one of theirs.
+func netOutRuleIDs(container garden.Container) (map[string]bool, error) {
+	rules, err := container.NetOutRules()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make(map[string]bool, len(rules))
+	for _, rule := range rules {
+		ids[rule.ID] = true
+	}
+
+	return ids, nil
+}
+
+func (s *GardenServer) handleListNetOut(w http.ResponseWriter, r *http.Request) {
+	handle := r.FormValue(":handle")
+
+	hLog := s.logger.Session("list-net-out", lager.Data{
+		"handle": handle,
+	})
+
+	container, err := s.backend.Lookup(handle)
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	s.bomberman.Pause(container.Handle())
+	defer s.bomberman.Unpause(container.Handle())
+
+	rules, err := container.NetOutRules()
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	if rules == nil {
+		rules = []garden.NetOutRule{}
+	}
+
+	s.writeResponse(w, rules)
+}
+
+func (s *GardenServer) handleRemoveNetOut(w http.ResponseWriter, r *http.Request) {
+	handle := r.FormValue(":handle")
+	ruleID := r.FormValue(":rule_id")
+
+	hLog := s.logger.Session("remove-net-out", lager.Data{
+		"handle": handle,
+		"rule":   ruleID,
+	})
+
+	container, err := s.backend.Lookup(handle)
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	s.bomberman.Pause(container.Handle())
+	defer s.bomberman.Unpause(container.Handle())
+
+	err = container.RemoveNetOut(ruleID)
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	hLog.Info("removed")
+
+	s.writeSuccess(w)
+}
+
+func (s *GardenServer) handleRemoveAllNetOut(w http.ResponseWriter, r *http.Request) {
+	handle := r.FormValue(":handle")
+
+	hLog := s.logger.Session("remove-all-net-out", lager.Data{
+		"handle": handle,
+	})
+
+	container, err := s.backend.Lookup(handle)
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	s.bomberman.Pause(container.Handle())
+	defer s.bomberman.Unpause(container.Handle())
+
+	err = container.RemoveAllNetOut()
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	hLog.Info("removed")
+
+	s.writeSuccess(w)
+}
+
+// newRandomID returns a random ID, e.g. for a NetOut rule given without one.
+func newRandomID() (string, error) {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(id), nil
+}
+
+func (s *GardenServer) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	handle := r.FormValue(":handle")
+
+	hLog := s.logger.Session("get-metrics", lager.Data{
+		"handle": handle,
+	})
+
+	container, err := s.backend.Lookup(handle)
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	s.bomberman.Pause(container.Handle())
+	defer s.bomberman.Unpause(container.Handle())
+
+	metrics, err := container.Metrics()
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	s.writeResponse(w, metrics)
+}
+
+func (s *GardenServer) handleDiskUsage(w http.ResponseWriter, r *http.Request) {
+	handle := r.FormValue(":handle")
+	paths := r.URL.Query()[routes.DiskUsagePathParam]
+
+	hLog := s.logger.Session("get-disk-usage", lager.Data{
+		"handle": handle,
+		"paths":  paths,
+	})
+
+	if err := validateDiskUsagePaths(paths); err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	container, err := s.backend.Lookup(handle)
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	s.bomberman.Pause(container.Handle())
+	defer s.bomberman.Unpause(container.Handle())
+
+	usage, err := container.DiskUsage(paths)
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	if usage == nil {
+		usage = []garden.PathDiskUsage{}
+	}
+
+	s.writeResponse(w, usage)
+}
+
+func validateDiskUsagePaths(paths []string) error {
+	if len(paths) == 0 {
+		return garden.MalformedRequestError{Cause: "path: at least one path is required"}
+	}
+
+	for _, p := range paths {
+		if !path.IsAbs(p) {
+			return garden.MalformedRequestError{Cause: fmt.Sprintf("path: %q is not absolute", p)}
+		}
+	}
+
+	return nil
+}
+
+func (s *GardenServer) handleProcesses(w http.ResponseWriter, r *http.Request) {
+	handle := r.FormValue(":handle")
+
+	hLog := s.logger.Session("list-processes", lager.Data{
+		"handle": handle,
+	})
+
+	container, err := s.backend.Lookup(handle)
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	s.bomberman.Pause(container.Handle())
+	defer s.bomberman.Unpause(container.Handle())
+
+	processes, err := container.Processes()
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	if processes == nil {
+		processes = []garden.ProcessInfo{}
+	}
+
+	for i, process := range processes {
+		if process.Name == "" {
+			processes[i].Name = s.processNames.name(container.Handle(), process.ID)
+		}
+	}
+
+	s.writeResponse(w, processes)
+}
+
+func (s *GardenServer) handleProcessMetrics(w http.ResponseWriter, r *http.Request) {
+	handle := r.FormValue(":handle")
+
+	hLog := s.logger.Session("process-metrics", lager.Data{
+		"handle": handle,
+	})
+
+	container, err := s.backend.Lookup(handle)
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	s.bomberman.Pause(container.Handle())
+	defer s.bomberman.Unpause(container.Handle())
+
+	metrics, err := container.ProcessMetrics()
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	if metrics == nil {
+		metrics = map[string]garden.ProcessMetrics{}
+	}
+
+	s.writeResponse(w, metrics)
+}
+
+func (s *GardenServer) handleHostResources(w http.ResponseWriter, r *http.Request) {
+	handle := r.FormValue(":handle")
+
+	hLog := s.logger.Session("get-host-resources", lager.Data{
+		"handle": handle,
+	})
+
+	container, err := s.backend.Lookup(handle)
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	s.bomberman.Pause(container.Handle())
+	defer s.bomberman.Unpause(container.Handle())
+
+	resources, err := container.HostResources()
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	s.writeResponse(w, resources)
+}
+
+func (s *GardenServer) handleProperties(w http.ResponseWriter, r *http.Request) {
+	handle := r.FormValue(":handle")
+
+	hLog := s.logger.Session("get-properties", lager.Data{
+		"handle": handle,
+	})
+
+	container, err := s.backend.Lookup(handle)
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	s.bomberman.Pause(container.Handle())
+	defer s.bomberman.Unpause(container.Handle())
+
+	properties, err := container.Properties()
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	hLog.Info("got-properties")
+
+	s.writeResponse(w, properties)
+}
+
+func (s *GardenServer) handleProperty(w http.ResponseWriter, r *http.Request) {
+	handle := r.FormValue(":handle")
+	key := r.FormValue(":key")
+
+	hLog := s.logger.Session("get-property", lager.Data{
+		"handle": handle,
+	})
+
+	container, err := s.backend.Lookup(handle)
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	s.bomberman.Pause(container.Handle())
+	defer s.bomberman.Unpause(container.Handle())
+
+	hLog.Debug("get-property", lager.Data{})
+
+	value, err := container.Property(key)
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	hLog.Debug("got-property", lager.Data{})
+
+	s.writeResponse(w, map[string]string{
+		"value": value,
+	})
+}
+
+func (s *GardenServer) handleSetProperty(w http.ResponseWriter, r *http.Request) {
+	handle := r.FormValue(":handle")
+	key := r.FormValue(":key")
+
+	hLog := s.logger.Session("set-property", lager.Data{
+		"handle": handle,
+	})
+
+	var request struct {
+		Value string `json:"value"`
+	}
+	if !s.readRequest(&request, w, r) {
+		return
+	}
+
+	value := request.Value
+
+	container, err := s.backend.Lookup(handle)
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	s.bomberman.Pause(container.Handle())
+	defer s.bomberman.Unpause(container.Handle())
+
+	hLog.Debug("set-property", lager.Data{})
+
+	s.propertiesL.Lock()
+	err = container.SetProperty(key, value)
+	s.propertiesL.Unlock()
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	hLog.Debug("set-property-complete", lager.Data{})
+
+	s.publishEvent(garden.Event{Kind: garden.EventPropertyChanged, Handle: container.Handle(), Property: key})
+
+	s.writeSuccess(w)
+}
+
+func (s *GardenServer) handleRemoveProperty(w http.ResponseWriter, r *http.Request) {
+	handle := r.FormValue(":handle")
+	key := r.FormValue(":key")
+
+	hLog := s.logger.Session("remove-property", lager.Data{
+		"handle": handle,
+	})
+
+	container, err := s.backend.Lookup(handle)
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	s.bomberman.Pause(container.Handle())
+	defer s.bomberman.Unpause(container.Handle())
+
+	hLog.Debug("remove-property", lager.Data{})
+
+	s.propertiesL.Lock()
+	err = container.RemoveProperty(key)
+	s.propertiesL.Unlock()
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	hLog.Info("removed-property", lager.Data{})
+
+	s.publishEvent(garden.Event{Kind: garden.EventPropertyChanged, Handle: container.Handle(), Property: key})
+
+	s.writeSuccess(w)
+}
+
+func (s *GardenServer) handleSetProperties(w http.ResponseWriter, r *http.Request) {
+	handle := r.FormValue(":handle")
+
+	hLog := s.logger.Session("set-properties", lager.Data{
+		"handle": handle,
+	})
+
+	var properties garden.Properties
+	if !s.readRequest(&properties, w, r) {
+		return
+	}
+
+	container, err := s.backend.Lookup(handle)
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	s.bomberman.Pause(container.Handle())
+	defer s.bomberman.Unpause(container.Handle())
+
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	hLog.Debug("set-properties", lager.Data{"count": len(names)})
+
+	err = s.changeProperties(container, names, func(name string) error {
+		return container.SetProperty(name, properties[name])
+	})
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	hLog.Info("set-properties-complete", lager.Data{"count": len(names)})
+
+	s.writeSuccess(w)
+}
+
+func (s *GardenServer) handleRemoveProperties(w http.ResponseWriter, r *http.Request) {
+	handle := r.FormValue(":handle")
+	names := r.URL.Query()[routes.PropertyKeyParam]
+
+	hLog := s.logger.Session("remove-properties", lager.Data{
+		"handle": handle,
+	})
+
+	container, err := s.backend.Lookup(handle)
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	s.bomberman.Pause(container.Handle())
+	defer s.bomberman.Unpause(container.Handle())
+
+	hLog.Debug("remove-properties", lager.Data{"count": len(names)})
+
+	err = s.changeProperties(container, names, container.RemoveProperty)
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	hLog.Info("removed-properties", lager.Data{"count": len(names)})
+
+	s.writeSuccess(w)
+}
+
+// changeProperties changes each of the named properties of the container in
+// turn, publishing an event for each, and stops at the first which fails to
+// change. Properties changed before it remain changed.
+func (s *GardenServer) changeProperties(container garden.Container, names []string, change func(name string) error) error {
+	s.propertiesL.Lock()
+	defer s.propertiesL.Unlock()
+
+	for _, name := range names {
+		if err := change(name); err != nil {
+			return err
+		}
+
+		s.publishEvent(garden.Event{Kind: garden.EventPropertyChanged, Handle: container.Handle(), Property: name})
+	}
+
+	return nil
+}
+
+func (s *GardenServer) handleCASProperty(w http.ResponseWriter, r *http.Request) {
+	handle := r.FormValue(":handle")
+	key := r.FormValue(":key")
+
+	hLog := s.logger.Session("cas-property", lager.Data{
+		"handle": handle,
+	})
+
+	var request transport.CASPropertyRequest
+	if !s.readRequest(&request, w, r) {
+		return
+	}
+
+	container, err := s.backend.Lookup(handle)
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	s.bomberman.Pause(container.Handle())
+	defer s.bomberman.Unpause(container.Handle())
+
+	hLog.Debug("cas-property", lager.Data{})
+
+	if err := s.casProperty(container, key, request.OldValue, request.Value); err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	hLog.Info("swapped-property", lager.Data{})
+
+	s.publishEvent(garden.Event{Kind: garden.EventPropertyChanged, Handle: container.Handle(), Property: key})
+
+	s.writeSuccess(w)
+}
+
+// casProperty sets the property to the new value if its current value, which
+// is empty if it is not set, is the old value, and otherwise fails with a
+// garden.PropertyConflictError.
+func (s *GardenServer) casProperty(container garden.Container, key, oldValue, newValue string) error {
+	s.propertiesL.Lock()
+	defer s.propertiesL.Unlock()
+
+	properties, err := container.Properties()
+	if err != nil {
+		return err
+	}
+
+	if current := properties[key]; current != oldValue {
+		return garden.PropertyConflictError{Handle: container.Handle(), Name: key, Value: current}
+	}
+
+	return container.SetProperty(key, newValue)
+}
+
+func (s *GardenServer) handleSetGraceTime(w http.ResponseWriter, r *http.Request) {
+	handle := r.FormValue(":handle")
+
+	var graceTime time.Duration
+	if !s.readRequest(&graceTime, w, r) {
+		return
+	}
+
+	hLog := s.logger.Session("set-grace-time", lager.Data{
+		"handle": handle,
+	})
+
+	container, err := s.backend.Lookup(handle)
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	if err := container.SetGraceTime(graceTime); err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	hLog.Info("set", lager.Data{"grace-time": graceTime.String()})
+
+	s.bomberman.Defuse(container.Handle())
+	s.bomberman.Strap(container)
+
+	s.writeSuccess(w)
+}
+
+func (s *GardenServer) handleSetDescription(w http.ResponseWriter, r *http.Request) {
+	handle := r.FormValue(":handle")
+
+	var description string
+	if !s.readRequest(&description, w, r) {
+		return
+	}
+
+	hLog := s.logger.Session("set-description", lager.Data{
+		"handle": handle,
+	})
+
+	if err := validateDescription(description); err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	container, err := s.backend.Lookup(handle)
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	s.bomberman.Pause(container.Handle())
+	defer s.bomberman.Unpause(container.Handle())
+
+	if err := container.SetDescription(description); err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	hLog.Info("set")
+
+	s.writeSuccess(w)
+}
+
+// validateDescription rejects descriptions longer than
+// garden.MaxDescriptionLength.
+func validateDescription(description string) error {
+	if len(description) > garden.MaxDescriptionLength {
+		return garden.MalformedRequestError{
+			Cause: fmt.Sprintf("description: %d bytes exceeds %d", len(description), garden.MaxDescriptionLength),
+		}
+	}
+
+	return nil
+}
+
+// validateDNS rejects nameservers which are not IPs, and search domains and
+// hosts entries which could not be written safely into the container's
+// resolv.conf and /etc/hosts.
+func validateDNS(spec garden.ContainerSpec) error {
+	malformed := func(format string, args ...interface{}) error {
+		return garden.MalformedRequestError{Cause: fmt.Sprintf(format, args...)}
+	}
+
+	for i, nameserver := range spec.Nameservers {
+		if net.ParseIP(nameserver) == nil {
+			return malformed("nameservers[%d]: %q is not an IP", i, nameserver)
+		}
+	}
+
+	for i, domain := range spec.SearchDomains {
+		if !validDNSName(domain) {
+			return malformed("search_domains[%d]: %q is not a domain name", i, domain)
+		}
+	}
+
+	for i, entry := range spec.Hosts {
+		if net.ParseIP(entry.IP) == nil {
+			return malformed("hosts[%d].ip: %q is not an IP", i, entry.IP)
+		}
+
+		if len(entry.Hostnames) == 0 {
+			return malformed("hosts[%d].hostnames: at least one hostname is required", i)
+		}
+
+		for j, hostname := range entry.Hostnames {
+			if !validDNSName(hostname) {
+				return malformed("hosts[%d].hostnames[%d]: %q is not a hostname", i, j, hostname)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateCapabilities rejects capabilities which are not known, and those
+// which are both added and dropped.
+func validateCapabilities(caps garden.Capabilities) error {
+	known := map[string]bool{}
+	for _, capability := range garden.KnownCapabilities {
+		known[capability] = true
+	}
+
+	added := map[string]bool{}
+	for i, capability := range caps.Add {
+		if !known[capability] {
+			return garden.MalformedRequestError{
+				Cause: fmt.Sprintf("capabilities.add[%d]: %q is not a known capability", i, capability),
+			}
+		}
+
+		added[capability] = true
+	}
+
+	for i, capability := range caps.Drop {
+		if !known[capability] && capability != garden.AllCapabilities {
+			return garden.MalformedRequestError{
+				Cause: fmt.Sprintf("capabilities.drop[%d]: %q is not a known capability", i, capability),
+			}
+		}
+
+		if added[capability] {
+			return garden.MalformedRequestError{
+				Cause: fmt.Sprintf("capabilities.drop[%d]: %q is also added", i, capability),
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateOOMScoreAdj rejects an OOM score adjustment outside the kernel's
+// bounds, which backends would otherwise fail to apply only once the process
+// has started.
+func validateOOMScoreAdj(field string, adj *int) error {
+	if adj != nil && (*adj < garden.MinOOMScoreAdj || *adj > garden.MaxOOMScoreAdj) {
+		return garden.MalformedRequestError{
+			Cause: fmt.Sprintf("%s: %d is not between %d and %d", field, *adj, garden.MinOOMScoreAdj, garden.MaxOOMScoreAdj),
+		}
+	}
+
+	return nil
+}
+
+// validHostname reports whether the name may be used as a container's
+// hostname.
+func validHostname(name string) bool {
+	return len(name) <= garden.MaxHostnameLength && validDNSName(name)
+}
+
+// validDNSName reports whether the name is a valid DNS name, made of labels
+// of letters, digits and hyphens which neither start nor end with a hyphen.
+// A trailing dot is permitted.
+func validDNSName(name string) bool {
+	name = strings.TrimSuffix(name, ".")
+	if name == "" || len(name) > 253 {
+		return false
+	}
+
+	for _, label := range strings.Split(name, ".") {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+
+		for _, c := range label {
+			if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-') {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func (s *GardenServer) handleEvents(w http.ResponseWriter, r *http.Request) {
+	hLog := s.logger.Session("events")
+
+	var since uint64
+	if value := r.URL.Query().Get(routes.EventsSinceParam); value != "" {
+		var err error
+		since, err = strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			s.writeError(w, garden.MalformedRequestError{Cause: err.Error()}, hLog)
+			return
+		}
+	}
+
+	subscription, unsubscribe := s.eventHub.SubscribeSince(since)
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	flusher, _ := w.(http.Flusher)
+	flush := func() {
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+
+	flush()
+
+	hLog.Debug("subscribed")
+
+	for {
+		select {
+		case event, ok := <-subscription:
+			if !ok {
+				hLog.Info("subscriber-fell-behind")
+				return
+			}
+
+			if err := transport.WriteMessage(w, event); err != nil {
+				hLog.Error("failed-to-write-event", err)
+				return
+			}
+
+			flush()
+		case <-r.Context().Done():
+			hLog.Debug("unsubscribed")
+			return
+		case <-s.stopping:
+			return
+		}
+	}
+}
+
+// MinMetricsStreamInterval is the shortest interval at which the metrics of
+// containers may be streamed.
+const MinMetricsStreamInterval = time.Second
+
+func (s *GardenServer) handleStreamMetrics(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	handles := splitHandles(query.Get(routes.StreamMetricsHandlesParam))
+
+	hLog := s.logger.Session("stream-metrics", lager.Data{
+		"handles": handles,
+	})
+
+	interval, err := time.ParseDuration(query.Get(routes.StreamMetricsIntervalParam))
+	if err != nil {
+		s.writeError(w, garden.MalformedRequestError{Cause: err.Error()}, hLog)
+		return
+	}
+
+	if interval < MinMetricsStreamInterval {
+		s.writeError(w, garden.MalformedRequestError{
+			Cause: fmt.Sprintf("interval: %s is less than %s", interval, MinMetricsStreamInterval),
+		}, hLog)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	flusher, _ := w.(http.Flusher)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	hLog.Debug("streaming", lager.Data{"interval": interval.String()})
+
+	for {
+		snapshot, err := s.metricsSnapshot(handles)
+		if err != nil {
+			hLog.Error("failed-to-get-metrics", err)
+		} else {
+			if err := transport.WriteMessage(w, snapshot); err != nil {
+				hLog.Error("failed-to-write-metrics", err)
+				return
+			}
+
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-r.Context().Done():
+			hLog.Debug("done")
+			return
+		case <-s.stopping:
+			return
+		}
+	}
+}
+
+// metricsSnapshot gets the metrics of the containers with the handles, or
+// of all containers if there are none.
+func (s *GardenServer) metricsSnapshot(handles []string) (garden.MetricsSnapshot, error) {
+	if len(handles) == 0 {
+		containers, err := s.backend.Containers(nil)
+		if err != nil {
+			return garden.MetricsSnapshot{}, err
+		}
+
+		for _, container := range containers {
+			handles = append(handles, container.Handle())
+		}
+	}
+
+	entries := map[string]garden.ContainerMetricsEntry{}
+	if len(handles) > 0 {
+		var err error
+		entries, err = s.backend.BulkMetrics(handles)
+		if err != nil {
+			return garden.MetricsSnapshot{}, err
+		}
+	}
+
+	return garden.MetricsSnapshot{Time: time.Now().UTC(), Entries: entries}, nil
+}
+
+func (s *GardenServer) handleReconciliation(w http.ResponseWriter, r *http.Request) {
+	s.writeResponse(w, s.reconciliation)
+}
+
+func (s *GardenServer) handlePressure(w http.ResponseWriter, r *http.Request) {
+	s.writeResponse(w, s.pressure.Sample())
+}
+
+// publishEvent sends the event to subscribers of the events route, stamping
+// it with the current time unless it already has one.
+func (s *GardenServer) publishEvent(event garden.Event) {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	event.Time = event.Time.UTC()
+	s.eventHub.Publish(event)
+}
+
+func (s *GardenServer) handleExpirations(w http.ResponseWriter, r *http.Request) {
+	hLog := s.logger.Session("expirations")
+
+	expirations := []garden.Expiration{}
+	for handle, deadline := range s.bomberman.Deadlines() {
+		expirations = append(expirations, garden.Expiration{
+			Handle:    handle,
+			ExpiresAt: deadline,
+			Reason:    garden.ExpirationReasonGraceTime,
+		})
+	}
+
+	if s.reaper != nil {
+		expirations = append(expirations, s.reaper.Upcoming()...)
+	}
+
+	sort.Sort(byExpiry(expirations))
+
+	hLog.Debug("listed", lager.Data{"count": len(expirations)})
+
+	s.writeResponse(w, &struct {
+		Expirations []garden.Expiration `json:"expirations"`
+	}{expirations})
+}
+
+func (s *GardenServer) handleHandleScheme(w http.ResponseWriter, r *http.Request) {
+	var scheme garden.HandleScheme
+	if s.handleGenerator != nil {
+		scheme = s.handleGenerator.Scheme()
+	}
+
+	s.writeResponse(w, scheme)
+}
+
+func (s *GardenServer) handleRun(w http.ResponseWriter, r *http.Request) {
+	handle := r.FormValue(":handle")
+
+	hLog := s.logger.Session("run", lager.Data{
+		"handle": handle,
+	})
+
+	var request garden.ProcessSpec
+	if !s.readRequest(&request, w, r) {
+		return
+	}
+
+	if err := validateCapabilities(request.Capabilities); err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	if err := validateOOMScoreAdj("oom_score_adj", request.OOMScoreAdj); err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	info := newProcessDebugInfo(request)
+
+	container, err := s.backend.Lookup(handle)
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	s.bomberman.Pause(container.Handle())
+	defer s.bomberman.Unpause(container.Handle())
+
+	hLog.Debug("running", lager.Data{
+		"spec": info,
+	})
 
 	stdout := make(chan []byte, 1000)
 	stderr := make(chan []byte, 1000)
 
 	stdinR, stdinW := io.Pipe()
 
-	processIO := garden.ProcessIO{
-		Stdin:  stdinR,
-		Stdout: &chanWriter{stdout},
-		Stderr: &chanWriter{stderr},
+	processIO, flushOutput := outputProcessIO(stdinR, stdout, stderr, r.URL.Query().Get("interleave") == "true")
+
+	process, err := s.runNamed(container, request, processIO)
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+	hLog.Info("spawned", lager.Data{
+		"spec": info,
+		"id":   process.ID(),
+	})
+
+	if s.processEnvAccess != ProcessEnvDenied {
+		s.processEnvs.record(container.Handle(), process.ID(), request.Env)
+	}
+
+	if request.Timeout > 0 {
+		s.processTimeouts.start(hLog, container.Handle(), process, request.Timeout)
+	}
+
+	s.publishEvent(garden.Event{Kind: garden.EventProcessStarted, Handle: container.Handle(), ProcessID: process.ID()})
+
+	streamID := s.streamer.Stream(stdout, stderr)
+	defer s.streamer.Stop(streamID)
+	defer flushOutput()
+
+	w.WriteHeader(http.StatusCreated)
+	w.Header().Set("Content-Type", "application/json")
+
+	conn, br, err := w.(http.Hijacker).Hijack()
+	if err != nil {
+		s.writeError(w, err, hLog)
+		stdinW.Close()
+		return
+	}
+
+	defer conn.Close()
+
+	contentType := payloadContentType(r)
+
+	transport.WriteMessage(conn, &transport.ProcessPayload{
+		ProcessID:   process.ID(),
+		StreamID:    string(streamID),
+		ContentType: contentType,
+	})
+
+	connCloseCh := make(chan struct{}, 1)
+
+	go s.streamInput(conn, s.streamDecoder(br, contentType), stdinW, process, connCloseCh)
+
+	s.streamProcess(hLog, handle, transport.NewPayloadEncoder(conn, contentType), process, stdinW, connCloseCh)
+}
+
+// handleRunDetached starts a process without attaching to its IO, returning
+// its ID at once. The server waits for the process itself, so that its exit
+// is retained for ProcessExit.
+func (s *GardenServer) handleRunDetached(w http.ResponseWriter, r *http.Request) {
+	handle := r.FormValue(":handle")
+
+	hLog := s.logger.Session("run-detached", lager.Data{
+		"handle": handle,
+	})
+
+	var request garden.ProcessSpec
+	if !s.readRequest(&request, w, r) {
+		return
+	}
+
+	if err := validateCapabilities(request.Capabilities); err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	if err := validateOOMScoreAdj("oom_score_adj", request.OOMScoreAdj); err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	info := newProcessDebugInfo(request)
+
+	container, err := s.backend.Lookup(handle)
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
 	}
 
-	process, err := container.Run(request, processIO)
+	s.bomberman.Pause(container.Handle())
+	defer s.bomberman.Unpause(container.Handle())
+
+	hLog.Debug("running", lager.Data{
+		"spec": info,
+	})
+
+	process, err := s.runNamed(container, request, garden.ProcessIO{})
 	if err != nil {
 		s.writeError(w, err, hLog)
 		return
 	}
+
 	hLog.Info("spawned", lager.Data{
 		"spec": info,
 		"id":   process.ID(),
 	})
 
-	streamID := s.streamer.Stream(stdout, stderr)
-	defer s.streamer.Stop(streamID)
-
-	w.WriteHeader(http.StatusCreated)
-	w.Header().Set("Content-Type", "application/json")
-
-	conn, br, err := w.(http.Hijacker).Hijack()
-	if err != nil {
-		s.writeError(w, err, hLog)
-		stdinW.Close()
-		return
+	if s.processEnvAccess != ProcessEnvDenied {
+		s.processEnvs.record(container.Handle(), process.ID(), request.Env)
 	}
 
-	defer conn.Close()
-
-	transport.WriteMessage(conn, &transport.ProcessPayload{
-		ProcessID: process.ID(),
-		StreamID:  string(streamID),
-	})
+	if request.Timeout > 0 {
+		s.processTimeouts.start(hLog, container.Handle(), process, request.Timeout)
+	}
 
-	connCloseCh := make(chan struct{}, 1)
+	s.publishEvent(garden.Event{Kind: garden.EventProcessStarted, Handle: container.Handle(), ProcessID: process.ID()})
 
-	go s.streamInput(json.NewDecoder(br), stdinW, process, connCloseCh)
+	go s.waitForExit(hLog, container.Handle(), process)
 
-	s.streamProcess(hLog, conn, process, stdinW, connCloseCh)
+	s.writeResponse(w, &transport.RunDetachedResponse{ProcessID: process.ID()})
 }
 
 func (s *GardenServer) handleAttach(w http.ResponseWriter, r *http.Request) {
@@ -770,8 +2897,6 @@ func (s *GardenServer) handleAttach(w http.ResponseWriter, r *http.Request) {
 		"handle": handle,
 	})
 
-	processID := r.FormValue(":pid")
-
 	container, err := s.backend.Lookup(handle)
 	if err != nil {
 		s.writeError(w, err, hLog)
@@ -781,16 +2906,18 @@ func (s *GardenServer) handleAttach(w http.ResponseWriter, r *http.Request) {
 	s.bomberman.Pause(container.Handle())
 	defer s.bomberman.Unpause(container.Handle())
 
+	processID, err := s.requestedProcessID(r, container)
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
 	stdout := make(chan []byte, 1000)
 	stderr := make(chan []byte, 1000)
 
 	stdinR, stdinW := io.Pipe()
 
-	processIO := garden.ProcessIO{
-		Stdin:  stdinR,
-		Stdout: &chanWriter{stdout},
-		Stderr: &chanWriter{stderr},
-	}
+	processIO, flushOutput := outputProcessIO(stdinR, stdout, stderr, r.URL.Query().Get("interleave") == "true")
 
 	hLog.Debug("attaching", lager.Data{
 		"id": processID,
@@ -809,6 +2936,7 @@ func (s *GardenServer) handleAttach(w http.ResponseWriter, r *http.Request) {
 
 	streamID := s.streamer.Stream(stdout, stderr)
 	defer s.streamer.Stop(streamID)
+	defer flushOutput()
 
 	w.WriteHeader(http.StatusOK)
 	w.Header().Set("Content-Type", "application/json")
@@ -822,16 +2950,19 @@ func (s *GardenServer) handleAttach(w http.ResponseWriter, r *http.Request) {
 
 	defer conn.Close()
 
+	contentType := payloadContentType(r)
+
 	transport.WriteMessage(conn, &transport.ProcessPayload{
-		ProcessID: process.ID(),
-		StreamID:  string(streamID),
+		ProcessID:   process.ID(),
+		StreamID:    string(streamID),
+		ContentType: contentType,
 	})
 
 	connCloseCh := make(chan struct{}, 1)
 
-	go s.streamInput(json.NewDecoder(br), stdinW, process, connCloseCh)
+	go s.streamInput(conn, s.streamDecoder(br, contentType), stdinW, process, connCloseCh)
 
-	s.streamProcess(hLog, conn, process, stdinW, connCloseCh)
+	s.streamProcess(hLog, handle, transport.NewPayloadEncoder(conn, contentType), process, stdinW, connCloseCh)
 }
 
 func (s *GardenServer) handleInfo(w http.ResponseWriter, r *http.Request) {
@@ -858,6 +2989,10 @@ func (s *GardenServer) handleInfo(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if health, found := s.healthChecks.status(container.Handle()); found {
+		info.Health = &health
+	}
+
 	hLog.Info("got-info")
 
 	s.writeResponse(w, info)
@@ -865,18 +3000,46 @@ func (s *GardenServer) handleInfo(w http.ResponseWriter, r *http.Request) {
 
 func (s *GardenServer) handleBulkInfo(w http.ResponseWriter, r *http.Request) {
 	handles := splitHandles(r.URL.Query()["handles"][0])
+	snapshot := r.URL.Query().Get("snapshot") == "true"
 
 	hLog := s.logger.Session("bulk_info", lager.Data{
-		"handles": handles,
+		"handles":  handles,
+		"snapshot": snapshot,
 	})
 	hLog.Debug("getting-bulkinfo")
 
+	var existing map[string]struct{}
+	if snapshot {
+		var err error
+		existing, err = s.existingHandles()
+		if err != nil {
+			s.writeError(w, err, hLog)
+			return
+		}
+	}
+
 	bulkInfo, err := s.backend.BulkInfo(handles)
 	if err != nil {
 		s.writeError(w, err, hLog)
 		return
 	}
 
+	if snapshot {
+		for handle, entry := range bulkInfo {
+			if _, ok := existing[handle]; ok && isNotFound(entry.Err) {
+				entry.Tombstone = true
+				bulkInfo[handle] = entry
+			}
+		}
+	}
+
+	for handle, entry := range bulkInfo {
+		if health, found := s.healthChecks.status(handle); found && entry.Err == nil {
+			entry.Info.Health = &health
+			bulkInfo[handle] = entry
+		}
+	}
+
 	hLog.Info("got-bulkinfo")
 
 	s.writeResponse(w, bulkInfo)
@@ -884,13 +3047,15 @@ func (s *GardenServer) handleBulkInfo(w http.ResponseWriter, r *http.Request) {
 
 func (s *GardenServer) handleBulkMetrics(w http.ResponseWriter, r *http.Request) {
 	handles := splitHandles(r.URL.Query()["handles"][0])
+	snapshot := r.URL.Query().Get("snapshot") == "true"
 
 	hLog := s.logger.Session("bulk_metrics", lager.Data{
-		"handles": handles,
+		"handles":  handles,
+		"snapshot": snapshot,
 	})
 	hLog.Debug("getting-bulkmetrics")
 
-	bulkMetrics, err := s.backend.BulkMetrics(handles)
+	bulkMetrics, err := s.bulkMetrics(handles, snapshot)
 	if err != nil {
 		s.writeError(w, err, hLog)
 		return
@@ -901,44 +3066,265 @@ func (s *GardenServer) handleBulkMetrics(w http.ResponseWriter, r *http.Request)
 	s.writeResponse(w, bulkMetrics)
 }
 
+func (s *GardenServer) handleBulkMetricsDelta(w http.ResponseWriter, r *http.Request) {
+	handles := splitHandles(r.URL.Query().Get("handles"))
+	snapshot := r.URL.Query().Get("snapshot") == "true"
+	since := r.URL.Query().Get(routes.BulkMetricsDeltaSinceParam)
+
+	hLog := s.logger.Session("bulk-metrics-delta", lager.Data{
+		"handles":  handles,
+		"snapshot": snapshot,
+	})
+
+	bulkMetrics, err := s.bulkMetrics(handles, snapshot)
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	delta, err := s.metricsBaselines.delta(since, bulkMetrics)
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	hLog.Info("got-delta", lager.Data{
+		"full":    delta.Full,
+		"changed": len(delta.Entries),
+	})
+
+	s.writeResponse(w, delta)
+}
+
+// bulkMetrics gets the metrics of the containers from the backend, marking
+// those destroyed while they were being collected if snapshot is true.
+func (s *GardenServer) bulkMetrics(handles []string, snapshot bool) (map[string]garden.ContainerMetricsEntry, error) {
+	var existing map[string]struct{}
+	if snapshot {
+		var err error
+		existing, err = s.existingHandles()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	bulkMetrics, err := s.backend.BulkMetrics(handles)
+	if err != nil {
+		return nil, err
+	}
+
+	if snapshot {
+		for handle, entry := range bulkMetrics {
+			if _, ok := existing[handle]; ok && isNotFound(entry.Err) {
+				entry.Tombstone = true
+				bulkMetrics[handle] = entry
+			}
+		}
+	}
+
+	return bulkMetrics, nil
+}
+
 func (s *GardenServer) writeError(w http.ResponseWriter, err error, logger lager.Logger) {
+	err = s.redact(err)
 	logger.Error("failed", err)
 
 	w.Header().Set("Content-Type", "application/json")
 	merr := &garden.Error{Err: err}
 
+	var retryAfter time.Duration
+	switch typed := err.(type) {
+	case garden.ServiceUnavailableError:
+		retryAfter = typed.RetryAfter
+	case garden.TooManyRequestsError:
+		retryAfter = typed.RetryAfter
+	}
+
+	if retryAfter > 0 {
+		seconds := int64(math.Ceil(retryAfter.Seconds()))
+		w.Header().Set("Retry-After", strconv.FormatInt(seconds, 10))
+	}
+
 	w.WriteHeader(merr.StatusCode())
 	json.NewEncoder(w).Encode(merr)
 }
 
+// redact removes sensitive text from the error before it is logged or sent to
+// a client.
+func (s *GardenServer) redact(err error) error {
+	return s.redactor.Error(err)
+}
+
 func (s *GardenServer) writeResponse(w http.ResponseWriter, msg interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	transport.WriteMessage(w, msg)
 }
 
+func (s *GardenServer) handleSetProcessTTY(w http.ResponseWriter, r *http.Request) {
+	handle := r.FormValue(":handle")
+	processID := r.FormValue(":pid")
+
+	hLog := s.logger.Session("set-process-tty", lager.Data{
+		"handle": handle,
+		"id":     processID,
+	})
+
+	var spec garden.TTYSpec
+	if !s.readRequest(&spec, w, r) {
+		return
+	}
+
+	container, err := s.backend.Lookup(handle)
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	s.bomberman.Pause(container.Handle())
+	defer s.bomberman.Unpause(container.Handle())
+
+	process, err := container.Attach(processID, garden.ProcessIO{})
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	hLog.Debug("setting-tty", lager.Data{"tty": spec})
+
+	err = process.SetTTY(spec)
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	s.writeSuccess(w)
+}
+
+func (s *GardenServer) handleSignalProcess(w http.ResponseWriter, r *http.Request) {
+	handle := r.FormValue(":handle")
+	processID := r.FormValue(":pid")
+
+	hLog := s.logger.Session("signal-process", lager.Data{
+		"handle": handle,
+		"id":     processID,
+	})
+
+	var request struct {
+		Signal garden.Signal `json:"signal"`
+	}
+	if !s.readRequest(&request, w, r) {
+		return
+	}
+
+	if !request.Signal.Valid() {
+		s.writeError(w, garden.MalformedRequestError{
+			Cause: fmt.Sprintf("signal: %s %d", ErrUnknownSignal, request.Signal),
+		}, hLog)
+		return
+	}
+
+	container, err := s.backend.Lookup(handle)
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	s.bomberman.Pause(container.Handle())
+	defer s.bomberman.Unpause(container.Handle())
+
+	processID, err = s.requestedProcessID(r, container)
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	process, err := container.Attach(processID, garden.ProcessIO{})
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	hLog.Info("signalling", lager.Data{"signal": request.Signal})
+
+	err = process.Signal(request.Signal)
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	s.writeSuccess(w)
+}
+
 func (s *GardenServer) readRequest(msg interface{}, w http.ResponseWriter, r *http.Request) bool {
-	err := json.NewDecoder(r.Body).Decode(msg)
+	var err error
+	if s.strictDecoding {
+		err = transport.DecodeStrict(r.Body, msg)
+	} else {
+		err = json.NewDecoder(r.Body).Decode(msg)
+	}
+
 	if err != nil {
-		s.writeError(w, err, s.logger)
+		s.writeError(w, bodyError(r, err), s.logger)
 		return false
 	}
 
 	return true
 }
 
-func (s *GardenServer) streamInput(decoder *json.Decoder, in *io.PipeWriter, process garden.Process, connCloseCh chan struct{}) {
+// payloadContentType returns the content type, other than JSON, in which the
+// request asks for process payloads to be encoded, or the empty string if they
+// are to be encoded as JSON.
+func payloadContentType(r *http.Request) string {
+	contentType := r.URL.Query().Get(routes.PayloadContentTypeParam)
+	if contentType == transport.JSONPayloadContentType || !transport.SupportedPayloadContentType(contentType) {
+		return ""
+	}
+
+	return contentType
+}
+
+func (s *GardenServer) streamDecoder(r io.Reader, contentType string) transport.PayloadDecoder {
+	return transport.NewPayloadDecoder(r, contentType, s.strictDecoding)
+}
+
+func (s *GardenServer) streamInput(conn net.Conn, decoder transport.PayloadDecoder, in *io.PipeWriter, process garden.Process, connCloseCh chan struct{}) {
 	for {
+		if s.idleConnectionTimeout > 0 {
+			conn.SetReadDeadline(time.Now().Add(s.idleConnectionTimeout))
+		}
+
 		var payload transport.ProcessPayload
 		err := decoder.Decode(&payload)
 		if err != nil {
+			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+				s.logger.Info("stream-input-reaped-idle-connection", lager.Data{
+					"id":   process.ID(),
+					"idle": s.idleConnectionTimeout.String(),
+				})
+			}
+
 			close(connCloseCh)
 			in.CloseWithError(errors.New("Connection closed"))
 			return
 		}
 
+		if s.strictDecoding {
+			if err := transport.Validate(&payload); err != nil {
+				s.logger.Error("stream-input-malformed-process-payload", err, lager.Data{"payload": payload})
+				in.Close()
+				return
+			}
+		}
+
 		switch {
 		case payload.TTY != nil:
-			process.SetTTY(*payload.TTY)
+			err = process.SetTTY(*payload.TTY)
+			if err != nil {
+				s.logger.Error("stream-input-process-set-tty-failed", err, lager.Data{"payload": payload})
+			}
+
+		case payload.CloseStdin:
+			in.Close()
 
 		case payload.Source != nil:
 			if payload.Data == nil {
@@ -954,23 +3340,20 @@ func (s *GardenServer) streamInput(decoder *json.Decoder, in *io.PipeWriter, pro
 		case payload.Signal != nil:
 			s.logger.Info("stream-input-process-signal", lager.Data{"payload": payload})
 
-			switch *payload.Signal {
-			case garden.SignalKill:
-				err = process.Signal(garden.SignalKill)
-				if err != nil {
-					s.logger.Error("stream-input-process-signal-kill-failed", err, lager.Data{"payload": payload})
-				}
-			case garden.SignalTerminate:
-				err = process.Signal(garden.SignalTerminate)
-				if err != nil {
-					s.logger.Error("stream-input-process-signal-terminate-failed", err, lager.Data{"payload": payload})
-				}
-			default:
+			if !payload.Signal.Valid() {
 				s.logger.Error("stream-input-unknown-process-payload-signal", nil, lager.Data{"payload": payload})
 				in.Close()
 				return
 			}
 
+			err = process.Signal(*payload.Signal)
+			if err != nil {
+				s.logger.Error("stream-input-process-signal-failed", err, lager.Data{"payload": payload})
+			}
+
+		case payload.Heartbeat != nil:
+			// the client is still there; the read deadline has been extended
+
 		default:
 			s.logger.Error("stream-input-unknown-process-payload", nil, lager.Data{"payload": payload})
 			in.Close()
@@ -979,43 +3362,57 @@ func (s *GardenServer) streamInput(decoder *json.Decoder, in *io.PipeWriter, pro
 	}
 }
 
-func (s *GardenServer) streamProcess(logger lager.Logger, conn net.Conn, process garden.Process, stdinPipe *io.PipeWriter, connCloseCh chan struct{}) {
-	statusCh := make(chan int, 1)
+func (s *GardenServer) streamProcess(logger lager.Logger, handle string, encoder transport.PayloadEncoder, process garden.Process, stdinPipe *io.PipeWriter, connCloseCh chan struct{}) {
+	exitCh := make(chan *transport.ProcessPayload, 1)
 	errCh := make(chan error, 1)
 
 	go func() {
-		status, err := process.Wait()
+		status, timedOut, err := s.waitForExit(logger, handle, process)
 		if err != nil {
-			logger.Error("wait-failed", err, lager.Data{
-				"id": process.ID(),
-			})
-
 			errCh <- err
 		} else {
-			logger.Info("exited", lager.Data{
-				"status": status,
-				"id":     process.ID(),
-			})
-
-			statusCh <- status
+			exitCh <- &transport.ProcessPayload{
+				ProcessID:  process.ID(),
+				ExitStatus: &status,
+				TimedOut:   timedOut,
+			}
 		}
 	}()
 
+	var heartbeat <-chan time.Time
+	if s.processHeartbeatInterval > 0 {
+		ticker := time.NewTicker(s.processHeartbeatInterval)
+		defer ticker.Stop()
+
+		heartbeat = ticker.C
+	}
+
 	for {
 		select {
 
-		case status := <-statusCh:
-			transport.WriteMessage(conn, &transport.ProcessPayload{
-				ProcessID:  process.ID(),
-				ExitStatus: &status,
-			})
+		case <-heartbeat:
+			interval := s.processHeartbeatInterval
+			payload := &transport.ProcessPayload{
+				ProcessID: process.ID(),
+				Heartbeat: &interval,
+			}
+
+			if s.idleConnectionTimeout > 0 {
+				idleTimeout := s.idleConnectionTimeout
+				payload.IdleTimeout = &idleTimeout
+			}
+
+			encoder.Encode(payload)
+
+		case exit := <-exitCh:
+			encoder.Encode(exit)
 
 			stdinPipe.Close()
 			return
 
 		case err := <-errCh:
-			e := err.Error()
-			transport.WriteMessage(conn, &transport.ProcessPayload{
+			e := s.redact(err).Error()
+			encoder.Encode(&transport.ProcessPayload{
 				ProcessID: process.ID(),
 				Error:     &e,
 			})
@@ -1037,6 +3434,135 @@ func (s *GardenServer) streamProcess(logger lager.Logger, conn net.Conn, process
 	}
 }
 
+// waitForExit waits for the process to exit and retains how it did. It
+// returns whether the process was killed for exceeding its timeout along with
+// its status.
+func (s *GardenServer) waitForExit(logger lager.Logger, handle string, process garden.Process) (int, bool, error) {
+	status, err := process.Wait()
+	timedOut := s.processTimeouts.exited(handle, process.ID())
+	s.recordExit(logger, handle, process, status, timedOut, err)
+	s.processEnvs.forget(handle, process.ID())
+	s.processNames.exited(handle, process.ID())
+
+	if err != nil {
+		logger.Error("wait-failed", err, lager.Data{
+			"id": process.ID(),
+		})
+	} else {
+		logger.Info("exited", lager.Data{
+			"status":    status,
+			"id":        process.ID(),
+			"timed-out": timedOut,
+		})
+	}
+
+	return status, timedOut, err
+}
+
+// recordExit retains how the process exited, so that clients which are not
+// attached to it may query it.
+func (s *GardenServer) recordExit(logger lager.Logger, handle string, process garden.Process, status int, timedOut bool, waitErr error) {
+	exit := garden.ProcessExit{
+		ProcessID:  process.ID(),
+		ExitStatus: status,
+		ExitedAt:   time.Now().UTC(),
+		TimedOut:   timedOut,
+	}
+
+	if waitErr != nil {
+		exit.Error = s.redact(waitErr).Error()
+	}
+
+	if reporter, ok := process.(garden.ProcessUsageReporter); ok {
+		usage, err := reporter.Usage()
+		if err != nil {
+			logger.Error("failed-to-get-usage", err, lager.Data{"id": process.ID()})
+		} else {
+			exit.Usage = &usage
+		}
+	}
+
+	s.exits.Record(handle, exit)
+}
+
+func (s *GardenServer) handleProcessExit(w http.ResponseWriter, r *http.Request) {
+	handle := r.FormValue(":handle")
+	processID := r.FormValue(":pid")
+
+	hLog := s.logger.Session("process-exit", lager.Data{
+		"handle": handle,
+		"id":     processID,
+	})
+
+	processID, err := s.requestedProcessID(r, nil)
+	if err != nil {
+		s.writeError(w, err, hLog)
+		return
+	}
+
+	exit, found := s.exits.Get(handle, processID)
+	if !found {
+		s.writeError(w, garden.ProcessNotFoundError{Handle: handle, ProcessID: processID}, hLog)
+		return
+	}
+
+	s.writeResponse(w, exit)
+}
+
+// existingHandles returns the handles of all containers known to the backend
+// at this point in time.
+func (s *GardenServer) existingHandles() (map[string]struct{}, error) {
+	containers, err := s.backend.Containers(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	handles := make(map[string]struct{}, len(containers))
+	for _, container := range containers {
+		handles[container.Handle()] = struct{}{}
+	}
+
+	return handles, nil
+}
+
+// outputProcessIO returns the ProcessIO to run or attach to a process with,
+// sending its output to the given channels. If interleave is true, stdout and
+// stderr are merged into annotated lines on the stdout channel, and the
+// returned function must be called once the process has exited to flush any
+// unterminated lines.
+func outputProcessIO(stdin io.Reader, stdout, stderr chan []byte, interleave bool) (garden.ProcessIO, func()) {
+	if !interleave {
+		return garden.ProcessIO{
+			Stdin:  stdin,
+			Stdout: &chanWriter{stdout},
+			Stderr: &chanWriter{stderr},
+		}, func() {}
+	}
+
+	merged := newInterleaver(&chanWriter{stdout})
+
+	return garden.ProcessIO{
+		Stdin:  stdin,
+		Stdout: merged.Writer("stdout"),
+		Stderr: merged.Writer("stderr"),
+	}, merged.Flush
+}
+
+type byExpiry []garden.Expiration
+
+func (e byExpiry) Len() int           { return len(e) }
+func (e byExpiry) Swap(i, j int)      { e[i], e[j] = e[j], e[i] }
+func (e byExpiry) Less(i, j int) bool { return e[i].ExpiresAt.Before(e[j].ExpiresAt) }
+
+func isNotFound(err *garden.Error) bool {
+	if err == nil {
+		return false
+	}
+
+	_, ok := err.Err.(garden.ContainerNotFoundError)
+	return ok
+}
+
 func splitHandles(queryHandles string) []string {
 	handles := []string{}
 	if queryHandles != "" {
@@ -0,0 +1,69 @@
+package server
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"net/http"
+)
+
+// maxRecordedErrorLength is how much of the body of an error response a
+// statusRecorder keeps.
+const maxRecordedErrorLength = 4096
+
+// statusRecorder records the status code written to a response, and the start
+// of its body if it is an error, passing flushes and hijacks through to the
+// underlying writer.
+type statusRecorder struct {
+	http.ResponseWriter
+	code      int
+	errorBody []byte
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	if r.code == 0 {
+		r.code = code
+	}
+
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *statusRecorder) Write(p []byte) (int, error) {
+	if r.code == 0 {
+		r.code = http.StatusOK
+	}
+
+	if r.code >= 400 && len(r.errorBody) < maxRecordedErrorLength {
+		n := maxRecordedErrorLength - len(r.errorBody)
+		if n > len(p) {
+			n = len(p)
+		}
+
+		r.errorBody = append(r.errorBody, p[:n]...)
+	}
+
+	return r.ResponseWriter.Write(p)
+}
+
+func (r *statusRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (r *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response cannot be hijacked")
+	}
+
+	return hijacker.Hijack()
+}
+
+func (r *statusRecorder) status() int {
+	if r.code == 0 {
+		return http.StatusOK
+	}
+
+	return r.code
+}
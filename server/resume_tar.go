@@ -0,0 +1,70 @@
+package server
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"path"
+)
+
+// resumeTar returns a tar stream holding the entries of the given stream from
+// the one named resumeFrom onwards. Reading it fails if there is no such
+// entry.
+func resumeTar(stream io.ReadCloser, resumeFrom string) io.ReadCloser {
+	pr, pw := io.Pipe()
+
+	go func() {
+		pw.CloseWithError(copyTarFrom(pw, stream, resumeFrom))
+	}()
+
+	return &resumedTar{PipeReader: pr, stream: stream}
+}
+
+func copyTarFrom(w io.Writer, stream io.Reader, resumeFrom string) error {
+	tr := tar.NewReader(stream)
+	tw := tar.NewWriter(w)
+
+	resumed := false
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if !resumed {
+			if path.Clean(header.Name) != path.Clean(resumeFrom) {
+				continue
+			}
+
+			resumed = true
+		}
+
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+
+		if _, err := io.Copy(tw, tr); err != nil {
+			return err
+		}
+	}
+
+	if !resumed {
+		return fmt.Errorf("entry not found in stream: %s", resumeFrom)
+	}
+
+	return tw.Close()
+}
+
+type resumedTar struct {
+	*io.PipeReader
+	stream io.Closer
+}
+
+func (r *resumedTar) Close() error {
+	r.PipeReader.Close()
+	return r.stream.Close()
+}
@@ -0,0 +1,67 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/lager"
+)
+
+// RouteLimit caps how many requests to a route are handled at once.
+type RouteLimit struct {
+	// Concurrency is how many requests to the route are handled at once.
+	Concurrency int
+
+	// QueueTimeout is how long a request beyond the cap waits for another to
+	// finish before it is shed. Requests are shed at once if it is zero.
+	QueueTimeout time.Duration
+
+	// RetryAfter is how long shed clients are told to wait before retrying,
+	// or zero to make no suggestion.
+	RetryAfter time.Duration
+}
+
+// limitConcurrency wraps the handler of a route so that requests beyond the
+// route's cap wait for a slot, and fail with a garden.ServiceUnavailableError
+// if none frees up in time.
+func (s *GardenServer) limitConcurrency(route string, limit RouteLimit, handler http.Handler) http.Handler {
+	slots := make(chan struct{}, limit.Concurrency)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case slots <- struct{}{}:
+		default:
+			if !s.waitForSlot(slots, limit.QueueTimeout) {
+				s.writeError(w, garden.NewServiceUnavailableErrorWithRetry(
+					fmt.Sprintf("too many concurrent %s requests: limit is %d", route, limit.Concurrency),
+					limit.RetryAfter,
+				), s.logger.Session("route-limit", lager.Data{"route": route}))
+				return
+			}
+		}
+
+		defer func() { <-slots }()
+
+		handler.ServeHTTP(w, r)
+	})
+}
+
+func (s *GardenServer) waitForSlot(slots chan struct{}, timeout time.Duration) bool {
+	if timeout <= 0 {
+		return false
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case slots <- struct{}{}:
+		return true
+	case <-timer.C:
+		return false
+	case <-s.stopping:
+		return false
+	}
+}
@@ -1,21 +1,277 @@
 package server
 
 import (
+	"crypto/tls"
 	"fmt"
 	"net"
 	"net/http"
 	"os"
+	"sort"
 	"sync"
 	"time"
 
 	"code.cloudfoundry.org/garden"
 	"code.cloudfoundry.org/garden/routes"
+	"code.cloudfoundry.org/garden/server/audit"
 	"code.cloudfoundry.org/garden/server/bomberman"
+	"code.cloudfoundry.org/garden/server/chaos"
+	"code.cloudfoundry.org/garden/server/events"
+	"code.cloudfoundry.org/garden/server/exits"
+	"code.cloudfoundry.org/garden/server/handles"
+	"code.cloudfoundry.org/garden/server/oomwatcher"
+	"code.cloudfoundry.org/garden/server/pressure"
+	"code.cloudfoundry.org/garden/server/properties"
+	"code.cloudfoundry.org/garden/server/quarantine"
+	"code.cloudfoundry.org/garden/server/reaper"
 	"code.cloudfoundry.org/garden/server/streamer"
+	"code.cloudfoundry.org/garden/server/telemetry"
 	"code.cloudfoundry.org/lager"
 	"github.com/tedsuo/rata"
 )
 
+// DefaultProcessHeartbeatInterval is how often a heartbeat frame is written to
+// a hijacked process connection unless configured otherwise.
+const DefaultProcessHeartbeatInterval = 30 * time.Second
+
+// DefaultBulkParallelism is how many containers a bulk create, destroy or
+// stop acts on at once unless configured otherwise.
+const DefaultBulkParallelism = 8
+
+// DefaultProcessExitRetention is how long the exits of processes are retained
+// for querying unless configured otherwise.
+const DefaultProcessExitRetention = 5 * time.Minute
+
+// Option configures optional behaviour of a GardenServer.
+type Option func(*GardenServer)
+
+// WithProcessHeartbeatInterval sets how often heartbeat frames are written to
+// hijacked process connections. An interval of zero disables heartbeats.
+func WithProcessHeartbeatInterval(interval time.Duration) Option {
+	return func(s *GardenServer) {
+		s.processHeartbeatInterval = interval
+	}
+}
+
+// WithIdleConnectionTimeout closes hijacked process connections on which
+// nothing has been received from the client for the given timeout, detaching
+// from the process as if the client had disconnected. This reaps the
+// connections of clients which vanished without closing them. Heartbeat
+// frames carry the timeout, and clients echo them back, so the timeout should
+// span a few heartbeat intervals and heartbeats must not be disabled. Clients
+// which predate this do not echo heartbeats, and are reaped when they have
+// nothing else to send. A timeout of zero, the default, leaves idle
+// connections open.
+func WithIdleConnectionTimeout(timeout time.Duration) Option {
+	return func(s *GardenServer) {
+		s.idleConnectionTimeout = timeout
+	}
+}
+
+// WithProcessExitRetention sets how long the exits of processes are retained
+// after they exit, for clients which were not attached at the time. A period
+// of zero disables retention.
+func WithProcessExitRetention(period time.Duration) Option {
+	return func(s *GardenServer) {
+		s.processExitRetention = period
+	}
+}
+
+// WithProcessEnvAccess permits clients to retrieve the environments which
+// processes started through the server were given, for debugging, while they
+// are running. It is denied by default.
+func WithProcessEnvAccess(access ProcessEnvAccess) Option {
+	return func(s *GardenServer) {
+		s.processEnvAccess = access
+	}
+}
+
+// WithMetricsThresholds sets how far the metrics of a container must move
+// before a differential BulkMetrics request reports them as changed.
+func WithMetricsThresholds(thresholds garden.MetricsThresholds) Option {
+	return func(s *GardenServer) {
+		s.metricsThresholds = thresholds
+	}
+}
+
+// WithBulkParallelism sets how many containers a bulk create, destroy or stop
+// acts on at once.
+func WithBulkParallelism(parallelism int) Option {
+	return func(s *GardenServer) {
+		s.bulkParallelism = parallelism
+	}
+}
+
+// WithStrictDecoding makes the server reject requests with fields it does not
+// know about, trailing data, or numeric values out of bounds, as checked by
+// transport.Validate, with a garden.MalformedRequestError. Process payloads
+// on hijacked connections are held to the same rules, and the process's stdin
+// is closed on the first one to break them.
+func WithStrictDecoding() Option {
+	return func(s *GardenServer) {
+		s.strictDecoding = true
+	}
+}
+
+// WithRouteLimits caps how many requests are handled at once for each route
+// named, by the names in the routes package, e.g. so that bulk log
+// collection streaming out of containers cannot crowd out creating them.
+// Requests beyond a route's cap queue for its QueueTimeout, and are then
+// shed with a garden.ServiceUnavailableError naming the route.
+func WithRouteLimits(limits map[string]RouteLimit) Option {
+	return func(s *GardenServer) {
+		s.routeLimits = limits
+	}
+}
+
+// WithClientLimits caps the rate and concurrency of each client's requests,
+// refusing those over the caps with a garden.TooManyRequestsError, which is
+// reported with a 429 and a Retry-After header. Clients of a unix socket are
+// only limited if they present a TLS certificate.
+func WithClientLimits(limit ClientLimit) Option {
+	return func(s *GardenServer) {
+		s.clientLimiter = newClientLimiter(limit)
+	}
+}
+
+// WithMaxBodySizes caps the size, in bytes, of the bodies of requests to each
+// route named, by the names in the routes package, e.g. routes.Create,
+// routes.Run and routes.StreamIn. Requests with larger bodies fail with a
+// garden.PayloadTooLargeError, which is reported with a 413.
+func WithMaxBodySizes(sizes map[string]int64) Option {
+	return func(s *GardenServer) {
+		s.maxBodySizes = sizes
+	}
+}
+
+// WithChaos injects the faults configured in the given chaos.Chaos into
+// requests, for testing how consumers cope with an unreliable server. Faults
+// may be changed through it while the server runs.
+func WithChaos(c *chaos.Chaos) Option {
+	return func(s *GardenServer) {
+		s.chaos = c
+	}
+}
+
+// WithTelemetry records the rates, status codes and durations of requests to
+// each route, the streams open and how many containers there are in the given
+// telemetry.Telemetry, and serves them for Prometheus on the ServerMetrics
+// route, which is not found otherwise.
+func WithTelemetry(t *telemetry.Telemetry) Option {
+	return func(s *GardenServer) {
+		s.telemetry = t
+	}
+}
+
+// WithAuditSink records each request which destroys or stops containers, or
+// changes their limits, network or properties, in the sink: who made it, when,
+// with which parameters and what came of it. The starts of request bodies are
+// recorded, redacted by the server's redactor.
+func WithAuditSink(sink audit.Sink) Option {
+	return func(s *GardenServer) {
+		s.auditSink = sink
+	}
+}
+
+// WithTLS serves over TLS with the certificates in the configuration's files,
+// requiring clients to present certificates signed by its client CAs, and
+// authorizing each request by the common name of the client's certificate if
+// the configuration has an Authorize func. The certificates are reloaded
+// whenever the process receives a SIGHUP, or ReloadTLS is called.
+func WithTLS(config TLSConfig) Option {
+	return func(s *GardenServer) {
+		s.tlsConfig = &config
+	}
+}
+
+// WithDebugListener serves net/http/pprof's profiles, e.g. of goroutines and
+// the heap, under /debug/pprof/ on a listener of their own, so that they can
+// be captured from a running server without exposing them to its clients.
+func WithDebugListener(network, addr string) Option {
+	return func(s *GardenServer) {
+		s.debugNetwork = network
+		s.debugAddr = addr
+	}
+}
+
+// WithHandleGenerator makes the server generate the handles of containers
+// created or restored without one, rather than leaving it to the backend, and
+// serves the generator's scheme on the HandleScheme route so that other
+// systems can pre-compute handles of the same form.
+func WithHandleGenerator(generator *handles.Generator) Option {
+	return func(s *GardenServer) {
+		s.handleGenerator = generator
+	}
+}
+
+// WithRedactor replaces garden.DefaultRedactor as the redactor of sensitive
+// text in the messages of errors before they are logged or returned to
+// clients, keeping the type of garden errors, and in audited request bodies.
+func WithRedactor(redactor garden.Redactor) Option {
+	return func(s *GardenServer) {
+		s.redactor = redactor
+	}
+}
+
+// WithReaper enables a reaper which scans the containers at the given
+// interval and destroys those whose garden.TTLProperty has elapsed.
+func WithReaper(interval time.Duration) Option {
+	return func(s *GardenServer) {
+		s.reaperInterval = interval
+	}
+}
+
+// WithOOMWatcher enables a watcher which polls the containers' info at the
+// given interval and publishes a garden.EventOOM on the events route whenever
+// a container's OOMCount grows.
+func WithOOMWatcher(interval time.Duration) Option {
+	return func(s *GardenServer) {
+		s.oomWatcherInterval = interval
+	}
+}
+
+// WithPropertyStore keeps the properties of containers in the given store
+// rather than relying on the backend to keep them, e.g. so that they survive
+// restarts of the server when using a properties.FileStore.
+func WithPropertyStore(store properties.Store) Option {
+	return func(s *GardenServer) {
+		s.backend = properties.NewBackend(s.backend, store, s.logger)
+	}
+}
+
+// WithGraphPath reports the free space on the volume holding the given path,
+// which should be where the backend keeps container images, in the pressure
+// on the machine.
+func WithGraphPath(path string) Option {
+	return func(s *GardenServer) {
+		s.pressure.GraphPath = path
+	}
+}
+
+// WithPortReuseGracePeriod quarantines the host ports of destroyed containers
+// for the given period, during which NetIn requests for them are refused with
+// a garden.PortUnavailableError. When no host port is requested and the
+// backend allocates a quarantined one, it is unmapped and another allocated.
+func WithPortReuseGracePeriod(period time.Duration) Option {
+	return func(s *GardenServer) {
+		s.portReuseGracePeriod = period
+	}
+}
+
+// WithPortRanges assigns ranges of host ports to namespaces, as named by
+// containers' garden.NamespaceProperty. NetIn for a container in one of the
+// namespaces only maps host ports from its range, choosing a free one when
+// none is requested, and other containers may not request ports in any of
+// the ranges; either is refused with a garden.PortUnavailableError. A range
+// for the empty namespace applies to containers without a namespace. The
+// backend's own port pool should not overlap the ranges. The ports mapped to
+// the backend's containers are read once, when a free port is first needed,
+// and the server then keeps track of those it maps itself.
+func WithPortRanges(ranges map[string]PortRange) Option {
+	return func(s *GardenServer) {
+		s.portRanges = ranges
+	}
+}
+
 type GardenServer struct {
 	logger lager.Logger
 
@@ -29,6 +285,13 @@ type GardenServer struct {
 	listener net.Listener
 	handling *sync.WaitGroup
 
+	tlsConfig       *TLSConfig
+	tlsCertificates *tlsCertificates
+
+	debugNetwork string
+	debugAddr    string
+	debugServer  *http.Server
+
 	started  bool
 	stopping chan bool
 
@@ -41,6 +304,68 @@ type GardenServer struct {
 
 	destroys  map[string]struct{}
 	destroysL *sync.Mutex
+
+	bulkParallelism int
+
+	strictDecoding bool
+
+	routeLimits map[string]RouteLimit
+
+	clientLimiter *clientLimiter
+
+	maxBodySizes map[string]int64
+
+	chaos *chaos.Chaos
+
+	telemetry *telemetry.Telemetry
+
+	auditSink audit.Sink
+
+	handleGenerator *handles.Generator
+
+	redactor garden.Redactor
+
+	processHeartbeatInterval time.Duration
+	idleConnectionTimeout    time.Duration
+
+	processExitRetention time.Duration
+	exits                *exits.Exits
+
+	processEnvAccess ProcessEnvAccess
+	processEnvs      *processEnvs
+	processNames     *processNames
+	processTimeouts  *processTimeouts
+	supervisors      *supervisors
+	healthChecks     *healthChecks
+
+	reaperInterval time.Duration
+	reaper         *reaper.Reaper
+
+	oomWatcherInterval time.Duration
+	oomWatcher         *oomwatcher.Watcher
+
+	portReuseGracePeriod time.Duration
+	ports                *quarantine.Quarantine
+
+	portRanges map[string]PortRange
+	usedPorts  *usedPorts
+
+	containerIPsL *sync.Mutex
+
+	// propertiesL serializes changes to properties, so that a compare-and-swap
+	// cannot interleave with another change.
+	propertiesL *sync.Mutex
+
+	eventHub *events.Hub
+
+	drain *drainState
+
+	reconciliation garden.Reconciliation
+
+	metricsThresholds garden.MetricsThresholds
+	metricsBaselines  *metricsBaselines
+
+	pressure pressure.Sampler
 }
 
 func New(
@@ -48,6 +373,7 @@ func New(
 	containerGraceTime time.Duration,
 	backend garden.Backend,
 	logger lager.Logger,
+	opts ...Option,
 ) *GardenServer {
 	s := &GardenServer{
 		logger: logger.Session("garden-server"),
@@ -67,36 +393,177 @@ func New(
 
 		destroys:  make(map[string]struct{}),
 		destroysL: new(sync.Mutex),
+
+		bulkParallelism: DefaultBulkParallelism,
+
+		metricsThresholds: DefaultMetricsThresholds,
+
+		redactor: garden.DefaultRedactor(),
+
+		processHeartbeatInterval: DefaultProcessHeartbeatInterval,
+		processExitRetention:     DefaultProcessExitRetention,
+
+		usedPorts: newUsedPorts(),
+
+		containerIPsL: new(sync.Mutex),
+
+		propertiesL: new(sync.Mutex),
+
+		eventHub: events.NewHub(),
+
+		drain: new(drainState),
+	}
+
+	for _, opt := range opts {
+		opt(s)
 	}
 
+	s.ports = quarantine.New(s.portReuseGracePeriod)
+	s.exits = exits.New(s.processExitRetention)
+	s.processEnvs = newProcessEnvs()
+	s.processNames = newProcessNames()
+	s.processTimeouts = newProcessTimeouts()
+	s.supervisors = newSupervisors()
+	s.healthChecks = newHealthChecks()
+	s.metricsBaselines = newMetricsBaselines(s.metricsThresholds, DefaultMetricsDeltaTokens)
+
 	handlers := map[string]http.Handler{
 		routes.Ping:                   http.HandlerFunc(s.handlePing),
 		routes.Capacity:               http.HandlerFunc(s.handleCapacity),
 		routes.Create:                 http.HandlerFunc(s.handleCreate),
+		routes.BulkCreate:             http.HandlerFunc(s.handleBulkCreate),
+		routes.Restore:                http.HandlerFunc(s.handleRestore),
 		routes.Destroy:                http.HandlerFunc(s.handleDestroy),
+		routes.BulkDestroy:            http.HandlerFunc(s.handleBulkDestroy),
+		routes.BulkStop:               http.HandlerFunc(s.handleBulkStop),
 		routes.List:                   http.HandlerFunc(s.handleList),
 		routes.Stop:                   http.HandlerFunc(s.handleStop),
+		routes.Pause:                  http.HandlerFunc(s.handlePause),
+		routes.Resume:                 http.HandlerFunc(s.handleResume),
+		routes.Checkpoint:             http.HandlerFunc(s.handleCheckpoint),
+		routes.Commit:                 http.HandlerFunc(s.handleCommit),
 		routes.StreamIn:               http.HandlerFunc(s.handleStreamIn),
 		routes.StreamOut:              http.HandlerFunc(s.handleStreamOut),
 		routes.CurrentBandwidthLimits: http.HandlerFunc(s.handleCurrentBandwidthLimits),
+		routes.LimitBandwidth:         http.HandlerFunc(s.handleLimitBandwidth),
 		routes.CurrentCPULimits:       http.HandlerFunc(s.handleCurrentCPULimits),
+		routes.LimitCPU:               http.HandlerFunc(s.handleLimitCPU),
 		routes.CurrentDiskLimits:      http.HandlerFunc(s.handleCurrentDiskLimits),
 		routes.CurrentMemoryLimits:    http.HandlerFunc(s.handleCurrentMemoryLimits),
+		routes.LimitMemory:            http.HandlerFunc(s.handleLimitMemory),
+		routes.CurrentPidLimits:       http.HandlerFunc(s.handleCurrentPidLimits),
+		routes.LimitPids:              http.HandlerFunc(s.handleLimitPids),
+		routes.CurrentBlockIOLimits:   http.HandlerFunc(s.handleCurrentBlockIOLimits),
+		routes.LimitBlockIO:           http.HandlerFunc(s.handleLimitBlockIO),
+		routes.CurrentLimits:          http.HandlerFunc(s.handleCurrentLimits),
+		routes.LimitAll:               http.HandlerFunc(s.handleLimitAll),
 		routes.NetIn:                  http.HandlerFunc(s.handleNetIn),
+		routes.ListNetIn:              http.HandlerFunc(s.handleListNetIn),
+		routes.RemoveNetIn:            http.HandlerFunc(s.handleRemoveNetIn),
 		routes.NetOut:                 http.HandlerFunc(s.handleNetOut),
+		routes.BulkNetOut:             http.HandlerFunc(s.handleBulkNetOut),
+		routes.ListNetOut:             http.HandlerFunc(s.handleListNetOut),
+		routes.RemoveNetOut:           http.HandlerFunc(s.handleRemoveNetOut),
+		routes.RemoveAllNetOut:        http.HandlerFunc(s.handleRemoveAllNetOut),
 		routes.Info:                   http.HandlerFunc(s.handleInfo),
 		routes.BulkInfo:               http.HandlerFunc(s.handleBulkInfo),
+		routes.BulkMetricsDelta:       http.HandlerFunc(s.handleBulkMetricsDelta),
 		routes.BulkMetrics:            http.HandlerFunc(s.handleBulkMetrics),
 		routes.Run:                    http.HandlerFunc(s.handleRun),
+		routes.RunDetached:            http.HandlerFunc(s.handleRunDetached),
 		routes.Stdout:                 streamer.HandlerFunc(s.streamer.ServeStdout),
 		routes.Stderr:                 streamer.HandlerFunc(s.streamer.ServeStderr),
+		routes.Processes:              http.HandlerFunc(s.handleProcesses),
+		routes.ProcessMetrics:         http.HandlerFunc(s.handleProcessMetrics),
 		routes.Attach:                 http.HandlerFunc(s.handleAttach),
+		routes.SetProcessTTY:          http.HandlerFunc(s.handleSetProcessTTY),
+		routes.SignalProcess:          http.HandlerFunc(s.handleSignalProcess),
+		routes.ProcessExit:            http.HandlerFunc(s.handleProcessExit),
+		routes.ProcessEnv:             http.HandlerFunc(s.handleProcessEnv),
 		routes.Metrics:                http.HandlerFunc(s.handleMetrics),
+		routes.DiskUsage:              http.HandlerFunc(s.handleDiskUsage),
+		routes.HostResources:          http.HandlerFunc(s.handleHostResources),
 		routes.Properties:             http.HandlerFunc(s.handleProperties),
 		routes.Property:               http.HandlerFunc(s.handleProperty),
 		routes.SetProperty:            http.HandlerFunc(s.handleSetProperty),
 		routes.RemoveProperty:         http.HandlerFunc(s.handleRemoveProperty),
+		routes.CASProperty:            http.HandlerFunc(s.handleCASProperty),
+		routes.SetProperties:          http.HandlerFunc(s.handleSetProperties),
+		routes.RemoveProperties:       http.HandlerFunc(s.handleRemoveProperties),
 		routes.SetGraceTime:           http.HandlerFunc(s.handleSetGraceTime),
+		routes.SetDescription:         http.HandlerFunc(s.handleSetDescription),
+		routes.PortAllocations:        http.HandlerFunc(s.handlePortAllocations),
+		routes.Events:                 http.HandlerFunc(s.handleEvents),
+		routes.StreamMetrics:          http.HandlerFunc(s.handleStreamMetrics),
+		routes.Reconciliation:         http.HandlerFunc(s.handleReconciliation),
+		routes.Pressure:               http.HandlerFunc(s.handlePressure),
+		routes.Expirations:            http.HandlerFunc(s.handleExpirations),
+		routes.HandleScheme:           http.HandlerFunc(s.handleHandleScheme),
+		routes.WarmImage:              http.HandlerFunc(s.handleWarmImage),
+		routes.ListImages:             http.HandlerFunc(s.handleListImages),
+		routes.RemoveImage:            http.HandlerFunc(s.handleRemoveImage),
+		routes.ServerMetrics:          http.HandlerFunc(s.handleServerMetrics),
+		routes.Drain:                  http.HandlerFunc(s.handleDrain),
+		routes.Undrain:                http.HandlerFunc(s.handleUndrain),
+		routes.DrainStatus:            http.HandlerFunc(s.handleDrainStatus),
+	}
+
+	for route, handler := range handlers {
+		if creationRoutes[route] || drainedStreamRoutes[route] || attachedRoutes[route] {
+			handlers[route] = s.trackDrain(route, handler)
+		}
+	}
+
+	for route, max := range s.maxBodySizes {
+		handler, found := handlers[route]
+		if !found || max <= 0 {
+			s.logger.Info("ignoring-max-body-size", lager.Data{"route": route, "max": max})
+			continue
+		}
+
+		handlers[route] = s.limitBody(route, max, handler)
+	}
+
+	for route, limit := range s.routeLimits {
+		handler, found := handlers[route]
+		if !found || limit.Concurrency <= 0 {
+			s.logger.Info("ignoring-route-limit", lager.Data{"route": route, "concurrency": limit.Concurrency})
+			continue
+		}
+
+		handlers[route] = s.limitConcurrency(route, limit, handler)
+	}
+
+	if s.chaos != nil {
+		for route, handler := range handlers {
+			handlers[route] = s.injectChaos(route, handler)
+		}
+	}
+
+	if s.tlsConfig != nil && s.tlsConfig.Authorize != nil {
+		for route, handler := range handlers {
+			handlers[route] = s.authorize(route, handler)
+		}
+	}
+
+	if s.clientLimiter != nil {
+		for route, handler := range handlers {
+			handlers[route] = s.limitClients(route, handler)
+		}
+	}
+
+	if s.auditSink != nil {
+		for route, handler := range handlers {
+			if auditedRoutes[route] {
+				handlers[route] = s.audit(route, handler)
+			}
+		}
+	}
+
+	if s.telemetry != nil {
+		for route, handler := range handlers {
+			handlers[route] = s.instrument(route, handler)
+		}
 	}
 
 	mux, err := rata.NewRouter(routes.Routes, handlers)
@@ -144,12 +611,20 @@ func New(
 
 func (s *GardenServer) Start() error {
 	s.started = true
+	startedAt := time.Now().UTC()
 
 	err := s.removeExistingSocket()
 	if err != nil {
 		return err
 	}
 
+	if s.tlsConfig != nil {
+		s.tlsCertificates, err = newTLSCertificates(*s.tlsConfig)
+		if err != nil {
+			return err
+		}
+	}
+
 	err = s.backend.Start()
 	if err != nil {
 		return err
@@ -160,12 +635,24 @@ func (s *GardenServer) Start() error {
 		return err
 	}
 
+	if s.tlsCertificates != nil {
+		listener = tls.NewListener(listener, s.tlsCertificates.tlsConfig())
+		s.reloadTLSOnHangup()
+	}
+
 	s.listener = listener
 
 	if s.listenNetwork == "unix" {
 		os.Chmod(s.listenAddr, 0777)
 	}
 
+	if s.debugAddr != "" {
+		if err := s.startDebugServer(); err != nil {
+			listener.Close()
+			return err
+		}
+	}
+
 	containers, err := s.backend.Containers(nil)
 	if err != nil {
 		return err
@@ -177,6 +664,18 @@ func (s *GardenServer) Start() error {
 		s.bomberman.Strap(container)
 	}
 
+	s.reconcile(startedAt, containers)
+
+	if s.reaperInterval > 0 {
+		s.reaper = reaper.New(s.backend, s.reaperInterval, s.reapContainer, s.logger)
+		s.reaper.Start()
+	}
+
+	if s.oomWatcherInterval > 0 {
+		s.oomWatcher = oomwatcher.New(s.backend, s.oomWatcherInterval, s.publishEvent, s.logger)
+		s.oomWatcher.Start()
+	}
+
 	go s.server.Serve(listener)
 
 	return nil
@@ -189,8 +688,20 @@ func (s *GardenServer) Stop() {
 
 	close(s.stopping)
 
+	if s.reaper != nil {
+		s.reaper.Stop()
+	}
+
+	if s.oomWatcher != nil {
+		s.oomWatcher.Stop()
+	}
+
 	s.listener.Close()
 
+	if s.debugServer != nil {
+		s.debugServer.Close()
+	}
+
 	s.mu.Lock()
 	conns := s.conns
 	s.conns = make(map[net.Conn]net.Conn)
@@ -213,6 +724,32 @@ func (s *GardenServer) Stop() {
 	s.logger.Info("stopped")
 }
 
+// reconcile records how the containers which existed when the server was
+// started were recovered.
+func (s *GardenServer) reconcile(startedAt time.Time, containers []garden.Container) {
+	var reconciliation garden.Reconciliation
+	if reporter, ok := s.backend.(garden.ReconciliationReporter); ok {
+		reconciliation = reporter.Reconciliation()
+	}
+
+	reconciliation.StartedAt = startedAt
+
+	reconciliation.Recovered = make([]string, len(containers))
+	for i, container := range containers {
+		reconciliation.Recovered[i] = container.Handle()
+	}
+
+	sort.Strings(reconciliation.Recovered)
+
+	s.logger.Info("reconciled", lager.Data{
+		"recovered":            len(reconciliation.Recovered),
+		"orphans-destroyed":    len(reconciliation.OrphansDestroyed),
+		"processes-reattached": reconciliation.ProcessesReattached,
+	})
+
+	s.reconciliation = reconciliation
+}
+
 func (s *GardenServer) removeExistingSocket() error {
 	if s.listenNetwork != "unix" {
 		return nil
@@ -252,7 +789,18 @@ func (s *GardenServer) reapContainer(container garden.Container) {
 		return
 	}
 
-	s.backend.Destroy(container.Handle())
+	ports := s.hostPortsToQuarantine(container.Handle())
+
+	s.supervisors.stop(container.Handle())
+	s.healthChecks.stop(container.Handle())
+
+	if err := s.backend.Destroy(container.Handle()); err == nil {
+		s.ports.Release(ports...)
+		s.usedPorts.forgetContainer(container.Handle())
+		s.processNames.forgetContainer(container.Handle())
+		s.processTimeouts.forgetContainer(container.Handle())
+		s.publishEvent(garden.Event{Kind: garden.EventDestroyed, Handle: container.Handle()})
+	}
 
 	s.destroysL.Lock()
 	delete(s.destroys, container.Handle())
@@ -1,10 +1,14 @@
 package server_test
 
 import (
+	"crypto/tls"
 	"errors"
 	"io/ioutil"
+	"net"
+	"net/http"
 	"os"
 	"path"
+	"syscall"
 	"time"
 
 	"code.cloudfoundry.org/lager/lagertest"
@@ -16,6 +20,7 @@ import (
 	"code.cloudfoundry.org/garden/client"
 	"code.cloudfoundry.org/garden/client/connection"
 	fakes "code.cloudfoundry.org/garden/gardenfakes"
+	"code.cloudfoundry.org/garden/routes"
 	"code.cloudfoundry.org/garden/server"
 )
 
@@ -87,6 +92,160 @@ var _ = Describe("The Garden server", func() {
 		})
 	})
 
+	Context("when serving over TLS", func() {
+		var (
+			apiServer   *server.GardenServer
+			fakeBackend *fakes.FakeBackend
+			ca          *testCA
+			tlsConfig   server.TLSConfig
+		)
+
+		writeCertificates := func(ca *testCA) {
+			certPEM, keyPEM := ca.issue("garden-server")
+			Ω(ioutil.WriteFile(tlsConfig.CertFile, certPEM, 0600)).Should(Succeed())
+			Ω(ioutil.WriteFile(tlsConfig.KeyFile, keyPEM, 0600)).Should(Succeed())
+			Ω(ioutil.WriteFile(tlsConfig.ClientCAFile, ca.pem, 0600)).Should(Succeed())
+		}
+
+		clientOf := func(config *tls.Config) garden.Client {
+			return client.New(connection.NewWithDialerAndLogger(func(string, string) (net.Conn, error) {
+				return tls.Dial("tcp", "127.0.0.1:60126", config)
+			}, logger))
+		}
+
+		BeforeEach(func() {
+			var err error
+			tmpdir, err = ioutil.TempDir(os.TempDir(), "api-server-test")
+			Ω(err).ShouldNot(HaveOccurred())
+
+			tlsConfig = server.TLSConfig{
+				CertFile:     path.Join(tmpdir, "server.crt"),
+				KeyFile:      path.Join(tmpdir, "server.key"),
+				ClientCAFile: path.Join(tmpdir, "client-ca.crt"),
+				Authorize: func(commonName, route string) bool {
+					return commonName == "admin" || route == routes.Ping
+				},
+			}
+
+			ca = newTestCA()
+			writeCertificates(ca)
+
+			fakeBackend = new(fakes.FakeBackend)
+			fakeBackend.CreateReturns(new(fakes.FakeContainer), nil)
+		})
+
+		JustBeforeEach(func() {
+			apiServer = server.New("tcp", "127.0.0.1:60126", 0, fakeBackend, logger, server.WithTLS(tlsConfig))
+			Ω(apiServer.Start()).Should(Succeed())
+		})
+
+		AfterEach(func() {
+			apiServer.Stop()
+		})
+
+		It("serves clients with certificates signed by its client CAs", func() {
+			admin := clientOf(ca.clientTLSConfig("admin"))
+
+			Ω(admin.Ping()).Should(Succeed())
+
+			_, err := admin.Create(garden.ContainerSpec{})
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		It("refuses clients without certificates", func() {
+			Ω(clientOf(ca.clientTLSConfig("")).Ping()).ShouldNot(Succeed())
+		})
+
+		It("refuses clients with certificates signed by other CAs", func() {
+			config := ca.clientTLSConfig("")
+			config.Certificates = newTestCA().clientTLSConfig("admin").Certificates
+
+			Ω(clientOf(config).Ping()).ShouldNot(Succeed())
+		})
+
+		It("authorizes requests by the common name of the client's certificate", func() {
+			reader := clientOf(ca.clientTLSConfig("reader"))
+
+			Ω(reader.Ping()).Should(Succeed())
+
+			_, err := reader.Create(garden.ContainerSpec{})
+			Ω(err).Should(MatchError(`"reader" may not make Create requests`))
+			Ω(err).Should(BeAssignableToTypeOf(garden.PermissionDeniedError{}))
+			Ω(fakeBackend.CreateCallCount()).Should(Equal(0))
+		})
+
+		It("reloads its certificates when asked to", func() {
+			newCA := newTestCA()
+			writeCertificates(newCA)
+
+			Ω(apiServer.ReloadTLS()).Should(Succeed())
+
+			Ω(clientOf(newCA.clientTLSConfig("admin")).Ping()).Should(Succeed())
+			Ω(clientOf(ca.clientTLSConfig("admin")).Ping()).ShouldNot(Succeed())
+		})
+
+		It("reloads its certificates on SIGHUP", func() {
+			newCA := newTestCA()
+			writeCertificates(newCA)
+
+			self, err := os.FindProcess(os.Getpid())
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(self.Signal(syscall.SIGHUP)).Should(Succeed())
+
+			Eventually(clientOf(newCA.clientTLSConfig("admin")).Ping).Should(Succeed())
+		})
+
+		It("keeps its certificates if reloading them fails", func() {
+			Ω(ioutil.WriteFile(tlsConfig.ClientCAFile, []byte("garbage"), 0600)).Should(Succeed())
+
+			Ω(apiServer.ReloadTLS()).ShouldNot(Succeed())
+
+			Ω(clientOf(ca.clientTLSConfig("admin")).Ping()).Should(Succeed())
+		})
+	})
+
+	Context("when given a debug listener", func() {
+		var apiServer *server.GardenServer
+
+		BeforeEach(func() {
+			apiServer = server.New("tcp", ":60124", 0, new(fakes.FakeBackend), logger,
+				server.WithDebugListener("tcp", "127.0.0.1:60125"))
+
+			Ω(apiServer.Start()).Should(Succeed())
+		})
+
+		It("serves profiles on the debug listener", func() {
+			response, err := http.Get("http://127.0.0.1:60125/debug/pprof/goroutine?debug=1")
+			Ω(err).ShouldNot(HaveOccurred())
+			defer response.Body.Close()
+
+			Ω(response.StatusCode).Should(Equal(http.StatusOK))
+
+			body, err := ioutil.ReadAll(response.Body)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(body)).Should(ContainSubstring("goroutine profile"))
+
+			apiServer.Stop()
+		})
+
+		It("does not serve profiles to the server's clients", func() {
+			response, err := http.Get("http://127.0.0.1:60124/debug/pprof/")
+			Ω(err).ShouldNot(HaveOccurred())
+			response.Body.Close()
+
+			Ω(response.StatusCode).Should(Equal(http.StatusNotFound))
+
+			apiServer.Stop()
+		})
+
+		It("stops serving profiles when the server is stopped", func() {
+			apiServer.Stop()
+
+			_, err := http.Get("http://127.0.0.1:60125/debug/pprof/")
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+
 	It("starts the backend", func() {
 		var err error
 		tmpdir, err = ioutil.TempDir(os.TempDir(), "api-server-test")
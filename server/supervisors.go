@@ -0,0 +1,159 @@
+package server
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/lager"
+)
+
+// supervisors tracks the containers whose main processes are supervised under
+// their RestartPolicy, so that supervision may be stopped when a container is
+// stopped or destroyed.
+type supervisors struct {
+	stops map[string]chan struct{}
+	lock  sync.Mutex
+}
+
+func newSupervisors() *supervisors {
+	return &supervisors{stops: map[string]chan struct{}{}}
+}
+
+// start returns a channel which is closed when supervision of the
+// container's main process is to stop.
+func (s *supervisors) start(handle string) <-chan struct{} {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	stop := make(chan struct{})
+	s.stops[handle] = stop
+	return stop
+}
+
+// finish forgets the supervision which start returned the stop channel for,
+// once the supervisor has returned.
+func (s *supervisors) finish(handle string, stop <-chan struct{}) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if current, found := s.stops[handle]; found && current == stop {
+		delete(s.stops, handle)
+	}
+}
+
+// stop stops supervision of the container's main process, if it is
+// supervised.
+func (s *supervisors) stop(handle string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if stop, found := s.stops[handle]; found {
+		close(stop)
+		delete(s.stops, handle)
+	}
+}
+
+// validateRestartPolicy rejects a restart policy whose mode the server does
+// not know how to supervise.
+func validateRestartPolicy(policy *garden.RestartPolicy) error {
+	if policy == nil {
+		return nil
+	}
+
+	switch policy.Mode {
+	case garden.RestartNever, garden.RestartOnFailure, garden.RestartAlways:
+		return nil
+	default:
+		return garden.MalformedRequestError{
+			Cause: fmt.Sprintf("restart_policy.mode: %q is not a known mode", policy.Mode),
+		}
+	}
+}
+
+// supervise runs the container's main process and runs it again whenever it
+// exits, as the restart policy says, until stop is closed or the server stops.
+func (s *GardenServer) supervise(container garden.Container, policy garden.RestartPolicy, stop <-chan struct{}) {
+	handle := container.Handle()
+
+	logger := s.logger.Session("supervise", lager.Data{
+		"handle": handle,
+		"mode":   policy.Mode,
+	})
+
+	defer s.supervisors.finish(handle, stop)
+
+	backoff := policy.Backoff
+	if backoff == 0 {
+		backoff = garden.DefaultRestartBackoff
+	}
+
+	maxBackoff := policy.MaxBackoff
+	if maxBackoff == 0 {
+		maxBackoff = garden.DefaultMaxRestartBackoff
+	}
+
+	delay := backoff
+
+	for restarts := 0; ; restarts++ {
+		process, err := s.runNamed(container, policy.Process, garden.ProcessIO{})
+		if err != nil {
+			logger.Error("failed-to-run", err, lager.Data{"restarts": restarts})
+			return
+		}
+
+		startedAt := time.Now()
+
+		if s.processEnvAccess != ProcessEnvDenied {
+			s.processEnvs.record(handle, process.ID(), policy.Process.Env)
+		}
+
+		if policy.Process.Timeout > 0 {
+			s.processTimeouts.start(logger, handle, process, policy.Process.Timeout)
+		}
+
+		if restarts == 0 {
+			s.publishEvent(garden.Event{Kind: garden.EventProcessStarted, Handle: handle, ProcessID: process.ID()})
+		} else {
+			s.publishEvent(garden.Event{Kind: garden.EventProcessRestarted, Handle: handle, ProcessID: process.ID(), Restarts: restarts})
+		}
+
+		status, _, err := s.waitForExit(logger, handle, process)
+
+		failed := err != nil || status != 0
+		if policy.Mode == garden.RestartNever || (policy.Mode == garden.RestartOnFailure && !failed) {
+			return
+		}
+
+		if policy.MaxRestarts > 0 && restarts >= policy.MaxRestarts {
+			logger.Info("giving-up", lager.Data{"restarts": restarts})
+			return
+		}
+
+		if time.Since(startedAt) > maxBackoff {
+			delay = backoff
+		}
+
+		logger.Debug("restarting", lager.Data{
+			"restarts": restarts,
+			"delay":    delay.String(),
+		})
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-stop:
+			timer.Stop()
+			return
+		case <-s.stopping:
+			timer.Stop()
+			return
+		}
+
+		delay *= 2
+		if delay > maxBackoff {
+			delay = maxBackoff
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package server
+
+import (
+	"net/http"
+	"time"
+
+	"code.cloudfoundry.org/garden/routes"
+	"code.cloudfoundry.org/garden/server/telemetry"
+)
+
+// streamKinds gives the kind of stream which requests to each streaming route
+// hold open while they are handled.
+var streamKinds = map[string]string{
+	routes.StreamIn:      "stream-in",
+	routes.StreamOut:     "stream-out",
+	routes.Stdout:        "stdout",
+	routes.Stderr:        "stderr",
+	routes.Run:           "process",
+	routes.Attach:        "process",
+	routes.Events:        "events",
+	routes.StreamMetrics: "metrics",
+}
+
+// instrument wraps the handler of a route so that its requests, and the
+// streams they hold open, are recorded in the server's telemetry.
+func (s *GardenServer) instrument(route string, handler http.Handler) http.Handler {
+	kind, streams := streamKinds[route]
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if streams {
+			s.telemetry.StreamOpened(kind)
+			defer s.telemetry.StreamClosed(kind)
+		}
+
+		recorder := &statusRecorder{ResponseWriter: w}
+		startedAt := time.Now()
+
+		defer func() {
+			s.telemetry.ObserveRequest(route, recorder.status(), time.Since(startedAt))
+		}()
+
+		handler.ServeHTTP(recorder, r)
+	})
+}
+
+func (s *GardenServer) handleServerMetrics(w http.ResponseWriter, r *http.Request) {
+	if s.telemetry == nil {
+		http.Error(w, "server metrics are not enabled", http.StatusNotFound)
+		return
+	}
+
+	hLog := s.logger.Session("server-metrics")
+
+	if containers, err := s.backend.Containers(nil); err != nil {
+		hLog.Error("failed-to-count-containers", err)
+	} else {
+		s.telemetry.SetContainers(len(containers))
+	}
+
+	w.Header().Set("Content-Type", telemetry.ContentType)
+	if err := s.telemetry.Write(w); err != nil {
+		hLog.Error("failed-to-write", err)
+	}
+}
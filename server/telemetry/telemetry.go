@@ -0,0 +1,186 @@
+package telemetry
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ContentType is the content type of the Prometheus text format in which
+// Write exposes the metrics.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DurationBuckets are the upper bounds, in seconds, of the buckets of the
+// request duration histograms.
+var DurationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// Telemetry records the requests handled by a server, the streams it has
+// open and how many containers it has, and exposes them as Prometheus
+// metrics.
+type Telemetry struct {
+	requests      map[requestKey]uint64
+	durations     map[string]*histogram
+	backendErrors map[string]uint64
+	streams       map[string]int64
+	containers    *int
+
+	lock *sync.Mutex
+}
+
+type requestKey struct {
+	route string
+	code  int
+}
+
+type histogram struct {
+	buckets []uint64
+	sum     float64
+	count   uint64
+}
+
+func New() *Telemetry {
+	return &Telemetry{
+		requests:      map[requestKey]uint64{},
+		durations:     map[string]*histogram{},
+		backendErrors: map[string]uint64{},
+		streams:       map[string]int64{},
+
+		lock: new(sync.Mutex),
+	}
+}
+
+// ObserveRequest records a request to the route which was responded to with
+// the status code after the duration. Requests failed with a 500 are counted
+// as errors of the backend, which is how the server reports them.
+func (t *Telemetry) ObserveRequest(route string, code int, duration time.Duration) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.requests[requestKey{route, code}]++
+
+	h, found := t.durations[route]
+	if !found {
+		h = &histogram{buckets: make([]uint64, len(DurationBuckets))}
+		t.durations[route] = h
+	}
+
+	seconds := duration.Seconds()
+	for i, bound := range DurationBuckets {
+		if seconds <= bound {
+			h.buckets[i]++
+		}
+	}
+
+	h.sum += seconds
+	h.count++
+
+	if code == 500 {
+		t.backendErrors[route]++
+	}
+}
+
+// StreamOpened records that a stream of the kind, e.g. "stdout", was opened.
+func (t *Telemetry) StreamOpened(kind string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.streams[kind]++
+}
+
+// StreamClosed records that a stream of the kind was closed.
+func (t *Telemetry) StreamClosed(kind string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.streams[kind]--
+}
+
+// SetContainers records how many containers the server has.
+func (t *Telemetry) SetContainers(n int) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.containers = &n
+}
+
+// Write writes the metrics in the Prometheus text format.
+func (t *Telemetry) Write(w io.Writer) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	bw := bufio.NewWriter(w)
+
+	header(bw, "garden_requests_total", "counter", "Requests handled, by route and status code.")
+	requests := make([]requestKey, 0, len(t.requests))
+	for key := range t.requests {
+		requests = append(requests, key)
+	}
+	sort.Slice(requests, func(i, j int) bool {
+		if requests[i].route != requests[j].route {
+			return requests[i].route < requests[j].route
+		}
+		return requests[i].code < requests[j].code
+	})
+	for _, key := range requests {
+		fmt.Fprintf(bw, "garden_requests_total{route=%s,code=\"%d\"} %d\n", label(key.route), key.code, t.requests[key])
+	}
+
+	header(bw, "garden_request_duration_seconds", "histogram", "How long requests took to handle, by route.")
+	routes := make([]string, 0, len(t.durations))
+	for route := range t.durations {
+		routes = append(routes, route)
+	}
+	sort.Strings(routes)
+	for _, route := range routes {
+		h := t.durations[route]
+		for i, bound := range DurationBuckets {
+			fmt.Fprintf(bw, "garden_request_duration_seconds_bucket{route=%s,le=\"%s\"} %d\n", label(route), number(bound), h.buckets[i])
+		}
+		fmt.Fprintf(bw, "garden_request_duration_seconds_bucket{route=%s,le=\"+Inf\"} %d\n", label(route), h.count)
+		fmt.Fprintf(bw, "garden_request_duration_seconds_sum{route=%s} %s\n", label(route), number(h.sum))
+		fmt.Fprintf(bw, "garden_request_duration_seconds_count{route=%s} %d\n", label(route), h.count)
+	}
+
+	header(bw, "garden_backend_errors_total", "counter", "Requests which failed with an error from the backend, by route.")
+	for _, route := range routes {
+		if errors, found := t.backendErrors[route]; found {
+			fmt.Fprintf(bw, "garden_backend_errors_total{route=%s} %d\n", label(route), errors)
+		}
+	}
+
+	header(bw, "garden_streams", "gauge", "Streams open, by kind.")
+	kinds := make([]string, 0, len(t.streams))
+	for kind := range t.streams {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	for _, kind := range kinds {
+		fmt.Fprintf(bw, "garden_streams{kind=%s} %d\n", label(kind), t.streams[kind])
+	}
+
+	if t.containers != nil {
+		header(bw, "garden_containers", "gauge", "Containers the server has.")
+		fmt.Fprintf(bw, "garden_containers %d\n", *t.containers)
+	}
+
+	return bw.Flush()
+}
+
+func header(w io.Writer, name, kind, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func label(value string) string {
+	return `"` + labelEscaper.Replace(value) + `"`
+}
+
+func number(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,79 @@
+package telemetry_test
+
+import (
+	"bytes"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"code.cloudfoundry.org/garden/server/telemetry"
+)
+
+var _ = Describe("Telemetry", func() {
+	var t *telemetry.Telemetry
+
+	BeforeEach(func() {
+		t = telemetry.New()
+	})
+
+	write := func() string {
+		buf := new(bytes.Buffer)
+		Ω(t.Write(buf)).Should(Succeed())
+		return buf.String()
+	}
+
+	It("counts requests by route and status code", func() {
+		t.ObserveRequest("Create", 200, time.Millisecond)
+		t.ObserveRequest("Create", 200, time.Millisecond)
+		t.ObserveRequest("Create", 409, time.Millisecond)
+
+		Ω(write()).Should(ContainSubstring("# TYPE garden_requests_total counter\n" +
+			`garden_requests_total{route="Create",code="200"} 2` + "\n" +
+			`garden_requests_total{route="Create",code="409"} 1` + "\n"))
+	})
+
+	It("records the durations of requests in cumulative buckets", func() {
+		t.ObserveRequest("Ping", 200, 20*time.Millisecond)
+		t.ObserveRequest("Ping", 200, 2*time.Second)
+
+		metrics := write()
+		Ω(metrics).Should(ContainSubstring(`garden_request_duration_seconds_bucket{route="Ping",le="0.01"} 0`))
+		Ω(metrics).Should(ContainSubstring(`garden_request_duration_seconds_bucket{route="Ping",le="0.025"} 1`))
+		Ω(metrics).Should(ContainSubstring(`garden_request_duration_seconds_bucket{route="Ping",le="2.5"} 2`))
+		Ω(metrics).Should(ContainSubstring(`garden_request_duration_seconds_bucket{route="Ping",le="+Inf"} 2`))
+		Ω(metrics).Should(ContainSubstring(`garden_request_duration_seconds_sum{route="Ping"} 2.02`))
+		Ω(metrics).Should(ContainSubstring(`garden_request_duration_seconds_count{route="Ping"} 2`))
+	})
+
+	It("counts requests failed with a 500 as errors of the backend", func() {
+		t.ObserveRequest("Create", 500, time.Millisecond)
+		t.ObserveRequest("Create", 404, time.Millisecond)
+
+		Ω(write()).Should(ContainSubstring(`garden_backend_errors_total{route="Create"} 1`))
+	})
+
+	It("reports the streams which are open", func() {
+		t.StreamOpened("stdout")
+		t.StreamOpened("stdout")
+		t.StreamOpened("stderr")
+		t.StreamClosed("stdout")
+
+		metrics := write()
+		Ω(metrics).Should(ContainSubstring(`garden_streams{kind="stderr"} 1`))
+		Ω(metrics).Should(ContainSubstring(`garden_streams{kind="stdout"} 1`))
+	})
+
+	It("reports the containers only once they are known", func() {
+		Ω(write()).ShouldNot(ContainSubstring("garden_containers"))
+
+		t.SetContainers(3)
+		Ω(write()).Should(ContainSubstring("# TYPE garden_containers gauge\ngarden_containers 3\n"))
+	})
+
+	It("escapes label values", func() {
+		t.StreamOpened("some \"odd\"\nkind")
+
+		Ω(write()).Should(ContainSubstring(`garden_streams{kind="some \"odd\"\nkind"} 1`))
+	})
+})
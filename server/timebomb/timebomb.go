@@ -9,10 +9,11 @@ type TimeBomb struct {
 	countdown time.Duration
 	detonate  func()
 
-	pauses  int
-	defused bool
-	timer   *time.Timer
-	lock    *sync.Mutex
+	pauses   int
+	defused  bool
+	timer    *time.Timer
+	deadline time.Time
+	lock     *sync.Mutex
 }
 
 func New(countdown time.Duration, detonate func()) *TimeBomb {
@@ -26,10 +27,28 @@ func New(countdown time.Duration, detonate func()) *TimeBomb {
 
 func (b *TimeBomb) Strap() {
 	b.lock.Lock()
-	b.timer = time.AfterFunc(b.countdown, b.detonate)
+	b.start()
 	b.lock.Unlock()
 }
 
+// Deadline returns the time at which the bomb will detonate, or false if it
+// is paused or defused.
+func (b *TimeBomb) Deadline() (time.Time, bool) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.timer == nil {
+		return time.Time{}, false
+	}
+
+	return b.deadline, true
+}
+
+func (b *TimeBomb) start() {
+	b.deadline = time.Now().Add(b.countdown)
+	b.timer = time.AfterFunc(b.countdown, b.detonate)
+}
+
 func (b *TimeBomb) Pause() bool {
 	b.lock.Lock()
 	defer b.lock.Unlock()
@@ -73,6 +92,6 @@ func (b *TimeBomb) Unpause() {
 	b.pauses--
 
 	if !b.defused && b.pauses == 0 {
-		b.timer = time.AfterFunc(b.countdown, b.detonate)
+		b.start()
 	}
 }
@@ -283,4 +283,39 @@ var _ = Describe("THE TIMEBOMB", func() {
 			})
 		})
 	})
+
+	Context("WHEN ASKED FOR ITS DEADLINE", func() {
+		It("REPORTS THE TIME IT WILL DETONATE", func() {
+			countdown := time.Hour
+
+			bomb := timebomb.New(countdown, func() {})
+
+			_, ok := bomb.Deadline()
+			Ω(ok).Should(BeFalse())
+
+			before := time.Now()
+			bomb.Strap()
+
+			deadline, ok := bomb.Deadline()
+			Ω(ok).Should(BeTrue())
+			Ω(deadline).Should(BeTemporally(">=", before.Add(countdown)))
+			Ω(deadline).Should(BeTemporally("<=", time.Now().Add(countdown)))
+
+			bomb.Defuse()
+		})
+
+		Context("AND PAUSED", func() {
+			It("REPORTS NO DEADLINE", func() {
+				bomb := timebomb.New(time.Hour, func() {})
+
+				bomb.Strap()
+				bomb.Pause()
+
+				_, ok := bomb.Deadline()
+				Ω(ok).Should(BeFalse())
+
+				bomb.Defuse()
+			})
+		})
+	})
 })
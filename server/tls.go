@@ -0,0 +1,161 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/lager"
+)
+
+// TLSConfig configures a server to serve over TLS, and to require clients to
+// present certificates signed by one of its client CAs.
+type TLSConfig struct {
+	// CertFile and KeyFile hold the PEM encoded certificate and key of the
+	// server.
+	CertFile string
+	KeyFile  string
+
+	// ClientCAFile holds the PEM encoded certificates of the CAs which must
+	// have signed clients' certificates.
+	ClientCAFile string
+
+	// Authorize, if not nil, decides whether the client whose certificate has
+	// the common name may make requests to the route, as named in the routes
+	// package. Requests it refuses fail with a garden.PermissionDeniedError.
+	Authorize func(commonName, route string) bool
+}
+
+// tlsCertificates holds the certificates a server serves TLS with, which may
+// be reloaded from their files while it runs.
+type tlsCertificates struct {
+	config TLSConfig
+
+	current *tls.Config
+	lock    sync.RWMutex
+}
+
+func newTLSCertificates(config TLSConfig) (*tlsCertificates, error) {
+	certs := &tlsCertificates{config: config}
+	if err := certs.reload(); err != nil {
+		return nil, err
+	}
+
+	return certs, nil
+}
+
+// reload loads the certificates from their files, for connections accepted
+// from then on. The certificates already loaded are kept if any fails to load.
+func (c *tlsCertificates) reload() error {
+	cert, err := tls.LoadX509KeyPair(c.config.CertFile, c.config.KeyFile)
+	if err != nil {
+		return fmt.Errorf("loading certificate: %s", err)
+	}
+
+	caPEM, err := ioutil.ReadFile(c.config.ClientCAFile)
+	if err != nil {
+		return fmt.Errorf("loading client CAs: %s", err)
+	}
+
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM(caPEM) {
+		return errors.New("loading client CAs: no certificates found")
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.current = &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientCAs:    clientCAs,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	return nil
+}
+
+// tlsConfig returns a configuration for a TLS listener which serves each
+// connection with the certificates current when it is accepted.
+func (c *tlsCertificates) tlsConfig() *tls.Config {
+	return &tls.Config{
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			c.lock.RLock()
+			defer c.lock.RUnlock()
+
+			return c.current, nil
+		},
+	}
+}
+
+// ReloadTLS reloads the server's TLS certificates from their files, as is done
+// when it receives a SIGHUP. Connections already accepted are unaffected.
+func (s *GardenServer) ReloadTLS() error {
+	if s.tlsCertificates == nil {
+		return errors.New("server is not serving TLS")
+	}
+
+	return s.tlsCertificates.reload()
+}
+
+// reloadTLSOnHangup reloads the server's TLS certificates whenever it receives
+// a SIGHUP, until it is stopped.
+func (s *GardenServer) reloadTLSOnHangup() {
+	hangups := make(chan os.Signal, 1)
+	signal.Notify(hangups, syscall.SIGHUP)
+
+	go func() {
+		defer signal.Stop(hangups)
+
+		rLog := s.logger.Session("reload-tls")
+
+		for {
+			select {
+			case <-hangups:
+				if err := s.ReloadTLS(); err != nil {
+					rLog.Error("failed", err)
+					continue
+				}
+
+				rLog.Info("reloaded")
+			case <-s.stopping:
+				return
+			}
+		}
+	}()
+}
+
+// authorize wraps the handler of a route so that only clients which the TLS
+// configuration's Authorize permits to make requests to it are served.
+func (s *GardenServer) authorize(route string, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		commonName := clientCommonName(r)
+		if commonName == "" || !s.tlsConfig.Authorize(commonName, route) {
+			s.writeError(w, garden.NewPermissionDeniedError(
+				r.FormValue(":handle"),
+				fmt.Sprintf("%q may not make %s requests", commonName, route),
+			), s.logger.Session("authorize", lager.Data{"route": route, "common-name": commonName}))
+			return
+		}
+
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// clientCommonName returns the common name of the certificate the client
+// presented, if it made the request over TLS.
+func clientCommonName(r *http.Request) string {
+	if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+		return ""
+	}
+
+	return r.TLS.PeerCertificates[0].Subject.CommonName
+}
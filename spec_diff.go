@@ -0,0 +1,199 @@
+package garden
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Kinds of Change.
+const (
+	ChangeAdded   = "added"
+	ChangeRemoved = "removed"
+	ChangeChanged = "changed"
+)
+
+// Change is a difference between two ContainerSpecs.
+type Change struct {
+	// Path names the field which differs by the JSON names of the fields
+	// leading to it, e.g. "limits.memory_limits.limit_in_bytes". Properties
+	// and environment variables are named by their key, e.g. "env.PATH", and
+	// bind mounts by their destination path, e.g. "bind_mounts./data".
+	Path string `json:"path"`
+
+	Kind string `json:"kind"`
+
+	// Old and New are the values formatted for display. The values of
+	// environment variables matching DefaultRedactedEnvPatterns, and the
+	// secrets of registry credentials, are redacted.
+	Old string `json:"old,omitempty"`
+	New string `json:"new,omitempty"`
+}
+
+func (c Change) String() string {
+	switch c.Kind {
+	case ChangeAdded:
+		return fmt.Sprintf("%s added: %s", c.Path, c.New)
+	case ChangeRemoved:
+		return fmt.Sprintf("%s removed: %s", c.Path, c.Old)
+	default:
+		return fmt.Sprintf("%s changed: %s -> %s", c.Path, c.Old, c.New)
+	}
+}
+
+// DiffSpecs returns the differences between the specs a and b, ordered by
+// path, e.g. to explain why a container was recreated. Zero values are
+// treated as absent, so a field set in b but not in a is reported as added.
+func DiffSpecs(a, b ContainerSpec) []Change {
+	changes := diffValues("", reflect.ValueOf(a), reflect.ValueOf(b))
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+
+	return changes
+}
+
+func diffValues(path string, a, b reflect.Value) []Change {
+	switch a.Type() {
+	case reflect.TypeOf(Properties{}):
+		return diffKeyed(path, propertyEntries(a.Interface().(Properties)), propertyEntries(b.Interface().(Properties)))
+	case reflect.TypeOf([]BindMount{}):
+		return diffKeyed(path, bindMountEntries(a.Interface().([]BindMount)), bindMountEntries(b.Interface().([]BindMount)))
+	}
+
+	if path == "env" {
+		changes := diffKeyed(path, envEntries(a.Interface().([]string)), envEntries(b.Interface().([]string)))
+		for i := range changes {
+			changes[i].Old = redactVariable(changes[i].Old)
+			changes[i].New = redactVariable(changes[i].New)
+		}
+
+		return changes
+	}
+
+	if a.Kind() == reflect.Struct {
+		changes := []Change{}
+		for i := 0; i < a.NumField(); i++ {
+			field := a.Type().Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+
+			changes = append(changes, diffValues(joinPath(path, jsonName(field)), a.Field(i), b.Field(i))...)
+		}
+
+		return changes
+	}
+
+	if reflect.DeepEqual(a.Interface(), b.Interface()) {
+		return nil
+	}
+
+	return []Change{change(path, a, b)}
+}
+
+func change(path string, a, b reflect.Value) Change {
+	c := Change{Path: path, Kind: ChangeChanged}
+
+	if !a.IsZero() {
+		c.Old = format(a)
+	}
+
+	if !b.IsZero() {
+		c.New = format(b)
+	}
+
+	switch {
+	case a.IsZero():
+		c.Kind = ChangeAdded
+	case b.IsZero():
+		c.Kind = ChangeRemoved
+	}
+
+	return c
+}
+
+func format(v reflect.Value) string {
+	if v.Kind() == reflect.Ptr {
+		return fmt.Sprintf("%v", v.Elem().Interface())
+	}
+
+	return fmt.Sprintf("%v", v.Interface())
+}
+
+func diffKeyed(path string, a, b map[string]string) []Change {
+	changes := []Change{}
+
+	for key, old := range a {
+		value, found := b[key]
+		switch {
+		case !found:
+			changes = append(changes, Change{Path: joinPath(path, key), Kind: ChangeRemoved, Old: old})
+		case value != old:
+			changes = append(changes, Change{Path: joinPath(path, key), Kind: ChangeChanged, Old: old, New: value})
+		}
+	}
+
+	for key, value := range b {
+		if _, found := a[key]; !found {
+			changes = append(changes, Change{Path: joinPath(path, key), Kind: ChangeAdded, New: value})
+		}
+	}
+
+	return changes
+}
+
+func propertyEntries(properties Properties) map[string]string {
+	entries := map[string]string{}
+	for key, value := range properties {
+		entries[key] = value
+	}
+
+	return entries
+}
+
+func bindMountEntries(mounts []BindMount) map[string]string {
+	entries := map[string]string{}
+	for _, mount := range mounts {
+		entries[mount.DstPath] = fmt.Sprintf("%+v", mount)
+	}
+
+	return entries
+}
+
+func envEntries(env []string) map[string]string {
+	entries := map[string]string{}
+	for _, variable := range env {
+		name := strings.SplitN(variable, "=", 2)[0]
+		entries[name] = variable
+	}
+
+	return entries
+}
+
+func redactVariable(variable string) string {
+	if variable == "" {
+		return ""
+	}
+
+	return RedactEnv([]string{variable}, DefaultRedactedEnvPatterns)[0]
+}
+
+func jsonName(field reflect.StructField) string {
+	name := strings.Split(field.Tag.Get("json"), ",")[0]
+	if name == "" || name == "-" {
+		return field.Name
+	}
+
+	return name
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+
+	return path + "." + name
+}
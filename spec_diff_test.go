@@ -0,0 +1,102 @@
+package garden_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"code.cloudfoundry.org/garden"
+)
+
+var _ = Describe("DiffSpecs", func() {
+	It("returns no changes for identical specs", func() {
+		spec := garden.ContainerSpec{
+			Handle:     "some-handle",
+			Env:        []string{"A=b"},
+			Properties: garden.Properties{"foo": "bar"},
+			BindMounts: []garden.BindMount{{SrcPath: "/src", DstPath: "/dst"}},
+		}
+
+		Ω(garden.DiffSpecs(spec, spec)).Should(BeEmpty())
+	})
+
+	It("reports changed, added and removed fields by their JSON path", func() {
+		a := garden.ContainerSpec{
+			RootFSPath: "docker:///busybox",
+			GraceTime:  time.Minute,
+			Limits: garden.Limits{
+				Memory: garden.MemoryLimits{LimitInBytes: 1024},
+			},
+		}
+		b := garden.ContainerSpec{
+			RootFSPath: "docker:///alpine",
+			Privileged: true,
+			Limits: garden.Limits{
+				Memory: garden.MemoryLimits{LimitInBytes: 2048},
+			},
+		}
+
+		Ω(garden.DiffSpecs(a, b)).Should(Equal([]garden.Change{
+			{Path: "grace_time", Kind: garden.ChangeRemoved, Old: "1m0s"},
+			{Path: "limits.memory_limits.limit_in_bytes", Kind: garden.ChangeChanged, Old: "1024", New: "2048"},
+			{Path: "privileged", Kind: garden.ChangeAdded, New: "true"},
+			{Path: "rootfs", Kind: garden.ChangeChanged, Old: "docker:///busybox", New: "docker:///alpine"},
+		}))
+	})
+
+	It("reports properties by key", func() {
+		a := garden.ContainerSpec{Properties: garden.Properties{"kept": "1", "changed": "old", "removed": "x"}}
+		b := garden.ContainerSpec{Properties: garden.Properties{"kept": "1", "changed": "new", "added": "y"}}
+
+		Ω(garden.DiffSpecs(a, b)).Should(Equal([]garden.Change{
+			{Path: "properties.added", Kind: garden.ChangeAdded, New: "y"},
+			{Path: "properties.changed", Kind: garden.ChangeChanged, Old: "old", New: "new"},
+			{Path: "properties.removed", Kind: garden.ChangeRemoved, Old: "x"},
+		}))
+	})
+
+	It("reports bind mounts by destination path regardless of order", func() {
+		a := garden.ContainerSpec{BindMounts: []garden.BindMount{
+			{SrcPath: "/a", DstPath: "/one"},
+			{SrcPath: "/b", DstPath: "/two"},
+		}}
+		b := garden.ContainerSpec{BindMounts: []garden.BindMount{
+			{SrcPath: "/b", DstPath: "/two", Mode: garden.BindMountModeRW},
+			{SrcPath: "/a", DstPath: "/one"},
+		}}
+
+		changes := garden.DiffSpecs(a, b)
+		Ω(changes).Should(HaveLen(1))
+		Ω(changes[0].Path).Should(Equal("bind_mounts./two"))
+		Ω(changes[0].Kind).Should(Equal(garden.ChangeChanged))
+	})
+
+	It("reports environment variables by name, redacting sensitive values", func() {
+		a := garden.ContainerSpec{Env: []string{"PATH=/bin", "DB_PASSWORD=old"}}
+		b := garden.ContainerSpec{Env: []string{"DB_PASSWORD=new", "PATH=/usr/bin"}}
+
+		Ω(garden.DiffSpecs(a, b)).Should(Equal([]garden.Change{
+			{Path: "env.DB_PASSWORD", Kind: garden.ChangeChanged, Old: "DB_PASSWORD=[REDACTED]", New: "DB_PASSWORD=[REDACTED]"},
+			{Path: "env.PATH", Kind: garden.ChangeChanged, Old: "PATH=/bin", New: "PATH=/usr/bin"},
+		}))
+	})
+
+	It("redacts the secrets of registry credentials", func() {
+		a := garden.ContainerSpec{RegistryCredentials: &garden.RegistryCredentials{Username: "user", Password: "old"}}
+		b := garden.ContainerSpec{RegistryCredentials: &garden.RegistryCredentials{Username: "user", Password: "new"}}
+
+		Ω(garden.DiffSpecs(a, b)).Should(Equal([]garden.Change{{
+			Path: "registry_credentials",
+			Kind: garden.ChangeChanged,
+			Old:  "{Username:user Password:[REDACTED] Token:}",
+			New:  "{Username:user Password:[REDACTED] Token:}",
+		}}))
+	})
+
+	It("describes changes for logging", func() {
+		Ω(garden.Change{Path: "rootfs", Kind: garden.ChangeChanged, Old: "a", New: "b"}.String()).Should(Equal("rootfs changed: a -> b"))
+		Ω(garden.Change{Path: "privileged", Kind: garden.ChangeAdded, New: "true"}.String()).Should(Equal("privileged added: true"))
+		Ω(garden.Change{Path: "env.A", Kind: garden.ChangeRemoved, Old: "A=b"}.String()).Should(Equal("env.A removed: A=b"))
+	})
+})
@@ -0,0 +1,346 @@
+package transport
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"code.cloudfoundry.org/garden"
+)
+
+// JSONPayloadContentType and BinaryPayloadContentType are the content types
+// in which the ProcessPayloads exchanged on a hijacked process connection may
+// be encoded. JSON is the default. A client asks for another content type
+// with the routes.PayloadContentTypeParam query parameter, and a server which
+// supports it confirms it in the ContentType of the first payload it sends,
+// which is always JSON followed by a newline, as written by WriteMessage;
+// every payload after it, in either direction, is then encoded in that
+// content type.
+const (
+	JSONPayloadContentType   = "application/json"
+	BinaryPayloadContentType = "application/vnd.garden.process-payload"
+)
+
+// MaxBinaryPayloadSize is the largest encoded ProcessPayload accepted by a
+// binary PayloadDecoder, in bytes.
+const MaxBinaryPayloadSize = 4 << 20
+
+// PayloadEncoder writes ProcessPayloads to a stream.
+type PayloadEncoder interface {
+	Encode(payload *ProcessPayload) error
+}
+
+// PayloadDecoder reads ProcessPayloads from a stream.
+type PayloadDecoder interface {
+	Decode(payload *ProcessPayload) error
+}
+
+// SupportedPayloadContentType reports whether payloads may be encoded in the
+// content type.
+func SupportedPayloadContentType(contentType string) bool {
+	return contentType == JSONPayloadContentType || contentType == BinaryPayloadContentType
+}
+
+// NewPayloadEncoder returns an encoder writing payloads to w in the content
+// type, which defaults to JSON if it is empty or not supported.
+func NewPayloadEncoder(w io.Writer, contentType string) PayloadEncoder {
+	if contentType == BinaryPayloadContentType {
+		return &binaryPayloadEncoder{w: w}
+	}
+
+	return jsonPayloadEncoder{json.NewEncoder(w)}
+}
+
+// NewPayloadDecoder returns a decoder reading payloads from r in the content
+// type, which defaults to JSON if it is empty or not supported. A strict
+// decoder rejects payloads with fields it does not know, as DecodeStrict does;
+// the payloads it decodes are not validated.
+func NewPayloadDecoder(r io.Reader, contentType string, strict bool) PayloadDecoder {
+	if contentType == BinaryPayloadContentType {
+		return &binaryPayloadDecoder{r: bufio.NewReader(r), strict: strict}
+	}
+
+	decoder := json.NewDecoder(r)
+	if strict {
+		decoder.DisallowUnknownFields()
+	}
+
+	return jsonPayloadDecoder{decoder}
+}
+
+type jsonPayloadEncoder struct {
+	encoder *json.Encoder
+}
+
+func (e jsonPayloadEncoder) Encode(payload *ProcessPayload) error {
+	return e.encoder.Encode(payload)
+}
+
+type jsonPayloadDecoder struct {
+	decoder *json.Decoder
+}
+
+func (d jsonPayloadDecoder) Decode(payload *ProcessPayload) error {
+	return d.decoder.Decode(payload)
+}
+
+// The binary encoding of a payload is its length, as a uvarint, followed by
+// its fields. Each field is its tag, as a uvarint, followed by the length of
+// its value, as a uvarint, and the value. Numbers are encoded as varints,
+// strings and data as is, flags as an empty value and the TTY spec as JSON.
+// Fields which are not set are omitted, and decoders skip fields they do not
+// know unless they are strict.
+const (
+	processIDTag uint64 = iota + 1
+	streamIDTag
+	sourceTag
+	dataTag
+	exitStatusTag
+	errorTag
+	ttyTag
+	signalTag
+	timedOutTag
+	heartbeatTag
+	idleTimeoutTag
+	closeStdinTag
+	contentTypeTag
+)
+
+type binaryPayloadEncoder struct {
+	w      io.Writer
+	fields []byte
+	frame  []byte
+}
+
+func (e *binaryPayloadEncoder) Encode(payload *ProcessPayload) error {
+	e.fields = e.fields[:0]
+
+	if payload.ProcessID != "" {
+		e.appendBytes(processIDTag, []byte(payload.ProcessID))
+	}
+	if payload.StreamID != "" {
+		e.appendBytes(streamIDTag, []byte(payload.StreamID))
+	}
+	if payload.Source != nil {
+		e.appendVarint(sourceTag, int64(*payload.Source))
+	}
+	if payload.Data != nil {
+		e.appendBytes(dataTag, []byte(*payload.Data))
+	}
+	if payload.ExitStatus != nil {
+		e.appendVarint(exitStatusTag, int64(*payload.ExitStatus))
+	}
+	if payload.Error != nil {
+		e.appendBytes(errorTag, []byte(*payload.Error))
+	}
+	if payload.TTY != nil {
+		tty, err := json.Marshal(payload.TTY)
+		if err != nil {
+			return err
+		}
+		e.appendBytes(ttyTag, tty)
+	}
+	if payload.Signal != nil {
+		e.appendVarint(signalTag, int64(*payload.Signal))
+	}
+	if payload.TimedOut {
+		e.appendBytes(timedOutTag, nil)
+	}
+	if payload.Heartbeat != nil {
+		e.appendVarint(heartbeatTag, int64(*payload.Heartbeat))
+	}
+	if payload.IdleTimeout != nil {
+		e.appendVarint(idleTimeoutTag, int64(*payload.IdleTimeout))
+	}
+	if payload.CloseStdin {
+		e.appendBytes(closeStdinTag, nil)
+	}
+	if payload.ContentType != "" {
+		e.appendBytes(contentTypeTag, []byte(payload.ContentType))
+	}
+
+	if len(e.fields) > MaxBinaryPayloadSize {
+		return fmt.Errorf("payload of %d bytes is larger than the limit of %d bytes", len(e.fields), MaxBinaryPayloadSize)
+	}
+
+	// write the payload in one go, so that it is not interleaved with others
+	// written concurrently to the same connection
+	e.frame = appendUvarint(e.frame[:0], uint64(len(e.fields)))
+	e.frame = append(e.frame, e.fields...)
+
+	_, err := e.w.Write(e.frame)
+	return err
+}
+
+func (e *binaryPayloadEncoder) appendBytes(tag uint64, value []byte) {
+	e.fields = appendUvarint(e.fields, tag)
+	e.fields = appendUvarint(e.fields, uint64(len(value)))
+	e.fields = append(e.fields, value...)
+}
+
+func (e *binaryPayloadEncoder) appendVarint(tag uint64, value int64) {
+	var buf [binary.MaxVarintLen64]byte
+	e.appendBytes(tag, buf[:binary.PutVarint(buf[:], value)])
+}
+
+func appendUvarint(b []byte, value uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(b, buf[:binary.PutUvarint(buf[:], value)]...)
+}
+
+type binaryPayloadDecoder struct {
+	r      *bufio.Reader
+	strict bool
+	frame  []byte
+}
+
+func (d *binaryPayloadDecoder) Decode(payload *ProcessPayload) error {
+	size, err := binary.ReadUvarint(d.r)
+	if err != nil {
+		return err
+	}
+
+	if size > MaxBinaryPayloadSize {
+		return fmt.Errorf("payload of %d bytes is larger than the limit of %d bytes", size, MaxBinaryPayloadSize)
+	}
+
+	if uint64(cap(d.frame)) < size {
+		d.frame = make([]byte, size)
+	}
+	d.frame = d.frame[:size]
+
+	if _, err := io.ReadFull(d.r, d.frame); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return err
+	}
+
+	*payload = ProcessPayload{}
+
+	fields := d.frame
+	for len(fields) > 0 {
+		var (
+			tag   uint64
+			value []byte
+		)
+
+		tag, value, fields, err = nextField(fields)
+		if err != nil {
+			return err
+		}
+
+		if err := d.decodeField(payload, tag, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// nextField splits the first field from the encoded fields. Its value refers
+// to the encoded fields rather than being a copy.
+func nextField(fields []byte) (uint64, []byte, []byte, error) {
+	tag, n := binary.Uvarint(fields)
+	if n <= 0 {
+		return 0, nil, nil, errMalformedPayload
+	}
+	fields = fields[n:]
+
+	length, n := binary.Uvarint(fields)
+	if n <= 0 || length > uint64(len(fields)-n) {
+		return 0, nil, nil, errMalformedPayload
+	}
+	fields = fields[n:]
+
+	return tag, fields[:length], fields[length:], nil
+}
+
+var errMalformedPayload = errors.New("malformed binary payload")
+
+func (d *binaryPayloadDecoder) decodeField(payload *ProcessPayload, tag uint64, value []byte) error {
+	switch tag {
+	case processIDTag:
+		payload.ProcessID = string(value)
+	case streamIDTag:
+		payload.StreamID = string(value)
+	case sourceTag:
+		n, err := decodeVarint(value)
+		if err != nil {
+			return err
+		}
+		source := Source(n)
+		payload.Source = &source
+	case dataTag:
+		data := string(value)
+		payload.Data = &data
+	case exitStatusTag:
+		n, err := decodeVarint(value)
+		if err != nil {
+			return err
+		}
+		status := int(n)
+		payload.ExitStatus = &status
+	case errorTag:
+		message := string(value)
+		payload.Error = &message
+	case ttyTag:
+		decoder := json.NewDecoder(bytes.NewReader(value))
+		if d.strict {
+			decoder.DisallowUnknownFields()
+		}
+
+		var tty garden.TTYSpec
+		if err := decoder.Decode(&tty); err != nil {
+			return fmt.Errorf("tty: %s", err)
+		}
+		payload.TTY = &tty
+	case signalTag:
+		n, err := decodeVarint(value)
+		if err != nil {
+			return err
+		}
+		signal := garden.Signal(n)
+		payload.Signal = &signal
+	case timedOutTag:
+		payload.TimedOut = true
+	case heartbeatTag:
+		n, err := decodeVarint(value)
+		if err != nil {
+			return err
+		}
+		heartbeat := time.Duration(n)
+		payload.Heartbeat = &heartbeat
+	case idleTimeoutTag:
+		n, err := decodeVarint(value)
+		if err != nil {
+			return err
+		}
+		idleTimeout := time.Duration(n)
+		payload.IdleTimeout = &idleTimeout
+	case closeStdinTag:
+		payload.CloseStdin = true
+	case contentTypeTag:
+		payload.ContentType = string(value)
+	default:
+		if d.strict {
+			return fmt.Errorf("unknown binary payload field %d", tag)
+		}
+	}
+
+	return nil
+}
+
+func decodeVarint(value []byte) (int64, error) {
+	n, read := binary.Varint(value)
+	if read <= 0 || read != len(value) {
+		return 0, errMalformedPayload
+	}
+
+	return n, nil
+}
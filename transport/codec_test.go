@@ -0,0 +1,151 @@
+package transport_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/garden/transport"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Payload codecs", func() {
+	var payload transport.ProcessPayload
+
+	BeforeEach(func() {
+		source := transport.Stderr
+		data := "some\x00data\n"
+		status := -1
+		message := "some error"
+		signal := garden.SignalKill
+		heartbeat := time.Second
+		idleTimeout := time.Minute
+
+		payload = transport.ProcessPayload{
+			ProcessID:   "some-process",
+			StreamID:    "some-stream",
+			Source:      &source,
+			Data:        &data,
+			ExitStatus:  &status,
+			Error:       &message,
+			TTY:         &garden.TTYSpec{WindowSize: &garden.WindowSize{Columns: 80, Rows: 24}},
+			Signal:      &signal,
+			TimedOut:    true,
+			Heartbeat:   &heartbeat,
+			IdleTimeout: &idleTimeout,
+			CloseStdin:  true,
+			ContentType: transport.BinaryPayloadContentType,
+		}
+	})
+
+	roundTrip := func(contentType string, payloads ...*transport.ProcessPayload) []transport.ProcessPayload {
+		var stream bytes.Buffer
+
+		encoder := transport.NewPayloadEncoder(&stream, contentType)
+		for _, p := range payloads {
+			Ω(encoder.Encode(p)).Should(Succeed())
+		}
+
+		decoder := transport.NewPayloadDecoder(&stream, contentType, true)
+
+		var decoded []transport.ProcessPayload
+		for range payloads {
+			var p transport.ProcessPayload
+			Ω(decoder.Decode(&p)).Should(Succeed())
+			decoded = append(decoded, p)
+		}
+
+		var p transport.ProcessPayload
+		Ω(decoder.Decode(&p)).Should(Equal(io.EOF))
+
+		return decoded
+	}
+
+	for _, contentType := range []string{transport.JSONPayloadContentType, transport.BinaryPayloadContentType} {
+		contentType := contentType
+
+		Context("encoding "+contentType, func() {
+			It("round trips a stream of payloads", func() {
+				empty := transport.ProcessPayload{}
+				Ω(roundTrip(contentType, &payload, &empty, &payload)).Should(Equal([]transport.ProcessPayload{payload, empty, payload}))
+			})
+		})
+	}
+
+	It("encodes as JSON when the content type is empty", func() {
+		var stream bytes.Buffer
+		Ω(transport.NewPayloadEncoder(&stream, "").Encode(&transport.ProcessPayload{ProcessID: "some-process"})).Should(Succeed())
+
+		var decoded map[string]interface{}
+		Ω(json.Unmarshal(stream.Bytes(), &decoded)).Should(Succeed())
+		Ω(decoded).Should(Equal(map[string]interface{}{"process_id": "some-process"}))
+	})
+
+	It("knows the content types it supports", func() {
+		Ω(transport.SupportedPayloadContentType(transport.JSONPayloadContentType)).Should(BeTrue())
+		Ω(transport.SupportedPayloadContentType(transport.BinaryPayloadContentType)).Should(BeTrue())
+		Ω(transport.SupportedPayloadContentType("application/x-protobuf")).Should(BeFalse())
+	})
+
+	Describe("decoding binary payloads", func() {
+		decode := func(encoded []byte, strict bool) (transport.ProcessPayload, error) {
+			var p transport.ProcessPayload
+			err := transport.NewPayloadDecoder(bytes.NewReader(encoded), transport.BinaryPayloadContentType, strict).Decode(&p)
+			return p, err
+		}
+
+		It("skips unknown fields unless strict", func() {
+			// a process ID, and a field 99 with a 1 byte value
+			encoded := []byte{6, 1, 1, 'p', 99, 1, 'x'}
+
+			p, err := decode(encoded, false)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(p.ProcessID).Should(Equal("p"))
+
+			_, err = decode(encoded, true)
+			Ω(err).Should(MatchError("unknown binary payload field 99"))
+		})
+
+		It("rejects fields which run past the end of the payload", func() {
+			_, err := decode([]byte{3, 1, 5, 'p'}, false)
+			Ω(err).Should(MatchError("malformed binary payload"))
+		})
+
+		It("rejects numbers which are not a single varint", func() {
+			_, err := decode([]byte{4, 5, 2, 1, 1}, false)
+			Ω(err).Should(MatchError("malformed binary payload"))
+		})
+
+		It("rejects TTY specs with unknown fields when strict", func() {
+			tty := `{"bogus":1}`
+			encoded := append([]byte{byte(2 + len(tty)), 7, byte(len(tty))}, tty...)
+
+			_, err := decode(encoded, true)
+			Ω(err).Should(MatchError(ContainSubstring(`unknown field "bogus"`)))
+		})
+
+		It("rejects payloads larger than the limit without reading them", func() {
+			_, err := decode([]byte{0x80, 0x80, 0x80, 0x04}, false)
+			Ω(err).Should(MatchError(ContainSubstring("larger than the limit of 4194304 bytes")))
+		})
+
+		It("reports a truncated payload", func() {
+			_, err := decode([]byte{4, 1, 2, 'p'}, false)
+			Ω(err).Should(Equal(io.ErrUnexpectedEOF))
+		})
+	})
+
+	It("refuses to encode binary payloads larger than the limit", func() {
+		data := strings.Repeat("x", transport.MaxBinaryPayloadSize)
+		stdin := transport.Stdin
+
+		var stream bytes.Buffer
+		err := transport.NewPayloadEncoder(&stream, transport.BinaryPayloadContentType).Encode(&transport.ProcessPayload{Source: &stdin, Data: &data})
+		Ω(err).Should(MatchError(ContainSubstring("larger than the limit")))
+		Ω(stream.Len()).Should(BeZero())
+	})
+})
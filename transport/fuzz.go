@@ -0,0 +1,126 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"code.cloudfoundry.org/garden"
+)
+
+// strictMessages returns a new value of each type that a server decodes
+// strictly from request bodies.
+func strictMessages() []interface{} {
+	var graceTime time.Duration
+
+	return []interface{}{
+		&garden.ContainerSpec{},
+		&garden.Limits{},
+		&garden.BandwidthLimits{},
+		&garden.CPULimits{},
+		&garden.MemoryLimits{},
+		&garden.PidLimits{},
+		&garden.BlockIOLimits{},
+		&BulkCreateRequest{},
+		&BulkDestroyRequest{},
+		&BulkStopRequest{},
+		&RestoreRequest{},
+		&CheckpointRequest{},
+		&CommitRequest{},
+		&CASPropertyRequest{},
+		&garden.Properties{},
+		&StopRequest{},
+		&WarmImageRequest{},
+		&graceTime,
+		&NetInRequest{},
+		&garden.NetOutRule{},
+		&BulkNetOutRequest{},
+		&garden.ProcessSpec{},
+		&garden.TTYSpec{},
+	}
+}
+
+// Fuzz is an entry point for go-fuzz over request bodies. It decodes the data
+// strictly as each kind of message, and panics if a message which was
+// accepted is not accepted again once encoded. It returns 1 if any kind of
+// message accepted the data, so that the fuzzer prefers such inputs, and 0
+// otherwise.
+func Fuzz(data []byte) int {
+	accepted := 0
+
+	for _, msg := range strictMessages() {
+		if DecodeStrict(bytes.NewReader(data), msg) != nil {
+			continue
+		}
+
+		accepted = 1
+		mustRoundTrip(msg)
+	}
+
+	return accepted
+}
+
+// FuzzProcessStream is an entry point for go-fuzz over the stream of
+// ProcessPayloads sent by a client on a hijacked process connection. It
+// decodes payloads as a server decoding strictly does, until one is rejected,
+// and panics if an accepted payload is not accepted again once encoded. It
+// returns 1 if any payload was accepted and 0 otherwise.
+func FuzzProcessStream(data []byte) int {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	accepted := 0
+	for {
+		var payload ProcessPayload
+		if decoder.Decode(&payload) != nil || Validate(&payload) != nil {
+			return accepted
+		}
+
+		accepted = 1
+		mustRoundTrip(&payload)
+	}
+}
+
+// FuzzBinaryProcessStream is like FuzzProcessStream, but decodes payloads
+// encoded as BinaryPayloadContentType.
+func FuzzBinaryProcessStream(data []byte) int {
+	decoder := NewPayloadDecoder(bytes.NewReader(data), BinaryPayloadContentType, true)
+
+	accepted := 0
+	for {
+		var payload ProcessPayload
+		if decoder.Decode(&payload) != nil || Validate(&payload) != nil {
+			return accepted
+		}
+
+		accepted = 1
+
+		var encoded bytes.Buffer
+		if err := NewPayloadEncoder(&encoded, BinaryPayloadContentType).Encode(&payload); err != nil {
+			panic(fmt.Sprintf("encoding accepted payload: %s", err))
+		}
+
+		var decoded ProcessPayload
+		if err := NewPayloadDecoder(&encoded, BinaryPayloadContentType, true).Decode(&decoded); err != nil {
+			panic(fmt.Sprintf("decoding re-encoded payload %x: %s", encoded.Bytes(), err))
+		}
+
+		if !reflect.DeepEqual(decoded, payload) {
+			panic(fmt.Sprintf("re-encoded payload %#v decoded as %#v", payload, decoded))
+		}
+	}
+}
+
+func mustRoundTrip(msg interface{}) {
+	encoded, err := json.Marshal(msg)
+	if err != nil {
+		panic(fmt.Sprintf("encoding accepted %T: %s", msg, err))
+	}
+
+	decoded := reflect.New(reflect.TypeOf(msg).Elem()).Interface()
+	if err := DecodeStrict(bytes.NewReader(encoded), decoded); err != nil {
+		panic(fmt.Sprintf("decoding re-encoded %T %s: %s", msg, encoded, err))
+	}
+}
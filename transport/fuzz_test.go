@@ -0,0 +1,41 @@
+package transport_test
+
+import (
+	"code.cloudfoundry.org/garden/transport"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Fuzz", func() {
+	It("returns 1 for data accepted as some message", func() {
+		Ω(transport.Fuzz([]byte(`{"handle":"some-handle"}`))).Should(Equal(1))
+		Ω(transport.Fuzz([]byte(`30000000000`))).Should(Equal(1))
+	})
+
+	It("returns 0 for data no message accepts", func() {
+		Ω(transport.Fuzz([]byte(`{"handle":`))).Should(Equal(0))
+		Ω(transport.Fuzz([]byte(`{"not-a-field":1}`))).Should(Equal(0))
+		Ω(transport.Fuzz([]byte(`-1`))).Should(Equal(0))
+	})
+})
+
+var _ = Describe("FuzzProcessStream", func() {
+	It("returns 1 when payloads are accepted", func() {
+		Ω(transport.FuzzProcessStream([]byte(`{"process_id":"p"} {"close_stdin":true}`))).Should(Equal(1))
+	})
+
+	It("returns 0 when the first payload is rejected", func() {
+		Ω(transport.FuzzProcessStream([]byte(`{"signal":42}`))).Should(Equal(0))
+	})
+})
+
+var _ = Describe("FuzzBinaryProcessStream", func() {
+	It("returns 1 when payloads are accepted", func() {
+		Ω(transport.FuzzBinaryProcessStream([]byte{4, 1, 2, 'p', 'i'})).Should(Equal(1))
+	})
+
+	It("returns 0 when the first payload is rejected", func() {
+		Ω(transport.FuzzBinaryProcessStream([]byte{3, 8, 1, 84})).Should(Equal(0))
+		Ω(transport.FuzzBinaryProcessStream([]byte{2, 99, 0})).Should(Equal(0))
+	})
+})
@@ -1,6 +1,10 @@
 package transport
 
-import "code.cloudfoundry.org/garden"
+import (
+	"time"
+
+	"code.cloudfoundry.org/garden"
+)
 
 type Source int
 
@@ -19,15 +23,90 @@ type ProcessPayload struct {
 	Error      *string         `json:"error,omitempty"`
 	TTY        *garden.TTYSpec `json:"tty,omitempty"`
 	Signal     *garden.Signal  `json:"signal,omitempty"`
+
+	// TimedOut accompanies the exit status of a process which the server
+	// killed because it ran for longer than its timeout.
+	TimedOut bool `json:"timed_out,omitempty"`
+
+	// Heartbeat is sent periodically by the server while a process is running
+	// and carries the interval at which further heartbeats will follow.
+	Heartbeat *time.Duration `json:"heartbeat,omitempty"`
+
+	// IdleTimeout accompanies heartbeats from servers which close connections
+	// on which nothing has been received for that long. Clients echo such
+	// heartbeats back to keep the connection open.
+	IdleTimeout *time.Duration `json:"idle_timeout,omitempty"`
+
+	// CloseStdin asks the server to close the process's stdin without
+	// tearing down the rest of the stream.
+	CloseStdin bool `json:"close_stdin,omitempty"`
+
+	// ContentType accompanies the first payload from servers which encode the
+	// payloads after it in a content type other than JSON, as the client
+	// asked them to.
+	ContentType string `json:"content_type,omitempty"`
 }
 
 type NetInRequest struct {
 	Handle        string `json:"handle,omitempty"`
 	HostPort      uint32 `json:"host_port,omitempty"`
 	ContainerPort uint32 `json:"container_port,omitempty"`
+	HostIP        string `json:"host_ip,omitempty"`
 }
 
 type NetInResponse struct {
 	HostPort      uint32 `json:"host_port,omitempty"`
 	ContainerPort uint32 `json:"container_port,omitempty"`
+	HostIP        string `json:"host_ip,omitempty"`
+}
+
+type BulkCreateRequest struct {
+	Specs []garden.ContainerSpec `json:"specs"`
+}
+
+type BulkDestroyRequest struct {
+	Handles []string `json:"handles"`
+}
+
+type BulkStopRequest struct {
+	Handles []string `json:"handles"`
+	Kill    bool     `json:"kill"`
+}
+
+type BulkNetOutRequest struct {
+	Rules []garden.NetOutRule `json:"rules"`
+}
+
+type CheckpointRequest struct {
+	Destination string `json:"destination"`
+}
+
+type CommitRequest struct {
+	ImageName string `json:"image_name"`
+}
+
+// CASPropertyRequest sets a property to Value only if its current value is
+// OldValue, where an empty OldValue means that the property is not set.
+type CASPropertyRequest struct {
+	OldValue string `json:"old_value"`
+	Value    string `json:"value"`
+}
+
+type RestoreRequest struct {
+	Spec   garden.ContainerSpec `json:"spec"`
+	Source string               `json:"source"`
+}
+
+type WarmImageRequest struct {
+	URI                 string                      `json:"uri"`
+	RegistryCredentials *garden.RegistryCredentials `json:"registry_credentials,omitempty"`
+}
+
+type RunDetachedResponse struct {
+	ProcessID string `json:"process_id"`
+}
+
+type StopRequest struct {
+	Kill    bool          `json:"kill"`
+	Timeout time.Duration `json:"timeout,omitempty"`
 }
@@ -0,0 +1,609 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"code.cloudfoundry.org/garden"
+)
+
+// MaxPort is the largest port accepted in a strictly decoded message.
+const MaxPort = 65535
+
+// MaxCPUShares is the largest CPU share limit accepted in a strictly decoded
+// message, matching the kernel's bound on cpu.shares.
+const MaxCPUShares = 262144
+
+// MinCPUPeriod and MaxCPUPeriod bound the CPU quota period accepted in a
+// strictly decoded message, and MinCPUQuota the quota, in microseconds,
+// matching the kernel's bounds on cpu.cfs_period_us and cpu.cfs_quota_us.
+const (
+	MinCPUPeriod = 1000
+	MaxCPUPeriod = 1000000
+	MinCPUQuota  = 1000
+)
+
+// MaxSwappiness is the largest swappiness accepted in a strictly decoded
+// message, matching the kernel's bound on memory.swappiness.
+const MaxSwappiness = 100
+
+// MaxWindowDimension is the largest number of columns or rows accepted for a
+// TTY window size in a strictly decoded message.
+const MaxWindowDimension = 65535
+
+// DecodeStrict decodes a single message from the reader into msg, rejecting
+// fields which msg does not have and any data following the message, and then
+// checks the message with Validate. Failures are returned as a
+// garden.MalformedRequestError.
+func DecodeStrict(r io.Reader, msg interface{}) error {
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(msg); err != nil {
+		return garden.MalformedRequestError{Cause: err.Error()}
+	}
+
+	if _, err := decoder.Token(); err != io.EOF {
+		return garden.MalformedRequestError{Cause: "unexpected data after message"}
+	}
+
+	return Validate(msg)
+}
+
+// Validate checks that the numeric values of a decoded message are within
+// their bounds, returning a garden.MalformedRequestError naming the first
+// offending field. Messages of types it does not know about are valid.
+func Validate(msg interface{}) error {
+	var err error
+
+	switch m := msg.(type) {
+	case *garden.ContainerSpec:
+		err = validateContainerSpec(*m)
+	case *BulkCreateRequest:
+		for i, spec := range m.Specs {
+			if err = validateContainerSpec(spec); err != nil {
+				err = fmt.Errorf("specs[%d].%s", i, err)
+				break
+			}
+		}
+	case *RestoreRequest:
+		if err = validateContainerSpec(m.Spec); err != nil {
+			err = fmt.Errorf("spec.%s", err)
+		}
+	case *CommitRequest:
+		if m.ImageName == "" || strings.Contains(m.ImageName, "@") || !imageReferencePattern.MatchString(m.ImageName) {
+			err = fmt.Errorf("image_name: %q is not an image name", m.ImageName)
+		}
+	case *WarmImageRequest:
+		err = validateWarmImageRequest(*m)
+	case *garden.Limits:
+		err = validateLimits(*m)
+	case *garden.CPULimits:
+		err = validateCPULimits(*m)
+	case *garden.MemoryLimits:
+		err = validateMemoryLimits(*m)
+	case *StopRequest:
+		err = validateDuration("timeout", m.Timeout)
+	case *time.Duration:
+		err = validateDuration("grace_time", *m)
+	case *NetInRequest:
+		err = validatePort("host_port", m.HostPort)
+		if err == nil {
+			err = validatePort("container_port", m.ContainerPort)
+		}
+		if err == nil && m.HostIP != "" && net.ParseIP(m.HostIP) == nil {
+			err = fmt.Errorf("host_ip: %q is not an IP", m.HostIP)
+		}
+	case *garden.NetOutRule:
+		err = validateNetOutRule(*m)
+	case *BulkNetOutRequest:
+		for i, rule := range m.Rules {
+			if err = validateNetOutRule(rule); err != nil {
+				err = fmt.Errorf("rules[%d].%s", i, err)
+				break
+			}
+		}
+	case *garden.ProcessSpec:
+		err = validateProcessSpec(*m)
+	case *garden.TTYSpec:
+		err = validateTTY("tty", *m)
+	case *ProcessPayload:
+		err = validateProcessPayload(*m)
+	}
+
+	if err != nil {
+		return garden.MalformedRequestError{Cause: err.Error()}
+	}
+
+	return nil
+}
+
+func validateContainerSpec(spec garden.ContainerSpec) error {
+	if err := validateDuration("grace_time", spec.GraceTime); err != nil {
+		return err
+	}
+
+	for i, mount := range spec.BindMounts {
+		if mount.Mode > garden.BindMountModeRW {
+			return fmt.Errorf("bind_mounts[%d].mode: %d is not a known mode", i, mount.Mode)
+		}
+
+		if mount.Origin > garden.BindMountOriginContainer {
+			return fmt.Errorf("bind_mounts[%d].origin: %d is not a known origin", i, mount.Origin)
+		}
+
+		if mount.Propagation > garden.BindMountPropagationSlave {
+			return fmt.Errorf("bind_mounts[%d].propagation: %d is not a known propagation", i, mount.Propagation)
+		}
+
+		if mount.RecursiveReadOnly && mount.Mode != garden.BindMountModeRO {
+			return fmt.Errorf("bind_mounts[%d].recursive_read_only: requires mode RO", i)
+		}
+	}
+
+	if err := validateLimits(spec.Limits); err != nil {
+		return fmt.Errorf("limits.%s", err)
+	}
+
+	if spec.Image != nil {
+		if err := validateImageRef(spec); err != nil {
+			return err
+		}
+	}
+
+	if spec.RegistryCredentials != nil {
+		if err := validateRegistryCredentials(*spec.RegistryCredentials); err != nil {
+			return err
+		}
+	}
+
+	if strings.IndexFunc(spec.LSMProfile, unicode.IsSpace) >= 0 {
+		return fmt.Errorf("lsm_profile: %q contains whitespace", spec.LSMProfile)
+	}
+
+	if spec.SeccompProfile != nil {
+		if err := validateSeccompProfile(*spec.SeccompProfile); err != nil {
+			return err
+		}
+	}
+
+	for i, device := range spec.Devices {
+		if err := validateDevice(device); err != nil {
+			return fmt.Errorf("devices[%d].%s", i, err)
+		}
+	}
+
+	if spec.RestartPolicy != nil {
+		if err := validateRestartPolicy(*spec.RestartPolicy); err != nil {
+			return fmt.Errorf("restart_policy.%s", err)
+		}
+	}
+
+	if spec.HealthCheck != nil {
+		if err := validateHealthCheck(*spec.HealthCheck); err != nil {
+			return fmt.Errorf("health_check.%s", err)
+		}
+	}
+
+	return nil
+}
+
+func validateHealthCheck(check garden.HealthCheck) error {
+	switch {
+	case check.Process != nil:
+		if check.TCP != nil || check.HTTP != nil {
+			return errors.New("process: cannot be combined with tcp or http")
+		}
+
+		if check.Process.Path == "" {
+			return errors.New("process.path: is required")
+		}
+
+		if err := validateProcessSpec(*check.Process); err != nil {
+			return fmt.Errorf("process.%s", err)
+		}
+	case check.TCP != nil:
+		if check.HTTP != nil {
+			return errors.New("tcp: cannot be combined with http")
+		}
+
+		if err := validateProbePort("tcp.port", check.TCP.Port); err != nil {
+			return err
+		}
+	case check.HTTP != nil:
+		if err := validateProbePort("http.port", check.HTTP.Port); err != nil {
+			return err
+		}
+
+		if check.HTTP.Path != "" && !strings.HasPrefix(check.HTTP.Path, "/") {
+			return fmt.Errorf("http.path: %q is not an absolute path", check.HTTP.Path)
+		}
+	default:
+		return errors.New("process, tcp or http: is required")
+	}
+
+	if err := validateDuration("interval", check.Interval); err != nil {
+		return err
+	}
+
+	if err := validateDuration("timeout", check.Timeout); err != nil {
+		return err
+	}
+
+	if check.FailureThreshold < 0 {
+		return fmt.Errorf("failure_threshold: %d is negative", check.FailureThreshold)
+	}
+
+	return nil
+}
+
+func validateRestartPolicy(policy garden.RestartPolicy) error {
+	switch policy.Mode {
+	case garden.RestartNever, garden.RestartOnFailure, garden.RestartAlways:
+	default:
+		return fmt.Errorf("mode: %q is not a known mode", policy.Mode)
+	}
+
+	if policy.Process.Path == "" {
+		return errors.New("process.path: is required")
+	}
+
+	if err := validateProcessSpec(policy.Process); err != nil {
+		return fmt.Errorf("process.%s", err)
+	}
+
+	if err := validateDuration("backoff", policy.Backoff); err != nil {
+		return err
+	}
+
+	if err := validateDuration("max_backoff", policy.MaxBackoff); err != nil {
+		return err
+	}
+
+	if policy.MaxRestarts < 0 {
+		return fmt.Errorf("max_restarts: %d is negative", policy.MaxRestarts)
+	}
+
+	return nil
+}
+
+// imageReferencePattern matches OCI image references: an optional registry
+// host and port, a repository of lower case path components, an optional tag
+// and an optional digest.
+var imageReferencePattern = regexp.MustCompile(`^` +
+	`(?:[a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?(?:\.[a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?)*(?::[0-9]+)?/)?` +
+	`[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*(?:/[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*)*` +
+	`(?::[\w][\w.-]{0,127})?` +
+	`(?:@[A-Za-z][A-Za-z0-9]*(?:[-_+.][A-Za-z][A-Za-z0-9]*)*:[0-9a-fA-F]{32,})?$`)
+
+// digestLengths are the lengths of the hex encoded digests of the algorithms
+// registered by the OCI image specification.
+var digestLengths = map[string]int{
+	"sha256": 64,
+	"sha512": 128,
+}
+
+func validateImageRef(spec garden.ContainerSpec) error {
+	if spec.RootFSPath != "" {
+		return errors.New("image: cannot be combined with rootfs")
+	}
+
+	reference := spec.Image.Reference
+	if !imageReferencePattern.MatchString(reference) {
+		return fmt.Errorf("image.reference: %q is not an OCI image reference", reference)
+	}
+
+	if digest := spec.Image.Digest(); digest != "" {
+		parts := strings.SplitN(digest, ":", 2)
+		if length, known := digestLengths[parts[0]]; known && len(parts[1]) != length {
+			return fmt.Errorf("image.reference: %s digest %q is not %d hex characters", parts[0], parts[1], length)
+		}
+	}
+
+	return nil
+}
+
+func validateWarmImageRequest(request WarmImageRequest) error {
+	if request.URI == "" {
+		return errors.New("uri: is required")
+	}
+
+	if request.RegistryCredentials != nil {
+		return validateRegistryCredentials(*request.RegistryCredentials)
+	}
+
+	return nil
+}
+
+func validateRegistryCredentials(credentials garden.RegistryCredentials) error {
+	if credentials.Token != "" {
+		if credentials.Username != "" || credentials.Password != "" {
+			return errors.New("registry_credentials: token cannot be combined with username and password")
+		}
+
+		return nil
+	}
+
+	if credentials.Username == "" || credentials.Password == "" {
+		return errors.New("registry_credentials: username and password, or token, are required")
+	}
+
+	return nil
+}
+
+func validateSeccompProfile(profile garden.SeccompProfile) error {
+	if (profile.Name == "") == (len(profile.Inline) == 0) {
+		return errors.New("seccomp_profile: exactly one of name and inline is required")
+	}
+
+	if len(profile.Inline) > 0 && !bytes.HasPrefix(bytes.TrimSpace(profile.Inline), []byte("{")) {
+		return errors.New("seccomp_profile.inline: must be a JSON object")
+	}
+
+	return nil
+}
+
+func validateDevice(device garden.Device) error {
+	if !path.IsAbs(device.Path) {
+		return fmt.Errorf("path: %q is not absolute", device.Path)
+	}
+
+	if device.Type != garden.DeviceTypeChar && device.Type != garden.DeviceTypeBlock {
+		return fmt.Errorf("type: %q is not a known type", device.Type)
+	}
+
+	if device.Major < 0 {
+		return fmt.Errorf("major: %d is negative", device.Major)
+	}
+
+	if device.Minor < 0 {
+		return fmt.Errorf("minor: %d is negative", device.Minor)
+	}
+
+	seen := map[rune]bool{}
+	for _, p := range device.Permissions {
+		if !strings.ContainsRune("rwm", p) || seen[p] {
+			return fmt.Errorf("permissions: %q is not made of r, w and m", device.Permissions)
+		}
+
+		seen[p] = true
+	}
+
+	return nil
+}
+
+func validateLimits(limits garden.Limits) error {
+	if err := validateCPULimits(limits.CPU); err != nil {
+		return fmt.Errorf("cpu_limits.%s", err)
+	}
+
+	if err := validateMemoryLimits(limits.Memory); err != nil {
+		return fmt.Errorf("memory_limits.%s", err)
+	}
+
+	disk := limits.Disk
+	if disk.Scope > garden.DiskLimitScopeExclusive {
+		return fmt.Errorf("disk_limits.scope: %d is not a known scope", disk.Scope)
+	}
+
+	if disk.ByteHard != 0 && disk.ByteSoft > disk.ByteHard {
+		return fmt.Errorf("disk_limits.byte_soft: %d exceeds byte_hard %d", disk.ByteSoft, disk.ByteHard)
+	}
+
+	if disk.InodeHard != 0 && disk.InodeSoft > disk.InodeHard {
+		return fmt.Errorf("disk_limits.inode_soft: %d exceeds inode_hard %d", disk.InodeSoft, disk.InodeHard)
+	}
+
+	return nil
+}
+
+func validateCPULimits(limits garden.CPULimits) error {
+	if limits.LimitInShares > MaxCPUShares {
+		return fmt.Errorf("limit_in_shares: %d exceeds %d", limits.LimitInShares, MaxCPUShares)
+	}
+
+	if limits.QuotaInMicroseconds != 0 && limits.QuotaInMicroseconds < MinCPUQuota {
+		return fmt.Errorf("quota_in_microseconds: %d is less than %d", limits.QuotaInMicroseconds, MinCPUQuota)
+	}
+
+	period := limits.PeriodInMicroseconds
+	if period != 0 && (period < MinCPUPeriod || period > MaxCPUPeriod) {
+		return fmt.Errorf("period_in_microseconds: %d is not between %d and %d", period, MinCPUPeriod, MaxCPUPeriod)
+	}
+
+	if limits.Cpuset != "" && !validCpuset(limits.Cpuset) {
+		return fmt.Errorf("cpuset: %q is not a list of CPUs", limits.Cpuset)
+	}
+
+	return nil
+}
+
+func validateMemoryLimits(limits garden.MemoryLimits) error {
+	if limits.Swappiness != nil && *limits.Swappiness > MaxSwappiness {
+		return fmt.Errorf("swappiness: %d exceeds %d", *limits.Swappiness, MaxSwappiness)
+	}
+
+	return nil
+}
+
+// validCpuset reports whether the cpuset is a comma separated list of CPUs
+// and ascending ranges of CPUs, e.g. "0-3,6".
+func validCpuset(cpuset string) bool {
+	for _, item := range strings.Split(cpuset, ",") {
+		bounds := strings.SplitN(item, "-", 2)
+
+		first, err := strconv.ParseUint(bounds[0], 10, 16)
+		if err != nil {
+			return false
+		}
+
+		if len(bounds) == 2 {
+			last, err := strconv.ParseUint(bounds[1], 10, 16)
+			if err != nil || last < first {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func validateNetOutRule(rule garden.NetOutRule) error {
+	if rule.Protocol > garden.ProtocolICMPv6 {
+		return fmt.Errorf("protocol: %d is not a known protocol", rule.Protocol)
+	}
+
+	if err := ValidateNetOutNetworks(rule); err != nil {
+		return err
+	}
+
+	for i, ports := range rule.Ports {
+		if ports.End != 0 && ports.Start > ports.End {
+			return fmt.Errorf("ports[%d]: start %d is after end %d", i, ports.Start, ports.End)
+		}
+	}
+
+	return nil
+}
+
+// ValidateNetOutNetworks checks that the bounds of each of the rule's networks
+// are of the same IP family and in order, and that ICMP and ICMPv6 rules apply
+// only to networks of their family. Unlike Validate, servers check this
+// whether or not they decode strictly, as backends cannot apply such rules.
+func ValidateNetOutNetworks(rule garden.NetOutRule) error {
+	for i, network := range rule.Networks {
+		if err := validateIPRange(network); err != nil {
+			return fmt.Errorf("networks[%d]: %s", i, err)
+		}
+
+		if network.Start == nil && network.End == nil {
+			continue
+		}
+
+		if rule.Protocol == garden.ProtocolICMP && network.IPv6() {
+			return fmt.Errorf("networks[%d]: ICMP rules apply only to IPv4 networks", i)
+		}
+
+		if rule.Protocol == garden.ProtocolICMPv6 && !network.IPv6() {
+			return fmt.Errorf("networks[%d]: ICMPv6 rules apply only to IPv6 networks", i)
+		}
+	}
+
+	return nil
+}
+
+func validateIPRange(network garden.IPRange) error {
+	if network.Start == nil || network.End == nil {
+		return nil
+	}
+
+	if (network.Start.To4() == nil) != (network.End.To4() == nil) {
+		return fmt.Errorf("start %s and end %s are not of the same family", network.Start, network.End)
+	}
+
+	if bytes.Compare(network.Start.To16(), network.End.To16()) > 0 {
+		return fmt.Errorf("start %s is after end %s", network.Start, network.End)
+	}
+
+	return nil
+}
+
+func validateProcessSpec(spec garden.ProcessSpec) error {
+	if strings.ContainsAny(spec.Name, "/?#") || strings.IndexFunc(spec.Name, unicode.IsSpace) >= 0 {
+		return fmt.Errorf("name: %q is not a process name", spec.Name)
+	}
+
+	for i, group := range spec.SupplementaryGroups {
+		if group == "" || strings.ContainsAny(group, ":\n") {
+			return fmt.Errorf("supplementary_groups[%d]: %q is not a group", i, group)
+		}
+	}
+
+	if spec.Umask != nil && *spec.Umask > 0777 {
+		return fmt.Errorf("umask: %#o is not a file mode mask", *spec.Umask)
+	}
+
+	if spec.OOMScoreAdj != nil && (*spec.OOMScoreAdj < garden.MinOOMScoreAdj || *spec.OOMScoreAdj > garden.MaxOOMScoreAdj) {
+		return fmt.Errorf("oom_score_adj: %d is not between %d and %d", *spec.OOMScoreAdj, garden.MinOOMScoreAdj, garden.MaxOOMScoreAdj)
+	}
+
+	if err := validateDuration("timeout", spec.Timeout); err != nil {
+		return err
+	}
+
+	if spec.TTY != nil {
+		return validateTTY("tty", *spec.TTY)
+	}
+
+	return nil
+}
+
+func validateProcessPayload(payload ProcessPayload) error {
+	if payload.Source != nil && (*payload.Source < Stdin || *payload.Source > Stderr) {
+		return fmt.Errorf("source: %d is not a known source", *payload.Source)
+	}
+
+	if payload.Signal != nil && !payload.Signal.Valid() {
+		return fmt.Errorf("signal: %d is not a known signal", *payload.Signal)
+	}
+
+	if payload.TTY != nil {
+		return validateTTY("tty", *payload.TTY)
+	}
+
+	return nil
+}
+
+func validateTTY(field string, tty garden.TTYSpec) error {
+	if tty.WindowSize == nil {
+		return nil
+	}
+
+	if err := validateWindowDimension(field+".window_size.columns", tty.WindowSize.Columns); err != nil {
+		return err
+	}
+
+	return validateWindowDimension(field+".window_size.rows", tty.WindowSize.Rows)
+}
+
+func validateWindowDimension(field string, value int) error {
+	if value < 0 || value > MaxWindowDimension {
+		return fmt.Errorf("%s: %d is not between 0 and %d", field, value, MaxWindowDimension)
+	}
+
+	return nil
+}
+
+func validateProbePort(field string, port uint32) error {
+	if port == 0 {
+		return fmt.Errorf("%s: is required", field)
+	}
+
+	return validatePort(field, port)
+}
+
+func validatePort(field string, port uint32) error {
+	if port > MaxPort {
+		return fmt.Errorf("%s: %d exceeds %d", field, port, MaxPort)
+	}
+
+	return nil
+}
+
+func validateDuration(field string, d time.Duration) error {
+	if d < 0 {
+		return fmt.Errorf("%s: %s is negative", field, d)
+	}
+
+	return nil
+}
@@ -0,0 +1,519 @@
+package transport_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/garden/transport"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DecodeStrict", func() {
+	It("decodes a well-formed message", func() {
+		var spec garden.ContainerSpec
+		err := transport.DecodeStrict(strings.NewReader(`{"handle":"some-handle","limits":{"cpu_limits":{"limit_in_shares":512}}}`), &spec)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(spec.Handle).Should(Equal("some-handle"))
+		Ω(spec.Limits.CPU.LimitInShares).Should(BeEquivalentTo(512))
+	})
+
+	It("rejects unknown fields", func() {
+		var spec garden.ContainerSpec
+		err := transport.DecodeStrict(strings.NewReader(`{"handle":"some-handle","bogus":1}`), &spec)
+		Ω(err).Should(BeAssignableToTypeOf(garden.MalformedRequestError{}))
+		Ω(err).Should(MatchError(ContainSubstring(`unknown field "bogus"`)))
+	})
+
+	It("rejects data following the message", func() {
+		var request transport.StopRequest
+		err := transport.DecodeStrict(strings.NewReader(`{"kill":true} {"kill":false}`), &request)
+		Ω(err).Should(MatchError("malformed request: unexpected data after message"))
+	})
+
+	It("rejects syntax errors", func() {
+		var request transport.StopRequest
+		err := transport.DecodeStrict(strings.NewReader(`{"kill":`), &request)
+		Ω(err).Should(BeAssignableToTypeOf(garden.MalformedRequestError{}))
+	})
+
+	It("rejects values out of bounds", func() {
+		var request transport.NetInRequest
+		err := transport.DecodeStrict(strings.NewReader(`{"host_port":70000}`), &request)
+		Ω(err).Should(MatchError("malformed request: host_port: 70000 exceeds 65535"))
+	})
+
+	It("rejects a host IP which is not an IP", func() {
+		var request transport.NetInRequest
+		err := transport.DecodeStrict(strings.NewReader(`{"host_ip":"eth0"}`), &request)
+		Ω(err).Should(MatchError(`malformed request: host_ip: "eth0" is not an IP`))
+
+		Ω(transport.DecodeStrict(strings.NewReader(`{"host_ip":"10.0.0.1"}`), &request)).Should(Succeed())
+		Ω(request.HostIP).Should(Equal("10.0.0.1"))
+	})
+})
+
+var _ = Describe("Validate", func() {
+	It("rejects a negative grace time", func() {
+		Ω(transport.Validate(&garden.ContainerSpec{GraceTime: -time.Second})).Should(Equal(garden.MalformedRequestError{
+			Cause: "grace_time: -1s is negative",
+		}))
+	})
+
+	It("rejects too many CPU shares", func() {
+		Ω(transport.Validate(&garden.ContainerSpec{
+			Limits: garden.Limits{CPU: garden.CPULimits{LimitInShares: transport.MaxCPUShares + 1}},
+		})).Should(Equal(garden.MalformedRequestError{
+			Cause: "limits.cpu_limits.limit_in_shares: 262145 exceeds 262144",
+		}))
+	})
+
+	It("rejects a CPU quota period out of bounds", func() {
+		Ω(transport.Validate(&garden.Limits{
+			CPU: garden.CPULimits{QuotaInMicroseconds: 50000, PeriodInMicroseconds: 10},
+		})).Should(Equal(garden.MalformedRequestError{
+			Cause: "cpu_limits.period_in_microseconds: 10 is not between 1000 and 1000000",
+		}))
+	})
+
+	It("rejects a CPU quota below the minimum", func() {
+		Ω(transport.Validate(&garden.Limits{
+			CPU: garden.CPULimits{QuotaInMicroseconds: 10},
+		})).Should(Equal(garden.MalformedRequestError{
+			Cause: "cpu_limits.quota_in_microseconds: 10 is less than 1000",
+		}))
+	})
+
+	It("rejects a malformed cpuset", func() {
+		for _, cpuset := range []string{"a", "0-", "3-1", "0,,1", "-1"} {
+			Ω(transport.Validate(&garden.Limits{
+				CPU: garden.CPULimits{Cpuset: cpuset},
+			})).Should(Equal(garden.MalformedRequestError{
+				Cause: fmt.Sprintf("cpu_limits.cpuset: %q is not a list of CPUs", cpuset),
+			}))
+		}
+	})
+
+	It("rejects CPU limits out of bounds on their own", func() {
+		Ω(transport.Validate(&garden.CPULimits{QuotaInMicroseconds: 10})).Should(Equal(garden.MalformedRequestError{
+			Cause: "quota_in_microseconds: 10 is less than 1000",
+		}))
+	})
+
+	It("rejects a swappiness above 100", func() {
+		swappiness := uint64(101)
+		Ω(transport.Validate(&garden.MemoryLimits{Swappiness: &swappiness})).Should(Equal(garden.MalformedRequestError{
+			Cause: "swappiness: 101 exceeds 100",
+		}))
+	})
+
+	It("rejects a disk soft limit above the hard limit", func() {
+		Ω(transport.Validate(&garden.ContainerSpec{
+			Limits: garden.Limits{Disk: garden.DiskLimits{ByteSoft: 2, ByteHard: 1}},
+		})).Should(Equal(garden.MalformedRequestError{
+			Cause: "limits.disk_limits.byte_soft: 2 exceeds byte_hard 1",
+		}))
+	})
+
+	It("rejects an unknown bind mount mode", func() {
+		Ω(transport.Validate(&garden.ContainerSpec{
+			BindMounts: []garden.BindMount{{Mode: 7}},
+		})).Should(Equal(garden.MalformedRequestError{
+			Cause: "bind_mounts[0].mode: 7 is not a known mode",
+		}))
+	})
+
+	It("rejects an unknown bind mount propagation", func() {
+		Ω(transport.Validate(&garden.ContainerSpec{
+			BindMounts: []garden.BindMount{{}, {Propagation: 3}},
+		})).Should(Equal(garden.MalformedRequestError{
+			Cause: "bind_mounts[1].propagation: 3 is not a known propagation",
+		}))
+	})
+
+	It("rejects a recursively read-only bind mount which is read-write", func() {
+		Ω(transport.Validate(&garden.ContainerSpec{
+			BindMounts: []garden.BindMount{{Mode: garden.BindMountModeRW, RecursiveReadOnly: true}},
+		})).Should(Equal(garden.MalformedRequestError{
+			Cause: "bind_mounts[0].recursive_read_only: requires mode RO",
+		}))
+
+		Ω(transport.Validate(&garden.ContainerSpec{
+			BindMounts: []garden.BindMount{{
+				Mode:              garden.BindMountModeRO,
+				Propagation:       garden.BindMountPropagationSlave,
+				RecursiveReadOnly: true,
+			}},
+		})).Should(Succeed())
+	})
+
+	It("rejects devices which are not well formed", func() {
+		Ω(transport.Validate(&garden.ContainerSpec{
+			Devices: []garden.Device{{Path: "dev/fuse", Type: garden.DeviceTypeChar}},
+		})).Should(Equal(garden.MalformedRequestError{
+			Cause: `devices[0].path: "dev/fuse" is not absolute`,
+		}))
+
+		Ω(transport.Validate(&garden.ContainerSpec{
+			Devices: []garden.Device{{Path: "/dev/fuse", Type: "p"}},
+		})).Should(Equal(garden.MalformedRequestError{
+			Cause: `devices[0].type: "p" is not a known type`,
+		}))
+
+		Ω(transport.Validate(&garden.ContainerSpec{
+			Devices: []garden.Device{{Path: "/dev/fuse", Type: garden.DeviceTypeChar, Major: -1}},
+		})).Should(Equal(garden.MalformedRequestError{
+			Cause: "devices[0].major: -1 is negative",
+		}))
+
+		Ω(transport.Validate(&garden.ContainerSpec{
+			Devices: []garden.Device{{Path: "/dev/fuse", Type: garden.DeviceTypeChar, Permissions: "rwx"}},
+		})).Should(Equal(garden.MalformedRequestError{
+			Cause: `devices[0].permissions: "rwx" is not made of r, w and m`,
+		}))
+	})
+
+	It("rejects a seccomp profile which is not named or given inline exactly once", func() {
+		Ω(transport.Validate(&garden.ContainerSpec{
+			SeccompProfile: &garden.SeccompProfile{},
+		})).Should(Equal(garden.MalformedRequestError{
+			Cause: "seccomp_profile: exactly one of name and inline is required",
+		}))
+
+		Ω(transport.Validate(&garden.ContainerSpec{
+			SeccompProfile: &garden.SeccompProfile{Name: "untrusted", Inline: json.RawMessage(`{}`)},
+		})).Should(Equal(garden.MalformedRequestError{
+			Cause: "seccomp_profile: exactly one of name and inline is required",
+		}))
+	})
+
+	It("rejects an image which is not an OCI image reference", func() {
+		Ω(transport.Validate(&garden.ContainerSpec{
+			Image: &garden.ImageRef{Reference: "Team/App"},
+		})).Should(Equal(garden.MalformedRequestError{
+			Cause: `image.reference: "Team/App" is not an OCI image reference`,
+		}))
+
+		Ω(transport.Validate(&garden.ContainerSpec{
+			Image: &garden.ImageRef{Reference: "busybox@sha256:" + strings.Repeat("a", 40)},
+		})).Should(Equal(garden.MalformedRequestError{
+			Cause: fmt.Sprintf("image.reference: sha256 digest %q is not 64 hex characters", strings.Repeat("a", 40)),
+		}))
+
+		Ω(transport.Validate(&garden.ContainerSpec{
+			Image: &garden.ImageRef{Reference: "registry.example.com:5000/team/app:1.2@sha256:" + strings.Repeat("a", 64)},
+		})).Should(Succeed())
+	})
+
+	It("rejects an image given with a rootfs", func() {
+		Ω(transport.Validate(&garden.ContainerSpec{
+			RootFSPath: "docker:///busybox",
+			Image:      &garden.ImageRef{Reference: "busybox"},
+		})).Should(Equal(garden.MalformedRequestError{
+			Cause: "image: cannot be combined with rootfs",
+		}))
+	})
+
+	It("rejects a commit to an image name which is not a name", func() {
+		Ω(transport.Validate(&transport.CommitRequest{ImageName: "base:1.0"})).Should(Succeed())
+
+		Ω(transport.Validate(&transport.CommitRequest{ImageName: "Base"})).Should(Equal(garden.MalformedRequestError{
+			Cause: `image_name: "Base" is not an image name`,
+		}))
+
+		Ω(transport.Validate(&transport.CommitRequest{ImageName: "base@sha256:" + strings.Repeat("a", 64)})).Should(Equal(garden.MalformedRequestError{
+			Cause: fmt.Sprintf("image_name: %q is not an image name", "base@sha256:"+strings.Repeat("a", 64)),
+		}))
+	})
+
+	It("rejects a warm image request without a URI", func() {
+		Ω(transport.Validate(&transport.WarmImageRequest{})).Should(Equal(garden.MalformedRequestError{
+			Cause: "uri: is required",
+		}))
+
+		Ω(transport.Validate(&transport.WarmImageRequest{
+			URI:                 "docker:///busybox",
+			RegistryCredentials: &garden.RegistryCredentials{Password: "secret"},
+		})).Should(Equal(garden.MalformedRequestError{
+			Cause: "registry_credentials: username and password, or token, are required",
+		}))
+	})
+
+	It("rejects registry credentials which are not a username and password or a token", func() {
+		Ω(transport.Validate(&garden.ContainerSpec{
+			RegistryCredentials: &garden.RegistryCredentials{Username: "user"},
+		})).Should(Equal(garden.MalformedRequestError{
+			Cause: "registry_credentials: username and password, or token, are required",
+		}))
+
+		Ω(transport.Validate(&garden.ContainerSpec{
+			RegistryCredentials: &garden.RegistryCredentials{Username: "user", Token: "abc"},
+		})).Should(Equal(garden.MalformedRequestError{
+			Cause: "registry_credentials: token cannot be combined with username and password",
+		}))
+
+		Ω(transport.Validate(&garden.ContainerSpec{
+			RegistryCredentials: &garden.RegistryCredentials{Username: "user", Password: "secret"},
+		})).Should(Succeed())
+
+		Ω(transport.Validate(&garden.ContainerSpec{
+			RegistryCredentials: &garden.RegistryCredentials{Token: "abc"},
+		})).Should(Succeed())
+	})
+
+	It("rejects an inline seccomp profile which is not an object", func() {
+		var spec garden.ContainerSpec
+		err := transport.DecodeStrict(strings.NewReader(`{"seccomp_profile":{"inline":"untrusted"}}`), &spec)
+		Ω(err).Should(MatchError("malformed request: seccomp_profile.inline: must be a JSON object"))
+
+		err = transport.DecodeStrict(strings.NewReader(`{"seccomp_profile":{"inline":{"defaultAction":"SCMP_ACT_ERRNO"}}}`), &spec)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(spec.SeccompProfile.Inline).Should(MatchJSON(`{"defaultAction":"SCMP_ACT_ERRNO"}`))
+	})
+
+	It("rejects an LSM profile containing whitespace", func() {
+		Ω(transport.Validate(&garden.ContainerSpec{
+			LSMProfile: "garden-default\nunconfined",
+		})).Should(Equal(garden.MalformedRequestError{
+			Cause: `lsm_profile: "garden-default\nunconfined" contains whitespace`,
+		}))
+
+		Ω(transport.Validate(&garden.ContainerSpec{
+			LSMProfile: "system_u:system_r:container_t:s0:c1,c2",
+		})).Should(Succeed())
+	})
+
+	It("accepts well formed devices", func() {
+		Ω(transport.Validate(&garden.ContainerSpec{
+			Devices: []garden.Device{
+				{Path: "/dev/fuse", Type: garden.DeviceTypeChar, Major: 10, Minor: 229, Permissions: "rwm"},
+				{Path: "/dev/nvidia0", Type: garden.DeviceTypeChar, Permissions: "rw"},
+			},
+		})).Should(Succeed())
+	})
+
+	It("rejects limits out of bounds", func() {
+		Ω(transport.Validate(&garden.Limits{
+			CPU: garden.CPULimits{LimitInShares: transport.MaxCPUShares + 1},
+		})).Should(Equal(garden.MalformedRequestError{
+			Cause: "cpu_limits.limit_in_shares: 262145 exceeds 262144",
+		}))
+	})
+
+	It("rejects a bad spec in a bulk create", func() {
+		Ω(transport.Validate(&transport.BulkCreateRequest{
+			Specs: []garden.ContainerSpec{{}, {GraceTime: -time.Second}},
+		})).Should(Equal(garden.MalformedRequestError{
+			Cause: "specs[1].grace_time: -1s is negative",
+		}))
+	})
+
+	It("rejects a negative stop timeout", func() {
+		Ω(transport.Validate(&transport.StopRequest{Timeout: -time.Second})).Should(Equal(garden.MalformedRequestError{
+			Cause: "timeout: -1s is negative",
+		}))
+	})
+
+	It("rejects an unknown net out protocol", func() {
+		Ω(transport.Validate(&garden.NetOutRule{Protocol: 9})).Should(Equal(garden.MalformedRequestError{
+			Cause: "protocol: 9 is not a known protocol",
+		}))
+	})
+
+	It("rejects an inverted net out port range", func() {
+		Ω(transport.Validate(&garden.NetOutRule{
+			Ports: []garden.PortRange{{Start: 80, End: 20}},
+		})).Should(Equal(garden.MalformedRequestError{
+			Cause: "ports[0]: start 80 is after end 20",
+		}))
+	})
+
+	It("rejects a net out network whose bounds are of different families", func() {
+		Ω(transport.Validate(&garden.NetOutRule{
+			Networks: []garden.IPRange{{Start: net.ParseIP("10.0.0.1"), End: net.ParseIP("2001:db8::1")}},
+		})).Should(Equal(garden.MalformedRequestError{
+			Cause: "networks[0]: start 10.0.0.1 and end 2001:db8::1 are not of the same family",
+		}))
+	})
+
+	It("rejects an inverted net out network", func() {
+		Ω(transport.Validate(&garden.NetOutRule{
+			Networks: []garden.IPRange{
+				garden.IPRangeFromIP(net.ParseIP("10.0.0.1")),
+				{Start: net.ParseIP("2001:db8::ff"), End: net.ParseIP("2001:db8::1")},
+			},
+		})).Should(Equal(garden.MalformedRequestError{
+			Cause: "networks[1]: start 2001:db8::ff is after end 2001:db8::1",
+		}))
+	})
+
+	It("rejects ICMP rules for networks of the other family", func() {
+		Ω(transport.Validate(&garden.NetOutRule{
+			Protocol: garden.ProtocolICMP,
+			Networks: []garden.IPRange{garden.IPRangeFromIP(net.ParseIP("2001:db8::1"))},
+		})).Should(Equal(garden.MalformedRequestError{
+			Cause: "networks[0]: ICMP rules apply only to IPv4 networks",
+		}))
+
+		Ω(transport.Validate(&garden.NetOutRule{
+			Protocol: garden.ProtocolICMPv6,
+			Networks: []garden.IPRange{garden.IPRangeFromIP(net.ParseIP("10.0.0.1"))},
+		})).Should(Equal(garden.MalformedRequestError{
+			Cause: "networks[0]: ICMPv6 rules apply only to IPv6 networks",
+		}))
+	})
+
+	It("rejects a bad rule in a bulk net out", func() {
+		Ω(transport.Validate(&transport.BulkNetOutRequest{
+			Rules: []garden.NetOutRule{{}, {Protocol: 9}},
+		})).Should(Equal(garden.MalformedRequestError{
+			Cause: "rules[1].protocol: 9 is not a known protocol",
+		}))
+	})
+
+	It("rejects a negative window size", func() {
+		Ω(transport.Validate(&garden.TTYSpec{
+			WindowSize: &garden.WindowSize{Columns: -1},
+		})).Should(Equal(garden.MalformedRequestError{
+			Cause: "tty.window_size.columns: -1 is not between 0 and 65535",
+		}))
+	})
+
+	It("rejects an OOM score adjustment out of bounds", func() {
+		adj := 1001
+		Ω(transport.Validate(&garden.ProcessSpec{OOMScoreAdj: &adj})).Should(Equal(garden.MalformedRequestError{
+			Cause: "oom_score_adj: 1001 is not between -1000 and 1000",
+		}))
+
+		adj = garden.MinOOMScoreAdj
+		Ω(transport.Validate(&garden.ProcessSpec{OOMScoreAdj: &adj})).Should(Succeed())
+	})
+
+	It("rejects bad supplementary groups and umasks", func() {
+		Ω(transport.Validate(&garden.ProcessSpec{SupplementaryGroups: []string{"vcap", ""}})).Should(Equal(garden.MalformedRequestError{
+			Cause: `supplementary_groups[1]: "" is not a group`,
+		}))
+
+		umask := uint32(01000)
+		Ω(transport.Validate(&garden.ProcessSpec{Umask: &umask})).Should(Equal(garden.MalformedRequestError{
+			Cause: "umask: 01000 is not a file mode mask",
+		}))
+
+		umask = 0002
+		Ω(transport.Validate(&garden.ProcessSpec{SupplementaryGroups: []string{"vcap", "1001"}, Umask: &umask})).Should(Succeed())
+	})
+
+	It("rejects a health check without exactly one probe", func() {
+		Ω(transport.Validate(&garden.ContainerSpec{HealthCheck: &garden.HealthCheck{}})).Should(Equal(garden.MalformedRequestError{
+			Cause: "health_check.process, tcp or http: is required",
+		}))
+
+		Ω(transport.Validate(&garden.ContainerSpec{HealthCheck: &garden.HealthCheck{
+			TCP:  &garden.TCPProbe{Port: 8080},
+			HTTP: &garden.HTTPProbe{Port: 8080},
+		}})).Should(Equal(garden.MalformedRequestError{
+			Cause: "health_check.tcp: cannot be combined with http",
+		}))
+
+		Ω(transport.Validate(&garden.ContainerSpec{HealthCheck: &garden.HealthCheck{
+			HTTP: &garden.HTTPProbe{Port: 8080, Path: "healthz"},
+		}})).Should(Equal(garden.MalformedRequestError{
+			Cause: `health_check.http.path: "healthz" is not an absolute path`,
+		}))
+
+		Ω(transport.Validate(&garden.ContainerSpec{HealthCheck: &garden.HealthCheck{
+			TCP: &garden.TCPProbe{},
+		}})).Should(Equal(garden.MalformedRequestError{
+			Cause: "health_check.tcp.port: is required",
+		}))
+
+		Ω(transport.Validate(&garden.ContainerSpec{HealthCheck: &garden.HealthCheck{
+			HTTP:             &garden.HTTPProbe{Port: 8080, Path: "/healthz"},
+			FailureThreshold: 3,
+		}})).Should(Succeed())
+	})
+
+	It("rejects a restart policy without a main process", func() {
+		Ω(transport.Validate(&garden.ContainerSpec{RestartPolicy: &garden.RestartPolicy{Mode: garden.RestartAlways}})).Should(Equal(garden.MalformedRequestError{
+			Cause: "restart_policy.process.path: is required",
+		}))
+
+		Ω(transport.Validate(&garden.ContainerSpec{RestartPolicy: &garden.RestartPolicy{
+			Mode:        garden.RestartOnFailure,
+			Process:     garden.ProcessSpec{Path: "/main"},
+			MaxRestarts: -1,
+		}})).Should(Equal(garden.MalformedRequestError{
+			Cause: "restart_policy.max_restarts: -1 is negative",
+		}))
+
+		Ω(transport.Validate(&garden.ContainerSpec{RestartPolicy: &garden.RestartPolicy{
+			Mode:    garden.RestartOnFailure,
+			Process: garden.ProcessSpec{Path: "/main"},
+		}})).Should(Succeed())
+	})
+
+	It("rejects a negative process timeout", func() {
+		Ω(transport.Validate(&garden.ProcessSpec{Timeout: -time.Second})).Should(Equal(garden.MalformedRequestError{
+			Cause: "timeout: -1s is negative",
+		}))
+	})
+
+	It("rejects process names which cannot be used in a route", func() {
+		Ω(transport.Validate(&garden.ProcessSpec{Name: "web/1"})).Should(Equal(garden.MalformedRequestError{
+			Cause: `name: "web/1" is not a process name`,
+		}))
+
+		Ω(transport.Validate(&garden.ProcessSpec{Name: "web 1"})).Should(Equal(garden.MalformedRequestError{
+			Cause: `name: "web 1" is not a process name`,
+		}))
+
+		Ω(transport.Validate(&garden.ProcessSpec{Name: "web-1"})).Should(Succeed())
+	})
+
+	It("rejects an unknown signal in a process payload", func() {
+		Ω(transport.Validate(&transport.ProcessPayload{
+			Signal: signal(42),
+		})).Should(Equal(garden.MalformedRequestError{
+			Cause: "signal: 42 is not a known signal",
+		}))
+	})
+
+	It("accepts messages within bounds", func() {
+		Ω(transport.Validate(&garden.ContainerSpec{
+			GraceTime: time.Minute,
+			Limits: garden.Limits{
+				CPU: garden.CPULimits{
+					LimitInShares:        transport.MaxCPUShares,
+					QuotaInMicroseconds:  50000,
+					PeriodInMicroseconds: 100000,
+					Cpuset:               "0-3,6",
+				},
+				Disk: garden.DiskLimits{ByteSoft: 1, ByteHard: 2, InodeSoft: 10},
+			},
+		})).Should(Succeed())
+
+		Ω(transport.Validate(&garden.NetOutRule{
+			Protocol: garden.ProtocolTCP,
+			Ports:    []garden.PortRange{{Start: 80}},
+		})).Should(Succeed())
+
+		Ω(transport.Validate(&garden.NetOutRule{
+			Protocol: garden.ProtocolICMPv6,
+			Networks: []garden.IPRange{{Start: net.ParseIP("2001:db8::1"), End: net.ParseIP("2001:db8::ff")}},
+			ICMPs:    &garden.ICMPControl{Type: 128},
+		})).Should(Succeed())
+	})
+
+	It("accepts messages of types it does not know", func() {
+		Ω(transport.Validate(&transport.CheckpointRequest{})).Should(Succeed())
+	})
+})
+
+func signal(s garden.Signal) *garden.Signal {
+	return &s
+}